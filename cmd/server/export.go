@@ -0,0 +1,60 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// runExport implements `server export PATH`: it opens the store selected by STORE_DSN and writes
+// every transaction to PATH as JSON Lines (see store.MemoryStore.ExportTo), for nightly backups.
+// Only a persistent DSN (file://) has anything to export; a fresh memory:// store is empty.
+// The file is written to a temporary name and renamed into place, so a failed export never
+// leaves a truncated backup behind.
+func runExport(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: server export PATH")
+	}
+	path := args[0]
+
+	backend, err := store.Open(cmp.Or(os.Getenv("STORE_DSN"), store.DefaultDSN))
+	if err != nil {
+		return err
+	}
+	if c, ok := backend.(interface{ Close() }); ok {
+		defer c.Close()
+	}
+	exporter, ok := backend.(interface{ ExportTo(io.Writer) error })
+	if !ok {
+		return fmt.Errorf("store %T does not support export", backend)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+	if err := exporter.ExportTo(tmp); err != nil {
+		tmp.Close()
+		return fmt.Errorf("export to %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	log.Printf("Exported %d transactions to %s", backend.Count(), path)
+	return nil
+}
@@ -1,40 +1,136 @@
 package main
 
 import (
+	"cmp"
 	"log"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/clock"
 	"github.com/synctera/tech-challenge/internal/store"
 )
 
 func main() {
-	// Initialize store
-	memStore := store.NewMemoryStore()
+	// `server export PATH` writes a JSON Lines backup of the store and exits instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "export" {
+		if err := runExport(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	// Initialize store; STORE_DSN picks the backend (memory:// or file:///path/to/snapshot.json).
+	// IDEMPOTENCY_TTL (e.g. 720h) expires old transactions so their IDs can be reused.
+	// MAX_TRANSACTIONS caps the store so a constrained deployment rejects creates (507) instead of running out of memory
+	// IDEMPOTENCY_IGNORES_DESCRIPTION=true lets retries that only reword the description count as duplicates
+	// IDEMPOTENCY_FIELDS (e.g. amount,currency) limits which fields must match for a retry to count as a duplicate
+	// More than RETRY_ALARM_THRESHOLD creates of one ID within RETRY_ALARM_WINDOW log a warning (0 disables)
+	// AUDIT_LOG_PATH appends every change to that file and enables GET /audit
+	// LOCK_FREE_GETS=true serves GET /transactions/{id} from a sync.Map instead of under the store lock
+	clk := clock.Real{}
+	dsn := cmp.Or(os.Getenv("STORE_DSN"), store.DefaultDSN)
+	var significant []string
+	if fields := os.Getenv("IDEMPOTENCY_FIELDS"); fields != "" {
+		significant = strings.Split(fields, ",")
+	}
+	comparator, err := store.SignificantFields(significant...)
+	if err != nil {
+		log.Fatalf("IDEMPOTENCY_FIELDS: %v", err)
+	}
+	var mutations store.MutationLog
+	if path := os.Getenv("AUDIT_LOG_PATH"); path != "" {
+		auditLog, err := store.OpenFileMutationLog(path)
+		if err != nil {
+			log.Fatalf("AUDIT_LOG_PATH: %v", err)
+		}
+		defer auditLog.Close()
+		mutations = auditLog
+	}
+	backend, err := store.OpenWithOptions(dsn, store.Options{
+		Clock:               clk,
+		TTL:                 envDuration("IDEMPOTENCY_TTL", 0),
+		Capacity:            envInt("MAX_TRANSACTIONS", 0),
+		Comparator:          comparator,
+		RetryAlarmThreshold: envInt("RETRY_ALARM_THRESHOLD", 10),
+		RetryAlarmWindow:    envDuration("RETRY_ALARM_WINDOW", time.Minute),
+		MutationLog:         mutations,
+		LockFreeGets:        os.Getenv("LOCK_FREE_GETS") == "true",
+
+		ExcludeDescriptionFromIdempotency: os.Getenv("IDEMPOTENCY_IGNORES_DESCRIPTION") == "true",
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if c, ok := backend.(interface{ Close() }); ok {
+		defer c.Close()
+	}
+
+	// GET_CACHE_SIZE > 0 puts an LRU cache of that many transactions in front of GET /transactions/{id}
+	if size := envInt("GET_CACHE_SIZE", 0); size > 0 {
+		backend = store.NewCachingStore(backend, size)
+	}
 
 	// Initialize handlers
-	handler := api.NewHandler(memStore)
+	cfg := api.DefaultConfig()
+	cfg.Clock = clk
+	cfg.AllowUnboundedExport = os.Getenv("ALLOW_UNBOUNDED_EXPORT") == "true"
+	cfg.EnableResetEndpoint = os.Getenv("ENABLE_RESET_ENDPOINT") == "true"
+	cfg.AcceptDecimalAmounts = os.Getenv("ACCEPT_DECIMAL_AMOUNTS") == "true"
+	cfg.AllowMissingContentType = os.Getenv("ALLOW_MISSING_CONTENT_TYPE") == "true"
+	// REJECT_ZERO_AMOUNT=true is for ledgers that forbid zero-amount transactions
+	cfg.AllowZeroAmount = os.Getenv("REJECT_ZERO_AMOUNT") != "true"
+	// STRICT_GONE=true answers 410 instead of 200 for a GET of a soft-deleted transaction
+	cfg.StrictGone = os.Getenv("STRICT_GONE") == "true"
+	// ALLOWED_CURRENCIES (e.g. USD,CAD) rejects creates in any other currency
+	if currencies := os.Getenv("ALLOWED_CURRENCIES"); currencies != "" {
+		cfg.AllowedCurrencies = strings.Split(currencies, ",")
+	}
+	cfg.Pagination.DefaultLimit = envInt("PAGE_DEFAULT_LIMIT", api.DefaultPageLimit)
+	cfg.Pagination.MaxLimit = envInt("PAGE_MAX_LIMIT", api.DefaultMaxLimit)
+	cfg.MaxFutureEffectiveAtDays = envInt("MAX_FUTURE_EFFECTIVE_DAYS", 0)
+	// RESPONSE_TIME_FORMAT=rfc3339|unix|date changes how effective_at is written in responses
+	cfg.TimeFormat = os.Getenv("RESPONSE_TIME_FORMAT")
+	if err := api.ValidateTimeFormat(cfg.TimeFormat); err != nil {
+		log.Fatalf("RESPONSE_TIME_FORMAT: %v", err)
+	}
+	handler := api.NewHandlerWithConfig(backend, cfg)
 
-	// Setup routes
-	mux := http.NewServeMux()
-	mux.HandleFunc("/transactions", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodPost:
-			handler.CreateTransaction(w, r)
-		case http.MethodGet:
-			handler.ListTransactions(w, r)
-		default:
-			w.WriteHeader(http.StatusMethodNotAllowed)
+	// WEBHOOK_URLS (comma-separated) receive a POST of every created transaction, signed with WEBHOOK_SECRET if set
+	if urls := os.Getenv("WEBHOOK_URLS"); urls != "" {
+		webhooks, err := api.NewWebhookNotifier(backend, api.WebhookConfig{
+			URLs:   strings.Split(urls, ","),
+			Secret: []byte(os.Getenv("WEBHOOK_SECRET")),
+		})
+		if err != nil {
+			log.Fatalf("WEBHOOK_URLS: %v", err)
 		}
-	})
-	mux.HandleFunc("/transactions/{id}", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			handler.GetTransaction(w, r)
-		default:
-			w.WriteHeader(http.StatusMethodNotAllowed)
+		defer webhooks.Close()
+	}
+
+	// Per-client rate limiting on the transaction endpoints; off unless RATE_LIMIT_RPS is set.
+	// TRUSTED_PROXIES (comma-separated CIDRs or IPs) are the load balancers whose X-Forwarded-For is believed.
+	var limit api.Middleware
+	if rps := envInt("RATE_LIMIT_RPS", 0); rps > 0 {
+		trustedProxies, err := api.ParseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
+		if err != nil {
+			log.Fatalf("TRUSTED_PROXIES: %v", err)
 		}
-	})
+		limit = api.RateLimitMiddlewareWithClock(rps, envInt("RATE_LIMIT_BURST", 100), trustedProxies, clk)
+	}
+
+	// Server-to-server deployments set HMAC_SECRET to require a signed, timestamped request on every transaction route
+	var auth api.Middleware
+	if secret := os.Getenv("HMAC_SECRET"); secret != "" {
+		auth = api.HMACAuthMiddleware([]byte(secret))
+	}
+
+	// Setup routes; rate limiting runs first so unsigned floods are still throttled
+	mux := http.NewServeMux()
+	handler.RegisterRoutes(mux, api.Chain(limit, auth))
 
 	// Health check
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -42,9 +138,41 @@ func main() {
 		_, _ = w.Write([]byte("ok"))
 	})
 
-	addr := ":8080"
-	log.Printf("Starting server on %s", addr)
-	if err := http.ListenAndServe(addr, mux); err != nil {
+	// Tag every request with an ID and log it; inside gzip so error bodies can still be appended to.
+	// Compress large list and export responses for clients that accept gzip
+	traced := api.Chain(api.RequestIDMiddleware, api.LoggingMiddleware(log.Default()))(mux)
+	compressed := api.GzipMiddleware(api.DefaultGzipMinSize)(traced)
+
+	// Outermost, so a panic anywhere below becomes a 500 instead of killing the server
+	root := api.RecoverMiddleware(nil)(compressed)
+
+	// SERVER_*_TIMEOUT override the defaults in api.DefaultServerTimeouts; zero or invalid keeps them
+	srv := api.NewServer(":8080", root, api.ServerTimeouts{
+		ReadHeader: envDuration("SERVER_READ_HEADER_TIMEOUT", api.DefaultReadHeaderTimeout),
+		Read:       envDuration("SERVER_READ_TIMEOUT", api.DefaultReadTimeout),
+		Write:      envDuration("SERVER_WRITE_TIMEOUT", api.DefaultWriteTimeout),
+		Idle:       envDuration("SERVER_IDLE_TIMEOUT", api.DefaultIdleTimeout),
+	})
+	log.Printf("Starting server on %s", srv.Addr)
+	if err := srv.ListenAndServe(); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// envInt reads an integer environment variable, falling back to def when unset or invalid.
+func envInt(key string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// envDuration reads a time.ParseDuration environment variable, falling back to def when unset or invalid.
+func envDuration(key string, def time.Duration) time.Duration {
+	v, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,42 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// auditProvider is implemented by stores that can keep a mutation log, e.g. store.MemoryStore.
+type auditProvider interface {
+	MutationHistory(id string) ([]store.MutationEvent, error)
+}
+
+// ServeAudit handles GET /audit?id=txn-1. It lists every logged change to the transaction,
+// oldest first, e.g. [{"at":"...","type":"create","id":"txn-1","version":1}]. An ID with no
+// history gets an empty list rather than a 404, since purged transactions keep their history.
+// Stores without a mutation log get a 501.
+func (h *Handler) ServeAudit(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "id is required", http.StatusBadRequest)
+		return
+	}
+
+	ap, ok := h.store.(auditProvider)
+	if !ok {
+		http.Error(w, store.ErrMutationLogDisabled.Error(), http.StatusNotImplemented)
+		return
+	}
+	events, err := ap.MutationHistory(id)
+	if errors.Is(err, store.ErrMutationLogDisabled) {
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+		return
+	} else if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	writeJSON(w, r, events)
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// ExpandComputed is the expand query value that adds derived fields to GET /transactions/{id}.
+const ExpandComputed = "computed"
+
+// ComputedFields are derived at read time and never stored.
+type ComputedFields struct {
+	// AgeDays is whole days from effective_at to now; negative for future-dated transactions.
+	AgeDays int `json:"age_days"`
+	// AmountFormatted is the amount in major units for the transaction's currency, e.g. "12.34".
+	AmountFormatted string `json:"amount_formatted"`
+}
+
+// transactionWithComputed is the ?expand=computed response: the usual transaction fields
+// plus a computed object. Kept here so the stored model stays free of presentation concerns.
+type transactionWithComputed struct {
+	model.Transaction
+	Computed ComputedFields `json:"computed"`
+}
+
+func computeFields(txn model.Transaction, now time.Time) ComputedFields {
+	return ComputedFields{
+		AgeDays:         int(now.Sub(txn.EffectiveAt) / (24 * time.Hour)),
+		AmountFormatted: model.FormatMinorUnits(txn.Amount, txn.Currency),
+	}
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/clock"
+)
+
+// Config holds per-deployment handler options.
+// Use DefaultConfig() as a starting point so unset options keep the default behavior.
+type Config struct {
+	// Clock is the time source for time-based validation and computed fields. Defaults to the real clock.
+	Clock clock.Clock
+
+	// ClockSkew is how far ahead of the server clock a timestamp may be
+	// before it counts as "in the future". Absorbs small client clock drift.
+	ClockSkew time.Duration
+
+	// AmountUnit is advertised in the X-Amount-Unit response header on transaction responses
+	// so clients don't have to guess how amounts are expressed. Empty omits the header.
+	// Responses currently always carry minor units, so AmountUnitMinor is the only supported value.
+	AmountUnit string
+
+	// AllowUnboundedExport lets Accept: application/x-ndjson list requests ignore limit and
+	// offset and stream every matching transaction. Off by default, so NDJSON is paginated
+	// like the JSON array response.
+	AllowUnboundedExport bool
+
+	// EnableResetEndpoint registers POST /transactions/_reset, which wipes the store so
+	// integration tests can load a fresh dataset without restarting. Never enable in prod.
+	EnableResetEndpoint bool
+
+	// Pagination sets the list endpoint's default and maximum page size.
+	Pagination PaginationConfig
+
+	// RequirePastEffectiveAt rejects any effective_at after now (plus ClockSkew)
+	// with 422. Intended for posted-ledger deployments. Off by default.
+	RequirePastEffectiveAt bool
+
+	// MaxFutureEffectiveAtDays rejects an effective_at more than this many days after now
+	// with 422, catching client bugs such as year-9999 dates while still allowing scheduled
+	// transactions. Zero disables the guard (the default).
+	MaxFutureEffectiveAtDays int
+
+	// AcceptDecimalAmounts lets create and import bodies send amount as a major-unit decimal
+	// string, e.g. "amount":"12.34" with "currency":"USD", which is stored as 1234 minor units.
+	// More decimal places than the currency has is a 400. Integer amounts are accepted as
+	// before. Off by default, so a string amount is a schema violation.
+	AcceptDecimalAmounts bool
+
+	// AllowedCurrencies restricts the currency codes create, import and the validation
+	// endpoints accept, compared case-insensitively; anything else is a 400 naming the allowed
+	// set. A US-only deployment sets it to ["USD"]. Empty accepts any currency.
+	AllowedCurrencies []string
+
+	// AllowZeroAmount accepts transactions with amount 0. Ledgers that forbid them set it to
+	// false, which makes a zero amount on create, import, merge patch or amount correction a
+	// 400, and an invalid row in a CSV pre-flight. True in DefaultConfig.
+	AllowZeroAmount bool
+
+	// StrictGone makes GET /transactions/{id} answer 410 Gone for a soft-deleted transaction
+	// instead of returning it, so clients can tell "was deleted" from "never existed" (404).
+	// include_deleted=true still fetches it, for audits. Off by default.
+	StrictGone bool
+
+	// AllowMissingContentType lets POST /transactions bodies without a Content-Type header
+	// through as JSON, for older clients that never set it. A Content-Type other than
+	// application/json is a 415 either way. Off by default.
+	AllowMissingContentType bool
+
+	// TimeFormat picks how effective_at is written in transaction responses, for downstream
+	// systems that can't parse RFC 3339 with fractional seconds: TimeFormatRFC3339,
+	// TimeFormatUnix or TimeFormatDate. Empty keeps the default RFC 3339 encoding. Only the
+	// response changes; the stored value and input parsing are unaffected.
+	TimeFormat string
+}
+
+// PaginationConfig bounds the limit query parameter.
+type PaginationConfig struct {
+	// DefaultLimit is used when the request has no (or an unparsable) limit.
+	DefaultLimit int
+	// MaxLimit is the largest limit accepted; larger values are rejected with 400.
+	MaxLimit int
+}
+
+// Default page sizes used by DefaultConfig.
+const (
+	DefaultPageLimit = 100
+	DefaultMaxLimit  = 1000
+)
+
+// Amount units for the X-Amount-Unit header.
+const (
+	AmountUnitMinor = "minor"
+)
+
+// DefaultClockSkew is the default tolerance for client clock drift.
+const DefaultClockSkew = time.Minute
+
+// DefaultConfig returns the configuration used by NewHandler.
+func DefaultConfig() Config {
+	return Config{
+		Clock:           clock.Real{},
+		ClockSkew:       DefaultClockSkew,
+		AmountUnit:      AmountUnitMinor,
+		Pagination:      PaginationConfig{DefaultLimit: DefaultPageLimit, MaxLimit: DefaultMaxLimit},
+		AllowZeroAmount: true,
+	}
+}
@@ -0,0 +1,56 @@
+package api
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// fieldDiff is one differing field in a 409 conflict body.
+type fieldDiff struct {
+	Submitted any `json:"submitted"`
+	Stored    any `json:"stored"`
+}
+
+// conflictResponse is the body of a create that hit an existing ID with different data, e.g.
+// {"error":"...","conflict":{"amount":{"submitted":9999,"stored":1000}},"request_id":"..."}.
+// RequestIDMiddleware only tags plain-text errors, so the JSON body carries the ID itself.
+type conflictResponse struct {
+	Error     string               `json:"error"`
+	Conflict  map[string]fieldDiff `json:"conflict"`
+	RequestID string               `json:"request_id,omitempty"`
+}
+
+// conflictDiff lists the client-supplied fields where submitted and stored differ, keyed by
+// JSON name. It compares the same fields as model.Transaction.Equal, so server-assigned fields
+// such as seq and version never show up.
+func conflictDiff(submitted, stored model.Transaction) map[string]fieldDiff {
+	diff := make(map[string]fieldDiff)
+	add := func(name string, differs bool, submittedValue, storedValue any) {
+		if differs {
+			diff[name] = fieldDiff{Submitted: submittedValue, Stored: storedValue}
+		}
+	}
+	add("account_id", submitted.AccountID != stored.AccountID, submitted.AccountID, stored.AccountID)
+	add("amount", submitted.Amount != stored.Amount, submitted.Amount, stored.Amount)
+	add("currency", submitted.Currency != stored.Currency, submitted.Currency, stored.Currency)
+	add("direction", submitted.Direction != stored.Direction, submitted.Direction, stored.Direction)
+	add("effective_at", !submitted.EffectiveAt.Equal(stored.EffectiveAt), submitted.EffectiveAt, stored.EffectiveAt)
+	add("metadata", !maps.Equal(submitted.Metadata, stored.Metadata), submitted.Metadata, stored.Metadata)
+	add("tags", !slices.Equal(submitted.Tags, stored.Tags), submitted.Tags, stored.Tags)
+	add("description", submitted.Description != stored.Description, submitted.Description, stored.Description)
+	return diff
+}
+
+// writeConflict writes the 409 for a create whose ID is taken by stored.
+func writeConflict(w http.ResponseWriter, r *http.Request, submitted, stored model.Transaction) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	writeJSON(w, r, conflictResponse{
+		Error:     "transaction ID already exists with different data",
+		Conflict:  conflictDiff(submitted, stored),
+		RequestID: RequestIDFromContext(r.Context()),
+	})
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"net/http"
+)
+
+// TransactionCounts handles GET /transactions/counts. It returns the number of transactions
+// per currency, e.g. {"USD":10,"EUR":3}, optionally limited by start_date and end_date.
+// Currency codes are uppercased, matching the case-insensitive currency filter.
+func (h *Handler) TransactionCounts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	startDate, endDate, err := ParseAndValidateDateFilters(query.Get("start_date"), query.Get("end_date"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Same inclusive end-of-day rule as the list endpoint
+	filter := Filter{StartDate: startDate, EndDate: endDate}
+	counts, err := h.store.CountByCurrency(filter.rangeStart(), filter.rangeEnd())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	writeJSON(w, r, counts)
+}
+
+// TransactionCurrencies handles GET /transactions/currencies. It returns the sorted currency
+// codes present in the store, e.g. ["EUR","USD"], for populating a filter dropdown.
+func (h *Handler) TransactionCurrencies(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	writeJSON(w, r, h.store.DistinctCurrencies())
+}
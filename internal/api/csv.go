@@ -0,0 +1,273 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// CSVColumns are the columns every transaction CSV must have, matched by header name.
+// An optional "metadata" column holds a JSON object of string values.
+var CSVColumns = []string{"id", "account_id", "amount", "currency", "direction", "effective_at"}
+
+// csvExportHeader is the header row written by WriteCSV. It matches what NewCSVReader
+// accepts, so an export can be fed straight back into validate-csv.
+var csvExportHeader = append(append([]string{}, CSVColumns...), "metadata")
+
+// CSVRowError describes a single rejected CSV row. Line is the 1-based line in the file.
+type CSVRowError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// CSVValidationReport summarizes a validate-only pass over a CSV file.
+type CSVValidationReport struct {
+	Total  int           `json:"total"`
+	Valid  int           `json:"valid"`
+	Errors []CSVRowError `json:"errors"`
+}
+
+// CSVReader decodes transactions from CSV with a header row.
+type CSVReader struct {
+	r       *csv.Reader
+	columns map[string]int
+}
+
+// NewCSVReader reads and checks the header row, returning an error if a required column is missing.
+func NewCSVReader(r io.Reader) (*CSVReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1 // row width is checked per row so one bad row doesn't abort the file
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("csv is empty, expected a header row")
+	} else if err != nil {
+		return nil, fmt.Errorf("invalid csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range CSVColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("csv header is missing required column %q", name)
+		}
+	}
+	return &CSVReader{r: cr, columns: columns}, nil
+}
+
+// Next returns the next row's transaction and its line number.
+// A row-level problem is returned as a non-nil rowErr so callers can keep reading;
+// err is io.EOF at the end of input or a fatal read error.
+func (c *CSVReader) Next() (txn model.Transaction, line int, rowErr error, err error) {
+	record, readErr := c.r.Read()
+	if errors.Is(readErr, io.EOF) {
+		return model.Transaction{}, 0, nil, io.EOF
+	}
+
+	var parseErr *csv.ParseError
+	if errors.As(readErr, &parseErr) {
+		// Malformed quoting etc. only affects this row
+		return model.Transaction{}, parseErr.Line, errors.New(parseErr.Err.Error()), nil
+	} else if readErr != nil {
+		return model.Transaction{}, 0, nil, readErr
+	}
+
+	// FieldPos is only valid after a successful Read
+	line, _ = c.r.FieldPos(0)
+	txn, rowErr = c.parseRecord(record)
+	return txn, line, rowErr, nil
+}
+
+func (c *CSVReader) field(record []string, name string) (string, bool) {
+	i, ok := c.columns[name]
+	if !ok || i >= len(record) {
+		return "", false
+	}
+	return strings.TrimSpace(record[i]), true
+}
+
+func (c *CSVReader) parseRecord(record []string) (model.Transaction, error) {
+	var txn model.Transaction
+
+	for _, name := range CSVColumns {
+		if _, ok := c.field(record, name); !ok {
+			return txn, fmt.Errorf("row is missing column %q", name)
+		}
+	}
+
+	txn.ID, _ = c.field(record, "id")
+	txn.AccountID, _ = c.field(record, "account_id")
+	txn.Currency, _ = c.field(record, "currency")
+	txn.Direction, _ = c.field(record, "direction")
+
+	amountStr, _ := c.field(record, "amount")
+	amount, err := strconv.ParseInt(amountStr, 10, 64)
+	if err != nil {
+		return txn, fmt.Errorf("invalid amount %q", amountStr)
+	}
+	txn.Amount = amount
+
+	effectiveAtStr, _ := c.field(record, "effective_at")
+	if effectiveAtStr != "" {
+		effectiveAt, err := time.Parse(time.RFC3339, effectiveAtStr)
+		if err != nil {
+			return txn, fmt.Errorf("invalid effective_at %q, use RFC3339", effectiveAtStr)
+		}
+		txn.EffectiveAt = effectiveAt
+	}
+
+	if metadataStr, ok := c.field(record, "metadata"); ok && metadataStr != "" {
+		if err := json.Unmarshal([]byte(metadataStr), &txn.Metadata); err != nil {
+			return txn, errors.New("invalid metadata, expected a JSON object of strings")
+		}
+	}
+
+	return txn, nil
+}
+
+// WriteCSV writes transactions as CSV with a header row. Metadata is JSON-encoded into a
+// single column and left empty when there is none. Cells are escaped with escapeCSVCell.
+func WriteCSV(w io.Writer, transactions []model.Transaction) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvExportHeader); err != nil {
+		return err
+	}
+
+	for _, txn := range transactions {
+		metadata := ""
+		if len(txn.Metadata) > 0 {
+			b, err := json.Marshal(txn.Metadata)
+			if err != nil {
+				return err
+			}
+			metadata = string(b)
+		}
+
+		record := []string{
+			txn.ID,
+			txn.AccountID,
+			strconv.FormatInt(txn.Amount, 10),
+			txn.Currency,
+			txn.Direction,
+			txn.EffectiveAt.Format(time.RFC3339Nano),
+			metadata,
+		}
+		for i, cell := range record {
+			record[i] = escapeCSVCell(cell)
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// escapeCSVCell prefixes a cell that a spreadsheet would read as a formula (starting with =,
+// +, -, @, a tab or a carriage return) with a single quote, so an export opened in Excel
+// can't run a client-supplied value such as account_id "=HYPERLINK(...)". Such a cell then
+// reads back from validate-csv with the quote.
+func escapeCSVCell(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// WantsCSV reports whether the client asked for CSV via format=csv or an Accept: text/csv header.
+func WantsCSV(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "csv" {
+		return true
+	}
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err == nil && mediaType == "text/csv" {
+			return true
+		}
+	}
+	return false
+}
+
+// writeCSVResponse sends transactions as a CSV attachment.
+func (h *Handler) writeCSVResponse(w http.ResponseWriter, transactions []model.Transaction) {
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", "attachment; filename=transactions.csv")
+	h.setAmountUnit(w)
+
+	if err := WriteCSV(w, transactions); err != nil {
+		// Headers are already sent, so the best we can do is log the truncated export
+		log.Printf("csv export failed: %v", err)
+	}
+}
+
+// ValidateCSV parses every row and checks it as a create would under cfg, with
+// ValidateTransaction, the currency allow-list, the zero-amount setting and the effective_at
+// limits, without storing anything. It returns an error only when the file as a whole is unreadable (e.g. bad header).
+func ValidateCSV(r io.Reader, cfg Config) (CSVValidationReport, error) {
+	report := CSVValidationReport{Errors: []CSVRowError{}}
+
+	reader, err := NewCSVReader(r)
+	if err != nil {
+		return report, err
+	}
+
+	for {
+		txn, line, rowErr, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return report, err
+		}
+
+		report.Total++
+		if rowErr == nil {
+			rowErr = ValidateTransaction(txn)
+		}
+		if rowErr == nil {
+			rowErr = validatePolicy(txn, cfg)
+		}
+		if rowErr == nil {
+			rowErr = validateEffectiveAtPolicy(txn.EffectiveAt, cfg)
+		}
+		if rowErr != nil {
+			report.Errors = append(report.Errors, CSVRowError{Line: line, Message: rowErr.Error()})
+			continue
+		}
+		report.Valid++
+	}
+
+	return report, nil
+}
+
+// ValidateTransactionsCSV handles POST /transactions/validate-csv.
+// It reports every invalid row without touching the store, as a pre-flight for imports.
+func (h *Handler) ValidateTransactionsCSV(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "text/csv" {
+		http.Error(w, "content type must be text/csv", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	report, err := ValidateCSV(r.Body, h.cfg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	writeJSON(w, r, report)
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+type deleteResponse struct {
+	Deleted int `json:"deleted"`
+}
+
+// DeleteTransactions handles DELETE /transactions for data retention. It removes every
+// transaction matching the list endpoint's filters and returns how many were deleted.
+// At least one filter is required so a bare DELETE can't purge the whole store.
+// Soft-deleted transactions are purged too, since retention applies to them as well.
+func (h *Handler) DeleteTransactions(w http.ResponseWriter, r *http.Request) {
+	filter, err := h.parseFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if filter.IsEmpty() {
+		http.Error(w, "at least one filter is required to delete transactions", http.StatusBadRequest)
+		return
+	}
+	filter.IncludeDeleted = true
+
+	deleted, err := h.store.DeleteWhere(filter.Matches)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	writeJSON(w, r, deleteResponse{Deleted: deleted})
+}
+
+// DeleteTransaction handles DELETE /transactions/{id}. It soft-deletes the transaction: the
+// record is kept for audit and still returned by GET /transactions/{id} with "deleted": true,
+// but listings skip it unless include_deleted=true. Deleting twice is not an error.
+func (h *Handler) DeleteTransaction(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	if err := h.store.Delete(id); errors.Is(err, store.ErrNotFound) {
+		http.Error(w, "transaction not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	deleted, err := h.store.Get(id)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	deleted = deleted.WithDefaults()
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", deleted.ETag())
+	h.setAmountUnit(w)
+	writeJSON(w, r, h.baseResponseOptions().transaction(deleted))
+}
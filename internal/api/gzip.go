@@ -0,0 +1,184 @@
+package api
+
+import (
+	"compress/gzip"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultGzipMinSize is the smallest response GzipMiddleware compresses. Below about one
+// packet the gzip header and CPU cost outweigh the bytes saved.
+const DefaultGzipMinSize = 1024
+
+// incompressibleTypes are content types that are already compressed, so gzipping them
+// again costs CPU for no gain. Entries ending in "/" match a whole top-level type.
+var incompressibleTypes = []string{
+	"application/gzip",
+	"application/x-gzip",
+	"application/zip",
+	"application/zstd",
+	"image/",
+	"video/",
+	"audio/",
+}
+
+// GzipMiddleware compresses responses of at least minSize bytes for clients that send
+// Accept-Encoding: gzip. The first minSize bytes are buffered to make the decision, so small
+// responses and already-compressed content types are sent unchanged.
+func GzipMiddleware(minSize int) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if r.Method == http.MethodHead || !acceptsGzip(r.Header.Get("Accept-Encoding")) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			gw := &gzipResponseWriter{ResponseWriter: w, minSize: minSize, status: http.StatusOK}
+			next.ServeHTTP(gw, r)
+			// Not deferred: if next panics, the held-back response must not go out as a 200,
+			// so RecoverMiddleware can still send a 500
+			gw.Close()
+		})
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip (or *) with a non-zero q.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		name, value, _ := strings.Cut(params, "=")
+		if strings.TrimSpace(name) == "q" {
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && q == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter holds back the status line and the first minSize bytes until it can
+// decide whether to compress, then either streams through a gzip.Writer or passes writes on.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	minSize int
+	status  int
+	buf     []byte
+	decided bool
+	gz      *gzip.Writer // non-nil once compressing
+}
+
+func (g *gzipResponseWriter) WriteHeader(status int) {
+	if g.decided {
+		return
+	}
+	// 1xx responses are informational and don't end the header phase
+	if status >= 100 && status < 200 {
+		g.ResponseWriter.WriteHeader(status)
+		return
+	}
+	g.status = status
+	// Bodyless responses can't be compressed, so there's nothing to wait for
+	if status == http.StatusNoContent || status == http.StatusNotModified {
+		g.decide(false)
+	}
+}
+
+func (g *gzipResponseWriter) Write(b []byte) (int, error) {
+	if !g.decided {
+		g.buf = append(g.buf, b...)
+		if len(g.buf) < g.minSize {
+			return len(b), nil
+		}
+		if err := g.decide(g.compressible()); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	if g.gz != nil {
+		return g.gz.Write(b)
+	}
+	return g.ResponseWriter.Write(b)
+}
+
+// compressible reports whether the buffered response should be gzipped, based on its
+// headers. It is only called once the buffer has reached minSize.
+func (g *gzipResponseWriter) compressible() bool {
+	header := g.Header()
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	for _, t := range incompressibleTypes {
+		if mediaType == t || (strings.HasSuffix(t, "/") && strings.HasPrefix(mediaType, t)) {
+			return false
+		}
+	}
+	return true
+}
+
+// decide commits the headers and flushes the buffered bytes, compressed or not.
+func (g *gzipResponseWriter) decide(compress bool) error {
+	g.decided = true
+	header := g.Header()
+
+	// Sniff the type from the uncompressed bytes; net/http would otherwise sniff the gzip stream
+	if header.Get("Content-Type") == "" && len(g.buf) > 0 {
+		header.Set("Content-Type", http.DetectContentType(g.buf))
+	}
+
+	if compress {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length") // the handler's length describes the uncompressed body
+		g.ResponseWriter.WriteHeader(g.status)
+		g.gz = gzip.NewWriter(g.ResponseWriter)
+		_, err := g.gz.Write(g.buf)
+		g.buf = nil
+		return err
+	}
+
+	g.ResponseWriter.WriteHeader(g.status)
+	if len(g.buf) == 0 {
+		return nil
+	}
+	_, err := g.ResponseWriter.Write(g.buf)
+	g.buf = nil
+	return err
+}
+
+// Flush keeps streaming responses (NDJSON) working. A flush before minSize bytes have been
+// written commits to sending the response uncompressed.
+func (g *gzipResponseWriter) Flush() {
+	if !g.decided {
+		g.decide(false)
+	}
+	if g.gz != nil {
+		g.gz.Flush()
+	}
+	if f, ok := g.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Close sends a response that never reached minSize and terminates the gzip stream.
+func (g *gzipResponseWriter) Close() error {
+	if !g.decided {
+		return g.decide(false)
+	}
+	if g.gz != nil {
+		return g.gz.Close()
+	}
+	return nil
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (g *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return g.ResponseWriter
+}
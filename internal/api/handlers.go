@@ -1,24 +1,83 @@
 package api
 
 import (
+	"bytes"
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"math"
+	"mime"
 	"net/http"
 	"net/url"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
+	"github.com/synctera/tech-challenge/internal/clock"
 	"github.com/synctera/tech-challenge/internal/model"
 	"github.com/synctera/tech-challenge/internal/store"
 )
 
+// MaxIDLength caps transaction IDs; see IsValidID.
+const MaxIDLength = 128
+
+// MaxAccountIDLength caps account IDs, which key the per-account index.
+const MaxAccountIDLength = 128
+
+// MaxIdempotencyKeyLength caps the Idempotency-Key header so keys can't be used to bloat the store.
+const MaxIdempotencyKeyLength = 255
+
+// Metadata limits, so a single transaction can't carry megabytes of free-form data.
+const (
+	MaxMetadataEntries     = model.MaxMetadataEntries
+	MaxMetadataKeyLength   = 256
+	MaxMetadataValueLength = 256
+)
+
+// Tag limits, for the same reason.
+const (
+	MaxTags      = 50
+	MaxTagLength = 256
+)
+
+// MaxDescriptionLength caps the free-text description, for the same reason.
+const MaxDescriptionLength = 256
+
 type Handler struct {
-	store store.Store
+	store   store.Store
+	cfg     Config
+	metrics *Metrics
 }
 
 func NewHandler(s store.Store) *Handler {
-	return &Handler{store: s}
+	return NewHandlerWithConfig(s, DefaultConfig())
+}
+
+// NewHandlerWithConfig creates a Handler with the given options.
+// A nil Clock falls back to the real clock and unset page sizes fall back to the defaults.
+func NewHandlerWithConfig(s store.Store, cfg Config) *Handler {
+	if cfg.Clock == nil {
+		cfg.Clock = clock.Real{}
+	}
+	if cfg.Pagination.DefaultLimit <= 0 {
+		cfg.Pagination.DefaultLimit = DefaultPageLimit
+	}
+	if cfg.Pagination.MaxLimit <= 0 {
+		cfg.Pagination.MaxLimit = DefaultMaxLimit
+	}
+	if cfg.Pagination.DefaultLimit > cfg.Pagination.MaxLimit {
+		// Otherwise requests without a limit would be rejected
+		cfg.Pagination.DefaultLimit = cfg.Pagination.MaxLimit
+	}
+	return &Handler{store: s, cfg: cfg, metrics: NewMetrics(s)}
 }
 
 func (h *Handler) GetTransaction(w http.ResponseWriter, r *http.Request) {
@@ -27,6 +86,19 @@ func (h *Handler) GetTransaction(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "missing transaction id", http.StatusBadRequest)
 		return
 	}
+	if !IsValidID(id) {
+		http.Error(w, errInvalidID.Error(), http.StatusBadRequest)
+		return
+	}
+
+	includeDeleted := false
+	if v := r.URL.Query().Get("include_deleted"); v != "" {
+		var err error
+		if includeDeleted, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "include_deleted must be true or false", http.StatusBadRequest)
+			return
+		}
+	}
 
 	txn, err := h.store.Get(id)
 	if errors.Is(err, store.ErrNotFound) {
@@ -36,100 +108,837 @@ func (h *Handler) GetTransaction(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
+	if txn.Deleted && h.cfg.StrictGone && !includeDeleted {
+		http.Error(w, "transaction was deleted", http.StatusGone)
+		return
+	}
+
+	txn = txn.WithDefaults()
+
+	opts, err := h.parseResponseOptions(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Optional derived fields for reporting clients; the default shape is unchanged
+	switch r.URL.Query().Get("expand") {
+	case "":
+	case ExpandComputed:
+		// The computed fields change over time, so this representation gets no ETag
+		w.Header().Set("Content-Type", "application/json")
+		h.setAmountUnit(w)
+		writeJSON(w, r, opts.withComputed(txn, computeFields(txn, h.cfg.Clock.Now())))
+		return
+	default:
+		http.Error(w, "expand must be computed", http.StatusBadRequest)
+		return
+	}
+
+	// Conditional GET: polling clients that already hold this version get an empty 304
+	etag := txn.ETag()
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(txn)
+	h.setAmountUnit(w)
+	writeJSON(w, r, opts.transaction(txn))
 }
 
-func (h *Handler) CreateTransaction(w http.ResponseWriter, r *http.Request) {
-	var txn model.Transaction
+// etagMatches reports whether an If-None-Match or If-Match header value matches etag.
+// The header may be "*" or a comma-separated list; weak validators (W/) compare by their tag.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeTransaction turns a create body into the transaction to store: it checks the body
+// against the schema, decodes it, normalizes tags and effective_at, and runs the field and
+// configured effective_at validation. On failure it also returns the status to report it with.
+// Shared by POST /transactions and POST /transactions/_import so both accept the same input,
+// except that serverMetadata, set only by the import, accepts the server-maintained metadata
+// keys a `server export` dump carries.
+func (h *Handler) decodeTransaction(body []byte, serverMetadata bool) (model.Transaction, int, error) {
+	if h.cfg.AcceptDecimalAmounts {
+		scaled, schemaErr, err := scaleDecimalAmount(body)
+		if err != nil {
+			return model.Transaction{}, http.StatusBadRequest, errors.New("invalid JSON")
+		}
+		if schemaErr != nil {
+			return model.Transaction{}, http.StatusBadRequest, errors.New(formatSchemaErrors([]SchemaError{*schemaErr}))
+		}
+		body = scaled
+	}
+
+	// Check field types against the schema first so e.g. "amount":"100" is reported by name
+	schemaErrs, err := ValidateTransactionJSON(body)
+	if err != nil {
+		return model.Transaction{}, http.StatusBadRequest, errors.New("invalid JSON")
+	}
+	if len(schemaErrs) > 0 {
+		return model.Transaction{}, http.StatusBadRequest, errors.New(formatSchemaErrors(schemaErrs))
+	}
 
 	// Parse JSON
-	if err := json.NewDecoder(r.Body).Decode(&txn); err != nil {
-		http.Error(w, "invalid JSON", http.StatusBadRequest)
-		return
+	var txn model.Transaction
+	if err := json.Unmarshal(body, &txn); err != nil {
+		return model.Transaction{}, http.StatusBadRequest, errors.New("invalid JSON")
 	}
 
+	// Tags are case-insensitive labels; store them lowercased, sorted and deduplicated
+	txn.Tags = model.NormalizeTags(txn.Tags)
+
 	// Validate required fields
-	if err := ValidateTransaction(txn); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+	if err := validateTransaction(txn, serverMetadata); err != nil {
+		return model.Transaction{}, http.StatusBadRequest, err
+	}
+	if err := validatePolicy(txn, h.cfg); err != nil {
+		return model.Transaction{}, http.StatusBadRequest, err
+	}
+
+	// Store and return effective_at in UTC so it lines up with the UTC date filters and
+	// the same instant sent with different offsets is stored identically. The submitted
+	// offset is kept alongside for clients that report in local time; any time_zone sent
+	// by the client is overwritten.
+	txn.TimeZone = model.TimeZoneOf(txn.EffectiveAt)
+	txn.EffectiveAt = txn.EffectiveAt.UTC()
+
+	// The payload is well-formed but not acceptable, so 422 rather than 400
+	if err := validateEffectiveAtPolicy(txn.EffectiveAt, h.cfg); err != nil {
+		return model.Transaction{}, http.StatusUnprocessableEntity, err
+	}
+	return txn, 0, nil
+}
+
+// checkJSONContentType requires r's Content-Type to be application/json, with any parameters
+// such as charset. A missing header is accepted only when Config.AllowMissingContentType is set.
+func (h *Handler) checkJSONContentType(r *http.Request) error {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		if h.cfg.AllowMissingContentType {
+			return nil
+		}
+		return errors.New("Content-Type must be application/json")
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "application/json" {
+		return fmt.Errorf("Content-Type must be application/json, got %q", contentType)
+	}
+	return nil
+}
+
+// scaleDecimalAmount rewrites a create body whose amount is a major-unit decimal string, e.g.
+// "amount":"12.34","currency":"USD", to carry the integer minor units (1234) the schema and the
+// model expect. Bodies with a numeric amount, or without a string currency to scale by, are
+// returned unchanged for the schema to judge. An unparsable or over-precise decimal is
+// reported as a violation of the amount field; err is set only if body isn't a JSON object.
+func scaleDecimalAmount(body []byte) ([]byte, *SchemaError, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber() // re-encoding must not turn the other numbers into floats
+
+	var fields map[string]any
+	if err := dec.Decode(&fields); err != nil {
+		return nil, nil, err
+	}
+	amount, isDecimal := fields["amount"].(string)
+	currency, hasCurrency := fields["currency"].(string)
+	if !isDecimal || !hasCurrency {
+		return body, nil, nil
+	}
+
+	minor, err := model.ParseMajorUnits(amount, currency)
+	if err != nil {
+		return nil, &SchemaError{Field: "amount", Message: err.Error()}, nil
+	}
+	fields["amount"] = minor
+	scaled, err := json.Marshal(fields)
+	return scaled, nil, err
+}
+
+// Create modes for POST /transactions?mode=.
+const (
+	CreateModeCreate = "create" // the default: an existing ID with different data is a 409
+	CreateModeUpsert = "upsert" // an existing ID with different data is replaced
+)
+
+func (h *Handler) CreateTransaction(w http.ResponseWriter, r *http.Request) {
+	mode := cmp.Or(r.URL.Query().Get("mode"), CreateModeCreate)
+	if mode != CreateModeCreate && mode != CreateModeUpsert {
+		http.Error(w, "mode must be create or upsert", http.StatusBadRequest)
 		return
 	}
 
-	// Call the store and create the transaction
-	err := h.store.Create(txn)
+	// Reject form posts and the like up front rather than reporting them as invalid JSON
+	if err := h.checkJSONContentType(r); err != nil {
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	txn, status, err := h.decodeTransaction(body, false)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	// Explicit Idempotency-Key: a retry with the same key replays the original transaction
+	// even if the payload changed (e.g. a regenerated timestamp)
+	idemKey := r.Header.Get("Idempotency-Key")
+	if len(idemKey) > MaxIdempotencyKeyLength {
+		http.Error(w, "Idempotency-Key is too long", http.StatusBadRequest)
+		return
+	}
+
+	// Call the store and create the transaction. With a key, the store checks and binds it in
+	// the same lock as the write, so two requests racing with one key can't both write.
+	status = http.StatusCreated
+	var created bool
+	switch {
+	case idemKey != "":
+		created, err = h.store.CreateWithIdempotencyKey(idemKey, txn, mode == CreateModeUpsert)
+	case mode == CreateModeUpsert:
+		created, err = h.store.Upsert(txn)
+	default:
+		err = h.store.Create(txn)
+		created = err == nil
+	}
+	if err == nil && !created {
+		// Replacing an existing transaction, or finding it identical, is a 200 like a retry
+		status = http.StatusOK
+	}
 
 	// Handle errors from store
-	if errors.Is(err, store.ErrDuplicate) {
-		// Idempotent retry - same transaction already exists
+	var conflict *store.ConflictError
+	var replay *store.ReplayError
+	if errors.As(err, &replay) {
+		// Key already used for this ID; answer with what was stored the first time
 		w.Header().Set("Content-Type", "application/json")
+		h.setAmountUnit(w)
 		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(txn)
+		writeJSON(w, r, h.baseResponseOptions().transaction(replay.Original))
+		return
+	} else if errors.Is(err, store.ErrIdempotencyKeyReused) {
+		http.Error(w, "Idempotency-Key already used for a different transaction", http.StatusConflict)
+		return
+	} else if errors.Is(err, store.ErrDuplicate) {
+		// Idempotent retry - same transaction already exists
+		status = http.StatusOK
+	} else if errors.As(err, &conflict) {
+		// Same ID, different data - conflict; say which fields differ
+		writeConflict(w, r, txn, conflict.Existing)
 		return
 	} else if errors.Is(err, store.ErrConflict) {
-		// Same ID, different data - conflict
+		// A store that doesn't report the stored copy
 		http.Error(w, "transaction ID already exists with different data", http.StatusConflict)
 		return
+	} else if errors.Is(err, store.ErrCapacityExceeded) {
+		http.Error(w, "transaction store is full", http.StatusInsufficientStorage)
+		return
+	} else if errors.Is(err, store.ErrInvalidAmountHistory) {
+		// An upsert changing the amount can't record it; see PatchTransaction
+		http.Error(w, "stored metadata."+model.MetadataAmountHistory+" is not a valid list, so the amount can't be changed", http.StatusConflict)
+		return
 	} else if err != nil {
 		// Some other error
 		http.Error(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	// 5. Success - new transaction created
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(txn)
+	// Success - 201 for a new transaction, 200 for an idempotent retry or an upsert replace
+	w.Header().Set("Content-Type", "application/json")
+	h.setAmountUnit(w)
+	w.WriteHeader(status)
+	writeJSON(w, r, h.baseResponseOptions().transaction(h.storedOrSubmitted(txn)))
+}
+
+// transactionPatch is the PATCH /transactions/{id} body: either a metadata merge, where a null
+// value deletes the key, or an amount correction. Metadata that is omitted or null decodes to
+// a nil map and leaves the stored metadata alone; an explicit {} decodes to an empty map and
+// clears it.
+type transactionPatch struct {
+	Metadata map[string]*string `json:"metadata"`
+	Amount   *int64             `json:"amount"`
+	// Version, when set, must equal the stored version or the patch is rejected (like If-Match)
+	Version *int `json:"version"`
+}
+
+// PatchTransaction handles PATCH /transactions/{id}. It either merges the body's metadata into
+// the stored transaction or corrects its amount, recording the prior amount in
+// metadata[amount_history]; other fields are immutable. An If-Match header or a body version
+// makes the patch conditional: if the transaction changed since the client read it, the patch
+// is rejected with 409 instead of silently overwriting the other change. A body sent as
+// application/merge-patch+json is a JSON Merge Patch instead; see mergePatchTransaction.
+func (h *Handler) PatchTransaction(w http.ResponseWriter, r *http.Request) {
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil && mediaType == mergePatchMediaType {
+		h.mergePatchTransaction(w, r)
+		return
+	}
+	id := r.PathValue("id")
+
+	var patch transactionPatch
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	switch {
+	case patch.Metadata == nil && patch.Amount == nil:
+		http.Error(w, "metadata or amount is required", http.StatusBadRequest)
+		return
+	case patch.Metadata != nil && patch.Amount != nil:
+		http.Error(w, "metadata and amount must be patched in separate requests", http.StatusBadRequest)
+		return
+	case patch.Amount != nil && *patch.Amount < 0:
+		http.Error(w, "amount must be non-negative", http.StatusBadRequest)
+		return
+	}
+	if patch.Amount != nil {
+		if err := ValidateAmount(*patch.Amount, h.cfg.AllowZeroAmount); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	// The audit trail is only written by amount corrections, and the reversal links only by a reversal
+	for k := range patch.Metadata {
+		if model.IsServerMetadataKey(k) {
+			http.Error(w, errServerMetadataKey(k).Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	current, err := h.store.Get(id)
+	if errors.Is(err, store.ErrNotFound) {
+		http.Error(w, "transaction not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Check the size limits against the merged result so repeated patches can't grow metadata unbounded
+	if patch.Metadata != nil {
+		if err := validateMetadata(model.MergeMetadata(current.Metadata, patch.Metadata)); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	ifMatch := r.Header.Get("If-Match")
+	conditional := ifMatch != "" || patch.Version != nil
+	if (ifMatch != "" && !etagMatches(ifMatch, current.ETag())) ||
+		(patch.Version != nil && *patch.Version != current.Version) {
+		http.Error(w, "transaction version does not match", http.StatusConflict)
+		return
+	}
+
+	// The ETag covers the version, so a matching precondition pins the version read above.
+	// The store re-checks it under its write lock in case another patch landed since.
+	switch {
+	case patch.Amount != nil && conditional:
+		err = h.store.UpdateAmountIfVersion(id, current.Version, *patch.Amount)
+	case patch.Amount != nil:
+		err = h.store.UpdateAmount(id, *patch.Amount)
+	case conditional:
+		err = h.store.UpdateMetadataIfVersion(id, current.Version, patch.Metadata)
+	default:
+		err = h.store.UpdateMetadata(id, patch.Metadata)
+	}
+	if errors.Is(err, store.ErrPreconditionFailed) {
+		http.Error(w, "transaction version does not match", http.StatusConflict)
+		return
+	} else if errors.Is(err, store.ErrNotFound) {
+		http.Error(w, "transaction not found", http.StatusNotFound)
+		return
+	} else if errors.Is(err, store.ErrInvalidAmountHistory) {
+		// Data written before amount_history was protected; the trail must not be overwritten
+		http.Error(w, "stored metadata."+model.MetadataAmountHistory+" is not a valid list, so the amount can't be corrected", http.StatusConflict)
+		return
+	} else if errors.Is(err, store.ErrTooManyMetadataEntries) {
+		// A concurrent patch added keys after the check above
+		http.Error(w, fmt.Sprintf("metadata would have more than %d entries", MaxMetadataEntries), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	h.writePatched(w, r, id)
+}
+
+// writePatched answers a successful PATCH with the transaction as now stored and its ETag.
+func (h *Handler) writePatched(w http.ResponseWriter, r *http.Request, id string) {
+	updated, err := h.store.Get(id)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	updated = updated.WithDefaults()
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("ETag", updated.ETag())
+	h.setAmountUnit(w)
+	writeJSON(w, r, h.baseResponseOptions().transaction(updated))
+}
+
+// reverseRequest is the optional POST /transactions/{id}/reverse body.
+type reverseRequest struct {
+	// ID for the reversal transaction. Defaults to defaultReversalID of the original.
+	ID string `json:"id"`
+}
+
+// reversalSuffix ends every default reversal ID.
+const reversalSuffix = "-reversal"
+
+// defaultReversalID returns "<id>-reversal", or, when that would exceed MaxIDLength, a prefix
+// of id, 16 hex digits of its SHA-256 and the suffix. The hash keeps long IDs that share a
+// prefix from colliding.
+func defaultReversalID(id string) string {
+	if len(id)+len(reversalSuffix) <= MaxIDLength {
+		return id + reversalSuffix
+	}
+	sum := sha256.Sum256([]byte(id))
+	hash := hex.EncodeToString(sum[:8])
+	prefix := id[:MaxIDLength-len(reversalSuffix)-len(hash)-1]
+	return prefix + "-" + hash + reversalSuffix
+}
+
+// ReverseTransaction handles POST /transactions/{id}/reverse. Instead of deleting, it voids a
+// transaction by storing a linked reversal (opposite direction, effective now) and marking the
+// original with metadata[reversed_by]. Each transaction can be reversed once; clients can't
+// write the reversal keys, so the link can't be removed to reverse it again.
+func (h *Handler) ReverseTransaction(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	var req reverseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.ID == "" {
+		req.ID = defaultReversalID(id)
+	}
+	// The reversal is stored like any other transaction, so its ID must be fetchable too
+	if !IsValidID(req.ID) {
+		http.Error(w, "reversal "+errInvalidID.Error(), http.StatusBadRequest)
+		return
+	}
+
+	original, err := h.store.Get(id)
+	if errors.Is(err, store.ErrNotFound) {
+		http.Error(w, "transaction not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	reversal := original.Reversal(req.ID, h.cfg.Clock.Now().UTC())
+	err = h.store.Reverse(id, reversal)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		http.Error(w, "transaction not found", http.StatusNotFound)
+		return
+	case errors.Is(err, store.ErrAlreadyReversed):
+		http.Error(w, "transaction already reversed", http.StatusConflict)
+		return
+	case errors.Is(err, store.ErrConflict):
+		http.Error(w, "reversal id already exists", http.StatusConflict)
+		return
+	case errors.Is(err, store.ErrCapacityExceeded):
+		http.Error(w, "transaction store is full", http.StatusInsufficientStorage)
+		return
+	case err != nil:
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	h.setAmountUnit(w)
+	w.WriteHeader(http.StatusCreated)
+	writeJSON(w, r, h.baseResponseOptions().transaction(h.storedOrSubmitted(reversal)))
+}
+
+// setAmountUnit advertises how amounts in the response body are expressed.
+func (h *Handler) setAmountUnit(w http.ResponseWriter) {
+	if h.cfg.AmountUnit != "" {
+		w.Header().Set("X-Amount-Unit", h.cfg.AmountUnit)
+	}
+}
+
+// storedOrSubmitted returns the stored copy of txn so responses include server-assigned
+// fields (e.g. Seq). Falls back to the submitted transaction if the lookup fails.
+func (h *Handler) storedOrSubmitted(txn model.Transaction) model.Transaction {
+	stored, err := h.store.Get(txn.ID)
+	if err != nil {
+		return txn
+	}
+	return stored
+}
+
+func (h *Handler) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	// Parse pagination, as limit/offset or as page/page_size
+	limit, offset, byPage, err := h.parsePagination(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sortOrder := query.Get("sort")
+	format := query.Get("format")
+
+	// Validate sort order
+	if err := ValidateSort(sortOrder); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Validate response format
+	if format != "" && format != "json" && format != "csv" {
+		http.Error(w, "format must be json or csv", http.StatusBadRequest)
+		return
+	}
+
+	// Validate the envelope flag; it only changes the JSON encoding
+	envelope := false
+	if v := query.Get("envelope"); v != "" {
+		var err error
+		if envelope, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "envelope must be true or false", http.StatusBadRequest)
+			return
+		}
+	}
+
+	// links=true adds hypermedia links; the collection-level ones need the envelope
+	links := false
+	if v := query.Get("links"); v != "" {
+		var err error
+		if links, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "links must be true or false", http.StatusBadRequest)
+			return
+		}
+		envelope = envelope || links
+	}
+
+	filter, err := h.parseFilter(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// A sync resumes from the last modified_at it saw, which only works in modification order
+	if filter.ModifiedSince != nil {
+		if sortOrder != "" && sortOrder != SortModifiedAsc {
+			http.Error(w, "modified_since results are always sorted by modified_asc", http.StatusBadRequest)
+			return
+		}
+		sortOrder = SortModifiedAsc
+	}
+
+	opts, err := h.parseResponseOptions(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Projection is list-only: a GET by ID carries an ETag for the full representation
+	if opts.fields, err = parseFields(query); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	wantsCSV := format == "csv" || (format == "" && !WantsNDJSON(r) && WantsCSV(r)) // NDJSON wins, as below
+	if opts.fields != nil && wantsCSV {
+		http.Error(w, "fields is not supported for CSV, which always has the same columns", http.StatusBadRequest)
+		return
+	}
+
+	// NDJSON exports stream line by line; full exports skip pagination only when enabled.
+	// An explicit format param takes precedence over the Accept header.
+	ndjson := format == "" && WantsNDJSON(r)
+	paginate := !ndjson || !h.cfg.AllowUnboundedExport
+
+	// An unbounded export in store order streams straight from the store, so the full result
+	// is never held in memory
+	if ndjson && !paginate && sortOrder == "" {
+		h.writeNDJSON(w, h.storeOrder(filter), opts)
+		return
+	}
+
+	var results []model.Transaction
+	total := 0 // filtered count before pagination; not computed on the date-range fast path
+	// Count the rows the filter is evaluated on, for the selectivity metrics. The store calls
+	// match from one goroutine, so a plain counter is enough.
+	scanned := 0
+	match := func(txn model.Transaction) bool {
+		scanned++
+		return filter.Matches(txn)
+	}
+	switch {
+	case paginate && sortOrder == "" && filter.DateRangeOnly() && !envelope:
+		// Store order already matches the date range, so the page can be sliced out by binary search
+		results, err = h.store.ListBetween(filter.rangeStart(), filter.rangeEnd(), limit, offset)
+	case paginate && sortOrder == "":
+		// Store order is the response order, so the store can page and count in one pass
+		results, total, err = h.listPage(filter, match, limit, offset)
+		h.recordSelectivity(filter, scanned, total)
+	default:
+		results, err = h.queryWith(filter, match)
+		total = len(results)
+		h.recordSelectivity(filter, scanned, total)
+		// Reorder if a non-default sort was requested (store order is effective_at, id)
+		results = ApplySort(results, sortOrder)
+		if paginate {
+			results = ApplyPagination(results, limit, offset)
+		}
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if ndjson {
+		h.writeNDJSON(w, slices.Values(results), opts)
+		return
+	}
+
+	// Fill read-time defaults for older data
+	for i := range results {
+		results[i] = results[i].WithDefaults()
+	}
+
+	// Spreadsheet export
+	if wantsCSV {
+		h.writeCSVResponse(w, results)
+		return
+	}
+
+	// Set response headers
+	w.Header().Set("Content-Type", "application/json")
+	h.setAmountUnit(w)
+
+	pagination := newPageInfo(limit, offset, total, byPage)
+	if links {
+		collection := newPageLinks(r.URL, limit, offset, total)
+		writeJSON(w, r, listEnvelope{
+			Data:       opts.linkedTransactions(results),
+			Pagination: pagination,
+			Links:      &collection,
+		})
+		return
+	}
+
+	if envelope {
+		writeJSON(w, r, listEnvelope{
+			Data:       opts.transactions(results),
+			Pagination: pagination,
+		})
+		return
+	}
+
+	// Return JSON array
+	writeJSON(w, r, opts.transactions(results))
+}
+
+// listEnvelope is the GET /transactions?envelope=true (or links=true) response shape.
+type listEnvelope struct {
+	Data       any        `json:"data"` // []model.Transaction, or its responseOptions form
+	Pagination pageInfo   `json:"pagination"`
+	Links      *pageLinks `json:"_links,omitempty"` // only with links=true
+}
+
+// pageInfo describes the returned page. Total is the filtered count before pagination.
+type pageInfo struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+	// Set only when the request paged with page/page_size; its fields are inlined
+	*pageNumbers
+}
+
+// pageNumbers is the page/page_size view of a page, for clients that think in page numbers.
+type pageNumbers struct {
+	Page       int `json:"page"`
+	PageSize   int `json:"page_size"`
+	TotalPages int `json:"total_pages"`
+}
+
+// newPageInfo describes the page at offset of total matches, adding page numbers if byPage.
+func newPageInfo(limit, offset, total int, byPage bool) pageInfo {
+	info := pageInfo{Limit: limit, Offset: offset, Total: total}
+	if byPage {
+		info.pageNumbers = &pageNumbers{
+			Page:       offset/limit + 1,
+			PageSize:   limit,
+			TotalPages: (total + limit - 1) / limit,
+		}
+	}
+	return info
+}
+
+// filteredTransactions parses and validates the filter query parameters shared by the
+// list-style endpoints and returns the matching transactions in store order
+// (effective_at, id). On failure it also returns the HTTP status to respond with.
+func (h *Handler) filteredTransactions(query url.Values) ([]model.Transaction, int, error) {
+	filter, err := h.parseFilter(query)
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+
+	filtered, err := h.query(filter)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	return filtered, http.StatusOK, nil
+}
+
+// query returns every transaction matching filter in store order.
+func (h *Handler) query(filter Filter) ([]model.Transaction, error) {
+	return h.queryWith(filter, filter.Matches)
+}
+
+// queryWith is query with match, which must be equivalent to filter.Matches, evaluated in place
+// of it; ListTransactions passes a wrapper that counts scanned rows.
+func (h *Handler) queryWith(filter Filter, match func(model.Transaction) bool) ([]model.Transaction, error) {
+	// An account filter narrows the scan to that account's index
+	if filter.AccountID != "" {
+		return h.store.QueryAccount(filter.AccountID, match)
+	}
+	// Filter inside the store over the full dataset so no matches are dropped.
+	// In production, filters would be pushed down to the database
+	return h.store.Query(match)
+}
+
+// listPage returns one page of the transactions for which match, equivalent to filter.Matches,
+// is true in store order, and the total number of matches.
+func (h *Handler) listPage(filter Filter, match func(model.Transaction) bool, limit, offset int) ([]model.Transaction, int, error) {
+	// An account's index is already small, so page it here rather than scan the whole store
+	if filter.AccountID != "" {
+		matches, err := h.store.QueryAccount(filter.AccountID, match)
+		if err != nil {
+			return nil, 0, err
+		}
+		return ApplyPagination(matches, limit, offset), len(matches), nil
+	}
+	return h.store.ListPage(match, limit, offset)
 }
 
-func (h *Handler) ListTransactions(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query()
+// recordSelectivity records, for a list request with active filters, how many of the scanned
+// rows matched. Unfiltered lists match everything and would only dilute the ratios.
+func (h *Handler) recordSelectivity(filter Filter, scanned, matched int) {
+	if filter.IsEmpty() || scanned == 0 {
+		return
+	}
+	h.metrics.observeSelectivity(strings.Join(filter.Active(), ","), scanned, matched)
+}
 
-	// Parse query parameters (no pre-declaration needed)
-	limit, offset, currency,
-		startDateStr, endDateStr,
-		minAmountStr, maxAmountStr := parseQueryParams(query)
+// parseFilter parses and validates the filter query parameters. Any error is a client error.
+func (h *Handler) parseFilter(query url.Values) (Filter, error) {
+	currencies, startDateStr, endDateStr := parseQueryParams(query)
+	direction := query.Get("direction")
+	search := query.Get("q")
 
-	// Validate pagination parameters
-	if err := ValidatePagination(limit, offset); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+	// Validate direction filter
+	if err := ValidateDirectionFilter(direction); err != nil {
+		return Filter{}, err
+	}
+
+	// An incremental sync must see deletions, so modified_since lists soft-deleted
+	// transactions unless include_deleted says otherwise
+	var modifiedSince *time.Time
+	if v := query.Get("modified_since"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return Filter{}, errors.New("invalid modified_since format, use RFC3339")
+		}
+		modifiedSince = &t
+	}
+
+	// Soft-deleted transactions are only listed on request
+	includeDeleted := modifiedSince != nil
+	if v := query.Get("include_deleted"); v != "" {
+		var err error
+		if includeDeleted, err = strconv.ParseBool(v); err != nil {
+			return Filter{}, errors.New("include_deleted must be true or false")
+		}
+	}
+
+	// Validate metadata presence filters
+	hasMetadata, missingMetadata := query.Get("has_metadata"), query.Get("missing_metadata")
+	if err := ValidateMetadataKeyFilters(hasMetadata, missingMetadata); err != nil {
+		return Filter{}, err
 	}
 
-	// Parse and validate date filters
+	// Parse and validate date filters; a period stands in for both dates
 	startDate, endDate, err := ParseAndValidateDateFilters(startDateStr, endDateStr)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+		return Filter{}, err
+	}
+	if period := query.Get("period"); period != "" {
+		if startDate != nil || endDate != nil {
+			return Filter{}, errors.New("period cannot be combined with start_date or end_date")
+		}
+		if startDate, endDate, err = ParsePeriod(period, h.cfg.Clock.Now()); err != nil {
+			return Filter{}, err
+		}
 	}
 
-	// Parse and validate amount filters
-	minAmount, maxAmount, err := ParseAndValidateAmountFilters(minAmountStr, maxAmountStr)
+	// Parse and validate server receipt-time filters
+	createdAfter, createdBefore, err := ParseAndValidateCreatedFilters(query.Get("created_after"), query.Get("created_before"))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+		return Filter{}, err
 	}
 
-	// For now, get a large batch to filter from
-	// In production, filters would be pushed down to the database
-	maxRecords := 10000 // Reasonable limit for in-memory filtering
-	allTransactions, err := h.store.List(maxRecords, 0)
+	// Parse and validate amount filters; each bound may be inclusive or exclusive, not both
+	minAmountStr, minExclusive, err := amountBoundParam(query, "min_amount")
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		return Filter{}, err
+	}
+	maxAmountStr, maxExclusive, err := amountBoundParam(query, "max_amount")
+	if err != nil {
+		return Filter{}, err
+	}
+	minAmount, maxAmount, err := ParseAndValidateAmountFilters(minAmountStr, maxAmountStr, minExclusive, maxExclusive)
+	if err != nil {
+		return Filter{}, err
+	}
+	// amount=N is shorthand for the inclusive range [N, N]
+	exact, err := exactAmountParam(query)
+	if err != nil {
+		return Filter{}, err
+	}
+	if exact != nil {
+		minAmount, maxAmount = exact, exact
 	}
 
-	// Apply filters to the retrieved transactions
-	filtered := ApplyFilters(allTransactions, currency, startDate, endDate, minAmount, maxAmount)
-
-	// Apply pagination to the filtered results
-	results := ApplyPagination(filtered, limit, offset)
-
-	// Set response header
-	w.Header().Set("Content-Type", "application/json")
-
-	// Return JSON array
-	json.NewEncoder(w).Encode(results)
+	return Filter{
+		AccountID:         query.Get("account_id"),
+		Currencies:        currencies,
+		StartDate:         startDate,
+		EndDate:           endDate,
+		CreatedAfter:      createdAfter,
+		CreatedBefore:     createdBefore,
+		MinAmount:         minAmount,
+		MaxAmount:         maxAmount,
+		MinExclusive:      minExclusive,
+		MaxExclusive:      maxExclusive,
+		Direction:         direction,
+		Search:            search,
+		DescriptionPrefix: query.Get("description_prefix"),
+		HasMetadata:       hasMetadata,
+		MissingMetadata:   missingMetadata,
+		Tags:              model.NormalizeTags(query["tag"]),
+		IncludeDeleted:    includeDeleted,
+		ModifiedSince:     modifiedSince,
+	}, nil
 }
 
 // EXPORTED HELPER FUNCTIONS
@@ -138,23 +947,273 @@ func (h *Handler) ListTransactions(w http.ResponseWriter, r *http.Request) {
 
 // ValidateTransaction validates the transaction fields before attempting to store it.
 func ValidateTransaction(txn model.Transaction) error {
+	return validateTransaction(txn, false)
+}
+
+// validateTransaction is ValidateTransaction. With serverMetadata set, the server-maintained
+// metadata keys are accepted if well formed instead of rejected, for re-ingesting a dump.
+func validateTransaction(txn model.Transaction, serverMetadata bool) error {
 	switch {
 	case txn.ID == "":
 		return errors.New("id is required")
+	case !IsValidID(txn.ID):
+		return errInvalidID
+	case txn.AccountID == "":
+		return errors.New("account_id is required")
+	case len(txn.AccountID) > MaxAccountIDLength:
+		return fmt.Errorf("account_id must be at most %d characters", MaxAccountIDLength)
 	case txn.Currency == "":
 		return errors.New("currency is required")
+	case txn.Direction == "":
+		return errors.New("direction is required")
+	case txn.Direction != model.DirectionDebit && txn.Direction != model.DirectionCredit:
+		return errors.New("direction must be debit or credit")
 	case txn.Amount < 0:
 		return errors.New("amount must be non-negative")
 	case txn.EffectiveAt.IsZero():
 		return errors.New("effective_at is required")
 	}
+	if err := validateTags(txn.Tags); err != nil {
+		return err
+	}
+	if err := validateDescription(txn.Description); err != nil {
+		return err
+	}
+	for k, v := range txn.Metadata {
+		if !model.IsServerMetadataKey(k) {
+			continue
+		}
+		if !serverMetadata {
+			return errServerMetadataKey(k)
+		}
+		if err := validateServerMetadata(k, v); err != nil {
+			return err
+		}
+	}
+	return validateMetadata(txn.Metadata)
+}
+
+// validateServerMetadata checks a server-maintained metadata value from a dump: the reversal
+// links must be valid IDs, and amount_history a list the next amount correction can append to.
+func validateServerMetadata(key, value string) error {
+	if key == model.MetadataAmountHistory {
+		_, err := model.AppendAmountChange(value, model.AmountChange{})
+		return err
+	}
+	if !IsValidID(value) {
+		return fmt.Errorf("metadata %s must be a transaction id", key)
+	}
+	return nil
+}
+
+// errServerMetadataKey is returned when a client tries to write a metadata key that only the
+// server maintains.
+func errServerMetadataKey(key string) error {
+	return errors.New("metadata key " + key + " is maintained by the server")
+}
+
+// validatePolicy runs the checks on top of ValidateTransaction that depend on the deployment:
+// the currency allow-list and whether a zero amount is accepted.
+func validatePolicy(txn model.Transaction, cfg Config) error {
+	if err := ValidateCurrencyAllowed(txn.Currency, cfg.AllowedCurrencies); err != nil {
+		return err
+	}
+	return ValidateAmount(txn.Amount, cfg.AllowZeroAmount)
+}
+
+// validateEffectiveAtPolicy runs the configured effective_at limits: RequirePastEffectiveAt
+// and MaxFutureEffectiveAtDays, both measured from cfg.Clock.
+func validateEffectiveAtPolicy(effectiveAt time.Time, cfg Config) error {
+	if cfg.RequirePastEffectiveAt {
+		if err := ValidateEffectiveAtNotFuture(effectiveAt, cfg.Clock.Now(), cfg.ClockSkew); err != nil {
+			return err
+		}
+	}
+	if cfg.MaxFutureEffectiveAtDays > 0 {
+		return ValidateEffectiveAtWithinDays(effectiveAt, cfg.Clock.Now(), cfg.MaxFutureEffectiveAtDays)
+	}
+	return nil
+}
+
+// ValidateAmount rejects a zero amount unless allowZero is set. Negative amounts are
+// ValidateTransaction's concern.
+func ValidateAmount(amount int64, allowZero bool) error {
+	if amount == 0 && !allowZero {
+		return errors.New("amount must be greater than zero")
+	}
+	return nil
+}
+
+// ValidateCurrencyAllowed rejects a currency that is not in allowed, ignoring case. An empty
+// allowed list accepts every currency.
+func ValidateCurrencyAllowed(currency string, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+	for _, code := range allowed {
+		if strings.EqualFold(currency, code) {
+			return nil
+		}
+	}
+	return fmt.Errorf("currency %s is not accepted; allowed: %s", currency, strings.Join(allowed, ", "))
+}
+
+// errInvalidID is returned for IDs IsValidID rejects.
+var errInvalidID = fmt.Errorf("id must be 1-%d characters of letters, digits, '-' or '_'", MaxIDLength)
+
+// IsValidID reports whether id is 1 to MaxIDLength ASCII letters, digits, dashes or underscores.
+// IDs end up in URL paths, so anything else (a slash, a control character) could make a
+// stored transaction impossible to fetch by ID.
+func IsValidID(id string) bool {
+	if id == "" || len(id) > MaxIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// validateTags enforces the tag limits. Nil or empty tags are valid.
+func validateTags(tags []string) error {
+	if len(tags) > MaxTags {
+		return fmt.Errorf("tags has %d entries, maximum is %d", len(tags), MaxTags)
+	}
+	for _, tag := range tags {
+		if len(tag) > MaxTagLength {
+			return fmt.Errorf("tag exceeds %d characters", MaxTagLength)
+		}
+	}
+	return nil
+}
+
+// validateDescription enforces the description limits, with the same text rules as metadata.
+// An empty description is valid.
+func validateDescription(description string) error {
+	if len(description) > MaxDescriptionLength {
+		return fmt.Errorf("description exceeds %d characters", MaxDescriptionLength)
+	}
+	if err := validateMetadataText(description); err != nil {
+		return fmt.Errorf("description %w", err)
+	}
 	return nil
 }
 
+// validateMetadata enforces the metadata size limits. Nil or empty metadata is valid.
+func validateMetadata(metadata map[string]string) error {
+	if len(metadata) > MaxMetadataEntries {
+		return fmt.Errorf("metadata has %d entries, maximum is %d", len(metadata), MaxMetadataEntries)
+	}
+	for k, v := range metadata {
+		if len(k) > MaxMetadataKeyLength {
+			return fmt.Errorf("metadata key exceeds %d characters", MaxMetadataKeyLength)
+		}
+		// amount_history grows with each correction; clients can't send it (see ValidateTransaction),
+		// so the only long value here is one the server wrote
+		if len(v) > MaxMetadataValueLength && k != model.MetadataAmountHistory {
+			return fmt.Errorf("metadata value for key %q exceeds %d characters", k, MaxMetadataValueLength)
+		}
+		if err := validateMetadataText(k); err != nil {
+			return fmt.Errorf("metadata key %q %w", k, err)
+		}
+		if err := validateMetadataText(v); err != nil {
+			return fmt.Errorf("metadata value for key %q %w", k, err)
+		}
+	}
+	return nil
+}
+
+// validateMetadataText rejects strings that aren't valid UTF-8 or contain control characters
+// (tabs and newlines included), which break CSV exports and log lines. The error reads after
+// the offending key or value.
+func validateMetadataText(s string) error {
+	if !utf8.ValidString(s) {
+		return errors.New("is not valid UTF-8")
+	}
+	if i := strings.IndexFunc(s, unicode.IsControl); i >= 0 {
+		r, _ := utf8.DecodeRuneInString(s[i:])
+		return fmt.Errorf("contains control character %U", r)
+	}
+	return nil
+}
+
+// ValidateEffectiveAtNotFuture rejects an effective_at later than now plus the allowed skew.
+func ValidateEffectiveAtNotFuture(effectiveAt, now time.Time, skew time.Duration) error {
+	if effectiveAt.After(now.Add(skew)) {
+		return errors.New("effective_at must not be in the future")
+	}
+	return nil
+}
+
+// ValidateEffectiveAtWithinDays rejects an effective_at more than days days after now.
+// The error states the allowed window and the latest accepted instant.
+func ValidateEffectiveAtWithinDays(effectiveAt, now time.Time, days int) error {
+	latest := now.UTC().AddDate(0, 0, days)
+	if effectiveAt.After(latest) {
+		return fmt.Errorf("effective_at must be at most %d days in the future (no later than %s)", days, latest.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// parsePagination reads the page to list from query, either as limit and offset or as a
+// 1-based page and page_size, where offset = (page-1)*page_size. Mixing the two styles is an
+// error. byPage reports which style was used. A parameter that is present must be an integer.
+func (h *Handler) parsePagination(query url.Values) (limit, offset int, byPage bool, err error) {
+	byPage = query.Has("page") || query.Has("page_size")
+	if byPage {
+		if query.Has("limit") || query.Has("offset") {
+			return 0, 0, false, errors.New("page and page_size cannot be combined with limit or offset")
+		}
+		page, err := ParseIntStrict(query.Get("page"), 1)
+		if err != nil {
+			return 0, 0, false, errors.New("page must be an integer")
+		}
+		pageSize, err := ParseIntStrict(query.Get("page_size"), h.cfg.Pagination.DefaultLimit)
+		if err != nil {
+			return 0, 0, false, errors.New("page_size must be an integer")
+		}
+		if offset, err = PageToOffset(page, pageSize, h.cfg.Pagination.MaxLimit); err != nil {
+			return 0, 0, false, err
+		}
+		return pageSize, offset, true, nil
+	}
+
+	limit, err = ParseIntStrict(query.Get("limit"), h.cfg.Pagination.DefaultLimit)
+	if err != nil {
+		return 0, 0, false, errors.New("limit must be an integer")
+	}
+	offset, err = ParseIntStrict(query.Get("offset"), 0)
+	if err != nil {
+		return 0, 0, false, errors.New("offset must be an integer")
+	}
+	if err := ValidatePagination(limit, offset, h.cfg.Pagination.MaxLimit); err != nil {
+		return 0, 0, false, err
+	}
+	return limit, offset, false, nil
+}
+
+// PageToOffset validates a 1-based page number and page size and returns the offset of the
+// first transaction on that page.
+func PageToOffset(page, pageSize, maxPageSize int) (int, error) {
+	if page < 1 {
+		return 0, errors.New("page must be at least 1")
+	}
+	if pageSize < 1 || pageSize > maxPageSize {
+		return 0, fmt.Errorf("page_size must be between 1 and %d", maxPageSize)
+	}
+	if page-1 > math.MaxInt/pageSize {
+		return 0, errors.New("page is too large")
+	}
+	return (page - 1) * pageSize, nil
+}
+
 // ValidatePagination checks that the limit and offset parameters are within acceptable ranges.
-func ValidatePagination(limit, offset int) error {
-	if limit < 1 || limit > 1000 {
-		return errors.New("limit must be between 1 and 1000")
+func ValidatePagination(limit, offset, maxLimit int) error {
+	if limit < 1 || limit > maxLimit {
+		return fmt.Errorf("limit must be between 1 and %d", maxLimit)
 	}
 	if offset < 0 {
 		return errors.New("offset must be non-negative")
@@ -162,17 +1221,14 @@ func ValidatePagination(limit, offset int) error {
 	return nil
 }
 
-// ParseIntOrDefault parses an integer query parameter,
-// returning the default value if the string is empty or invalid.
-func ParseIntOrDefault(s string, defaultVal int) int {
+// ParseIntStrict parses an integer query parameter, returning defaultVal if the string is empty
+// (the parameter is absent) and an error if it is present but not an integer, so a typo such as
+// limit=1O is reported instead of silently replaced by the default.
+func ParseIntStrict(s string, defaultVal int) (int, error) {
 	if s == "" {
-		return defaultVal
-	}
-	val, err := strconv.Atoi(s)
-	if err != nil {
-		return defaultVal
+		return defaultVal, nil
 	}
-	return val
+	return strconv.Atoi(s)
 }
 
 // ParseDateOrNil parses a YYYY-MM-DD date string into a *time.Time.
@@ -218,9 +1274,71 @@ func ParseAndValidateDateFilters(startDateStr, endDateStr string) (*time.Time, *
 	return startDate, endDate, nil
 }
 
-// ParseAndValidateAmountFilters parses and validates the min_amount and max_amount
-// query parameters, returning pointers to int64 values.
-func ParseAndValidateAmountFilters(minAmountStr, maxAmountStr string) (*int64, *int64, error) {
+// Relative date ranges for the period query parameter. Each is a range of whole UTC days that
+// ends today, except this_month, which spans the whole calendar month.
+const (
+	PeriodToday     = "today"
+	PeriodLast7d    = "last_7d"  // today and the 6 days before it
+	PeriodLast30d   = "last_30d" // today and the 29 days before it
+	PeriodThisMonth = "this_month"
+)
+
+// ParsePeriod turns a period name into the start_date and end_date it stands for, computed
+// from now in UTC so it lines up with the explicit date filters.
+func ParsePeriod(period string, now time.Time) (*time.Time, *time.Time, error) {
+	y, m, d := now.UTC().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+
+	var start, end time.Time
+	switch period {
+	case PeriodToday:
+		start, end = today, today
+	case PeriodLast7d:
+		start, end = today.AddDate(0, 0, -6), today
+	case PeriodLast30d:
+		start, end = today.AddDate(0, 0, -29), today
+	case PeriodThisMonth:
+		start = time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+		end = start.AddDate(0, 1, -1)
+	default:
+		return nil, nil, fmt.Errorf("period must be one of %s, %s, %s, %s", PeriodToday, PeriodLast7d, PeriodLast30d, PeriodThisMonth)
+	}
+	return &start, &end, nil
+}
+
+// ParseAndValidateCreatedFilters parses the created_after and created_before query parameters
+// as RFC3339 timestamps. Both bounds are exclusive, so they must leave a non-empty range.
+func ParseAndValidateCreatedFilters(afterStr, beforeStr string) (*time.Time, *time.Time, error) {
+	var after, before *time.Time
+
+	if afterStr != "" {
+		t, err := time.Parse(time.RFC3339, afterStr)
+		if err != nil {
+			return nil, nil, errors.New("invalid created_after format, use RFC3339")
+		}
+		after = &t
+	}
+
+	if beforeStr != "" {
+		t, err := time.Parse(time.RFC3339, beforeStr)
+		if err != nil {
+			return nil, nil, errors.New("invalid created_before format, use RFC3339")
+		}
+		before = &t
+	}
+
+	if after != nil && before != nil && !after.Before(*before) {
+		return nil, nil, errors.New("created_after must be before created_before")
+	}
+
+	return after, before, nil
+}
+
+// ParseAndValidateAmountFilters parses and validates the amount bound query parameters,
+// returning pointers to int64 values. minExclusive and maxExclusive say whether each string came
+// from the inclusive (min_amount/max_amount) or exclusive (*_exclusive) parameter; they select the
+// parameter name used in errors and reject ranges that can't match anything.
+func ParseAndValidateAmountFilters(minAmountStr, maxAmountStr string, minExclusive, maxExclusive bool) (*int64, *int64, error) {
 	// Using pointers to distinguish between "not provided" (nil) and "provided with zero value" (0)
 	// int64 is used for amounts to avoid overflow issues with large values
 	var minAmount, maxAmount *int64
@@ -228,7 +1346,7 @@ func ParseAndValidateAmountFilters(minAmountStr, maxAmountStr string) (*int64, *
 	if minAmountStr != "" {
 		val, err := strconv.ParseInt(minAmountStr, 10, 64)
 		if err != nil {
-			return nil, nil, errors.New("invalid min_amount")
+			return nil, nil, fmt.Errorf("invalid %s", amountParamName("min_amount", minExclusive))
 		}
 		minAmount = &val
 	}
@@ -236,54 +1354,335 @@ func ParseAndValidateAmountFilters(minAmountStr, maxAmountStr string) (*int64, *
 	if maxAmountStr != "" {
 		val, err := strconv.ParseInt(maxAmountStr, 10, 64)
 		if err != nil {
-			return nil, nil, errors.New("invalid max_amount")
+			return nil, nil, fmt.Errorf("invalid %s", amountParamName("max_amount", maxExclusive))
 		}
 		maxAmount = &val
 	}
 
-	if minAmount != nil && maxAmount != nil && *minAmount > *maxAmount {
-		return nil, nil, errors.New("min_amount must be less than or equal to max_amount")
+	if minAmount != nil && maxAmount != nil {
+		if *minAmount > *maxAmount {
+			return nil, nil, errors.New("min_amount must be less than or equal to max_amount")
+		}
+		// With either end exclusive, min == max excludes the only candidate value
+		if *minAmount == *maxAmount && (minExclusive || maxExclusive) {
+			return nil, nil, errors.New("amount range is empty, an exclusive bound requires min < max")
+		}
 	}
 
 	return minAmount, maxAmount, nil
 }
 
-// ApplyFilters filters a slice of transactions based on optional currency, date, and amount constraints.
-func ApplyFilters(transactions []model.Transaction, currency string, startDate, endDate *time.Time, minAmount, maxAmount *int64) []model.Transaction {
-	// Create a new slice to hold the filtered transactions.
-	// We can preallocate it with the same length as the input slice for efficiency
-	filtered := make([]model.Transaction, 0, len(transactions))
+// amountParamName returns the query parameter name for an amount bound in the given mode.
+func amountParamName(base string, exclusive bool) string {
+	if exclusive {
+		return base + "_exclusive"
+	}
+	return base
+}
 
-	for _, txn := range transactions {
-		// Continue to the next transaction if any of the filters do not match
-		if currency != "" && !strings.EqualFold(txn.Currency, currency) {
-			continue
+// amountBoundParam returns whichever of the inclusive or exclusive variant of an amount bound was
+// supplied, and whether it was the exclusive one. Supplying both is an error.
+func amountBoundParam(query url.Values, base string) (string, bool, error) {
+	inclusive, exclusive := query.Get(base), query.Get(amountParamName(base, true))
+	if inclusive != "" && exclusive != "" {
+		return "", false, fmt.Errorf("%s and %s cannot both be set", base, amountParamName(base, true))
+	}
+	if exclusive != "" {
+		return exclusive, true, nil
+	}
+	return inclusive, false, nil
+}
+
+// exactAmountParam parses the amount query parameter, or returns nil if it is unset. It is
+// mutually exclusive with the range bounds, which it would otherwise silently override.
+func exactAmountParam(query url.Values) (*int64, error) {
+	v := query.Get("amount")
+	if v == "" {
+		return nil, nil
+	}
+	for _, base := range []string{"min_amount", "max_amount"} {
+		for _, exclusive := range []bool{false, true} {
+			if name := amountParamName(base, exclusive); query.Get(name) != "" {
+				return nil, fmt.Errorf("amount cannot be combined with %s", name)
+			}
 		}
-		if startDate != nil && txn.EffectiveAt.Before(*startDate) {
-			continue
+	}
+	amount, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid amount")
+	}
+	return &amount, nil
+}
+
+// Filter holds the optional list filters. Zero-valued fields are ignored, so Filter{} matches everything.
+type Filter struct {
+	AccountID          string              // exact match; empty disables the filter
+	Currencies         map[string]struct{} // uppercased codes, see ParseCurrencies
+	StartDate, EndDate *time.Time
+	MinAmount          *int64
+	MaxAmount          *int64
+	MinExclusive       bool     // MinAmount itself is excluded (amount > min)
+	MaxExclusive       bool     // MaxAmount itself is excluded (amount < max)
+	Direction          string   // debit or credit; older data without a direction counts as a debit
+	Search             string   // case-insensitive substring of the ID or any metadata value
+	DescriptionPrefix  string   // case-insensitive prefix of the description
+	HasMetadata        string   // metadata key that must be present
+	MissingMetadata    string   // metadata key that must be absent; nil metadata lacks every key
+	Tags               []string // normalized tags that must all be present (see model.NormalizeTags)
+	IncludeDeleted     bool     // also match soft-deleted transactions, which are hidden by default
+
+	// CreatedAfter and CreatedBefore are exclusive bounds on the server-assigned CreatedAt.
+	// Older data without a CreatedAt never matches them.
+	CreatedAfter, CreatedBefore *time.Time
+
+	// ModifiedSince is an exclusive lower bound on LastModified, for incremental sync.
+	ModifiedSince *time.Time
+}
+
+// Active names the active filters by query parameter, in a fixed order, with a range's two
+// bounds as one name (date, amount, created). IncludeDeleted is not a filter; see IsEmpty.
+func (f Filter) Active() []string {
+	var active []string
+	add := func(on bool, name string) {
+		if on {
+			active = append(active, name)
 		}
+	}
+	add(f.AccountID != "", "account_id")
+	add(len(f.Currencies) > 0, "currency")
+	add(f.StartDate != nil || f.EndDate != nil, "date")
+	add(f.MinAmount != nil || f.MaxAmount != nil, "amount")
+	add(f.Direction != "", "direction")
+	add(f.Search != "", "q")
+	add(f.DescriptionPrefix != "", "description_prefix")
+	add(f.HasMetadata != "", "has_metadata")
+	add(f.MissingMetadata != "", "missing_metadata")
+	add(len(f.Tags) > 0, "tag")
+	add(f.CreatedAfter != nil || f.CreatedBefore != nil, "created")
+	add(f.ModifiedSince != nil, "modified_since")
+	return active
+}
 
-		// Add 24 hours to endDate to include transactions that occur on the endDate up until 23:59:59
-		// Check nil BEFORE dereferencing
-		if endDate != nil {
-			endOfDay := endDate.Add(24 * time.Hour)
-			if txn.EffectiveAt.After(endOfDay) {
-				continue
-			}
+// DateRangeOnly reports whether start_date and end_date are the only active filters
+// (or no filter is active), so the store's date-ordered fast path applies. That path always
+// skips soft-deleted transactions, so IncludeDeleted rules it out.
+func (f Filter) DateRangeOnly() bool {
+	return !f.IncludeDeleted && f.narrowsByDateOnly()
+}
+
+// IsEmpty reports whether no filter is active, i.e. the filter matches every transaction.
+// IncludeDeleted only widens what is visible, so it doesn't count as a filter.
+func (f Filter) IsEmpty() bool {
+	return f.narrowsByDateOnly() && f.StartDate == nil && f.EndDate == nil
+}
+
+// narrowsByDateOnly reports whether every narrowing filter other than the date range is unset.
+func (f Filter) narrowsByDateOnly() bool {
+	return f.AccountID == "" && len(f.Currencies) == 0 &&
+		f.CreatedAfter == nil && f.CreatedBefore == nil && f.ModifiedSince == nil &&
+		f.MinAmount == nil && f.MaxAmount == nil &&
+		f.Direction == "" && f.Search == "" && f.DescriptionPrefix == "" &&
+		f.HasMetadata == "" && f.MissingMetadata == "" &&
+		len(f.Tags) == 0
+}
+
+// rangeStart and rangeEnd translate the date filters into the inclusive bounds
+// Store.ListBetween takes, using the same end-of-day rule as Matches. Zero means open.
+func (f Filter) rangeStart() time.Time {
+	if f.StartDate == nil {
+		return time.Time{}
+	}
+	return *f.StartDate
+}
+
+func (f Filter) rangeEnd() time.Time {
+	if f.EndDate == nil {
+		return time.Time{}
+	}
+	return f.EndDate.Add(24 * time.Hour)
+}
+
+// Matches reports whether txn satisfies every active filter.
+func (f Filter) Matches(txn model.Transaction) bool {
+	// Reject as soon as any of the filters do not match
+	if txn.Deleted && !f.IncludeDeleted {
+		return false
+	}
+	if f.AccountID != "" && txn.AccountID != f.AccountID {
+		return false
+	}
+	if len(f.Currencies) > 0 {
+		if _, ok := f.Currencies[strings.ToUpper(txn.Currency)]; !ok {
+			return false
 		}
+	}
+	if f.StartDate != nil && txn.EffectiveAt.Before(*f.StartDate) {
+		return false
+	}
 
-		if minAmount != nil && txn.Amount < *minAmount {
-			continue
+	// Add 24 hours to EndDate to include transactions that occur on the end date up until 23:59:59
+	// Check nil BEFORE dereferencing
+	if f.EndDate != nil {
+		endOfDay := f.EndDate.Add(24 * time.Hour)
+		if txn.EffectiveAt.After(endOfDay) {
+			return false
+		}
+	}
+
+	if f.CreatedAfter != nil || f.CreatedBefore != nil {
+		if txn.CreatedAt.IsZero() ||
+			(f.CreatedAfter != nil && !txn.CreatedAt.After(*f.CreatedAfter)) ||
+			(f.CreatedBefore != nil && !txn.CreatedAt.Before(*f.CreatedBefore)) {
+			return false
+		}
+	}
+
+	if f.ModifiedSince != nil && !txn.LastModified().After(*f.ModifiedSince) {
+		return false
+	}
+
+	if f.MinAmount != nil && (txn.Amount < *f.MinAmount || (f.MinExclusive && txn.Amount == *f.MinAmount)) {
+		return false
+	}
+	if f.MaxAmount != nil && (txn.Amount > *f.MaxAmount || (f.MaxExclusive && txn.Amount == *f.MaxAmount)) {
+		return false
+	}
+	if !matchesDirection(txn, f.Direction) {
+		return false
+	}
+	if f.DescriptionPrefix != "" && !strings.HasPrefix(strings.ToLower(txn.Description), strings.ToLower(f.DescriptionPrefix)) {
+		return false
+	}
+	if f.Search != "" && !matchesSearch(txn, strings.ToLower(f.Search)) {
+		return false
+	}
+	// Indexing a nil map is fine, so missing metadata lacks every key
+	if f.HasMetadata != "" {
+		if _, ok := txn.Metadata[f.HasMetadata]; !ok {
+			return false
+		}
+	}
+	if f.MissingMetadata != "" {
+		if _, ok := txn.Metadata[f.MissingMetadata]; ok {
+			return false
+		}
+	}
+	for _, tag := range f.Tags {
+		if !txn.HasTag(tag) {
+			return false
 		}
-		if maxAmount != nil && txn.Amount > *maxAmount {
+	}
+	return true
+}
+
+// ParseCurrencies splits a comma-separated currency filter such as "USD,eur" into a set of
+// uppercased codes so matching is case-insensitive and O(1). Blank entries are ignored and an
+// empty string returns nil, which disables the filter.
+func ParseCurrencies(s string) map[string]struct{} {
+	var set map[string]struct{}
+	for _, code := range strings.Split(s, ",") {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code == "" {
 			continue
 		}
-		filtered = append(filtered, txn)
+		if set == nil {
+			set = make(map[string]struct{})
+		}
+		set[code] = struct{}{}
+	}
+	return set
+}
+
+// ApplyFilters returns the transactions matching filter, preserving order.
+func ApplyFilters(transactions []model.Transaction, filter Filter) []model.Transaction {
+	// Create a new slice to hold the filtered transactions.
+	// We can preallocate it with the same length as the input slice for efficiency
+	filtered := make([]model.Transaction, 0, len(transactions))
+
+	for _, txn := range transactions {
+		if filter.Matches(txn) {
+			filtered = append(filtered, txn)
+		}
 	}
 
 	return filtered
 }
 
+// ValidateMetadataKeyFilters rejects has_metadata and missing_metadata naming the same key,
+// which could never match anything.
+func ValidateMetadataKeyFilters(has, missing string) error {
+	if has != "" && has == missing {
+		return fmt.Errorf("has_metadata and missing_metadata cannot both be %q", has)
+	}
+	return nil
+}
+
+// ValidateDirectionFilter checks that the direction query parameter is empty or a known direction.
+func ValidateDirectionFilter(direction string) error {
+	switch direction {
+	case "", model.DirectionDebit, model.DirectionCredit:
+		return nil
+	}
+	return errors.New("direction must be debit or credit")
+}
+
+// matchesDirection reports whether txn has the given direction; an empty direction matches all.
+func matchesDirection(txn model.Transaction, direction string) bool {
+	return direction == "" || txn.WithDefaults().Direction == direction
+}
+
+// matchesSearch reports whether the lowercased needle appears in the ID or a metadata value.
+// This is a linear scan over every candidate's metadata with no index behind it,
+// so it is meant for ad-hoc support lookups, not high-volume queries.
+func matchesSearch(txn model.Transaction, needle string) bool {
+	if strings.Contains(strings.ToLower(txn.ID), needle) {
+		return true
+	}
+	for _, v := range txn.Metadata {
+		if strings.Contains(strings.ToLower(v), needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// Supported values for the sort query parameter.
+// The default (empty) order is effective_at ascending, then id ascending.
+const (
+	SortInsertedAsc  = "inserted_asc"
+	SortInsertedDesc = "inserted_desc"
+	// SortReceived is the order the server received the transactions in; same as SortInsertedAsc
+	SortReceived = "received"
+	// SortModifiedAsc is oldest change first, the order modified_since returns
+	SortModifiedAsc = "modified_asc"
+)
+
+// ValidateSort checks that the sort parameter is empty or a supported order.
+func ValidateSort(sortOrder string) error {
+	switch sortOrder {
+	case "", SortInsertedAsc, SortInsertedDesc, SortReceived, SortModifiedAsc:
+		return nil
+	}
+	return errors.New("sort must be one of: inserted_asc, inserted_desc, received, modified_asc")
+}
+
+// ApplySort reorders transactions by insertion sequence or modification time when requested.
+// The input is expected in the store's default order and is returned unchanged for the default sort.
+func ApplySort(transactions []model.Transaction, sortOrder string) []model.Transaction {
+	switch sortOrder {
+	case SortInsertedAsc, SortReceived:
+		sort.Slice(transactions, func(i, j int) bool { return transactions[i].Seq < transactions[j].Seq })
+	case SortInsertedDesc:
+		sort.Slice(transactions, func(i, j int) bool { return transactions[i].Seq > transactions[j].Seq })
+	case SortModifiedAsc:
+		// Seq breaks ties between older records that fall back to the same CreatedAt
+		slices.SortFunc(transactions, func(a, b model.Transaction) int {
+			return cmp.Or(a.LastModified().Compare(b.LastModified()), cmp.Compare(a.Seq, b.Seq))
+		})
+	}
+	return transactions
+}
+
 // ApplyPagination slices a transaction list to the requested page window.
 func ApplyPagination(transactions []model.Transaction, limit, offset int) []model.Transaction {
 	start := offset
@@ -301,15 +1700,11 @@ func ApplyPagination(transactions []model.Transaction, limit, offset int) []mode
 	return transactions[start:end]
 }
 
-// parseQueryParams extracts all list query parameters from the URL values.
-// Kept private as it is an internal detail of ListTransactions.
-func parseQueryParams(query url.Values) (limit, offset int, currency, startDateStr, endDateStr, minAmountStr, maxAmountStr string) {
-	limit = ParseIntOrDefault(query.Get("limit"), 100)
-	offset = ParseIntOrDefault(query.Get("offset"), 0)
-	currency = query.Get("currency")
+// parseQueryParams extracts the raw list filter parameters from the URL values.
+// Kept private as it is an internal detail of parseFilter.
+func parseQueryParams(query url.Values) (currencies map[string]struct{}, startDateStr, endDateStr string) {
+	currencies = ParseCurrencies(query.Get("currency"))
 	startDateStr = query.Get("start_date")
 	endDateStr = query.Get("end_date")
-	minAmountStr = query.Get("min_amount")
-	maxAmountStr = query.Get("max_amount")
 	return
 }
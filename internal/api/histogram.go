@@ -0,0 +1,128 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// Supported histogram intervals.
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+// HistogramBucket is one time bucket of the histogram for one currency. Bucket is the bucket's
+// start date (YYYY-MM-DD, UTC); weeks start on Monday. Sum is in Currency's minor units, so
+// buckets are never summed across currencies.
+type HistogramBucket struct {
+	Bucket   string `json:"bucket"`
+	Currency string `json:"currency"`
+	Count    int    `json:"count"`
+	Sum      int64  `json:"sum"`
+}
+
+// bucketStart truncates t (in UTC) to the start of its interval.
+func bucketStart(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case IntervalWeek:
+		// time.Weekday has Sunday=0; shift so Monday starts the week
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case IntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// ValidateInterval checks that interval is one of day, week, or month.
+func ValidateInterval(interval string) error {
+	switch interval {
+	case IntervalDay, IntervalWeek, IntervalMonth:
+		return nil
+	}
+	return errors.New("interval must be one of: day, week, month")
+}
+
+// BuildHistogram buckets transactions by effective_at and uppercased currency. Amounts in
+// different currencies (or with different minor units, like USD cents and whole JPY) are never
+// added together: each period gets one bucket per currency, ordered by currency code.
+// The input must already be sorted by effective_at (as returned by the store), which lets
+// periods be emitted in a single pass. Empty buckets are omitted. A bucket sum that does not
+// fit in an int64 returns model.ErrAmountOverflow instead of a wrapped-around total.
+func BuildHistogram(transactions []model.Transaction, interval string) ([]HistogramBucket, error) {
+	buckets := make([]HistogramBucket, 0)
+
+	var current time.Time
+	var period map[string]int // currency -> index in buckets, for the current period
+	periodStart := 0
+	for _, txn := range transactions {
+		start := bucketStart(txn.EffectiveAt, interval)
+		if period == nil || !start.Equal(current) {
+			sortByCurrency(buckets[periodStart:])
+			current, period, periodStart = start, make(map[string]int), len(buckets)
+		}
+
+		currency := strings.ToUpper(txn.Currency)
+		i, ok := period[currency]
+		if !ok {
+			i = len(buckets)
+			period[currency] = i
+			buckets = append(buckets, HistogramBucket{Bucket: start.Format("2006-01-02"), Currency: currency})
+		}
+		b := &buckets[i]
+		sum, err := model.AddAmounts(b.Sum, txn.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("bucket %s %s: %w", b.Bucket, b.Currency, err)
+		}
+		b.Count++
+		b.Sum = sum
+	}
+	sortByCurrency(buckets[periodStart:])
+
+	return buckets, nil
+}
+
+// sortByCurrency orders one period's buckets by currency code.
+func sortByCurrency(buckets []HistogramBucket) {
+	slices.SortFunc(buckets, func(a, b HistogramBucket) int { return strings.Compare(a.Currency, b.Currency) })
+}
+
+// TransactionHistogram handles GET /transactions/histogram?interval=day|week|month.
+// It accepts the same filters as the list endpoint and ignores pagination.
+func (h *Handler) TransactionHistogram(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	interval := query.Get("interval")
+	if interval == "" {
+		interval = IntervalDay
+	}
+	if err := ValidateInterval(interval); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	filtered, status, err := h.filteredTransactions(query)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	buckets, err := BuildHistogram(filtered, interval)
+	if err != nil {
+		http.Error(w, "cannot compute histogram: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	h.setAmountUnit(w)
+	writeJSON(w, r, buckets)
+}
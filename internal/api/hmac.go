@@ -0,0 +1,96 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/clock"
+)
+
+// SignatureHeader carries the hex-encoded HMAC-SHA256 of the request; see Sign.
+const SignatureHeader = "X-Signature"
+
+// SignatureTimestampHeader carries the signing time in Unix seconds. It is part of the signed
+// data, so a captured request can only be replayed while it is fresh.
+const SignatureTimestampHeader = "X-Signature-Timestamp"
+
+// SignatureMaxAge is how far a signing timestamp may be from the server clock, either way.
+const SignatureMaxAge = 5 * time.Minute
+
+// MaxSignedBodyBytes caps the body HMACAuthMiddleware buffers to check the signature, so an
+// unauthenticated client can't make the server hold an arbitrarily large body. Larger bodies
+// get 413. A signed import is buffered whole too, so bigger dumps must be split.
+const MaxSignedBodyBytes = 32 << 20
+
+// HMACAuthMiddleware rejects requests whose X-Signature header is not the hex Sign of the
+// method, request URI, X-Signature-Timestamp and raw body under secret, or whose timestamp is
+// more than SignatureMaxAge from now, with 401. It is meant for server-to-server ingestion,
+// where both sides share the secret. Signing the method and URI keeps a signature for one
+// request from authorizing another, such as a bodyless GET authorizing a DELETE.
+// The body is buffered to compute the MAC and then restored, so handlers can still read it.
+func HMACAuthMiddleware(secret []byte) Middleware {
+	return HMACAuthMiddlewareWithClock(secret, clock.Real{})
+}
+
+// HMACAuthMiddlewareWithClock is HMACAuthMiddleware with timestamps checked against c, so
+// tests can pin the time.
+func HMACAuthMiddlewareWithClock(secret []byte, c clock.Clock) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			signature, err := hex.DecodeString(r.Header.Get(SignatureHeader))
+			if err != nil || len(signature) == 0 {
+				http.Error(w, "missing or malformed "+SignatureHeader+" header", http.StatusUnauthorized)
+				return
+			}
+			timestamp := r.Header.Get(SignatureTimestampHeader)
+			unix, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil {
+				http.Error(w, "missing or malformed "+SignatureTimestampHeader+" header", http.StatusUnauthorized)
+				return
+			}
+			if age := c.Now().Sub(time.Unix(unix, 0)); age > SignatureMaxAge || age < -SignatureMaxAge {
+				http.Error(w, "signature timestamp is too old or too far in the future", http.StatusUnauthorized)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxSignedBodyBytes))
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, "request body exceeds "+strconv.Itoa(MaxSignedBodyBytes)+" bytes", http.StatusRequestEntityTooLarge)
+				return
+			} else if err != nil {
+				http.Error(w, "failed to read request body", http.StatusBadRequest)
+				return
+			}
+			r.Body.Close()
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			// hmac.Equal compares in constant time, so the check doesn't leak how many bytes matched
+			if !hmac.Equal(signature, Sign(secret, r.Method, r.URL.RequestURI(), timestamp, body)) {
+				http.Error(w, "invalid signature", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Sign returns the HMAC-SHA256 under secret of method, requestURI (path and query, as sent),
+// timestamp and body, each of the first three followed by a newline. Hex-encode it for the
+// X-Signature header, and send timestamp as X-Signature-Timestamp.
+func Sign(secret []byte, method, requestURI, timestamp string, body []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	for _, part := range []string{method, requestURI, timestamp} {
+		mac.Write([]byte(part))
+		mac.Write([]byte{'\n'})
+	}
+	mac.Write(body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// MaxImportLineBytes caps a single NDJSON record in POST /transactions/_import.
+const MaxImportLineBytes = 64 << 10
+
+// MaxImportErrors caps how many per-line errors an import response lists; the counts are
+// always complete.
+const MaxImportErrors = 100
+
+// ImportLineError describes one record that was not stored.
+type ImportLineError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// ImportReport is the POST /transactions/_import response.
+type ImportReport struct {
+	Created   int               `json:"created"`
+	Duplicate int               `json:"duplicate"` // identical to a stored transaction; not an error
+	Conflict  int               `json:"conflict"`  // ID already stored with different data
+	Invalid   int               `json:"invalid"`   // rejected by the same validation as POST /transactions
+	Errors    []ImportLineError `json:"errors"`    // conflicts and invalid lines, first MaxImportErrors only
+}
+
+func (rep *ImportReport) addError(line int, msg string) {
+	if len(rep.Errors) < MaxImportErrors {
+		rep.Errors = append(rep.Errors, ImportLineError{Line: line, Message: msg})
+	}
+}
+
+// ImportTransactions handles POST /transactions/_import. The body is NDJSON, one create
+// payload per line (the format of an NDJSON or `server export` dump), decoded line by line so
+// a large import is never buffered whole. Each line is validated and created like POST
+// /transactions, with two exceptions for dumps: the server-maintained metadata keys
+// (reverses, reversed_by, amount_history) are kept if well formed, so reversals and amount
+// corrections survive a round trip, and a line marked deleted is soft-deleted after it is
+// created. A line
+// identical to a stored transaction counts as a duplicate rather than an error, so re-running
+// an import is safe. Bad lines are counted and skipped.
+// A line longer than MaxImportLineBytes stops the import with 400; lines before it stay stored.
+func (h *Handler) ImportTransactions(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != ContentTypeNDJSON {
+		http.Error(w, "content type must be "+ContentTypeNDJSON, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	report := ImportReport{Errors: []ImportLineError{}}
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 4096), MaxImportLineBytes)
+	line := 0
+	for scanner.Scan() {
+		line++
+		record := bytes.TrimSpace(scanner.Bytes())
+		if len(record) == 0 {
+			continue
+		}
+
+		txn, _, err := h.decodeTransaction(record, true)
+		if err != nil {
+			report.Invalid++
+			report.addError(line, err.Error())
+			continue
+		}
+
+		err = h.store.Create(txn)
+		switch {
+		case err == nil:
+			report.Created++
+			// Like store.ImportFrom: a dumped soft-deleted transaction comes back deleted
+			if txn.Deleted {
+				if err := h.store.Delete(txn.ID); err != nil {
+					http.Error(w, "internal server error", http.StatusInternalServerError)
+					return
+				}
+			}
+		case errors.Is(err, store.ErrDuplicate):
+			report.Duplicate++
+		case errors.Is(err, store.ErrConflict):
+			report.Conflict++
+			report.addError(line, "transaction ID already exists with different data")
+		case errors.Is(err, store.ErrCapacityExceeded):
+			// Every later line would fail the same way; the lines before it stay imported
+			http.Error(w, fmt.Sprintf("line %d: transaction store is full; %d transactions were imported before it", line, report.Created), http.StatusInsufficientStorage)
+			return
+		default:
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+	if err := scanner.Err(); errors.Is(err, bufio.ErrTooLong) {
+		http.Error(w, fmt.Sprintf("line %d: record exceeds %d bytes", line+1, MaxImportLineBytes), http.StatusBadRequest)
+		return
+	} else if err != nil {
+		http.Error(w, "failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	writeJSON(w, r, report)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// link is a HAL-style hyperlink. Hrefs are relative to the server root so they survive proxies
+// that rewrite the host.
+type link struct {
+	Href string `json:"href"`
+}
+
+// itemLinks is the _links object added to each listed transaction with links=true.
+type itemLinks struct {
+	Self link `json:"self"`
+}
+
+// pageLinks is the collection-level _links object of a links=true list. Next and Prev are
+// omitted on the last and first page.
+type pageLinks struct {
+	Self link  `json:"self"`
+	Next *link `json:"next,omitempty"`
+	Prev *link `json:"prev,omitempty"`
+}
+
+// transactionLink returns the URL a transaction can be fetched from.
+func transactionLink(id string) link {
+	return link{Href: "/transactions/" + url.PathEscape(id)}
+}
+
+// newPageLinks builds the self, next and prev links for the page at offset out of total
+// matches. Next and prev keep every other query parameter and pin limit, so following them
+// walks the same filtered, sorted result. A request that paged by number gets page/page_size
+// links instead of limit/offset ones.
+func newPageLinks(u *url.URL, limit, offset, total int) pageLinks {
+	page := func(offset int) link {
+		query := u.Query()
+		if query.Has("page") || query.Has("page_size") {
+			query.Set("page", strconv.Itoa(offset/limit+1))
+			query.Set("page_size", strconv.Itoa(limit))
+		} else {
+			query.Set("limit", strconv.Itoa(limit))
+			query.Set("offset", strconv.Itoa(offset))
+		}
+		return link{Href: u.Path + "?" + query.Encode()}
+	}
+
+	links := pageLinks{Self: link{Href: u.RequestURI()}}
+	if offset+limit < total {
+		next := page(offset + limit)
+		links.Next = &next
+	}
+	if offset > 0 {
+		prev := page(max(offset-limit, 0))
+		links.Prev = &prev
+	}
+	return links
+}
+
+// linkedTransactions returns txns encoded with opts, each with a _links object added. Like
+// project, it works from the encoded form, so links compose with every other response option.
+func (o responseOptions) linkedTransactions(txns []model.Transaction) []map[string]json.RawMessage {
+	out := make([]map[string]json.RawMessage, len(txns))
+	for i, txn := range txns {
+		// Marshalling the transaction DTOs cannot fail, and neither can decoding the result
+		b, _ := json.Marshal(o.transaction(txn))
+		var encoded map[string]json.RawMessage
+		_ = json.Unmarshal(b, &encoded)
+		encoded["_links"], _ = json.Marshal(itemLinks{Self: transactionLink(txn.ID)})
+		out[i] = encoded
+	}
+	return out
+}
@@ -0,0 +1,183 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// mergePatchMediaType selects RFC 7386 JSON Merge Patch semantics on PATCH /transactions/{id}.
+const mergePatchMediaType = "application/merge-patch+json"
+
+// mergePatchDocument is the part of a transaction a merge patch applies to: the fields a client
+// supplies on create. Server-assigned fields (seq, created_at, version, deleted, time_zone) are
+// not in the document, so a patch naming them is rejected rather than silently ignored.
+type mergePatchDocument struct {
+	ID          string            `json:"id"`
+	AccountID   string            `json:"account_id,omitempty"`
+	Amount      int64             `json:"amount"`
+	Currency    string            `json:"currency"`
+	Direction   string            `json:"direction"`
+	EffectiveAt time.Time         `json:"effective_at"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Description string            `json:"description,omitempty"`
+}
+
+// mergePatchTransaction handles PATCH /transactions/{id} with Content-Type
+// application/merge-patch+json. The patch is merged into the stored transaction as RFC 7386
+// describes (objects merge recursively, null removes a member, anything else replaces it) and
+// the result goes through the same validation as a create, then replaces the stored
+// transaction, moving it if effective_at changed. The ID and the server-maintained metadata
+// keys cannot be changed, and an amount change is appended to metadata[amount_history]. If-Match makes the patch conditional, as for a metadata patch.
+func (h *Handler) mergePatchTransaction(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	var patch map[string]any
+	if err := decodeNumbers(body, &patch); err != nil || patch == nil {
+		http.Error(w, "merge patch must be a JSON object", http.StatusBadRequest)
+		return
+	}
+
+	current, err := h.store.Get(id)
+	if errors.Is(err, store.ErrNotFound) {
+		http.Error(w, "transaction not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && !etagMatches(ifMatch, current.ETag()) {
+		http.Error(w, "transaction version does not match", http.StatusConflict)
+		return
+	}
+
+	merged, status, err := h.applyMergePatch(current, patch)
+	if err != nil {
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	// CompareAndSwap fails if another write landed since the Get, rather than undoing it
+	err = h.store.CompareAndSwap(id, current, merged)
+	if errors.Is(err, store.ErrPreconditionFailed) {
+		http.Error(w, "transaction version does not match", http.StatusConflict)
+		return
+	} else if errors.Is(err, store.ErrNotFound) {
+		http.Error(w, "transaction not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	h.writePatched(w, r, id)
+}
+
+// applyMergePatch merges patch into current's client-supplied fields and validates the result
+// like a create body, returning the status to answer with if it is rejected.
+func (h *Handler) applyMergePatch(current model.Transaction, patch map[string]any) (model.Transaction, int, error) {
+	// Start from the submitted offset so an untouched effective_at keeps its time_zone
+	base, err := json.Marshal(mergePatchDocument{
+		ID:          current.ID,
+		AccountID:   current.AccountID,
+		Amount:      current.Amount,
+		Currency:    current.Currency,
+		Direction:   current.WithDefaults().Direction,
+		EffectiveAt: current.LocalEffectiveAt(),
+		Metadata:    model.WithServerMetadata(current.Metadata, nil), // the patch can't see or name server keys
+		Tags:        current.Tags,
+		Description: current.Description,
+	})
+	if err != nil {
+		return model.Transaction{}, http.StatusInternalServerError, errors.New("internal server error")
+	}
+	var doc map[string]any
+	if err := decodeNumbers(base, &doc); err != nil {
+		return model.Transaction{}, http.StatusInternalServerError, errors.New("internal server error")
+	}
+	for field := range patch {
+		if !isMergePatchField(field) {
+			return model.Transaction{}, http.StatusBadRequest, errors.New("field " + field + " cannot be patched")
+		}
+	}
+	// Naming a server key is rejected even as null, which would otherwise be a silent no-op
+	if metadata, ok := patch["metadata"].(map[string]any); ok {
+		for k := range metadata {
+			if model.IsServerMetadataKey(k) {
+				return model.Transaction{}, http.StatusBadRequest, errServerMetadataKey(k)
+			}
+		}
+	}
+
+	mergedBody, err := json.Marshal(mergePatch(doc, patch))
+	if err != nil {
+		return model.Transaction{}, http.StatusBadRequest, errors.New("invalid JSON")
+	}
+	merged, status, err := h.decodeTransaction(mergedBody, false)
+	if err != nil {
+		return model.Transaction{}, status, err
+	}
+	if merged.ID != current.ID {
+		return model.Transaction{}, http.StatusBadRequest, errors.New("id cannot be changed")
+	}
+	merged.Metadata = model.WithServerMetadata(merged.Metadata, current.Metadata)
+	// An amount change is audited exactly as PATCH {"amount":N} does it
+	if merged.Amount != current.Amount {
+		history, err := model.AppendAmountChange(current.Metadata[model.MetadataAmountHistory],
+			model.AmountChange{Amount: current.Amount, ChangedAt: h.cfg.Clock.Now()})
+		if err != nil {
+			return model.Transaction{}, http.StatusConflict, errors.New("stored metadata." + model.MetadataAmountHistory + " is not a valid list, so the amount can't be corrected")
+		}
+		merged.Metadata = model.MergeMetadata(merged.Metadata, map[string]*string{model.MetadataAmountHistory: &history})
+	}
+	return merged, 0, nil
+}
+
+// isMergePatchField reports whether field is a member of mergePatchDocument.
+func isMergePatchField(field string) bool {
+	switch field {
+	case "id", "account_id", "amount", "currency", "direction", "effective_at", "metadata", "tags", "description":
+		return true
+	}
+	return false
+}
+
+// mergePatch applies patch to target as RFC 7386 defines and returns the result. target may be
+// modified.
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any, len(p))
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = mergePatch(t[k], v)
+		}
+	}
+	return t
+}
+
+// decodeNumbers unmarshals body into v, keeping numbers as json.Number so int64 amounts
+// survive the round trip through map[string]any.
+func decodeNumbers(body []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
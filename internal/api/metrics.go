@@ -0,0 +1,176 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// selectivityBuckets are the upper bounds of the filter selectivity histogram, the fraction of
+// scanned rows a list filter matched. The low end is fine-grained because very selective
+// filters are the ones worth an index.
+var selectivityBuckets = []float64{.001, .01, .05, .1, .25, .5, .75, 1}
+
+// statsProvider is implemented by stores that count Create outcomes, e.g. store.MemoryStore.
+type statsProvider interface {
+	Stats() store.Stats
+}
+
+// Metrics collects per-route request counts and latencies, list filter selectivity, and the
+// store's size and Create outcomes, for GET /metrics. Each Metrics has its own registry, so
+// handlers built side by side (as in tests) don't share series.
+type Metrics struct {
+	registry    *prometheus.Registry
+	requests    *prometheus.CounterVec   // method, path, status
+	latencies   *prometheus.HistogramVec // method, path
+	selectivity *prometheus.HistogramVec // filters: comma-joined Filter.Active names
+	scanned     *prometheus.CounterVec   // filters
+	matched     *prometheus.CounterVec   // filters
+}
+
+// NewMetrics returns an empty request metrics collector that also reports on s at scrape time.
+func NewMetrics(s store.Store) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by method, route, and status code.",
+		}, []string{"method", "path", "status"}),
+		latencies: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method and route.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "path"}),
+		selectivity: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "transaction_filter_selectivity",
+			Help:    "Fraction of scanned rows matched per GET /transactions, by active filters.",
+			Buckets: selectivityBuckets,
+		}, []string{"filters"}),
+		scanned: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "transaction_filter_rows_scanned_total",
+			Help: "Rows GET /transactions evaluated its filters on, by active filters.",
+		}, []string{"filters"}),
+		matched: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "transaction_filter_rows_matched_total",
+			Help: "Rows that matched GET /transactions filters, by active filters.",
+		}, []string{"filters"}),
+	}
+	m.registry.MustRegister(m.requests, m.latencies, m.selectivity, m.scanned, m.matched, newStoreCollector(s))
+	return m
+}
+
+// Middleware records the status and latency of every request it wraps.
+// It must run inside the mux so r.Pattern is populated.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		path := r.Pattern
+		if path == "" {
+			path = "unmatched"
+		}
+		method := methodLabel(r.Method)
+		m.requests.WithLabelValues(method, path, strconv.Itoa(rec.status)).Inc()
+		m.latencies.WithLabelValues(method, path).Observe(time.Since(start).Seconds())
+	})
+}
+
+// methodLabel returns method for the standard HTTP methods and "other" for anything else.
+// Routes registered without a method accept any method string, so labelling by the raw value
+// would let clients create unbounded series.
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	}
+	return "other"
+}
+
+// observeSelectivity records that a list request filtered by filters matched matched of the
+// scanned rows it evaluated.
+func (m *Metrics) observeSelectivity(filters string, scanned, matched int) {
+	m.selectivity.WithLabelValues(filters).Observe(float64(matched) / float64(scanned))
+	m.scanned.WithLabelValues(filters).Add(float64(scanned))
+	m.matched.WithLabelValues(filters).Add(float64(matched))
+}
+
+// storeCollector reads the store's size and, from stores that count them, its Create
+// outcomes when /metrics is scraped, so the store needs no metrics code of its own.
+type storeCollector struct {
+	store   store.Store
+	stored  *prometheus.Desc
+	creates *prometheus.Desc
+}
+
+func newStoreCollector(s store.Store) *storeCollector {
+	return &storeCollector{
+		store:   s,
+		stored:  prometheus.NewDesc("transactions_stored", "Number of transactions currently in the store.", nil, nil),
+		creates: prometheus.NewDesc("transactions_create_total", "Transaction creates by outcome (created, duplicate, conflict).", []string{"result"}, nil),
+	}
+}
+
+func (c *storeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.stored
+	ch <- c.creates
+}
+
+func (c *storeCollector) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(c.stored, prometheus.GaugeValue, float64(c.store.Count()))
+
+	// Create outcome counters are only available from stores that track them
+	sp, ok := c.store.(statsProvider)
+	if !ok {
+		return
+	}
+	stats := sp.Stats()
+	ch <- prometheus.MustNewConstMetric(c.creates, prometheus.CounterValue, float64(stats.Created), "created")
+	ch <- prometheus.MustNewConstMetric(c.creates, prometheus.CounterValue, float64(stats.Duplicate), "duplicate")
+	ch <- prometheus.MustNewConstMetric(c.creates, prometheus.CounterValue, float64(stats.Conflict), "conflict")
+}
+
+// statusRecorder captures the status code written by the wrapped handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush keeps streaming responses (NDJSON) working through the recorder.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// ServeMetrics handles GET /metrics in the Prometheus text exposition format.
+func (h *Handler) ServeMetrics(w http.ResponseWriter, r *http.Request) {
+	// Compression is left to GzipMiddleware, like every other route
+	promhttp.HandlerFor(h.metrics.registry, promhttp.HandlerOpts{DisableCompression: true}).ServeHTTP(w, r)
+}
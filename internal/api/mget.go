@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// MaxMGetIDs caps how many IDs one POST /transactions/_mget may request.
+const MaxMGetIDs = 100
+
+type mgetRequest struct {
+	IDs []string `json:"ids"`
+}
+
+type mgetResponse struct {
+	Found   any      `json:"found"` // []model.Transaction, or its responseOptions form
+	Missing []string `json:"missing"`
+}
+
+// MGetTransactions handles POST /transactions/_mget.
+// It fetches up to MaxMGetIDs transactions in one call and reports which IDs don't exist,
+// rather than failing the whole request on the first miss.
+func (h *Handler) MGetTransactions(w http.ResponseWriter, r *http.Request) {
+	var req mgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if len(req.IDs) == 0 {
+		http.Error(w, "ids is required", http.StatusBadRequest)
+		return
+	}
+	if len(req.IDs) > MaxMGetIDs {
+		http.Error(w, fmt.Sprintf("too many ids: %d requested, maximum is %d", len(req.IDs), MaxMGetIDs), http.StatusBadRequest)
+		return
+	}
+
+	found, missing, err := h.store.GetMany(req.IDs)
+	if err != nil {
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Fill read-time defaults for older data
+	for i := range found {
+		found[i] = found[i].WithDefaults()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	h.setAmountUnit(w)
+	writeJSON(w, r, mgetResponse{Found: h.baseResponseOptions().transactions(found), Missing: missing})
+}
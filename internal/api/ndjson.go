@@ -0,0 +1,71 @@
+package api
+
+import (
+	"encoding/json"
+	"iter"
+	"log"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// ContentTypeNDJSON is the media type for newline-delimited JSON exports.
+const ContentTypeNDJSON = "application/x-ndjson"
+
+// ndjsonFlushEvery controls how many lines are written between flushes.
+const ndjsonFlushEvery = 100
+
+// WantsNDJSON reports whether the Accept header asks for newline-delimited JSON.
+func WantsNDJSON(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err == nil && mediaType == ContentTypeNDJSON {
+			return true
+		}
+	}
+	return false
+}
+
+// writeNDJSON streams one JSON transaction per line, flushing periodically so large exports
+// reach the client incrementally instead of being buffered whole.
+// The 200 status is committed with the first byte, so a write failure mid-stream (usually a
+// disconnected client) can't be reported to the client; it is logged and the stream stops.
+func (h *Handler) writeNDJSON(w http.ResponseWriter, transactions iter.Seq[model.Transaction], opts responseOptions) {
+	w.Header().Set("Content-Type", ContentTypeNDJSON)
+	h.setAmountUnit(w)
+
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w) // Encode appends the newline that terminates each record
+
+	written := 0
+	for txn := range transactions {
+		if err := enc.Encode(opts.transaction(txn.WithDefaults())); err != nil {
+			log.Printf("ndjson export aborted after %d transactions: %v", written, err)
+			return
+		}
+		written++
+		if flusher != nil && written%ndjsonFlushEvery == 0 {
+			flusher.Flush()
+		}
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
+
+// storeOrder returns the transactions matching filter in store order, read from the store as
+// the sequence is consumed rather than collected first.
+func (h *Handler) storeOrder(filter Filter) iter.Seq[model.Transaction] {
+	return func(yield func(model.Transaction) bool) {
+		// ForEach never fails for the in-memory store; an error from another backend would
+		// surface mid-stream, after the 200, so it can only be logged
+		err := h.store.ForEach(func(txn model.Transaction) bool {
+			return !filter.Matches(txn) || yield(txn)
+		})
+		if err != nil {
+			log.Printf("ndjson export: %v", err)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPISpec is the handwritten OpenAPI 3.0 contract for this service.
+// Keep it in sync with the handlers when endpoints or parameters change.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// OpenAPISpec returns the raw embedded OpenAPI document.
+func OpenAPISpec() []byte {
+	return openAPISpec
+}
+
+// ServeOpenAPI serves the embedded OpenAPI document at GET /openapi.json.
+func ServeOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPISpec)
+}
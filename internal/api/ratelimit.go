@@ -0,0 +1,173 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/clock"
+	"golang.org/x/time/rate"
+)
+
+// limiterIdleTTL is how long a client's limiter may sit unused before it is evicted.
+const limiterIdleTTL = 10 * time.Minute
+
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// clientLimiters holds one token bucket per client IP.
+type clientLimiters struct {
+	mu        sync.Mutex
+	clients   map[string]*clientLimiter
+	rps       rate.Limit
+	burst     int
+	lastSweep time.Time
+}
+
+// get returns the limiter for ip, creating it on first use.
+// Idle entries are swept at most once per limiterIdleTTL so the map stays bounded
+// without needing a background goroutine.
+func (c *clientLimiters) get(ip string, now time.Time) *rate.Limiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Sub(c.lastSweep) > limiterIdleTTL {
+		for key, cl := range c.clients {
+			if now.Sub(cl.lastSeen) > limiterIdleTTL {
+				delete(c.clients, key)
+			}
+		}
+		c.lastSweep = now
+	}
+
+	cl, ok := c.clients[ip]
+	if !ok {
+		cl = &clientLimiter{limiter: rate.NewLimiter(c.rps, c.burst)}
+		c.clients[ip] = cl
+	}
+	cl.lastSeen = now
+	return cl.limiter
+}
+
+// RateLimitMiddleware applies a per-client token bucket allowing rps requests per second
+// with bursts of up to burst requests. Clients over the limit get 429 with a Retry-After header.
+// Clients are told apart by ClientIP, which only believes X-Forwarded-For from trustedProxies.
+func RateLimitMiddleware(rps int, burst int, trustedProxies []netip.Prefix) func(http.Handler) http.Handler {
+	return RateLimitMiddlewareWithClock(rps, burst, trustedProxies, clock.Real{})
+}
+
+// RateLimitMiddlewareWithClock is RateLimitMiddleware with token refill and idle eviction
+// driven by c, so tests can advance time instead of sleeping.
+func RateLimitMiddlewareWithClock(rps int, burst int, trustedProxies []netip.Prefix, c clock.Clock) func(http.Handler) http.Handler {
+	limiters := &clientLimiters{
+		clients:   make(map[string]*clientLimiter),
+		rps:       rate.Limit(rps),
+		burst:     burst,
+		lastSweep: c.Now(),
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			now := c.Now()
+			lim := limiters.get(ClientIP(r, trustedProxies), now)
+
+			// Reserve instead of Allow so we know how long the client has to wait
+			res := lim.ReserveN(now, 1)
+			if delay := res.DelayFrom(now); !res.OK() || delay > 0 {
+				res.CancelAt(now)
+				retryAfter := int(math.Ceil(delay.Seconds()))
+				if retryAfter < 1 {
+					retryAfter = 1
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+				http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ParseTrustedProxies parses a comma-separated list of proxy addresses for ClientIP, each a
+// CIDR prefix (10.0.0.0/8) or a single IP. An empty string trusts no proxies.
+func ParseTrustedProxies(s string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if strings.Contains(part, "/") {
+			prefix, err := netip.ParsePrefix(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", part, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", part, err)
+		}
+		prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+	}
+	return prefixes, nil
+}
+
+// ClientIP returns the originating client IP for the request. X-Forwarded-For is only
+// honored when the connection comes from one of trustedProxies: the header is then walked
+// from the right, skipping trusted hops, and the first untrusted address wins, since entries
+// to its left were written by the client and can be forged. A direct client's header is
+// ignored and the host part of RemoteAddr is used.
+func ClientIP(r *http.Request, trustedProxies []netip.Prefix) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !isTrustedProxy(host, trustedProxies) {
+		return host
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(header, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		if !isTrustedProxy(hops[i], trustedProxies) {
+			return hops[i]
+		}
+	}
+	// Every hop is one of our proxies; the left-most is the closest we have to the client
+	if len(hops) > 0 {
+		return hops[0]
+	}
+	return host
+}
+
+// isTrustedProxy reports whether ip parses and falls in one of trustedProxies.
+func isTrustedProxy(ip string, trustedProxies []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
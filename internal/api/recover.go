@@ -0,0 +1,45 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// RecoverMiddleware turns a panic in next into a 500 so one bad request can't take down the
+// server. The panic value and stack trace are logged to logger (slog.Default if nil), with the
+// request ID when RequestIDMiddleware runs inside it. If the handler had already started the
+// response, the status can't be changed; the connection is closed instead. It should be the
+// outermost middleware so it also covers the others.
+func RecoverMiddleware(logger *slog.Logger) Middleware {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+				if p == http.ErrAbortHandler {
+					panic(p) // a deliberate abort; net/http handles it quietly
+				}
+
+				logger.Error("panic serving request",
+					"request_id", w.Header().Get(RequestIDHeader),
+					"method", r.Method,
+					"path", r.URL.Path,
+					"panic", p,
+					"stack", string(debug.Stack()))
+
+				if rec.wroteHeader {
+					panic(http.ErrAbortHandler)
+				}
+				http.Error(w, "internal server error", http.StatusInternalServerError)
+			}()
+			next.ServeHTTP(rec, r)
+		})
+	}
+}
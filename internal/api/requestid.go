@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestIDHeader carries the request ID in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength caps client-supplied request IDs so they can't bloat every log line.
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the ID RequestIDMiddleware stored in ctx, or "" if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestIDMiddleware gives every request an ID for tracing: the client's X-Request-ID if it is
+// usable, otherwise a new random UUID. The ID is stored in the request context, echoed in the
+// X-Request-ID response header, and appended to plain-text error bodies so it survives a
+// copy-paste of the error into a support ticket.
+// It must run inside GzipMiddleware, since it may write after the handler returns.
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+
+		if rec.status >= http.StatusBadRequest && strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") {
+			fmt.Fprintf(rec, "request_id: %s\n", id)
+		}
+	})
+}
+
+// validRequestID reports whether a client-supplied ID is non-empty, at most maxRequestIDLength
+// bytes, and printable ASCII without spaces, so it is safe to echo in headers and logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random (version 4) UUID.
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])         // never fails; see crypto/rand.Read
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// LoggingMiddleware writes one access log line per request to logger, tagged with the request
+// ID when RequestIDMiddleware runs before it.
+func LoggingMiddleware(logger *log.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rec, r)
+
+			logger.Printf("request_id=%s method=%s path=%s status=%d duration=%s",
+				RequestIDFromContext(r.Context()), r.Method, r.URL.Path, rec.status, time.Since(start))
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package api
+
+import "net/http"
+
+// resetter is implemented by stores that can be wiped, e.g. store.MemoryStore.
+type resetter interface {
+	Reset()
+}
+
+// ResetTransactions handles POST /transactions/_reset.
+// It clears the store for test fixtures and is only routed when Config.EnableResetEndpoint is set.
+func (h *Handler) ResetTransactions(w http.ResponseWriter, r *http.Request) {
+	rs, ok := h.store.(resetter)
+	if !ok {
+		http.Error(w, "store does not support reset", http.StatusNotImplemented)
+		return
+	}
+
+	rs.Reset()
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,312 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// writeJSON encodes v as the response body. pretty=true on the request indents it by two spaces
+// for reading by hand; anything else keeps the compact encoding. Callers set Content-Type and
+// the status first.
+func writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	enc := json.NewEncoder(w)
+	if pretty, _ := strconv.ParseBool(r.URL.Query().Get("pretty")); pretty {
+		enc.SetIndent("", "  ")
+	}
+	enc.Encode(v)
+}
+
+// responseOptions are per-request presentation choices. They only affect how transactions are
+// encoded, never what is stored.
+type responseOptions struct {
+	// metadataEmptyObject writes missing metadata as {} instead of omitting the field, for
+	// clients that expect metadata on every transaction (metadata_empty_object=true).
+	metadataEmptyObject bool
+	// camelCase writes transaction keys in camelCase (effectiveAt) for JavaScript clients
+	// (field_case=camel). Metadata keys are client data and are never renamed.
+	camelCase bool
+	// timeFormat is the deployment's Config.TimeFormat for effective_at.
+	timeFormat string
+	// fields, when non-nil, limits each transaction to these snake_case field names (fields=id,amount).
+	fields []string
+}
+
+// plain reports whether o leaves the model.Transaction encoding unchanged. Every option must
+// be checked here.
+func (o responseOptions) plain() bool {
+	return !o.metadataEmptyObject && !o.camelCase && o.timeFormat == "" && o.fields == nil
+}
+
+// Accepted field_case values.
+const (
+	FieldCaseSnake = "snake"
+	FieldCaseCamel = "camel"
+)
+
+// Accepted Config.TimeFormat values.
+const (
+	TimeFormatRFC3339 = "rfc3339" // 2024-01-15T10:30:00Z, truncated to whole seconds
+	TimeFormatUnix    = "unix"    // 1705314600, seconds since the epoch as a JSON number
+	TimeFormatDate    = "date"    // "2024-01-15", the UTC calendar date
+)
+
+// ValidateTimeFormat checks that format is empty (the default encoding) or a supported preset.
+func ValidateTimeFormat(format string) error {
+	switch format {
+	case "", TimeFormatRFC3339, TimeFormatUnix, TimeFormatDate:
+		return nil
+	}
+	return errors.New("time format must be one of: rfc3339, unix, date")
+}
+
+// baseResponseOptions returns the deployment-wide presentation options. Responses that take no
+// presentation query parameters (creates, patches, reversals, deletes, _mget) use them as is.
+func (h *Handler) baseResponseOptions() responseOptions {
+	return responseOptions{timeFormat: h.cfg.TimeFormat}
+}
+
+// parseResponseOptions reads the presentation query parameters on top of baseResponseOptions.
+// Any error is a client error.
+func (h *Handler) parseResponseOptions(query url.Values) (responseOptions, error) {
+	opts := h.baseResponseOptions()
+	if v := query.Get("metadata_empty_object"); v != "" {
+		var err error
+		if opts.metadataEmptyObject, err = strconv.ParseBool(v); err != nil {
+			return responseOptions{}, errors.New("metadata_empty_object must be true or false")
+		}
+	}
+	switch query.Get("field_case") {
+	case "", FieldCaseSnake:
+	case FieldCaseCamel:
+		opts.camelCase = true
+	default:
+		return responseOptions{}, errors.New("field_case must be snake or camel")
+	}
+	return opts, nil
+}
+
+// transactionFields holds the JSON field names of model.Transaction, the names fields= accepts.
+var transactionFields = func() map[string]bool {
+	names := make(map[string]bool)
+	t := reflect.TypeFor[model.Transaction]()
+	for i := range t.NumField() {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ","); name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}()
+
+// parseFields reads the fields list parameter: comma-separated snake_case field names. It
+// returns nil when the parameter is absent, and an error naming the valid fields for an unknown one.
+func parseFields(query url.Values) ([]string, error) {
+	if !query.Has("fields") {
+		return nil, nil
+	}
+	var fields []string
+	for _, name := range strings.Split(query.Get("fields"), ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if !transactionFields[name] {
+			return nil, fmt.Errorf("unknown field %q in fields; valid fields are %s",
+				name, strings.Join(slices.Sorted(maps.Keys(transactionFields)), ", "))
+		}
+		fields = append(fields, name)
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("fields must name at least one field")
+	}
+	return fields, nil
+}
+
+// camelKey converts a snake_case field name to the key field_case=camel writes (effective_at -> effectiveAt).
+func camelKey(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+	return strings.Join(parts, "")
+}
+
+// formattedTime encodes a time in a Config.TimeFormat preset. The empty format encodes like
+// time.Time itself (RFC 3339 with fractional seconds when present).
+type formattedTime struct {
+	time   time.Time
+	format string
+}
+
+func (t formattedTime) MarshalJSON() ([]byte, error) {
+	switch t.format {
+	case TimeFormatRFC3339:
+		return json.Marshal(t.time.Truncate(time.Second).Format(time.RFC3339))
+	case TimeFormatUnix:
+		return strconv.AppendInt(nil, t.time.Unix(), 10), nil
+	case TimeFormatDate:
+		return json.Marshal(t.time.UTC().Format(time.DateOnly))
+	}
+	return json.Marshal(t.time)
+}
+
+// transactionResponse is model.Transaction with the snake_case response options applied. Its
+// EffectiveAt and Metadata fields are shallower than the embedded ones, so encoding/json uses
+// them in their place.
+type transactionResponse struct {
+	model.Transaction
+	EffectiveAt formattedTime `json:"effective_at"`
+	// A pointer so {} can be written on request: omitempty drops only a nil pointer
+	Metadata *map[string]string `json:"metadata,omitempty"`
+}
+
+// transactionResponseWithComputed is transactionWithComputed with the response options applied.
+type transactionResponseWithComputed struct {
+	transactionResponse
+	Computed ComputedFields `json:"computed"`
+}
+
+// camelTransaction is model.Transaction with camelCase keys. It must list every field of
+// model.Transaction; tests compare the two encodings key by key to catch drift.
+type camelTransaction struct {
+	ID          string        `json:"id"`
+	AccountID   string        `json:"accountId,omitempty"`
+	Amount      int64         `json:"amount"`
+	Currency    string        `json:"currency"`
+	Direction   string        `json:"direction"`
+	EffectiveAt formattedTime `json:"effectiveAt"`
+	// A pointer so {} can be written on request: omitempty drops only a nil pointer
+	Metadata    *map[string]string `json:"metadata,omitempty"`
+	TimeZone    string             `json:"timeZone,omitempty"`
+	Tags        []string           `json:"tags,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Seq         uint64             `json:"seq,omitempty"`
+	CreatedAt   time.Time          `json:"createdAt,omitzero"`
+	Deleted     bool               `json:"deleted,omitempty"`
+	Version     int                `json:"version,omitempty"`
+	ModifiedAt  time.Time          `json:"modifiedAt,omitzero"`
+}
+
+// camelComputedFields is ComputedFields with camelCase keys.
+type camelComputedFields struct {
+	AgeDays         int    `json:"ageDays"`
+	AmountFormatted string `json:"amountFormatted"`
+}
+
+// camelTransactionWithComputed is transactionWithComputed with camelCase keys.
+type camelTransactionWithComputed struct {
+	camelTransaction
+	Computed camelComputedFields `json:"computed"`
+}
+
+func (o responseOptions) newCamelTransaction(txn model.Transaction) camelTransaction {
+	return camelTransaction{
+		ID:          txn.ID,
+		AccountID:   txn.AccountID,
+		Amount:      txn.Amount,
+		Currency:    txn.Currency,
+		Direction:   txn.Direction,
+		EffectiveAt: formattedTime{time: txn.EffectiveAt, format: o.timeFormat},
+		Metadata:    o.metadata(txn),
+		TimeZone:    txn.TimeZone,
+		Tags:        txn.Tags,
+		Description: txn.Description,
+		Seq:         txn.Seq,
+		CreatedAt:   txn.CreatedAt,
+		Deleted:     txn.Deleted,
+		Version:     txn.Version,
+		ModifiedAt:  txn.ModifiedAt,
+	}
+}
+
+func (o responseOptions) newTransactionResponse(txn model.Transaction) transactionResponse {
+	return transactionResponse{
+		Transaction: txn,
+		EffectiveAt: formattedTime{time: txn.EffectiveAt, format: o.timeFormat},
+		Metadata:    o.metadata(txn),
+	}
+}
+
+// metadata returns txn's metadata for the response DTOs: nil (omitted) when empty, unless
+// metadataEmptyObject asks for {}.
+func (o responseOptions) metadata(txn model.Transaction) *map[string]string {
+	if len(txn.Metadata) == 0 && !o.metadataEmptyObject {
+		return nil
+	}
+	metadata := txn.Metadata
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	return &metadata
+}
+
+// transaction returns the value to encode for txn.
+func (o responseOptions) transaction(txn model.Transaction) any {
+	switch {
+	case o.fields != nil:
+		return o.project(txn)
+	case o.camelCase:
+		return o.newCamelTransaction(txn)
+	case !o.plain():
+		return o.newTransactionResponse(txn)
+	}
+	return txn
+}
+
+// project returns txn as a map holding only the selected fields. It encodes txn with the other
+// options first and picks keys from the result, so a projected field always looks exactly as
+// it would unprojected. Fields the full encoding omits (e.g. empty tags) stay omitted.
+func (o responseOptions) project(txn model.Transaction) map[string]any {
+	full := o
+	full.fields = nil
+	// Marshalling the transaction DTOs cannot fail, and neither can decoding the result
+	b, _ := json.Marshal(full.transaction(txn))
+	var encoded map[string]json.RawMessage
+	_ = json.Unmarshal(b, &encoded)
+
+	projected := make(map[string]any, len(o.fields))
+	for _, name := range o.fields {
+		if o.camelCase {
+			name = camelKey(name)
+		}
+		if v, ok := encoded[name]; ok {
+			projected[name] = v
+		}
+	}
+	return projected
+}
+
+// withComputed returns the value to encode for txn with its computed fields (expand=computed).
+func (o responseOptions) withComputed(txn model.Transaction, computed ComputedFields) any {
+	switch {
+	case o.camelCase:
+		return camelTransactionWithComputed{
+			camelTransaction: o.newCamelTransaction(txn),
+			Computed:         camelComputedFields(computed),
+		}
+	case !o.plain():
+		return transactionResponseWithComputed{transactionResponse: o.newTransactionResponse(txn), Computed: computed}
+	}
+	return transactionWithComputed{Transaction: txn, Computed: computed}
+}
+
+// transactions returns the value to encode for a list of transactions. It is always a JSON array.
+func (o responseOptions) transactions(txns []model.Transaction) any {
+	if o.plain() {
+		return txns
+	}
+	out := make([]any, len(txns))
+	for i, txn := range txns {
+		out[i] = o.transaction(txn)
+	}
+	return out
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Middleware wraps an http.Handler, e.g. RateLimitMiddleware.
+type Middleware func(http.Handler) http.Handler
+
+// Chain composes middlewares so the first one listed runs first. Nil entries are skipped.
+func Chain(mws ...Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			if mws[i] != nil {
+				next = mws[i](next)
+			}
+		}
+		return next
+	}
+}
+
+// RegisterRoutes registers the transaction endpoints on mux.
+// mw is applied to every transaction endpoint (pass nil for none). Shared by main and the
+// test server so routing behavior can't drift between them.
+// Every route is observed for GET /metrics, including requests rejected by mw.
+func (h *Handler) RegisterRoutes(mux *http.ServeMux, mw Middleware) {
+	if mw == nil {
+		mw = func(next http.Handler) http.Handler { return next }
+	}
+	inner := mw
+	mw = func(next http.Handler) http.Handler { return h.metrics.Middleware(inner(next)) }
+
+	mux.Handle("/transactions", mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			h.CreateTransaction(w, r)
+		case http.MethodGet:
+			h.ListTransactions(w, r)
+		case http.MethodDelete:
+			h.DeleteTransactions(w, r)
+		default:
+			MethodNotAllowed(w, http.MethodGet, http.MethodPost, http.MethodDelete)
+		}
+	})))
+	mux.Handle("/transactions/{id}", mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			h.GetTransaction(w, r)
+		case http.MethodPatch:
+			h.PatchTransaction(w, r)
+		case http.MethodDelete:
+			h.DeleteTransaction(w, r)
+		default:
+			MethodNotAllowed(w, http.MethodGet, http.MethodPatch, http.MethodDelete)
+		}
+	})))
+
+	// Voiding is append-only: the reversal is a new transaction linked to the original
+	mux.Handle("POST /transactions/{id}/reverse", mw(http.HandlerFunc(h.ReverseTransaction)))
+
+	// Batch lookup; POST because the ID list can be too long for a query string
+	mux.Handle("POST /transactions/_mget", mw(http.HandlerFunc(h.MGetTransactions)))
+
+	// Dashboard aggregates; more specific than /transactions/{id} so it wins for GET
+	mux.Handle("GET /transactions/histogram", mw(http.HandlerFunc(h.TransactionHistogram)))
+	mux.Handle("GET /transactions/counts", mw(http.HandlerFunc(h.TransactionCounts)))
+	mux.Handle("GET /transactions/currencies", mw(http.HandlerFunc(h.TransactionCurrencies)))
+
+	// Live feed of new transactions for dashboards (server-sent events)
+	mux.Handle("GET /transactions/stream", mw(http.HandlerFunc(h.StreamTransactions)))
+
+	// Import pre-flight: validates a CSV without storing anything
+	mux.Handle("POST /transactions/validate-csv", mw(http.HandlerFunc(h.ValidateTransactionsCSV)))
+	// Import pre-flight for a JSON array, with the full create validation
+	mux.Handle("POST /transactions/_validate", mw(http.HandlerFunc(h.ValidateTransactions)))
+
+	// Bulk load from an NDJSON dump; safe to re-run because duplicates are skipped
+	mux.Handle("POST /transactions/_import", mw(http.HandlerFunc(h.ImportTransactions)))
+
+	// Change history for one transaction, from the store's mutation log
+	mux.Handle("GET /audit", mw(http.HandlerFunc(h.ServeAudit)))
+
+	// Test-fixture reset; only registered when explicitly enabled so prod returns 404
+	if h.cfg.EnableResetEndpoint {
+		mux.Handle("POST /transactions/_reset", mw(http.HandlerFunc(h.ResetTransactions)))
+	}
+
+	// Scrape target; not rate limited so monitoring keeps working under load
+	mux.Handle("GET /metrics", h.metrics.Middleware(http.HandlerFunc(h.ServeMetrics)))
+
+	// Ingestion rate at a glance; exempt from mw like /metrics
+	mux.Handle("GET /stats", h.metrics.Middleware(http.HandlerFunc(h.ServeStats)))
+
+	// API contract for client generation
+	mux.Handle("GET /openapi.json", h.metrics.Middleware(http.HandlerFunc(ServeOpenAPI)))
+}
+
+// MethodNotAllowed writes a 405 with the Allow header listing the permitted methods.
+func MethodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+}
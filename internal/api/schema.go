@@ -0,0 +1,258 @@
+package api
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// transactionSchemaJSON is the JSON Schema a POST /transactions body must satisfy.
+//
+//go:embed transaction.schema.json
+var transactionSchemaJSON []byte
+
+// transactionSchema is compiled once at startup; a malformed schema file fails fast.
+var transactionSchema = mustCompileSchema(transactionSchemaJSON)
+
+// SchemaError is one field-level violation. Field is a dotted path from the body root,
+// e.g. "amount" or "metadata.note"; violations of the body itself use "body".
+type SchemaError struct {
+	Field   string
+	Message string
+}
+
+func (e SchemaError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidateTransactionJSON checks a raw create body against the embedded transaction schema
+// before it is unmarshalled, so type mistakes such as "amount":"100" get a message naming
+// the field rather than a generic decode error. It returns an error only if body isn't JSON.
+// Violations are sorted by field.
+func ValidateTransactionJSON(body []byte) ([]SchemaError, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber() // keep integers exact so 1.5 and 1e30 can be told apart from int64s
+
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	var errs []SchemaError
+	transactionSchema.validate("body", value, &errs)
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return errs, nil
+}
+
+// formatSchemaErrors renders violations as the plain-text body of a 400, one field per line.
+func formatSchemaErrors(errs []SchemaError) string {
+	lines := make([]string, 0, len(errs)+1)
+	lines = append(lines, "invalid transaction:")
+	for _, e := range errs {
+		lines = append(lines, e.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// jsonSchema is the subset of JSON Schema used by transaction.schema.json: type, required,
+// properties, additionalProperties, items, enum, minLength, minimum, and the date-time format.
+// Keywords outside this subset are ignored, so extend validate when the schema grows.
+type jsonSchema struct {
+	Type                 schemaTypes            `json:"type"`
+	Required             []string               `json:"required"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Enum                 []string               `json:"enum"`
+	MinLength            *int                   `json:"minLength"`
+	Minimum              *float64               `json:"minimum"`
+	Format               string                 `json:"format"`
+}
+
+// schemaTypes accepts "type" as either a single name or a list of names.
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*t = schemaTypes{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return fmt.Errorf("schema type must be a string or list of strings: %w", err)
+	}
+	*t = many
+	return nil
+}
+
+func mustCompileSchema(raw []byte) *jsonSchema {
+	var s jsonSchema
+	if err := json.Unmarshal(raw, &s); err != nil {
+		panic(fmt.Sprintf("invalid embedded JSON schema: %v", err))
+	}
+	return &s
+}
+
+// validate appends a SchemaError for every violation in value. A type mismatch stops
+// further checks on that value, since they would only repeat the same problem.
+func (s *jsonSchema) validate(path string, value any, errs *[]SchemaError) {
+	fail := func(format string, args ...any) {
+		*errs = append(*errs, SchemaError{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(s.Type) > 0 && !slices.ContainsFunc(s.Type, func(t string) bool { return matchesType(t, value) }) {
+		// A number where an integer is expected gets the specific reason, e.g. an exponent
+		if n, ok := value.(json.Number); ok && slices.Contains(s.Type, "integer") {
+			if _, err := ParseInt64Number(n); err != nil {
+				fail("%s", err)
+				return
+			}
+		}
+		fail("must be %s, got %s", joinTypes(s.Type), jsonTypeName(value))
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				*errs = append(*errs, SchemaError{Field: childPath(path, name), Message: "is required"})
+			}
+		}
+		for name, child := range v {
+			if prop, ok := s.Properties[name]; ok {
+				prop.validate(childPath(path, name), child, errs)
+			} else if s.AdditionalProperties != nil {
+				s.AdditionalProperties.validate(childPath(path, name), child, errs)
+			}
+		}
+
+	case []any:
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, errs)
+			}
+		}
+
+	case string:
+		if s.MinLength != nil && len([]rune(v)) < *s.MinLength {
+			if *s.MinLength == 1 {
+				fail("must not be empty")
+			} else {
+				fail("must be at least %d characters", *s.MinLength)
+			}
+		}
+		if len(s.Enum) > 0 && !slices.Contains(s.Enum, v) {
+			fail("must be one of %s", strings.Join(s.Enum, ", "))
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, v); err != nil {
+				fail("must be an RFC3339 date-time")
+			}
+		}
+
+	case json.Number:
+		if s.Minimum != nil {
+			if f, err := v.Float64(); err == nil && f < *s.Minimum {
+				fail("must be >= %g", *s.Minimum)
+			}
+		}
+	}
+}
+
+// childPath joins a property name onto path, dropping the "body" root for top-level fields.
+func childPath(path, name string) string {
+	if path == "body" {
+		return name
+	}
+	return path + "." + name
+}
+
+func matchesType(t string, value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return t == "null"
+	case bool:
+		return t == "boolean"
+	case string:
+		return t == "string"
+	case []any:
+		return t == "array"
+	case map[string]any:
+		return t == "object"
+	case json.Number:
+		if t == "number" {
+			return true
+		}
+		// Integers must be written without a fraction or exponent and fit the int64 the
+		// field decodes into, the same rule encoding/json applies
+		if t == "integer" {
+			_, err := v.Int64()
+			return err == nil
+		}
+	}
+	return false
+}
+
+// ParseInt64Number converts a JSON number decoded with UseNumber to an int64, rejecting the
+// forms a JavaScript client's float serialization can produce instead of silently rounding:
+// exponents (1e3), fractions (1.5), and values outside the int64 range.
+func ParseInt64Number(n json.Number) (int64, error) {
+	s := n.String()
+	if strings.ContainsAny(s, "eE") {
+		return 0, fmt.Errorf("must be a plain integer without an exponent, got %s", s)
+	}
+	if strings.Contains(s, ".") {
+		return 0, fmt.Errorf("must be a whole number, got %s", s)
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("is out of range for a 64-bit integer, got %s", s)
+	}
+	return v, nil
+}
+
+// jsonTypeName names the JSON type of a decoded value for error messages.
+func jsonTypeName(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// joinTypes renders schema types with an article, e.g. "an object or null".
+func joinTypes(types []string) string {
+	names := make([]string, len(types))
+	for i, t := range types {
+		switch t {
+		case "integer", "object", "array":
+			names[i] = "an " + t
+		case "null":
+			names[i] = t
+		default:
+			names[i] = "a " + t
+		}
+	}
+	return strings.Join(names, " or ")
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+// Default server timeouts used by DefaultServerTimeouts.
+const (
+	DefaultReadHeaderTimeout = 5 * time.Second
+	DefaultReadTimeout       = 30 * time.Second
+	DefaultWriteTimeout      = 2 * time.Minute // long enough for a large NDJSON or CSV export
+	DefaultIdleTimeout       = 2 * time.Minute
+)
+
+// ServerTimeouts bound how long a client may hold a connection at each stage, so slow or
+// stalled clients (slowloris) can't tie up the server. See http.Server for what each covers.
+type ServerTimeouts struct {
+	ReadHeader time.Duration // reading the request headers
+	Read       time.Duration // reading the whole request, body included
+	Write      time.Duration // from the end of the request headers to the end of the response
+	Idle       time.Duration // waiting for the next request on a keep-alive connection
+}
+
+// DefaultServerTimeouts returns timeouts suitable for a publicly exposed server.
+func DefaultServerTimeouts() ServerTimeouts {
+	return ServerTimeouts{
+		ReadHeader: DefaultReadHeaderTimeout,
+		Read:       DefaultReadTimeout,
+		Write:      DefaultWriteTimeout,
+		Idle:       DefaultIdleTimeout,
+	}
+}
+
+// NewServer returns an *http.Server serving handler on addr with the given timeouts. A
+// timeout <= 0 falls back to its default rather than disabling it, so a misconfigured
+// deployment is never left without protection.
+func NewServer(addr string, handler http.Handler, timeouts ServerTimeouts) *http.Server {
+	defaults := DefaultServerTimeouts()
+	orDefault := func(d, def time.Duration) time.Duration {
+		if d <= 0 {
+			return def
+		}
+		return d
+	}
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: orDefault(timeouts.ReadHeader, defaults.ReadHeader),
+		ReadTimeout:       orDefault(timeouts.Read, defaults.Read),
+		WriteTimeout:      orDefault(timeouts.Write, defaults.Write),
+		IdleTimeout:       orDefault(timeouts.Idle, defaults.Idle),
+	}
+}
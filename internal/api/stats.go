@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// throughputProvider is implemented by stores that track recent creates, e.g. store.MemoryStore.
+type throughputProvider interface {
+	Throughput() store.Throughput
+}
+
+// statsResponse is the body of GET /stats.
+type statsResponse struct {
+	Creates store.Throughput `json:"creates"`
+}
+
+// ServeStats handles GET /stats. It reports how many transactions were created in the last
+// 1, 5 and 15 minutes, e.g. {"creates":{"1m":12,"5m":40,"15m":95}}, so ops can gauge the
+// ingestion rate at a glance. Stores that don't track it get a 501.
+func (h *Handler) ServeStats(w http.ResponseWriter, r *http.Request) {
+	tp, ok := h.store.(throughputProvider)
+	if !ok {
+		http.Error(w, "throughput is not tracked by this store", http.StatusNotImplemented)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	writeJSON(w, r, statsResponse{Creates: tp.Throughput()})
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// ContentTypeEventStream is the media type of server-sent events.
+const ContentTypeEventStream = "text/event-stream"
+
+// streamBuffer is how many events a stream may fall behind the store before it is dropped.
+const streamBuffer = 256
+
+// streamHeartbeat is how often an idle stream sends an SSE comment, so proxies and load
+// balancers don't close it for inactivity.
+const streamHeartbeat = 15 * time.Second
+
+// subscriber is implemented by stores that can notify about new transactions, e.g. store.MemoryStore.
+type subscriber interface {
+	Subscribe(buffer int) (<-chan model.Transaction, func())
+}
+
+// StreamTransactions handles GET /transactions/stream. It holds the connection open and sends
+// each newly created transaction as a server-sent event:
+//
+//	event: transaction
+//	id: 42
+//	data: {"id":"txn-1",...}
+//
+// The id is the transaction's seq. currency limits the stream like it does the list, and
+// fields, field_case and metadata_empty_object shape data as they do the list's rows. Past
+// transactions are not replayed; a dashboard loads them from the list first. A client that
+// can't keep up is disconnected rather than sent a stream with gaps, and should reconnect.
+// Stores that can't notify get a 501.
+func (h *Handler) StreamTransactions(w http.ResponseWriter, r *http.Request) {
+	sub, ok := h.store.(subscriber)
+	if !ok {
+		http.Error(w, "streaming is not supported by this store", http.StatusNotImplemented)
+		return
+	}
+	query := r.URL.Query()
+	opts, err := h.parseResponseOptions(query)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if opts.fields, err = parseFields(query); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter := Filter{Currencies: ParseCurrencies(query.Get("currency"))}
+
+	events, cancel := sub.Subscribe(streamBuffer)
+	defer cancel()
+
+	rc := http.NewResponseController(w)
+	// The stream is meant to outlive the server's write timeout
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", ContentTypeEventStream)
+	w.Header().Set("Cache-Control", "no-cache")
+	h.setAmountUnit(w)
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		log.Printf("transaction stream: %v", err)
+		return
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case txn, ok := <-events:
+			if !ok {
+				return // dropped for falling behind
+			}
+			if !filter.Matches(txn) {
+				continue
+			}
+			// Marshalling the transaction DTOs cannot fail; compact JSON keeps data on one line
+			data, _ := json.Marshal(opts.transaction(txn.WithDefaults()))
+			if _, err := fmt.Fprintf(w, "event: transaction\nid: %d\ndata: %s\n\n", txn.Seq, data); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// BatchValidationResult is the outcome for one element of a POST /transactions/_validate body.
+type BatchValidationResult struct {
+	Index int    `json:"index"`
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// BatchValidationReport is the POST /transactions/_validate response. Results has one entry per
+// array element, in order.
+type BatchValidationReport struct {
+	Total   int                     `json:"total"`
+	Valid   int                     `json:"valid"`
+	Results []BatchValidationResult `json:"results"`
+}
+
+// ValidateTransactions handles POST /transactions/_validate. The body is a JSON array of create
+// payloads; each is checked exactly as POST /transactions would check it, and nothing is
+// stored. It never touches the store, so a big pre-flight doesn't contend with writers. Elements
+// are decoded one at a time, so a large file is not held in memory twice. An element that isn't
+// valid JSON ends the array, since nothing after it can be located reliably; that is a 400.
+func (h *Handler) ValidateTransactions(w http.ResponseWriter, r *http.Request) {
+	if err := h.checkJSONContentType(r); err != nil {
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	dec := json.NewDecoder(r.Body)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+		http.Error(w, "body must be a JSON array of transactions", http.StatusBadRequest)
+		return
+	}
+
+	report := BatchValidationReport{Results: []BatchValidationResult{}}
+	for index := 0; dec.More(); index++ {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			http.Error(w, "invalid JSON in array", http.StatusBadRequest)
+			return
+		}
+		result := BatchValidationResult{Index: index, Valid: true}
+		if _, _, err := h.decodeTransaction(raw, false); err != nil {
+			result.Valid, result.Error = false, err.Error()
+		} else {
+			report.Valid++
+		}
+		report.Total++
+		report.Results = append(report.Results, result)
+	}
+	if tok, err := dec.Token(); err != nil || tok != json.Delim(']') {
+		http.Error(w, "invalid JSON in array", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	writeJSON(w, r, report)
+}
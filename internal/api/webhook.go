@@ -0,0 +1,230 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Defaults for WebhookConfig fields left at zero.
+const (
+	DefaultWebhookQueueSize   = 1024
+	DefaultWebhookMaxAttempts = 5
+	DefaultWebhookBackoff     = 500 * time.Millisecond
+	DefaultWebhookTimeout     = 5 * time.Second
+)
+
+// webhookMaxBackoff caps the doubling delay between delivery attempts.
+const webhookMaxBackoff = 30 * time.Second
+
+// WebhookConfig configures a WebhookNotifier.
+type WebhookConfig struct {
+	// URLs receive a POST for every created transaction.
+	URLs []string
+	// Secret, if set, signs each delivery attempt into the X-Signature and
+	// X-Signature-Timestamp headers exactly as HMACAuthMiddleware expects, so receivers can
+	// verify it came from this server.
+	Secret []byte
+	// QueueSize bounds the payloads waiting per URL; more are dropped and logged.
+	QueueSize int
+	// MaxAttempts is how many times a payload is tried before it is dropped.
+	MaxAttempts int
+	// Backoff is the delay before the first retry; it doubles per attempt, up to 30s.
+	Backoff time.Duration
+	// Timeout bounds each request.
+	Timeout time.Duration
+	// Client sends the requests. Defaults to a client with Timeout.
+	Client *http.Client
+}
+
+// WebhookNotifier POSTs every transaction the store creates, as JSON, to the configured URLs.
+// Delivery is asynchronous and never slows down or fails a create: each URL has its own bounded
+// queue and worker, so one slow endpoint only delays itself, and a full queue drops payloads
+// with a log line rather than blocking. Network errors, 5xx and 429 responses are retried with
+// exponential backoff; other responses other than 2xx are not. Delivery is at least once, so
+// receivers should deduplicate on the transaction id.
+type WebhookNotifier struct {
+	cfg       WebhookConfig
+	endpoints []*webhookEndpoint
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+type webhookEndpoint struct {
+	url   string
+	queue chan []byte
+}
+
+// NewWebhookNotifier validates cfg, subscribes to s and starts delivering. It fails if there
+// are no URLs, a URL isn't absolute http(s), or s can't notify about new transactions
+// (see store.MemoryStore.Subscribe). Close it to stop.
+func NewWebhookNotifier(s store.Store, cfg WebhookConfig) (*WebhookNotifier, error) {
+	if len(cfg.URLs) == 0 {
+		return nil, errors.New("no webhook URLs configured")
+	}
+	for _, raw := range cfg.URLs {
+		u, err := url.Parse(raw)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid webhook URL %q", raw)
+		}
+	}
+	sub, ok := s.(subscriber)
+	if !ok {
+		return nil, errors.New("store cannot notify about new transactions")
+	}
+
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultWebhookQueueSize
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultWebhookMaxAttempts
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = DefaultWebhookBackoff
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultWebhookTimeout
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n := &WebhookNotifier{cfg: cfg, cancel: cancel}
+	for _, u := range cfg.URLs {
+		ep := &webhookEndpoint{url: u, queue: make(chan []byte, cfg.QueueSize)}
+		n.endpoints = append(n.endpoints, ep)
+		n.wg.Add(1)
+		go func() {
+			defer n.wg.Done()
+			n.deliverAll(ctx, ep)
+		}()
+	}
+
+	// Subscribe before returning so creates made right after construction are delivered
+	events, unsubscribe := sub.Subscribe(streamBuffer)
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		n.fanOut(ctx, sub, events, unsubscribe)
+	}()
+	return n, nil
+}
+
+// fanOut hands every transaction the store creates to the endpoints until ctx is cancelled.
+// The store drops a subscriber whose buffer fills up, which a burst of creates can do faster
+// than payloads are marshalled. The transactions created in that gap are lost, so it is
+// logged, and fanOut subscribes again so later creates are still delivered.
+func (n *WebhookNotifier) fanOut(ctx context.Context, sub subscriber, events <-chan model.Transaction, unsubscribe func()) {
+	for {
+		// Unsubscribing closes events, which ends the range below once ctx is cancelled
+		stop := context.AfterFunc(ctx, unsubscribe)
+		for txn := range events {
+			n.enqueue(txn)
+		}
+		stop()
+		unsubscribe()
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("webhook: fell behind the store and missed transactions; resubscribing")
+		events, unsubscribe = sub.Subscribe(streamBuffer)
+	}
+}
+
+// enqueue hands txn's payload to every endpoint, dropping it for endpoints whose queue is full.
+func (n *WebhookNotifier) enqueue(txn model.Transaction) {
+	// Marshalling a transaction cannot fail
+	body, _ := json.Marshal(txn.WithDefaults())
+	for _, ep := range n.endpoints {
+		select {
+		case ep.queue <- body:
+		default:
+			log.Printf("webhook %s: queue full, dropped transaction %s", ep.url, txn.ID)
+		}
+	}
+}
+
+// deliverAll sends ep's queued payloads one at a time until ctx is cancelled.
+func (n *WebhookNotifier) deliverAll(ctx context.Context, ep *webhookEndpoint) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case body := <-ep.queue:
+			if err := n.deliver(ctx, ep.url, body); err != nil {
+				log.Printf("webhook %s: %v", ep.url, err)
+			}
+		}
+	}
+}
+
+// deliver POSTs body to target, retrying transient failures with exponential backoff.
+func (n *WebhookNotifier) deliver(ctx context.Context, target string, body []byte) error {
+	backoff := n.cfg.Backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		var retry bool
+		retry, err = n.post(ctx, target, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt == n.cfg.MaxAttempts {
+			return fmt.Errorf("giving up after %d attempt(s): %w", attempt, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, webhookMaxBackoff)
+	}
+}
+
+// post makes one delivery attempt and reports whether a failure is worth retrying.
+func (n *WebhookNotifier) post(ctx context.Context, target string, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(n.cfg.Secret) > 0 {
+		// Each retry is signed afresh, so a late one isn't rejected as stale
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(SignatureTimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, hex.EncodeToString(Sign(n.cfg.Secret, http.MethodPost, req.URL.RequestURI(), timestamp, body)))
+	}
+
+	resp, err := n.cfg.Client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return true, fmt.Errorf("status %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("status %d", resp.StatusCode)
+	}
+}
+
+// Close unsubscribes from the store and stops delivery, abandoning queued payloads and
+// in-flight retries. It waits for the workers to exit.
+func (n *WebhookNotifier) Close() {
+	n.cancel()
+	n.wg.Wait()
+}
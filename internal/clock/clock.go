@@ -0,0 +1,48 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock abstracts time.Now so time-based logic can be tested deterministically.
+type Clock interface {
+	Now() time.Time
+}
+
+// Real is the production clock backed by time.Now.
+type Real struct{}
+
+func (Real) Now() time.Time { return time.Now() }
+
+// Fake is a manually controlled clock for tests.
+// It is safe for concurrent use.
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFake returns a Fake clock frozen at the given time.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Set moves the fake clock to the given time.
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+// Advance moves the fake clock forward by d.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
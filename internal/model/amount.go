@@ -0,0 +1,44 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrAmountOverflow is returned when a sum of amounts does not fit in an int64.
+var ErrAmountOverflow = errors.New("amount sum overflows int64")
+
+// AddAmounts returns a+b, or ErrAmountOverflow if the result would wrap around.
+func AddAmounts(a, b int64) (int64, error) {
+	sum := a + b
+	// Two's-complement addition overflows exactly when both operands share a sign
+	// and the result's sign differs from it
+	if (a >= 0) == (b >= 0) && (sum >= 0) != (a >= 0) {
+		return 0, ErrAmountOverflow
+	}
+	return sum, nil
+}
+
+// AmountChange records an amount that was corrected away from, and when.
+type AmountChange struct {
+	Amount    int64     `json:"amount"`     // the amount before the correction
+	ChangedAt time.Time `json:"changed_at"` // when the correction was stored
+}
+
+// AppendAmountChange returns history (a JSON list of AmountChange, or "" for none) with change
+// appended. It fails if history is not such a list, so a corrupted trail is never overwritten.
+func AppendAmountChange(history string, change AmountChange) (string, error) {
+	var changes []AmountChange
+	if history != "" {
+		if err := json.Unmarshal([]byte(history), &changes); err != nil {
+			return "", fmt.Errorf("invalid %s: %w", MetadataAmountHistory, err)
+		}
+	}
+	out, err := json.Marshal(append(changes, change))
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// minorUnitExponents lists ISO 4217 currencies whose minor unit isn't 1/100.
+// Any currency not listed is assumed to have two decimal places.
+var minorUnitExponents = map[string]int{
+	// No minor unit
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	// Thousandths
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// MinorUnitExponent returns how many decimal places the currency's minor unit has
+// (2 for USD, 0 for JPY, 3 for KWD). Currency codes are case-insensitive.
+func MinorUnitExponent(currency string) int {
+	if exp, ok := minorUnitExponents[strings.ToUpper(currency)]; ok {
+		return exp
+	}
+	return 2
+}
+
+// FormatMinorUnits renders an amount in minor units as a major-unit decimal string,
+// e.g. 1234 USD -> "12.34", 100 JPY -> "100", 5 KWD -> "0.005".
+func FormatMinorUnits(amount int64, currency string) string {
+	exp := MinorUnitExponent(currency)
+	if exp == 0 {
+		return strconv.FormatInt(amount, 10)
+	}
+
+	sign := ""
+	// Work on the magnitude as uint64 so math.MinInt64 doesn't overflow
+	magnitude := uint64(amount)
+	if amount < 0 {
+		sign = "-"
+		magnitude = -magnitude
+	}
+
+	digits := strconv.FormatUint(magnitude, 10)
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	split := len(digits) - exp
+	return sign + digits[:split] + "." + digits[split:]
+}
+
+// ParseMajorUnits is the inverse of FormatMinorUnits for non-negative amounts: it converts a
+// major-unit decimal string to minor units, e.g. "12.34" USD -> 1234, "100" JPY -> 100. The
+// string must be plain digits with an optional fraction; signs, exponents and more decimal
+// places than the currency's minor unit are rejected rather than rounded.
+func ParseMajorUnits(s, currency string) (int64, error) {
+	whole, fraction, hasPoint := strings.Cut(s, ".")
+	if whole == "" || (hasPoint && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return 0, fmt.Errorf("must be a decimal string such as \"12.34\", got %q", s)
+	}
+	exp := MinorUnitExponent(currency)
+	if len(fraction) > exp {
+		return 0, fmt.Errorf("has %d decimal places but %s allows %d, got %q", len(fraction), strings.ToUpper(currency), exp, s)
+	}
+
+	// Pad the fraction to the minor unit and parse the digits as one integer, so no float is involved
+	v, err := strconv.ParseInt(whole+fraction+strings.Repeat("0", exp-len(fraction)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("is out of range for a 64-bit amount, got %q", s)
+	}
+	return v, nil
+}
+
+func isDigits(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' }) == -1
+}
+
+// NormalizeAmount converts an amount in minor units to major units for display or rough
+// comparison, e.g. 1234 USD -> 12.34, 100 JPY -> 100. The float64 result can't represent every
+// int64 exactly, so use it only for presentation; sums must stay in minor units and only ever
+// combine amounts of the same currency.
+func NormalizeAmount(amount int64, currency string) float64 {
+	return float64(amount) / math.Pow10(MinorUnitExponent(currency))
+}
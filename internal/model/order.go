@@ -0,0 +1,18 @@
+package model
+
+import "sort"
+
+// LessByEffectiveAtThenID reports whether a sorts before b in the default list order:
+// effective_at ascending, with ID ascending as the tie-break for equal timestamps. IDs are
+// unique, so the order is total and pages stay stable across requests.
+func LessByEffectiveAtThenID(a, b Transaction) bool {
+	if !a.EffectiveAt.Equal(b.EffectiveAt) {
+		return a.EffectiveAt.Before(b.EffectiveAt)
+	}
+	return a.ID < b.ID
+}
+
+// SortTransactions sorts txns in place into the default list order (see LessByEffectiveAtThenID).
+func SortTransactions(txns []Transaction) {
+	sort.Slice(txns, func(i, j int) bool { return LessByEffectiveAtThenID(txns[i], txns[j]) })
+}
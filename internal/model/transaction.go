@@ -1,14 +1,131 @@
 package model
 
-import "time"
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Transaction directions. Amount is always non-negative; Direction carries the sign.
+const (
+	DirectionDebit  = "debit"
+	DirectionCredit = "credit"
+)
+
+// Metadata keys set by the server when a transaction is reversed or its amount corrected.
+const (
+	MetadataReverses      = "reverses"       // on the reversal: the original transaction's ID
+	MetadataReversedBy    = "reversed_by"    // on the original: the reversal transaction's ID
+	MetadataAmountHistory = "amount_history" // JSON list of AmountChange, oldest first
+)
+
+// MaxMetadataEntries caps the metadata keys on one transaction. The API checks it on input,
+// and the store re-checks a metadata patch against what is stored when it applies it.
+const MaxMetadataEntries = 50
 
 // Transaction represents a financial transaction.
 type Transaction struct {
 	ID          string            `json:"id"`
+	AccountID   string            `json:"account_id,omitempty"` // empty only on data stored before accounts existed
 	Amount      int64             `json:"amount"`
 	Currency    string            `json:"currency"`
+	Direction   string            `json:"direction"`
 	EffectiveAt time.Time         `json:"effective_at"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
+	// TimeZone is the UTC offset effective_at was submitted with, e.g. "-05:00", so clients can
+	// reconstruct local time (see LocalEffectiveAt); EffectiveAt itself is stored in UTC. Empty
+	// for UTC submissions. Server-derived and ignored by Equal, so a retry that sends the same
+	// instant with another offset is still a duplicate.
+	TimeZone string `json:"time_zone,omitempty"`
+	// Tags are freeform lowercase labels for categorization, kept sorted and unique (see NormalizeTags).
+	Tags []string `json:"tags,omitempty"`
+	// Description is free text such as the merchant name shown on a statement.
+	Description string `json:"description,omitempty"`
+
+	// Seq is the server-assigned insertion sequence (1 for the first stored transaction).
+	// It reflects ingestion order, is never taken from the client, and is ignored by Equal.
+	Seq uint64 `json:"seq,omitempty"`
+	// CreatedAt is when the store accepted the transaction, as opposed to the business
+	// EffectiveAt. Like Seq it is server-assigned and ignored by Equal, so idempotent
+	// retries still match. Zero on data stored before it existed.
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	// Deleted marks a soft-deleted transaction. It is kept for audit and still returned by ID,
+	// but hidden from listings by default. Server-assigned and ignored by Equal, like Seq.
+	Deleted bool `json:"deleted,omitempty"`
+	// Version starts at 1 when the store accepts the transaction and goes up by one on every
+	// change (metadata patch, amount correction, upsert, reversal, soft delete), for optimistic concurrency. Server-assigned
+	// and ignored by Equal. Zero on data stored before it existed.
+	Version int `json:"version,omitempty"`
+	// ModifiedAt is when the store last changed the transaction: its creation, then every
+	// change that bumps Version. The store keeps it unique and increasing across all
+	// transactions, so an incremental sync asking for "modified after T" never misses a write
+	// made in the same instant as T. Server-assigned and ignored by Equal. Zero on data stored
+	// before it existed; see LastModified.
+	ModifiedAt time.Time `json:"modified_at,omitzero"`
+}
+
+// LastModified returns ModifiedAt, or CreatedAt for data stored before ModifiedAt existed.
+func (t Transaction) LastModified() time.Time {
+	if t.ModifiedAt.IsZero() {
+		return t.CreatedAt
+	}
+	return t.ModifiedAt
+}
+
+// WithDefaults returns a copy with defaults applied for fields that older stored data may lack.
+// Transactions stored before Direction existed are treated as debits. Only use this on reads;
+// creates must supply every required field.
+func (t Transaction) WithDefaults() Transaction {
+	if t.Direction == "" {
+		t.Direction = DirectionDebit
+	}
+	return t
+}
+
+// TimeZoneOf returns the UTC offset of t formatted as "-07:00", or "" if t is in UTC.
+func TimeZoneOf(t time.Time) string {
+	if _, offset := t.Zone(); offset == 0 {
+		return ""
+	}
+	return t.Format("-07:00")
+}
+
+// LocalEffectiveAt returns EffectiveAt in the offset it was submitted with, or in UTC if
+// TimeZone is empty or malformed.
+func (t Transaction) LocalEffectiveAt() time.Time {
+	if t.TimeZone == "" {
+		return t.EffectiveAt.UTC()
+	}
+	zone, err := time.Parse("-07:00", t.TimeZone)
+	if err != nil {
+		return t.EffectiveAt.UTC()
+	}
+	return t.EffectiveAt.In(zone.Location())
+}
+
+// Reversal returns a transaction that voids t: same account, amount and currency, opposite
+// direction, and Metadata[MetadataReverses] set to t.ID. The caller supplies the new ID and effective time.
+func (t Transaction) Reversal(id string, effectiveAt time.Time) Transaction {
+	direction := DirectionCredit
+	if t.WithDefaults().Direction == DirectionCredit {
+		direction = DirectionDebit
+	}
+	return Transaction{
+		ID:          id,
+		AccountID:   t.AccountID,
+		Amount:      t.Amount,
+		Currency:    t.Currency,
+		Direction:   direction,
+		EffectiveAt: effectiveAt,
+		Metadata:    map[string]string{MetadataReverses: t.ID},
+	}
 }
 
 // Clone returns a deep copy of the transaction.
@@ -16,6 +133,9 @@ type Transaction struct {
 // prevent callers from mutating the store's internal state.
 func (t Transaction) Clone() Transaction {
 	c := t
+	if t.Tags != nil {
+		c.Tags = append([]string(nil), t.Tags...)
+	}
 	if t.Metadata != nil {
 		c.Metadata = make(map[string]string, len(t.Metadata))
 		for k, v := range t.Metadata {
@@ -25,16 +145,104 @@ func (t Transaction) Clone() Transaction {
 	return c
 }
 
+// MergeMetadata returns a new map with patch applied to base: keys with a non-nil value are
+// added or overwritten and keys with a nil value are deleted. base is not modified.
+// An empty result is returned as nil so it serializes the same as no metadata.
+func MergeMetadata(base map[string]string, patch map[string]*string) map[string]string {
+	merged := make(map[string]string, len(base)+len(patch))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range patch {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = *v
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// ClearMetadata returns what is left of base when a client clears its metadata: only the
+// server-maintained keys (see IsServerMetadataKey), or nil if there are none. base is not modified.
+func ClearMetadata(base map[string]string) map[string]string {
+	return WithServerMetadata(nil, base)
+}
+
+// IsServerMetadataKey reports whether key is one of the metadata keys above, which only the
+// server writes. Clients can't set, change or remove them: a forged amount_history would break
+// the next amount correction, and a removed reversed_by would make a reversed transaction
+// reversible again.
+func IsServerMetadataKey(key string) bool {
+	switch key {
+	case MetadataReverses, MetadataReversedBy, MetadataAmountHistory:
+		return true
+	}
+	return false
+}
+
+// WithServerMetadata returns the client-supplied entries of metadata together with the
+// server-maintained entries of from (see IsServerMetadataKey), so a write that replaces a
+// transaction's metadata keeps what the server recorded. Neither map is modified. An empty
+// result is returned as nil so it serializes the same as no metadata.
+func WithServerMetadata(metadata, from map[string]string) map[string]string {
+	out := make(map[string]string, len(metadata)+len(from))
+	for k, v := range metadata {
+		if !IsServerMetadataKey(k) {
+			out[k] = v
+		}
+	}
+	for k, v := range from {
+		if IsServerMetadataKey(k) {
+			out[k] = v
+		}
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
+
+// NormalizeTags lowercases and trims each tag, drops empty ones, and returns the rest sorted
+// and deduplicated, so tag order and case never affect Equal or filtering.
+// An empty result is returned as nil so it serializes the same as no tags.
+func NormalizeTags(tags []string) []string {
+	var out []string
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			out = append(out, tag)
+		}
+	}
+	slices.Sort(out)
+	return slices.Compact(out)
+}
+
+// HasTag reports whether the normalized tag is present.
+func (t Transaction) HasTag(tag string) bool {
+	return slices.Contains(t.Tags, tag)
+}
+
 // Equal returns true if two transactions have identical field values.
-// Used for idempotency checks.
+// Used for idempotency checks. Server-assigned fields (Seq, CreatedAt, Deleted, Version,
+// ModifiedAt) are not compared.
 func (t Transaction) Equal(other Transaction) bool {
 	if t.ID != other.ID ||
+		t.AccountID != other.AccountID ||
 		t.Amount != other.Amount ||
 		t.Currency != other.Currency ||
+		t.Direction != other.Direction ||
+		t.Description != other.Description ||
 		!t.EffectiveAt.Equal(other.EffectiveAt) {
 		return false
 	}
 
+	if !slices.Equal(t.Tags, other.Tags) {
+		return false
+	}
+
 	if len(t.Metadata) != len(other.Metadata) {
 		return false
 	}
@@ -46,3 +254,100 @@ func (t Transaction) Equal(other Transaction) bool {
 	}
 	return true
 }
+
+// contentFields is the canonical form hashed by ContentHash: only the client-supplied
+// business fields, with EffectiveAt normalized to UTC and empty metadata as null.
+type contentFields struct {
+	ID          string            `json:"id"`
+	AccountID   string            `json:"account_id"`
+	Amount      int64             `json:"amount"`
+	Currency    string            `json:"currency"`
+	Direction   string            `json:"direction"`
+	EffectiveAt string            `json:"effective_at"`
+	Metadata    map[string]string `json:"metadata"`
+	Tags        []string          `json:"tags"`
+	// omitempty keeps the hash of transactions without a description unchanged
+	Description string `json:"description,omitempty"`
+}
+
+// ContentHash returns the hex SHA-256 of the transaction's business fields in canonical JSON.
+// Transactions that Equal reports equal always share a hash, and any difference in a business
+// field changes it, so the store can compare one precomputed string instead of walking metadata
+// on every create. encoding/json writes map keys in sorted order, which keeps it deterministic.
+func (t Transaction) ContentHash() string {
+	canonical := contentFields{
+		ID:          t.ID,
+		AccountID:   t.AccountID,
+		Amount:      t.Amount,
+		Currency:    t.Currency,
+		Direction:   t.Direction,
+		EffectiveAt: t.EffectiveAt.UTC().Format(time.RFC3339Nano),
+		Description: t.Description,
+	}
+	if len(t.Metadata) > 0 {
+		canonical.Metadata = t.Metadata
+	}
+	if len(t.Tags) > 0 {
+		canonical.Tags = t.Tags
+	}
+
+	// Marshalling strings, ints and a string map cannot fail
+	b, _ := json.Marshal(canonical)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// ETag returns a strong, quoted entity tag derived from the transaction's client-visible fields.
+// Metadata keys are hashed in sorted order so equal transactions always produce the same tag.
+// Seq is excluded, like in Equal, but Version is included so every change gets a new tag.
+func (t Transaction) ETag() string {
+	h := sha256.New()
+	writeField(h, t.ID)
+	writeField(h, t.AccountID)
+	writeField(h, t.Currency)
+	writeField(h, t.Direction)
+	writeField(h, t.EffectiveAt.UTC().Format(time.RFC3339Nano))
+	binary.Write(h, binary.BigEndian, t.Amount)
+
+	keys := make([]string, 0, len(t.Metadata))
+	for k := range t.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		writeField(h, k)
+		writeField(h, t.Metadata[k])
+	}
+	// Tags are only hashed when present, so untagged transactions keep their existing ETags.
+	// The count separates the tag list from the metadata so a tag can't pose as a key/value
+	if len(t.Tags) > 0 {
+		binary.Write(h, binary.BigEndian, uint64(len(t.Tags)))
+		for _, tag := range t.Tags {
+			writeField(h, tag)
+		}
+	}
+	// Like tags, the description is only hashed when present. It is preceded by a length no
+	// real field can have, so it can't pose as a metadata entry or tag
+	if t.Description != "" {
+		binary.Write(h, binary.BigEndian, uint64(math.MaxUint64))
+		writeField(h, t.Description)
+	}
+	// Soft deletion changes the representation, so cached copies must not revalidate
+	if t.Deleted {
+		writeField(h, "deleted")
+	}
+	// Hashed only past the first version, so never-modified transactions keep their existing ETags
+	if t.Version > 1 {
+		writeField(h, "version")
+		binary.Write(h, binary.BigEndian, int64(t.Version))
+	}
+
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// writeField writes a length-prefixed string so adjacent fields can't run together
+// (e.g. ID "ab" + currency "c" vs ID "a" + currency "bc").
+func writeField(h hash.Hash, s string) {
+	binary.Write(h, binary.BigEndian, uint64(len(s)))
+	h.Write([]byte(s))
+}
@@ -0,0 +1,213 @@
+package store
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// CachingStore is a Store decorator that keeps the most recently read transactions in an
+// in-process LRU cache in front of Get. Every other read passes straight through to the
+// wrapped store. Writes go to the wrapped store and then drop the IDs they touched from the
+// cache, so it composes with any backend.
+//
+// Writes that bypass the decorator, such as MemoryStore's TTL sweeper, are not seen, so a
+// cached entry can outlive its eviction from the backend until it is written or pushed out.
+type CachingStore struct {
+	Store // wrapped backend; any method that writes must be overridden below to invalidate
+
+	mu    sync.Mutex
+	size  int
+	lru   *list.List               // front is most recently used; values are model.Transaction
+	items map[string]*list.Element // transaction ID -> element in lru
+	gen   uint64                   // bumped on every invalidation, see Get
+}
+
+// NewCachingStore wraps inner with an LRU cache holding up to size transactions.
+// A size below 1 is treated as 1.
+func NewCachingStore(inner Store, size int) *CachingStore {
+	return &CachingStore{
+		Store: inner,
+		size:  max(size, 1),
+		lru:   list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached transaction if present, otherwise reads through and caches it.
+// Only found transactions are cached.
+func (c *CachingStore) Get(id string) (model.Transaction, error) {
+	c.mu.Lock()
+	if el, ok := c.items[id]; ok {
+		c.lru.MoveToFront(el)
+		txn := el.Value.(model.Transaction).Clone()
+		c.mu.Unlock()
+		return txn, nil
+	}
+	gen := c.gen
+	c.mu.Unlock()
+
+	txn, err := c.Store.Get(id)
+	if err != nil {
+		return txn, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// A write that landed while we were reading may have made txn stale; don't cache it
+	if c.gen == gen {
+		c.put(txn)
+	}
+	return txn, nil
+}
+
+// put caches a copy of txn, evicting the least recently used entry when full.
+// Callers must hold mu.
+func (c *CachingStore) put(txn model.Transaction) {
+	if el, ok := c.items[txn.ID]; ok {
+		el.Value = txn.Clone()
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.items[txn.ID] = c.lru.PushFront(txn.Clone())
+	if c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.items, oldest.Value.(model.Transaction).ID)
+	}
+}
+
+// invalidate drops ids from the cache.
+func (c *CachingStore) invalidate(ids ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	for _, id := range ids {
+		if el, ok := c.items[id]; ok {
+			c.lru.Remove(el)
+			delete(c.items, id)
+		}
+	}
+}
+
+// purge empties the cache, for writes that can touch IDs the decorator can't see.
+func (c *CachingStore) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	c.lru.Init()
+	c.items = make(map[string]*list.Element)
+}
+
+// Len returns the number of cached transactions.
+func (c *CachingStore) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *CachingStore) Create(txn model.Transaction) error {
+	defer c.invalidate(txn.ID)
+	return c.Store.Create(txn)
+}
+
+func (c *CachingStore) Upsert(txn model.Transaction) (bool, error) {
+	defer c.invalidate(txn.ID)
+	return c.Store.Upsert(txn)
+}
+
+func (c *CachingStore) CreateWithIdempotencyKey(key string, txn model.Transaction, upsert bool) (bool, error) {
+	defer c.invalidate(txn.ID)
+	return c.Store.CreateWithIdempotencyKey(key, txn, upsert)
+}
+
+func (c *CachingStore) CompareAndSwap(id string, expected, newTxn model.Transaction) error {
+	defer c.invalidate(id)
+	return c.Store.CompareAndSwap(id, expected, newTxn)
+}
+
+func (c *CachingStore) UpdateMetadata(id string, patch map[string]*string) error {
+	defer c.invalidate(id)
+	return c.Store.UpdateMetadata(id, patch)
+}
+
+func (c *CachingStore) UpdateMetadataIfVersion(id string, version int, patch map[string]*string) error {
+	defer c.invalidate(id)
+	return c.Store.UpdateMetadataIfVersion(id, version, patch)
+}
+
+func (c *CachingStore) UpdateAmount(id string, amount int64) error {
+	defer c.invalidate(id)
+	return c.Store.UpdateAmount(id, amount)
+}
+
+func (c *CachingStore) UpdateAmountIfVersion(id string, version int, amount int64) error {
+	defer c.invalidate(id)
+	return c.Store.UpdateAmountIfVersion(id, version, amount)
+}
+
+func (c *CachingStore) Reverse(originalID string, reversal model.Transaction) error {
+	defer c.invalidate(originalID, reversal.ID)
+	return c.Store.Reverse(originalID, reversal)
+}
+
+func (c *CachingStore) Delete(id string) error {
+	defer c.invalidate(id)
+	return c.Store.Delete(id)
+}
+
+// DeleteWhere can remove any ID, so it empties the whole cache.
+func (c *CachingStore) DeleteWhere(match func(model.Transaction) bool) (int, error) {
+	defer c.purge()
+	return c.Store.DeleteWhere(match)
+}
+
+// Reset wipes the wrapped store, if it supports Reset, and the cache.
+func (c *CachingStore) Reset() {
+	if rs, ok := c.Store.(interface{ Reset() }); ok {
+		rs.Reset()
+	}
+	c.purge()
+}
+
+// Stats forwards the wrapped store's Create outcome counters, or zeros if it has none.
+func (c *CachingStore) Stats() Stats {
+	if sp, ok := c.Store.(interface{ Stats() Stats }); ok {
+		return sp.Stats()
+	}
+	return Stats{}
+}
+
+// Throughput forwards the wrapped store's recent create counts, or zeros if it has none.
+func (c *CachingStore) Throughput() Throughput {
+	if tp, ok := c.Store.(interface{ Throughput() Throughput }); ok {
+		return tp.Throughput()
+	}
+	return Throughput{}
+}
+
+// Subscribe forwards to the wrapped store. If it can't notify, the returned channel is
+// already closed.
+func (c *CachingStore) Subscribe(buffer int) (<-chan model.Transaction, func()) {
+	if sub, ok := c.Store.(interface {
+		Subscribe(buffer int) (<-chan model.Transaction, func())
+	}); ok {
+		return sub.Subscribe(buffer)
+	}
+	ch := make(chan model.Transaction)
+	close(ch)
+	return ch, func() {}
+}
+
+// MutationHistory forwards to the wrapped store, or returns ErrMutationLogDisabled if it keeps no log.
+func (c *CachingStore) MutationHistory(id string) ([]MutationEvent, error) {
+	if ml, ok := c.Store.(interface {
+		MutationHistory(id string) ([]MutationEvent, error)
+	}); ok {
+		return ml.MutationHistory(id)
+	}
+	return nil, ErrMutationLogDisabled
+}
@@ -0,0 +1,73 @@
+package store
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// Comparator reports whether a create of submitted, whose ID is already held by stored, is an
+// idempotent retry (ErrDuplicate) rather than a conflicting reuse of the ID (ErrConflict).
+// Both transactions have the same ID.
+type Comparator func(stored, submitted model.Transaction) bool
+
+// significantFields maps each field SignificantFields accepts, by JSON name, to its comparison.
+var significantFields = map[string]func(a, b model.Transaction) bool{
+	"id":           func(a, b model.Transaction) bool { return a.ID == b.ID },
+	"account_id":   func(a, b model.Transaction) bool { return a.AccountID == b.AccountID },
+	"amount":       func(a, b model.Transaction) bool { return a.Amount == b.Amount },
+	"currency":     func(a, b model.Transaction) bool { return a.Currency == b.Currency },
+	"direction":    func(a, b model.Transaction) bool { return a.Direction == b.Direction },
+	"effective_at": func(a, b model.Transaction) bool { return a.EffectiveAt.Equal(b.EffectiveAt) },
+	"metadata":     func(a, b model.Transaction) bool { return maps.Equal(a.Metadata, b.Metadata) },
+	"tags":         func(a, b model.Transaction) bool { return slices.Equal(a.Tags, b.Tags) },
+	"description":  func(a, b model.Transaction) bool { return a.Description == b.Description },
+}
+
+// SignificantFields returns a Comparator that treats a retry as a duplicate when the named
+// fields (JSON names such as "amount" or "effective_at") match, ignoring every other field.
+// With no names it returns nil, which keeps the full comparison. An unknown name is an error.
+func SignificantFields(names ...string) (Comparator, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	compares := make([]func(a, b model.Transaction) bool, 0, len(names))
+	for _, name := range names {
+		cmp, ok := significantFields[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown significant field %q (want %s)", name, strings.Join(slices.Sorted(maps.Keys(significantFields)), ", "))
+		}
+		compares = append(compares, cmp)
+	}
+	return func(stored, submitted model.Transaction) bool {
+		for _, cmp := range compares {
+			if !cmp(stored, submitted) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// NewMemoryStoreWithComparator returns a MemoryStore whose Create uses same, instead of the full
+// comparison of model.Transaction.Equal, to tell a duplicate from a conflict. A nil same keeps
+// the default. It takes precedence over ExcludeDescriptionFromIdempotency. Upsert is
+// unaffected, since it always applies any difference.
+func NewMemoryStoreWithComparator(same Comparator) *MemoryStore {
+	s := NewMemoryStore()
+	s.comparator = same
+	return s
+}
+
+// isRetry reports whether txn, whose ID is held by stored, counts as a duplicate.
+// Callers must hold the lock.
+func (s *MemoryStore) isRetry(stored, txn model.Transaction) bool {
+	if s.comparator != nil {
+		return s.comparator(stored, txn)
+	}
+	// Comparing content hashes is equivalent to Equal but skips the metadata walk
+	return s.contentHashes[txn.ID] == s.contentHash(txn)
+}
@@ -0,0 +1,165 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// FileStore is a MemoryStore that survives restarts: every successful write saves a JSON
+// snapshot of the whole store to a file, and opening the store loads it back. Reads are
+// served from memory as usual.
+//
+// Each write rewrites the full snapshot, so writes cost O(n). That is fine for a single-node
+// deployment with a modest dataset; anything bigger wants a real database behind Store.
+// A change is applied in memory even if saving it fails; the error is returned to the caller
+// and the next successful save writes it out.
+type FileStore struct {
+	*MemoryStore
+
+	path   string
+	saveMu sync.Mutex // serializes saves so an older snapshot never overwrites a newer one
+}
+
+// fileSnapshot is the on-disk format.
+type fileSnapshot struct {
+	Transactions    []model.Transaction `json:"transactions"`
+	IdempotencyKeys map[string]string   `json:"idempotency_keys,omitempty"`
+}
+
+// OpenFileStore loads the snapshot at path into mem, or starts empty if the file doesn't exist
+// yet, and returns a store that saves back to path. mem should be new and empty.
+func OpenFileStore(path string, mem *MemoryStore) (*FileStore, error) {
+	f := &FileStore{MemoryStore: mem, path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var snap fileSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	mem.restore(snap.Transactions, snap.IdempotencyKeys)
+	return f, nil
+}
+
+// save writes the current contents to a temporary file and renames it over path, so a crash
+// mid-write leaves the previous snapshot intact.
+func (f *FileStore) save() error {
+	f.saveMu.Lock()
+	defer f.saveMu.Unlock()
+
+	txns, keys := f.MemoryStore.snapshot()
+	data, err := json.Marshal(fileSnapshot{Transactions: txns, IdempotencyKeys: keys})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+// saveAfter saves the snapshot if err, the result of a write, is nil, and returns err otherwise.
+func (f *FileStore) saveAfter(err error) error {
+	if err != nil {
+		return err
+	}
+	return f.save()
+}
+
+func (f *FileStore) Create(txn model.Transaction) error {
+	return f.saveAfter(f.MemoryStore.Create(txn))
+}
+
+func (f *FileStore) Upsert(txn model.Transaction) (bool, error) {
+	created, err := f.MemoryStore.Upsert(txn)
+	return created, f.saveAfter(err)
+}
+
+func (f *FileStore) CompareAndSwap(id string, expected, newTxn model.Transaction) error {
+	return f.saveAfter(f.MemoryStore.CompareAndSwap(id, expected, newTxn))
+}
+
+func (f *FileStore) UpdateMetadata(id string, patch map[string]*string) error {
+	return f.saveAfter(f.MemoryStore.UpdateMetadata(id, patch))
+}
+
+func (f *FileStore) UpdateMetadataIfVersion(id string, version int, patch map[string]*string) error {
+	return f.saveAfter(f.MemoryStore.UpdateMetadataIfVersion(id, version, patch))
+}
+
+func (f *FileStore) UpdateAmount(id string, amount int64) error {
+	return f.saveAfter(f.MemoryStore.UpdateAmount(id, amount))
+}
+
+func (f *FileStore) UpdateAmountIfVersion(id string, version int, amount int64) error {
+	return f.saveAfter(f.MemoryStore.UpdateAmountIfVersion(id, version, amount))
+}
+
+func (f *FileStore) Reverse(originalID string, reversal model.Transaction) error {
+	return f.saveAfter(f.MemoryStore.Reverse(originalID, reversal))
+}
+
+func (f *FileStore) Delete(id string) error {
+	return f.saveAfter(f.MemoryStore.Delete(id))
+}
+
+func (f *FileStore) DeleteWhere(match func(model.Transaction) bool) (int, error) {
+	n, err := f.MemoryStore.DeleteWhere(match)
+	if err != nil || n == 0 {
+		return n, err
+	}
+	return n, f.save()
+}
+
+// ImportFrom imports like MemoryStore.ImportFrom and saves one snapshot at the end rather than
+// one per record. Records created before an error are saved too.
+func (f *FileStore) ImportFrom(r io.Reader) (int, error) {
+	n, err := f.MemoryStore.ImportFrom(r)
+	if n > 0 {
+		err = errors.Join(err, f.save())
+	}
+	return n, err
+}
+
+// CreateWithIdempotencyKey saves after a write. A replay wrote nothing, so it doesn't save.
+func (f *FileStore) CreateWithIdempotencyKey(key string, txn model.Transaction, upsert bool) (bool, error) {
+	created, err := f.MemoryStore.CreateWithIdempotencyKey(key, txn, upsert)
+	if errors.Is(err, ErrDuplicate) {
+		// The key may have just been bound
+		return created, errors.Join(err, f.save())
+	}
+	return created, f.saveAfter(err)
+}
+
+// Reset wipes the store and its snapshot. Reset has no error result, so a failed save is
+// dropped; the next successful write replaces the stale file.
+func (f *FileStore) Reset() {
+	f.MemoryStore.Reset()
+	_ = f.save()
+}
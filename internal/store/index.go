@@ -0,0 +1,220 @@
+package store
+
+import (
+	"iter"
+	"slices"
+	"sort"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// indexChunk is the target chunk size of an orderedIndex. A chunk splits in two when it reaches
+// twice this, and bulk loads fill chunks to exactly this.
+const indexChunk = 512
+
+// orderedIndex holds transactions sorted by model.LessByEffectiveAtThenID, as a list of sorted
+// chunks where every element of a chunk sorts before every element of the next.
+//
+// A plain sorted slice makes each out-of-order insert shift everything after it, so ingesting
+// a backfill or a feed that arrives newest-first is O(n²). Here an insert shifts at most one
+// chunk plus the chunk headers, which keeps bulk ingestion near linear whatever the arrival
+// order, while positions stay cheap to find for offset-based paging: a position is a walk over
+// the chunk lengths, one per indexChunk transactions. google/btree would make inserts O(log n),
+// but it keeps no subtree counts, so it can't find the transaction at an offset: List's
+// and ListBetween's offsets would walk the tree from the start instead.
+//
+// Readers must copy what they need under the store's read lock, as with the old slice: chunks
+// are modified in place by writers. A nil *orderedIndex is empty and may be read.
+type orderedIndex struct {
+	chunks [][]model.Transaction // never empty slices
+	size   int
+}
+
+// newOrderedIndex returns an index holding sorted, which must already be in
+// LessByEffectiveAtThenID order. sorted is copied.
+func newOrderedIndex(sorted []model.Transaction) *orderedIndex {
+	x := &orderedIndex{size: len(sorted)}
+	for start := 0; start < len(sorted); start += indexChunk {
+		end := min(start+indexChunk, len(sorted))
+		chunk := make([]model.Transaction, end-start, 2*indexChunk)
+		copy(chunk, sorted[start:end])
+		x.chunks = append(x.chunks, chunk)
+	}
+	return x
+}
+
+// Len returns the number of transactions in the index.
+func (x *orderedIndex) Len() int {
+	if x == nil {
+		return 0
+	}
+	return x.size
+}
+
+// chunkFor returns the chunk txn belongs in: the first whose last element does not sort before
+// txn, or the last chunk if txn sorts after everything.
+func (x *orderedIndex) chunkFor(txn model.Transaction) int {
+	c := sort.Search(len(x.chunks), func(c int) bool {
+		chunk := x.chunks[c]
+		return !model.LessByEffectiveAtThenID(chunk[len(chunk)-1], txn)
+	})
+	return min(c, len(x.chunks)-1)
+}
+
+// insert adds txn at its sorted position.
+func (x *orderedIndex) insert(txn model.Transaction) {
+	x.size++
+	if len(x.chunks) == 0 {
+		chunk := make([]model.Transaction, 1, 2*indexChunk)
+		chunk[0] = txn
+		x.chunks = append(x.chunks, chunk)
+		return
+	}
+
+	c := x.chunkFor(txn)
+	chunk := x.chunks[c]
+	i := sort.Search(len(chunk), func(i int) bool {
+		return model.LessByEffectiveAtThenID(txn, chunk[i])
+	})
+	// Between the grow and the final assignment the chunk briefly holds a zero value or a
+	// duplicate; that is safe only because callers hold the write lock and every reader copies
+	// under the read lock
+	chunk = append(chunk, model.Transaction{})
+	copy(chunk[i+1:], chunk[i:])
+	chunk[i] = txn
+	x.chunks[c] = chunk
+
+	if len(chunk) >= 2*indexChunk {
+		x.split(c)
+	}
+}
+
+// split moves the upper half of chunk c into a new chunk after it.
+func (x *orderedIndex) split(c int) {
+	chunk := x.chunks[c]
+	half := len(chunk) / 2
+	upper := make([]model.Transaction, len(chunk)-half, 2*indexChunk)
+	copy(upper, chunk[half:])
+	clear(chunk[half:]) // drop references to the moved metadata maps
+	x.chunks[c] = chunk[:half]
+	x.chunks = slices.Insert(x.chunks, c+1, upper)
+}
+
+// find returns the chunk and offset within it of the transaction with txn's sort key and ID.
+func (x *orderedIndex) find(txn model.Transaction) (c, i int, ok bool) {
+	if x.Len() == 0 {
+		return 0, 0, false
+	}
+	c = x.chunkFor(txn)
+	chunk := x.chunks[c]
+	// First element that is not before txn in (EffectiveAt, ID) order
+	i = sort.Search(len(chunk), func(i int) bool {
+		return !model.LessByEffectiveAtThenID(chunk[i], txn)
+	})
+	return c, i, i < len(chunk) && chunk[i].ID == txn.ID
+}
+
+// set overwrites the stored copy of old with txn, which must have the same sort key, and
+// reports whether old was found.
+func (x *orderedIndex) set(old, txn model.Transaction) bool {
+	c, i, ok := x.find(old)
+	if ok {
+		x.chunks[c][i] = txn
+	}
+	return ok
+}
+
+// remove deletes txn and reports whether it was found. An emptied chunk is dropped, and a
+// small one is merged into its successor so heavy deletion doesn't leave many tiny chunks.
+func (x *orderedIndex) remove(txn model.Transaction) bool {
+	c, i, ok := x.find(txn)
+	if !ok {
+		return false
+	}
+	x.size--
+	chunk := x.chunks[c]
+	copy(chunk[i:], chunk[i+1:])
+	chunk[len(chunk)-1] = model.Transaction{} // drop the reference to the old metadata map
+	chunk = chunk[:len(chunk)-1]
+	x.chunks[c] = chunk
+
+	switch {
+	case len(chunk) == 0:
+		x.chunks = slices.Delete(x.chunks, c, c+1)
+	case len(chunk) < indexChunk/4 && c+1 < len(x.chunks) && len(chunk)+len(x.chunks[c+1]) <= indexChunk:
+		x.chunks[c] = append(chunk, x.chunks[c+1]...)
+		x.chunks = slices.Delete(x.chunks, c+1, c+2)
+	}
+	return true
+}
+
+// search returns the position of the first transaction for which f is true, or Len if there
+// is none, like sort.Search. f must be false for a prefix of the index and true after it.
+func (x *orderedIndex) search(f func(model.Transaction) bool) int {
+	if x.Len() == 0 {
+		return 0
+	}
+	c := sort.Search(len(x.chunks), func(c int) bool {
+		chunk := x.chunks[c]
+		return f(chunk[len(chunk)-1])
+	})
+	pos := 0
+	for _, chunk := range x.chunks[:c] {
+		pos += len(chunk)
+	}
+	if c == len(x.chunks) {
+		return pos
+	}
+	chunk := x.chunks[c]
+	return pos + sort.Search(len(chunk), func(i int) bool { return f(chunk[i]) })
+}
+
+// ascend yields the transactions at positions [from, to) in order. It must be consumed
+// under the store's lock.
+func (x *orderedIndex) ascend(from, to int) iter.Seq[model.Transaction] {
+	return func(yield func(model.Transaction) bool) {
+		if x == nil {
+			return
+		}
+		lo, hi := max(from, 0), min(to, x.size)
+		pos := 0
+		for _, chunk := range x.chunks {
+			if pos >= hi {
+				return
+			}
+			if pos+len(chunk) > lo {
+				for _, txn := range chunk[max(lo-pos, 0):min(hi-pos, len(chunk))] {
+					if !yield(txn) {
+						return
+					}
+				}
+			}
+			pos += len(chunk)
+		}
+	}
+}
+
+// all yields every transaction in order. It must be consumed under the store's lock.
+func (x *orderedIndex) all() iter.Seq[model.Transaction] {
+	return x.ascend(0, x.Len())
+}
+
+// appendRange appends the transactions at positions [from, to) to dst and returns it. The
+// copies share metadata maps with the store; see cloneAll.
+func (x *orderedIndex) appendRange(dst []model.Transaction, from, to int) []model.Transaction {
+	if x == nil {
+		return dst
+	}
+	from, to = max(from, 0), min(to, x.size)
+	pos := 0
+	for _, chunk := range x.chunks {
+		if pos >= to {
+			break
+		}
+		if pos+len(chunk) > from {
+			dst = append(dst, chunk[max(from-pos, 0):min(to-pos, len(chunk))]...)
+		}
+		pos += len(chunk)
+	}
+	return dst
+}
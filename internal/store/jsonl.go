@@ -0,0 +1,68 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// ExportTo writes every stored transaction to w as JSON Lines (one object per line), in list
+// order, soft-deleted ones included. effective_at is written in the offset it was submitted
+// with (see LocalEffectiveAt), so POST /transactions/_import, which derives time_zone from that
+// offset, restores it. Each line is encoded straight from the ordered index, so memory stays
+// flat however large the store is, but writers wait for the read lock until the export
+// finishes; point w at something fast such as a local file.
+func (s *MemoryStore) ExportTo(w io.Writer) error {
+	s.memstoreMux.RLock()
+	defer s.memstoreMux.RUnlock()
+
+	enc := json.NewEncoder(w)
+	for txn := range s.ordered.all() {
+		txn.EffectiveAt = txn.LocalEffectiveAt()
+		if err := enc.Encode(txn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportFrom reads JSON Lines in the ExportTo format and creates each transaction, returning
+// how many were created. Records go through Create, so server-assigned fields (Seq, CreatedAt,
+// Version) are assigned afresh, and a record identical to a stored transaction is skipped,
+// which makes re-running an import safe. effective_at is stored in UTC, as a create stores it,
+// and time_zone is taken from its offset when the record has none. A record marked deleted is created and then
+// soft-deleted, so an export keeps its deleted state through a round trip.
+// Import stops at the first malformed record or ErrConflict; records before it stay created.
+func (s *MemoryStore) ImportFrom(r io.Reader) (int, error) {
+	dec := json.NewDecoder(r)
+	created := 0
+	for record := 1; ; record++ {
+		var txn model.Transaction
+		if err := dec.Decode(&txn); errors.Is(err, io.EOF) {
+			return created, nil
+		} else if err != nil {
+			return created, fmt.Errorf("record %d: %w", record, err)
+		}
+		if txn.TimeZone == "" {
+			txn.TimeZone = model.TimeZoneOf(txn.EffectiveAt)
+		}
+		txn.EffectiveAt = txn.EffectiveAt.UTC()
+
+		err := s.Create(txn)
+		if errors.Is(err, ErrDuplicate) {
+			continue
+		} else if err != nil {
+			return created, fmt.Errorf("record %d (id %q): %w", record, txn.ID, err)
+		}
+		created++
+
+		if txn.Deleted {
+			if err := s.Delete(txn.ID); err != nil {
+				return created, fmt.Errorf("record %d (id %q): %w", record, txn.ID, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// EnableLockFreeGets makes Get read from a sync.Map mirror of the transactions instead of
+// taking the read lock, for Get-heavy workloads where readers contend on the store's RWMutex.
+// Writers still take the write lock and update the map first, then the mirror, so the map
+// stays authoritative for every other read (List, Query, ...) and for idempotency checks. A Get
+// sees each write as soon as its mirror update is done, possibly just before the write lock is
+// released; during a Reset or snapshot restore it may see a mix of old and new contents.
+// The mirror costs one extra copy of each stored transaction header (metadata maps are shared).
+// Call it before the store is in use.
+func (s *MemoryStore) EnableLockFreeGets() {
+	s.memstoreMux.Lock()
+	defer s.memstoreMux.Unlock()
+
+	s.lockFreeGets = &sync.Map{}
+	for id, txn := range s.transactions {
+		s.lockFreeGets.Store(id, txn)
+	}
+}
+
+// getLockFree is Get through the mirror.
+func (s *MemoryStore) getLockFree(id string) (model.Transaction, error) {
+	v, ok := s.lockFreeGets.Load(id)
+	if !ok {
+		return model.Transaction{}, ErrNotFound
+	}
+	return v.(model.Transaction).Clone(), nil
+}
+
+// mirrorPut records txn in the lock-free mirror, if enabled. Callers must hold the write lock.
+func (s *MemoryStore) mirrorPut(txn model.Transaction) {
+	if s.lockFreeGets != nil {
+		s.lockFreeGets.Store(txn.ID, txn)
+	}
+}
+
+// mirrorDelete drops id from the lock-free mirror, if enabled. Callers must hold the write lock.
+func (s *MemoryStore) mirrorDelete(id string) {
+	if s.lockFreeGets != nil {
+		s.lockFreeGets.Delete(id)
+	}
+}
+
+// mirrorClear empties the lock-free mirror, if enabled. Callers must hold the write lock.
+func (s *MemoryStore) mirrorClear() {
+	if s.lockFreeGets != nil {
+		s.lockFreeGets.Clear()
+	}
+}
@@ -3,26 +3,243 @@ package store
 /* sync is imported for potential use in synchronizing access to the in-memory data structures,
 such as using mutexes to ensure thread safety when multiple goroutines access the store concurrently.*/
 import (
+	"errors"
+	"fmt"
+	"github.com/synctera/tech-challenge/internal/clock"
 	"github.com/synctera/tech-challenge/internal/model"
 	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type MemoryStore struct {
-	transactions map[string]model.Transaction // Fast O(1) lookups by ID
-	ordered      []model.Transaction          // Slice maintains sorted order for queries
-	memstoreMux  sync.RWMutex                 // Mutex to protect concurrent access
+	// Stored metadata maps are copy-on-write: once stored, a map is never modified, only replaced.
+	// List and Query rely on this to clone outside the lock.
+	transactions    map[string]model.Transaction // Fast O(1) lookups by ID
+	ordered         *orderedIndex                // Every transaction in (EffectiveAt, ID) order for queries
+	byAccount       map[string]*orderedIndex     // Per-account indexes in the same order as ordered
+	contentHashes   map[string]string            // Transaction ID -> ContentHash of the stored copy
+	idempotencyKeys map[string]string            // Client Idempotency-Key -> transaction ID
+	memstoreMux     sync.RWMutex                 // Mutex to protect concurrent access
+	lastSeq         uint64                       // Insertion sequence of the most recently created transaction
+	lastModified    time.Time                    // Latest ModifiedAt handed out; see nextModifiedAt
+	softDeleted     int                          // Stored transactions with Deleted set
+	clock           clock.Clock                  // Source of CreatedAt
+	ttl             time.Duration                // Idempotency window; zero keeps transactions forever
+	stopSweep       chan struct{}                // Closed by Close to stop the TTL sweeper
+	retries         *retryAlarm                  // nil unless EnableRetryAlarm was called
+	capacity        int                          // Maximum stored transactions; zero is unlimited
+	skipDescription bool                         // Description is left out of the idempotency check
+	comparator      Comparator                   // Decides duplicate vs conflict in Create; nil compares every field
+	mutations       MutationLog                  // nil unless EnableMutationLog was called
+	lockFreeGets    *sync.Map                    // nil unless EnableLockFreeGets was called; mirrors transactions
+	closeOnce       sync.Once
+
+	// Create outcome counters; atomic so Stats doesn't need the store lock
+	created, duplicates, conflicts atomic.Uint64
+	// Recent creates per second, for Throughput; has its own lock for the same reason
+	throughput throughputRing
+	// Subscribe channels that receive each new transaction; has its own lock
+	subscribers broadcaster
+}
+
+// Stats counts Create outcomes since the store was constructed. A spike in Duplicate or
+// Conflict usually means a client retry storm.
+type Stats struct {
+	Created   uint64 `json:"created"`
+	Duplicate uint64 `json:"duplicate"`
+	Conflict  uint64 `json:"conflict"`
+}
+
+// Stats returns a snapshot of the Create outcome counters.
+// The counters are monotonic and are not cleared by Reset.
+func (s *MemoryStore) Stats() Stats {
+	return Stats{
+		Created:   s.created.Load(),
+		Duplicate: s.duplicates.Load(),
+		Conflict:  s.conflicts.Load(),
+	}
 }
 
 func NewMemoryStore() *MemoryStore {
+	return NewMemoryStoreWithClock(clock.Real{})
+}
+
+// NewMemoryStoreWithClock creates a store that stamps CreatedAt from c, so tests can control it.
+func NewMemoryStoreWithClock(c clock.Clock) *MemoryStore {
 	// Initialize the in-memory store with empty data structures
 	return &MemoryStore{
-		transactions: make(map[string]model.Transaction),
-		ordered:      make([]model.Transaction, 0),
+		clock:           c,
+		transactions:    make(map[string]model.Transaction),
+		ordered:         newOrderedIndex(nil),
+		byAccount:       make(map[string]*orderedIndex),
+		contentHashes:   make(map[string]string),
+		idempotencyKeys: make(map[string]string),
+	}
+}
+
+// NewMemoryStoreWithCapacity returns a MemoryStore that holds at most max transactions, so a
+// constrained deployment fails requests with ErrCapacityExceeded instead of running out of
+// memory. Only Create, Upsert and Reverse add transactions, and only they check the cap.
+// max <= 0 means unlimited.
+func NewMemoryStoreWithCapacity(max int) *MemoryStore {
+	s := NewMemoryStore()
+	s.capacity = max
+	return s
+}
+
+// NewMemoryStoreWithTTL creates a store whose transactions only count for idempotency for ttl
+// after their CreatedAt. Once a transaction is older than that, creating the same ID again
+// overwrites it instead of returning ErrDuplicate or ErrConflict. A background goroutine
+// evicts expired transactions every sweepEvery; call Close to stop it.
+func NewMemoryStoreWithTTL(c clock.Clock, ttl, sweepEvery time.Duration) *MemoryStore {
+	s := NewMemoryStoreWithClock(c)
+	s.ttl = ttl
+	s.stopSweep = make(chan struct{})
+	go s.sweep(sweepEvery)
+	return s
+}
+
+// sweep calls EvictExpired on every tick until Close is called.
+func (s *MemoryStore) sweep(every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.EvictExpired()
+		case <-s.stopSweep:
+			return
+		}
+	}
+}
+
+// Close stops the TTL sweeper. It is safe to call more than once, and a no-op for stores
+// created without a TTL.
+func (s *MemoryStore) Close() {
+	s.closeOnce.Do(func() {
+		if s.stopSweep != nil {
+			close(s.stopSweep)
+		}
+	})
+}
+
+// expired reports whether txn is past the idempotency window. Transactions without a
+// CreatedAt (older data) never expire. Callers must hold the lock.
+func (s *MemoryStore) expired(txn model.Transaction, now time.Time) bool {
+	return s.ttl > 0 && !txn.CreatedAt.IsZero() && now.Sub(txn.CreatedAt) >= s.ttl
+}
+
+// EvictExpired removes every transaction past the idempotency window, along with any
+// Idempotency-Keys bound to them, and returns how many were removed.
+// The sweeper calls it periodically; it is exported so tests can evict deterministically.
+func (s *MemoryStore) EvictExpired() int {
+	s.memstoreMux.Lock()
+	defer s.memstoreMux.Unlock()
+
+	if s.ttl <= 0 {
+		return 0
+	}
+
+	now := s.clock.Now()
+	return s.deleteLocked(func(txn model.Transaction) bool { return s.expired(txn, now) })
+}
+
+// DeleteWhere removes every transaction for which match returns true, along with any
+// Idempotency-Keys bound to them, and returns how many were removed. A nil match deletes
+// nothing, so a missing predicate can't wipe the store.
+func (s *MemoryStore) DeleteWhere(match func(model.Transaction) bool) (int, error) {
+	if match == nil {
+		return 0, nil
+	}
+
+	s.memstoreMux.Lock()
+	defer s.memstoreMux.Unlock()
+
+	return s.deleteLocked(match), nil
+}
+
+// deleteLocked rebuilds the ordered index, the map, and the account index keeping only
+// transactions match rejects. One O(n) pass is cheaper than removing matches one by one.
+// Callers must hold the write lock.
+func (s *MemoryStore) deleteLocked(match func(model.Transaction) bool) int {
+	deleted := make(map[string]struct{})
+	kept := make([]model.Transaction, 0, s.ordered.Len())
+	for txn := range s.ordered.all() {
+		if match(txn) {
+			deleted[txn.ID] = struct{}{}
+			delete(s.transactions, txn.ID)
+			s.mirrorDelete(txn.ID)
+			delete(s.contentHashes, txn.ID)
+			if txn.Deleted {
+				s.softDeleted--
+			}
+			s.logMutation(MutationPurge, txn.ID, 0)
+			continue
+		}
+		kept = append(kept, txn)
+	}
+	if len(deleted) == 0 {
+		return 0
+	}
+
+	s.ordered, s.byAccount = indexAll(kept)
+	for key, id := range s.idempotencyKeys {
+		if _, ok := deleted[id]; ok {
+			delete(s.idempotencyKeys, key)
+		}
+	}
+	return len(deleted)
+}
+
+// remove deletes a stored transaction from every index. Callers must hold the write lock.
+func (s *MemoryStore) remove(txn model.Transaction) {
+	delete(s.transactions, txn.ID)
+	s.mirrorDelete(txn.ID)
+	delete(s.contentHashes, txn.ID)
+	s.removeOrdered(txn)
+	if txn.Deleted {
+		s.softDeleted--
 	}
+	s.logMutation(MutationPurge, txn.ID, 0)
+}
+
+// Delete soft-deletes a transaction: it stays stored, readable by ID and counted for
+// idempotency, but listings skip it unless asked to include deleted rows.
+func (s *MemoryStore) Delete(id string) error {
+	s.memstoreMux.Lock()
+	defer s.memstoreMux.Unlock()
+
+	current, exists := s.transactions[id]
+	if !exists {
+		return ErrNotFound
+	}
+	if current.Deleted {
+		return nil
+	}
+
+	updated := current
+	updated.Deleted = true
+	s.replace(current, updated)
+	s.softDeleted++
+	return nil
 }
 
+// Create stores txn if its ID is new. If the ID is taken, it returns ErrDuplicate for an
+// identical payload (see model.Transaction.Equal, or the store's Comparator) and a *ConflictError holding the stored copy
+// for any other, leaving the stored transaction untouched. A new ID is refused with ErrCapacityExceeded once the store
+// holds its capacity, but a duplicate still reports ErrDuplicate. The lookup and the insert happen under one write lock, so
+// concurrent creates of one ID are serialized: whichever takes the lock first wins, and every
+// later one is compared against the winner. Racing creates with different payloads therefore
+// always produce exactly one success and ErrConflict for the rest, never ErrDuplicate.
 func (s *MemoryStore) Create(txn model.Transaction) error {
+	// Outside the store lock, so logging a warning never holds up other requests
+	if s.retries != nil {
+		s.retries.observe(txn.ID, s.clock.Now())
+	}
+
 	// lock the store in order to safely perform the operations below
 	// this lock prevents others from performing read/write operations on the store until the lock is released
 	s.memstoreMux.Lock()
@@ -31,56 +248,384 @@ func (s *MemoryStore) Create(txn model.Transaction) error {
 	// defer will wait until the function returns before executing the unlock
 	defer s.memstoreMux.Unlock()
 
+	return s.create(txn)
+}
+
+// create is Create for callers that already hold the write lock.
+func (s *MemoryStore) create(txn model.Transaction) error {
 	// this uses the comma ok idiom
 	// basically it checks if the transaction with the given ID already exists in the store
 	// and returns the value + a boolean indicating whether it was found or not
 	// thought about just calling the "Get" method here but that would require an additional lock/unlock which is inefficient
 	existingTxn, exists := s.transactions[txn.ID]
 
+	// A transaction past the idempotency window no longer blocks the ID; the new create replaces it
+	if exists && s.expired(existingTxn, s.clock.Now()) {
+		s.remove(existingTxn)
+		exists = false
+	}
+
 	// if transaction exists
 	if exists {
-		// if the existing transaction is identical to the new one, return ErrDuplicate
-		if existingTxn.Equal(txn) {
+		// if the existing transaction matches the new one, return ErrDuplicate
+		if s.isRetry(existingTxn, txn) {
+			s.duplicates.Add(1)
 			return ErrDuplicate
 		}
 
-		return ErrConflict
+		s.conflicts.Add(1)
+		return &ConflictError{Existing: existingTxn.Clone()}
+	}
+
+	// Checked only for new IDs, so retries of stored transactions keep succeeding when full
+	if s.full() {
+		return ErrCapacityExceeded
+	}
+
+	// if the transaction does not exist, add it to the store
+	s.insert(txn)
+
+	return nil
+}
+
+// Upsert stores txn under its ID whatever is there: a new ID is inserted (created is true),
+// an existing one with a different payload is replaced in place, keeping its Seq, CreatedAt and
+// deleted flag, and an identical one is left alone. A replace also keeps the server-maintained
+// metadata (see model.WithServerMetadata), so a reversed transaction stays reversed, and records
+// a changed amount in amount_history as UpdateAmount does; ErrInvalidAmountHistory is returned
+// if that history can't be appended to. Replacing re-sorts only if effective_at changed. An
+// expired transaction is treated as absent, as in Create.
+func (s *MemoryStore) Upsert(txn model.Transaction) (created bool, err error) {
+	s.memstoreMux.Lock()
+	defer s.memstoreMux.Unlock()
+	return s.upsert(txn)
+}
+
+// upsert is Upsert for callers that already hold the write lock.
+func (s *MemoryStore) upsert(txn model.Transaction) (created bool, err error) {
+	existing, exists := s.transactions[txn.ID]
+	if exists && s.expired(existing, s.clock.Now()) {
+		s.remove(existing)
+		exists = false
+	}
+	if !exists {
+		if s.full() {
+			return false, ErrCapacityExceeded
+		}
+		s.insert(txn)
+		return true, nil
+	}
+
+	replacement := txn
+	replacement.Metadata = model.WithServerMetadata(txn.Metadata, existing.Metadata)
+	// Equal rather than the idempotency hash, so an upsert always applies a new description
+	if existing.Equal(replacement) {
+		return false, nil
+	}
+	if replacement.Amount != existing.Amount {
+		history, err := model.AppendAmountChange(existing.Metadata[model.MetadataAmountHistory],
+			model.AmountChange{Amount: existing.Amount, ChangedAt: s.clock.Now()})
+		if err != nil {
+			return false, fmt.Errorf("%w: %v", ErrInvalidAmountHistory, err)
+		}
+		replacement.Metadata = model.MergeMetadata(replacement.Metadata, map[string]*string{model.MetadataAmountHistory: &history})
 	}
+	replacement.Deleted = existing.Deleted // only Delete changes it
+	s.replace(existing, replacement)
+	return false, nil
+}
+
+// ExcludeDescriptionFromIdempotency makes Create ignore Description when deciding whether a
+// retry is a duplicate: a create that differs from the stored transaction only in its
+// description returns ErrDuplicate, and the stored description is kept. Clients that rewrite
+// merchant text between retries then don't get spurious conflicts. Upsert still applies the
+// new description. Call it before the store is in use.
+func (s *MemoryStore) ExcludeDescriptionFromIdempotency() {
+	s.skipDescription = true
+}
 
+// contentHash is what Create compares to detect duplicates: txn.ContentHash, without the
+// description if ExcludeDescriptionFromIdempotency was called.
+func (s *MemoryStore) contentHash(txn model.Transaction) string {
+	if s.skipDescription {
+		txn.Description = ""
+	}
+	return txn.ContentHash()
+}
+
+// full reports whether the store holds its capacity, so inserting one more would exceed it.
+// Soft-deleted transactions still take up memory, so they count. Callers must hold the lock.
+func (s *MemoryStore) full() bool {
+	return s.capacity > 0 && len(s.transactions) >= s.capacity
+}
+
+// insert stores a new transaction, assigning its Seq and CreatedAt. Callers must hold the write lock
+// and have checked that the ID is unused.
+func (s *MemoryStore) insert(txn model.Transaction) {
 	// Clone before storing so the store's copy is isolated from the caller's map reference
 	stored := txn.Clone()
 
-	// if the transaction does not exist, add it to the store
-	s.transactions[txn.ID] = stored
+	// Assign the insertion sequence under the write lock so it matches the true ingestion order
+	s.lastSeq++
+	stored.Seq = s.lastSeq
+	stored.CreatedAt = s.clock.Now().UTC() // never taken from the client
+	stored.ModifiedAt = s.nextModifiedAt(stored.CreatedAt)
+	stored.Deleted = false // only Delete sets it
+	stored.Version = 1
+
+	s.transactions[stored.ID] = stored
+	s.mirrorPut(stored)
+	s.contentHashes[stored.ID] = s.contentHash(stored)
+	s.insertOrdered(stored)
+	s.created.Add(1)
+	s.throughput.record(stored.CreatedAt)
+	s.logMutation(MutationCreate, stored.ID, stored.Version)
+	s.subscribers.publish(stored)
+}
+
+// Reverse stores reversal and marks the original as reversed_by it, both under one write lock
+// so no reader sees one without the other. Returns ErrNotFound if the original is missing,
+// ErrAlreadyReversed if it was reversed before, and ErrConflict if reversal's ID is taken.
+func (s *MemoryStore) Reverse(originalID string, reversal model.Transaction) error {
+	s.memstoreMux.Lock()
+	defer s.memstoreMux.Unlock()
+
+	original, exists := s.transactions[originalID]
+	if !exists {
+		return ErrNotFound
+	}
+	if _, reversed := original.Metadata[model.MetadataReversedBy]; reversed {
+		return ErrAlreadyReversed
+	}
+	if _, taken := s.transactions[reversal.ID]; taken {
+		return ErrConflict
+	}
+	if s.full() {
+		return ErrCapacityExceeded
+	}
+
+	s.insert(reversal)
+
+	marked := original
+	marked.Metadata = model.MergeMetadata(original.Metadata, map[string]*string{model.MetadataReversedBy: &reversal.ID})
+	s.replace(original, marked)
+	return nil
+}
+
+// Reset drops every transaction and idempotency key, returning the store to its freshly
+// constructed state. Intended for test fixtures only.
+func (s *MemoryStore) Reset() {
+	s.memstoreMux.Lock()
+	defer s.memstoreMux.Unlock()
 
-	// Define comparison function for readability
-	shouldInsertBefore := func(i int) bool {
-		existing := s.ordered[i]
+	s.transactions = make(map[string]model.Transaction)
+	s.mirrorClear()
+	s.ordered = newOrderedIndex(nil)
+	s.byAccount = make(map[string]*orderedIndex)
+	s.contentHashes = make(map[string]string)
+	s.idempotencyKeys = make(map[string]string)
+	s.lastSeq = 0
+	s.softDeleted = 0
+}
+
+// insertOrdered places txn into the ordered index and its account's index at their sorted positions.
+// Callers must hold the write lock.
+func (s *MemoryStore) insertOrdered(txn model.Transaction) {
+	s.ordered.insert(txn)
+	if txn.AccountID == "" {
+		return
+	}
+	list, ok := s.byAccount[txn.AccountID]
+	if !ok {
+		list = newOrderedIndex(nil)
+		s.byAccount[txn.AccountID] = list
+	}
+	list.insert(txn)
+}
+
+// removeOrdered deletes txn from the ordered index and its account's index.
+// Callers must hold the write lock.
+func (s *MemoryStore) removeOrdered(txn model.Transaction) {
+	s.ordered.remove(txn)
+	if txn.AccountID == "" {
+		return
+	}
+	if list := s.byAccount[txn.AccountID]; list != nil {
+		list.remove(txn)
+		if list.Len() == 0 {
+			delete(s.byAccount, txn.AccountID) // don't keep empty entries for accounts that moved away
+		}
+	}
+}
+
+// indexAll builds the ordered and per-account indexes for txns, which must already be in
+// LessByEffectiveAtThenID order.
+func indexAll(txns []model.Transaction) (*orderedIndex, map[string]*orderedIndex) {
+	accounts := make(map[string][]model.Transaction)
+	for _, txn := range txns {
+		if txn.AccountID != "" {
+			accounts[txn.AccountID] = append(accounts[txn.AccountID], txn)
+		}
+	}
+	byAccount := make(map[string]*orderedIndex, len(accounts))
+	for accountID, list := range accounts {
+		byAccount[accountID] = newOrderedIndex(list)
+	}
+	return newOrderedIndex(txns), byAccount
+}
+
+// replace swaps the stored copy of an existing transaction, keeping the ordered index and the
+// account index sorted. The server-assigned Seq and CreatedAt are carried over from the old copy
+// and Version is bumped; Deleted is taken from txn, so callers that aren't deleting must pass the old value through.
+// Callers must hold the write lock.
+func (s *MemoryStore) replace(old, txn model.Transaction) {
+	stored := txn.Clone()
+	stored.Seq = old.Seq
+	stored.CreatedAt = old.CreatedAt
+	stored.Version = old.Version + 1
+	stored.ModifiedAt = s.nextModifiedAt(s.clock.Now().UTC())
+	s.transactions[stored.ID] = stored
+	s.mirrorPut(stored)
+	s.contentHashes[stored.ID] = s.contentHash(stored)
+	if stored.Deleted && !old.Deleted {
+		s.logMutation(MutationDelete, stored.ID, stored.Version)
+	} else {
+		s.logMutation(MutationUpdate, stored.ID, stored.Version)
+	}
 
-		if txn.EffectiveAt.Before(existing.EffectiveAt) {
-			return true
-		} else if txn.EffectiveAt.After(existing.EffectiveAt) {
-			return false
+	// Only move the element when its sort key or account changed; otherwise overwrite in place
+	if old.EffectiveAt.Equal(stored.EffectiveAt) && old.AccountID == stored.AccountID {
+		if s.ordered.set(old, stored) {
+			if list := s.byAccount[stored.AccountID]; list != nil {
+				list.set(old, stored)
+			}
+			return
 		}
+	}
+	s.removeOrdered(old)
+	s.insertOrdered(stored)
+}
 
-		return txn.ID < existing.ID
+// nextModifiedAt returns now, or if that is not after the last ModifiedAt handed out (two
+// writes in one clock tick, or the clock stepping back), a nanosecond after it. ModifiedAt is
+// then unique and increasing in write order, which makes modified-since sync exact. Callers
+// must hold the write lock.
+func (s *MemoryStore) nextModifiedAt(now time.Time) time.Time {
+	if !now.After(s.lastModified) {
+		now = s.lastModified.Add(time.Nanosecond)
 	}
+	s.lastModified = now
+	return now
+}
 
-	// search works by finding the index where the new transaction should be inserted to maintain sorted order
-	// you pass in a function because sort.Search will call it with different indices to find the correct position for the new transaction
-	index := sort.Search(len(s.ordered), shouldInsertBefore)
+// CompareAndSwap replaces the transaction stored under id with newTxn, but only if the
+// stored transaction still equals expected. This lets internal callers do a safe
+// read-modify-write: read with Get, modify a copy, then CompareAndSwap with the original.
+func (s *MemoryStore) CompareAndSwap(id string, expected, newTxn model.Transaction) error {
+	if newTxn.ID != id {
+		return ErrIDMismatch
+	}
 
-	// Grow the slice by one element to make room for the new transaction
-	// Shift elements to the right to make space for the new transaction at the correct index
-	// set the new transaction at the correct index in the ordered slice
-	s.ordered = append(s.ordered, model.Transaction{}) // grow the slice by one element
-	copy(s.ordered[index+1:], s.ordered[index:])
-	s.ordered[index] = stored
+	s.memstoreMux.Lock()
+	defer s.memstoreMux.Unlock()
+
+	current, exists := s.transactions[id]
+	if !exists {
+		return ErrNotFound
+	}
+	if !current.Equal(expected) {
+		return ErrPreconditionFailed
+	}
 
+	newTxn.Deleted = current.Deleted // only Delete changes it
+	s.replace(current, newTxn)
+	return nil
+}
+
+// UpdateMetadata applies a metadata patch under the write lock. Only metadata changes, so the
+// transaction keeps its position in the ordered index and its Seq.
+func (s *MemoryStore) UpdateMetadata(id string, patch map[string]*string) error {
+	return s.updateMetadata(id, nil, patch)
+}
+
+// UpdateMetadataIfVersion is UpdateMetadata guarded by the stored Version. The check and the
+// write happen under the same write lock, so of two writers holding the same version only one wins.
+func (s *MemoryStore) UpdateMetadataIfVersion(id string, version int, patch map[string]*string) error {
+	return s.updateMetadata(id, &version, patch)
+}
+
+// updateMetadata applies patch, first checking the stored Version against version when it is non-nil.
+func (s *MemoryStore) updateMetadata(id string, version *int, patch map[string]*string) error {
+	s.memstoreMux.Lock()
+	defer s.memstoreMux.Unlock()
+
+	current, exists := s.transactions[id]
+	if !exists {
+		return ErrNotFound
+	}
+	if version != nil && current.Version != *version {
+		return ErrPreconditionFailed
+	}
+
+	updated := current
+	if patch != nil && len(patch) == 0 {
+		updated.Metadata = model.ClearMetadata(current.Metadata)
+	} else {
+		updated.Metadata = model.MergeMetadata(current.Metadata, patch)
+	}
+	// Checked here, against what is stored, so concurrent patches can't together pass the limit
+	if len(updated.Metadata) > model.MaxMetadataEntries {
+		return ErrTooManyMetadataEntries
+	}
+	s.replace(current, updated)
+	return nil
+}
+
+// UpdateAmount corrects the stored amount under the write lock and appends the prior amount,
+// stamped with the store clock, to metadata[amount_history]. Setting the amount it already
+// has changes nothing. The amount is not a sort key, so the transaction keeps its position.
+func (s *MemoryStore) UpdateAmount(id string, amount int64) error {
+	return s.updateAmount(id, nil, amount)
+}
+
+// UpdateAmountIfVersion is UpdateAmount guarded by the stored Version, like UpdateMetadataIfVersion.
+func (s *MemoryStore) UpdateAmountIfVersion(id string, version int, amount int64) error {
+	return s.updateAmount(id, &version, amount)
+}
+
+// updateAmount applies the correction, first checking the stored Version against version when it is non-nil.
+func (s *MemoryStore) updateAmount(id string, version *int, amount int64) error {
+	s.memstoreMux.Lock()
+	defer s.memstoreMux.Unlock()
+
+	current, exists := s.transactions[id]
+	if !exists {
+		return ErrNotFound
+	}
+	if version != nil && current.Version != *version {
+		return ErrPreconditionFailed
+	}
+	if current.Amount == amount {
+		return nil
+	}
+
+	history, err := model.AppendAmountChange(current.Metadata[model.MetadataAmountHistory],
+		model.AmountChange{Amount: current.Amount, ChangedAt: s.clock.Now()})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAmountHistory, err)
+	}
+	updated := current
+	updated.Amount = amount
+	updated.Metadata = model.MergeMetadata(current.Metadata, map[string]*string{model.MetadataAmountHistory: &history})
+	s.replace(current, updated)
 	return nil
 }
 
 func (s *MemoryStore) Get(id string) (model.Transaction, error) {
+	if s.lockFreeGets != nil {
+		return s.getLockFree(id)
+	}
+
 	// only need read lock here since we're just reading from the store
 	// defer will wait until the function returns before executing the unlock
 	s.memstoreMux.RLock()
@@ -96,6 +641,32 @@ func (s *MemoryStore) Get(id string) (model.Transaction, error) {
 	return model.Transaction{}, ErrNotFound
 }
 
+// GetMany looks up every ID under a single read lock, so the result is a consistent snapshot.
+// found is in request order; an ID repeated in ids is returned (or reported missing) once.
+func (s *MemoryStore) GetMany(ids []string) (found []model.Transaction, missing []string, err error) {
+	found = make([]model.Transaction, 0, len(ids))
+	missing = []string{}
+	seen := make(map[string]struct{}, len(ids))
+
+	s.memstoreMux.RLock()
+	for _, id := range ids {
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		if txn, ok := s.transactions[id]; ok {
+			found = append(found, txn)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	s.memstoreMux.RUnlock()
+
+	cloneAll(found)
+	return found, missing, nil
+}
+
 // List returns a slice of transactions based on the provided limit and offset for pagination.
 // ----------------------------------------------------------------------------------------------
 // initially I handled edge cases but after re-reading the requirements I realized it just says
@@ -105,24 +676,364 @@ func (s *MemoryStore) Get(id string) (model.Transaction, error) {
 // offset will just return the first "limit" transactions)
 func (s *MemoryStore) List(limit, offset int) ([]model.Transaction, error) {
 	s.memstoreMux.RLock()
-	defer s.memstoreMux.RUnlock()
 
 	// Handle offset beyond data - return empty slice
-	if offset >= len(s.ordered) {
+	if offset >= s.ordered.Len() {
+		s.memstoreMux.RUnlock()
 		return []model.Transaction{}, nil
 	}
 
 	end := offset + limit
 	// Cap end to available data instead of erroring
-	if end > len(s.ordered) {
-		end = len(s.ordered)
+	if end > s.ordered.Len() {
+		end = s.ordered.Len()
 	}
 
-	// Clone each element so callers cannot mutate the store's internal map references
-	result := make([]model.Transaction, end-offset)
-	for i, txn := range s.ordered[offset:end] {
-		result[i] = txn.Clone()
+	// Only the flat copy of the range happens under the lock, so writers aren't starved
+	// while a large page is deep-copied
+	result := s.ordered.appendRange(make([]model.Transaction, 0, max(end-offset, 0)), offset, end)
+	s.memstoreMux.RUnlock()
+
+	cloneAll(result)
+	return result, nil
+}
+
+// ListBetween returns up to limit transactions with start <= effective_at <= end, skipping the
+// first offset matches. Soft-deleted transactions are skipped. A zero start or end leaves that
+// side of the range open. Because ordered is sorted by effective_at, both ends are found with a
+// binary search and the page is copied out directly, so the cost is O(log n + limit) plus a walk
+// over the chunk lengths (see orderedIndex) rather than a full scan. Once anything is soft-deleted
+// the range is walked instead, adding O(offset).
+func (s *MemoryStore) ListBetween(start, end time.Time, limit, offset int) ([]model.Transaction, error) {
+	s.memstoreMux.RLock()
+
+	from, to := s.between(start, end)
+	var result []model.Transaction
+	if s.softDeleted == 0 {
+		from += max(offset, 0)
+		if from >= to {
+			s.memstoreMux.RUnlock()
+			return []model.Transaction{}, nil
+		}
+		to = min(to, from+max(limit, 0))
+
+		// Same as List: flat copy under the lock, deep copy after
+		result = s.ordered.appendRange(make([]model.Transaction, 0, to-from), from, to)
+	} else {
+		// Soft-deleted rows don't count toward offset, so the range has to be walked
+		result = make([]model.Transaction, 0, min(max(limit, 0), max(to-from, 0)))
+		skip := max(offset, 0)
+		for txn := range s.ordered.ascend(from, to) {
+			if len(result) >= limit {
+				break
+			}
+			if txn.Deleted {
+				continue
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+			result = append(result, txn)
+		}
 	}
+	s.memstoreMux.RUnlock()
 
+	cloneAll(result)
 	return result, nil
 }
+
+// between returns the half-open index range [from, to) of ordered holding transactions with
+// start <= effective_at <= end. A zero start or end leaves that side open. Callers must hold the lock.
+func (s *MemoryStore) between(start, end time.Time) (from, to int) {
+	// First transaction at or after start
+	if !start.IsZero() {
+		from = s.ordered.search(func(txn model.Transaction) bool {
+			return !txn.EffectiveAt.Before(start)
+		})
+	}
+	// First transaction after end
+	to = s.ordered.Len()
+	if !end.IsZero() {
+		to = s.ordered.search(func(txn model.Transaction) bool {
+			return txn.EffectiveAt.After(end)
+		})
+	}
+	return from, to
+}
+
+// CountByCurrency counts transactions with start <= effective_at <= end per uppercased
+// currency code, in one pass over that range of ordered under the read lock. Nothing is
+// copied, so it is cheap even over the whole store.
+func (s *MemoryStore) CountByCurrency(start, end time.Time) (map[string]int, error) {
+	s.memstoreMux.RLock()
+	defer s.memstoreMux.RUnlock()
+
+	counts := make(map[string]int)
+	from, to := s.between(start, end)
+	for txn := range s.ordered.ascend(from, to) {
+		if !txn.Deleted {
+			counts[strings.ToUpper(txn.Currency)]++
+		}
+	}
+	return counts, nil
+}
+
+// DistinctCurrencies returns the uppercased currency codes of the stored transactions, sorted
+// and deduplicated, for filter dropdowns. Soft-deleted transactions are skipped, as in
+// CountByCurrency. An empty store gives an empty, non-nil slice.
+func (s *MemoryStore) DistinctCurrencies() []string {
+	s.memstoreMux.RLock()
+	seen := make(map[string]struct{})
+	for _, txn := range s.transactions {
+		if !txn.Deleted {
+			seen[strings.ToUpper(txn.Currency)] = struct{}{}
+		}
+	}
+	s.memstoreMux.RUnlock()
+
+	currencies := make([]string, 0, len(seen))
+	for currency := range seen {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// cloneAll replaces each element with a deep copy so callers cannot mutate the store's
+// metadata maps. It is safe to call after releasing the lock because stored metadata maps are
+// never modified in place: updates always swap in a new map (see replace and UpdateMetadata).
+func cloneAll(txns []model.Transaction) {
+	for i := range txns {
+		txns[i] = txns[i].Clone()
+	}
+}
+
+// forEachChunk is how many transactions ForEach copies per read lock acquisition.
+const forEachChunk = 256
+
+// ForEach calls fn with a clone of each transaction in store order (effective_at, id),
+// soft-deleted ones included, until fn returns false.
+//
+// It never holds the lock while fn runs: it copies up to forEachChunk transactions under the
+// read lock, releases it, hands them to fn, then resumes after the last one by sort key. A slow
+// fn (say, writing to a slow client) therefore doesn't block writers, and memory stays bounded
+// by the chunk size however large the store is. The price is that the walk is not a snapshot:
+// transactions created ahead of the cursor during the walk are visited, ones created behind it
+// are not, and one whose effective_at is moved ahead of the cursor can be visited twice.
+// Transactions that exist and don't move for the whole walk are visited exactly once.
+func (s *MemoryStore) ForEach(fn func(model.Transaction) bool) error {
+	chunk := make([]model.Transaction, 0, forEachChunk)
+	var last model.Transaction // cursor; only its sort key is used
+	for started := false; ; started = true {
+		s.memstoreMux.RLock()
+		from := 0
+		if started {
+			// First element after the last one visited
+			from = s.ordered.search(func(txn model.Transaction) bool {
+				return model.LessByEffectiveAtThenID(last, txn)
+			})
+		}
+		chunk = s.ordered.appendRange(chunk[:0], from, from+forEachChunk)
+		s.memstoreMux.RUnlock()
+
+		// Only an empty chunk ends the walk: writers (fn included) may have added transactions past a short one
+		if len(chunk) == 0 {
+			return nil
+		}
+		for _, txn := range chunk {
+			if !fn(txn.Clone()) {
+				return nil
+			}
+		}
+		last = chunk[len(chunk)-1]
+	}
+}
+
+// Count returns the number of stored transactions.
+func (s *MemoryStore) Count() int {
+	s.memstoreMux.RLock()
+	defer s.memstoreMux.RUnlock()
+
+	return s.ordered.Len()
+}
+
+// Query returns clones of the transactions matching the predicate. Like List, it only copies
+// the ordered index under the read lock; matching and cloning happen after it is released.
+// The predicate sees values that share metadata maps with the store, so it must not modify them.
+func (s *MemoryStore) Query(match func(model.Transaction) bool) ([]model.Transaction, error) {
+	s.memstoreMux.RLock()
+	snapshot := s.ordered.appendRange(make([]model.Transaction, 0, s.ordered.Len()), 0, s.ordered.Len())
+	s.memstoreMux.RUnlock()
+
+	// Filter in place; the snapshot is private to this call
+	result := snapshot[:0]
+	for _, txn := range snapshot {
+		if match == nil || match(txn) {
+			result = append(result, txn)
+		}
+	}
+
+	cloneAll(result)
+	return result, nil
+}
+
+// ListPage pages through the transactions matching the predicate and counts every match, in one
+// pass over the ordered index under the read lock. Only the page is copied, so a broad filter
+// doesn't copy the rest of the store the way Query would. The predicate runs under the lock, so it
+// must be cheap and must not call back into the store; like Query's, it must not modify what it sees.
+func (s *MemoryStore) ListPage(match func(model.Transaction) bool, limit, offset int) ([]model.Transaction, int, error) {
+	offset, limit = max(offset, 0), max(limit, 0)
+	page := make([]model.Transaction, 0, min(limit, 64))
+	total := 0
+
+	s.memstoreMux.RLock()
+	for txn := range s.ordered.all() {
+		if match != nil && !match(txn) {
+			continue
+		}
+		if total >= offset && len(page) < limit {
+			page = append(page, txn)
+		}
+		total++
+	}
+	s.memstoreMux.RUnlock()
+
+	cloneAll(page)
+	return page, total, nil
+}
+
+// QueryAccount is Query restricted to one account. It walks only that account's index, so the
+// cost is proportional to the account's size rather than the whole store.
+func (s *MemoryStore) QueryAccount(accountID string, match func(model.Transaction) bool) ([]model.Transaction, error) {
+	s.memstoreMux.RLock()
+	list := s.byAccount[accountID]
+	snapshot := list.appendRange(make([]model.Transaction, 0, list.Len()), 0, list.Len())
+	s.memstoreMux.RUnlock()
+
+	// Filter in place; the snapshot is private to this call
+	result := snapshot[:0]
+	for _, txn := range snapshot {
+		if match == nil || match(txn) {
+			result = append(result, txn)
+		}
+	}
+
+	cloneAll(result)
+	return result, nil
+}
+
+// snapshot returns copies of every stored transaction, in list order, and of the Idempotency-Key
+// bindings, read under one read lock so the two are consistent.
+func (s *MemoryStore) snapshot() ([]model.Transaction, map[string]string) {
+	s.memstoreMux.RLock()
+	txns := s.ordered.appendRange(make([]model.Transaction, 0, s.ordered.Len()), 0, s.ordered.Len())
+	keys := make(map[string]string, len(s.idempotencyKeys))
+	for k, id := range s.idempotencyKeys {
+		keys[k] = id
+	}
+	s.memstoreMux.RUnlock()
+
+	cloneAll(txns)
+	return txns, keys
+}
+
+// restore replaces the store's contents with txns and keys, keeping their server-assigned Seq,
+// CreatedAt and Deleted as they are. The next Create continues after the highest Seq.
+func (s *MemoryStore) restore(txns []model.Transaction, keys map[string]string) {
+	ordered := make([]model.Transaction, len(txns))
+	for i, txn := range txns {
+		ordered[i] = txn.Clone()
+	}
+	model.SortTransactions(ordered)
+
+	s.memstoreMux.Lock()
+	defer s.memstoreMux.Unlock()
+
+	s.transactions = make(map[string]model.Transaction, len(ordered))
+	s.mirrorClear()
+	s.contentHashes = make(map[string]string, len(ordered))
+	s.ordered, s.byAccount = indexAll(ordered)
+	s.idempotencyKeys = make(map[string]string, len(keys))
+	s.lastSeq, s.softDeleted = 0, 0
+	for _, txn := range ordered {
+		s.transactions[txn.ID] = txn
+		s.mirrorPut(txn)
+		s.contentHashes[txn.ID] = s.contentHash(txn)
+		if txn.Deleted {
+			s.softDeleted++
+		}
+		s.lastSeq = max(s.lastSeq, txn.Seq)
+		if m := txn.LastModified(); m.After(s.lastModified) {
+			s.lastModified = m
+		}
+	}
+	for k, id := range keys {
+		s.idempotencyKeys[k] = id
+	}
+}
+
+// CreateWithIdempotencyKey is Create, or Upsert when upsert is set, guarded by a client
+// Idempotency-Key. Looking up the key, writing and binding the key happen under one write lock,
+// so of two requests racing with the same key exactly one writes and the other is told about
+// it, and a key is never left unbound after its write succeeded. If key is bound to txn.ID
+// and that transaction is still stored, nothing is written and a *ReplayError holding it is
+// returned. A key bound to another ID returns ErrIdempotencyKeyReused. Otherwise txn is written
+// as Create or Upsert would, and key is bound to txn.ID if that succeeded or found a duplicate.
+// created reports whether txn's ID was new.
+func (s *MemoryStore) CreateWithIdempotencyKey(key string, txn model.Transaction, upsert bool) (created bool, err error) {
+	// Outside the store lock, as in Create
+	if s.retries != nil && !upsert {
+		s.retries.observe(txn.ID, s.clock.Now())
+	}
+
+	s.memstoreMux.Lock()
+	defer s.memstoreMux.Unlock()
+
+	if id, bound := s.idempotencyKeys[key]; bound {
+		if id != txn.ID {
+			return false, ErrIdempotencyKeyReused
+		}
+		if original, ok := s.transactions[id]; ok {
+			return false, &ReplayError{Original: original.Clone()}
+		}
+		// Key recorded but transaction gone; write it again
+	}
+
+	if upsert {
+		created, err = s.upsert(txn)
+	} else {
+		err = s.create(txn)
+		created = err == nil
+	}
+	if err == nil || errors.Is(err, ErrDuplicate) {
+		s.idempotencyKeys[key] = txn.ID
+	}
+	return created, err
+}
+
+// GetByIdempotencyKey looks up the transaction ID a client Idempotency-Key was first used with.
+// It and PutIdempotencyKey are not part of Store: writes bind keys through CreateWithIdempotencyKey.
+func (s *MemoryStore) GetByIdempotencyKey(key string) (string, error) {
+	s.memstoreMux.RLock()
+	defer s.memstoreMux.RUnlock()
+
+	id, exists := s.idempotencyKeys[key]
+	if !exists {
+		return "", ErrNotFound
+	}
+	return id, nil
+}
+
+// PutIdempotencyKey binds an Idempotency-Key to a transaction ID.
+// The first binding wins: a later attempt to bind the key to a different ID returns ErrConflict.
+func (s *MemoryStore) PutIdempotencyKey(key, id string) error {
+	s.memstoreMux.Lock()
+	defer s.memstoreMux.Unlock()
+
+	if existing, exists := s.idempotencyKeys[key]; exists && existing != id {
+		return ErrConflict
+	}
+	s.idempotencyKeys[key] = id
+	return nil
+}
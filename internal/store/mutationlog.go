@@ -0,0 +1,133 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Mutation types recorded in a MutationLog.
+const (
+	MutationCreate = "create" // a new transaction was stored, by Create, Upsert or Reverse
+	MutationUpdate = "update" // a stored transaction was changed in place
+	MutationDelete = "delete" // a transaction was soft-deleted
+	MutationPurge  = "purge"  // a transaction was removed outright, by DeleteWhere or TTL expiry
+)
+
+// MutationEvent is one entry in a MutationLog.
+type MutationEvent struct {
+	At      time.Time `json:"at"`
+	Type    string    `json:"type"`
+	ID      string    `json:"id"`
+	Version int       `json:"version,omitempty"` // the transaction's version after the change; zero for purges
+}
+
+// MutationLog records every change made to a store, for auditing. Append is called under the
+// store's write lock, so entries arrive in the order the changes were applied and should be
+// cheap; History returns the entries for one transaction ID, oldest first.
+type MutationLog interface {
+	Append(event MutationEvent) error
+	History(id string) ([]MutationEvent, error)
+}
+
+// FileMutationLog is a MutationLog kept as JSON Lines in an append-only file, so the audit
+// trail survives restarts independently of the store's own persistence. History scans the
+// whole file, which is fine for occasional audit lookups but not for a hot path.
+type FileMutationLog struct {
+	path string
+	mu   sync.Mutex // serializes appends and keeps History from reading a half-written line
+	file *os.File
+}
+
+// OpenFileMutationLog opens the log at path for appending, creating it if it doesn't exist.
+// Close it when done.
+func OpenFileMutationLog(path string) (*FileMutationLog, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileMutationLog{path: path, file: file}, nil
+}
+
+// Append writes event as one line at the end of the file.
+func (l *FileMutationLog) Append(event MutationEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.file.Write(line)
+	return err
+}
+
+// History reads the file and returns the events for id in the order they were appended.
+func (l *FileMutationLog) History(id string) ([]MutationEvent, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.Open(l.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	events := []MutationEvent{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		var event MutationEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("%s line %d: %w", l.path, line, err)
+		}
+		if event.ID == id {
+			events = append(events, event)
+		}
+	}
+	return events, scanner.Err()
+}
+
+// Close closes the file. Appends after Close fail.
+func (l *FileMutationLog) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
+
+// ErrMutationLogDisabled is returned by MutationHistory when no MutationLog is attached.
+var ErrMutationLogDisabled = errors.New("mutation log is not enabled")
+
+// EnableMutationLog makes the store append an event to log for every create, update, soft
+// delete and purge. Restoring a FileStore snapshot and Reset are not logged. A nil log
+// disables logging. Call it before the store is in use.
+func (s *MemoryStore) EnableMutationLog(log MutationLog) {
+	s.mutations = log
+}
+
+// MutationHistory returns the logged changes to the transaction with the given ID, oldest
+// first, or ErrMutationLogDisabled if the store has no MutationLog.
+func (s *MemoryStore) MutationHistory(id string) ([]MutationEvent, error) {
+	if s.mutations == nil {
+		return nil, ErrMutationLogDisabled
+	}
+	return s.mutations.History(id)
+}
+
+// logMutation appends an event to the mutation log, if any. The change has already been
+// applied by then, so a failed append is logged rather than returned. Callers must hold the
+// write lock.
+func (s *MemoryStore) logMutation(kind, id string, version int) {
+	if s.mutations == nil {
+		return
+	}
+	event := MutationEvent{At: s.clock.Now().UTC(), Type: kind, ID: id, Version: version}
+	if err := s.mutations.Append(event); err != nil {
+		slog.Error("append to mutation log failed", "type", kind, "id", id, "error", err)
+	}
+}
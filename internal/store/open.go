@@ -0,0 +1,110 @@
+package store
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/clock"
+)
+
+// DefaultDSN selects the in-process MemoryStore.
+const DefaultDSN = "memory://"
+
+// Options configure the store Open returns. The zero value matches NewMemoryStore.
+type Options struct {
+	Clock clock.Clock // source of CreatedAt; defaults to clock.Real
+
+	// TTL and SweepEvery are passed to NewMemoryStoreWithTTL when TTL > 0.
+	// SweepEvery defaults to min(TTL, time.Minute).
+	TTL, SweepEvery time.Duration
+
+	// Capacity caps the number of stored transactions (see NewMemoryStoreWithCapacity). Zero is unlimited.
+	Capacity int
+
+	// ExcludeDescriptionFromIdempotency calls MemoryStore.ExcludeDescriptionFromIdempotency.
+	ExcludeDescriptionFromIdempotency bool
+
+	// Comparator decides duplicate vs conflict in Create (see NewMemoryStoreWithComparator and
+	// SignificantFields). Nil compares every field.
+	Comparator Comparator
+
+	// RetryAlarmThreshold and RetryAlarmWindow are passed to EnableRetryAlarm when both are > 0,
+	// with RetryAlarmLogger (slog.Default if nil).
+	RetryAlarmThreshold int
+	RetryAlarmWindow    time.Duration
+	RetryAlarmLogger    *slog.Logger
+
+	// MutationLog is passed to EnableMutationLog. Nil disables the audit trail.
+	MutationLog MutationLog
+
+	// LockFreeGets calls MemoryStore.EnableLockFreeGets.
+	LockFreeGets bool
+}
+
+// Open returns the Store selected by dsn with default Options. See OpenWithOptions.
+func Open(dsn string) (Store, error) {
+	return OpenWithOptions(dsn, Options{})
+}
+
+// OpenWithOptions returns the Store selected by dsn's scheme, so the backend can be picked at
+// deploy time without code changes:
+//
+//	memory://                 in-process MemoryStore; data is lost on restart
+//	file:///var/lib/txns.json MemoryStore persisted to a JSON snapshot file (see FileStore)
+//
+// Any other scheme is an error. The returned store may need closing (see MemoryStore.Close).
+func OpenWithOptions(dsn string, opts Options) (Store, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid store DSN %q: %w", dsn, err)
+	}
+
+	switch u.Scheme {
+	case "memory":
+		return newMemoryStore(opts), nil
+	case "file":
+		if u.Path == "" {
+			return nil, fmt.Errorf("invalid store DSN %q: file:// needs a path, e.g. file:///var/lib/txns.json", dsn)
+		}
+		mem := newMemoryStore(opts)
+		fs, err := OpenFileStore(u.Path, mem)
+		if err != nil {
+			mem.Close() // stop the TTL sweeper, if any
+			return nil, err
+		}
+		return fs, nil
+	default:
+		return nil, fmt.Errorf("unsupported store DSN scheme %q (want memory or file)", u.Scheme)
+	}
+}
+
+// newMemoryStore builds the MemoryStore described by opts.
+func newMemoryStore(opts Options) *MemoryStore {
+	c := opts.Clock
+	if c == nil {
+		c = clock.Real{}
+	}
+	var s *MemoryStore
+	if opts.TTL <= 0 {
+		s = NewMemoryStoreWithClock(c)
+	} else {
+		every := opts.SweepEvery
+		if every <= 0 {
+			every = min(opts.TTL, time.Minute)
+		}
+		s = NewMemoryStoreWithTTL(c, opts.TTL, every)
+	}
+	s.capacity = opts.Capacity
+	s.comparator = opts.Comparator
+	if opts.ExcludeDescriptionFromIdempotency {
+		s.ExcludeDescriptionFromIdempotency()
+	}
+	s.EnableRetryAlarm(opts.RetryAlarmThreshold, opts.RetryAlarmWindow, opts.RetryAlarmLogger)
+	s.EnableMutationLog(opts.MutationLog)
+	if opts.LockFreeGets {
+		s.EnableLockFreeGets()
+	}
+	return s
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// retryAlarm watches for one transaction ID being created over and over, the signature of a
+// client stuck in a retry loop. It keeps, per ID, the timestamps of the most recent creates
+// within the window, at most threshold+1 of them, which is all it takes to tell whether more
+// than threshold arrived within the window.
+type retryAlarm struct {
+	threshold int
+	window    time.Duration
+	logger    *slog.Logger
+
+	mu         sync.Mutex
+	recent     map[string][]time.Time // ID -> recent create times, oldest first
+	lastPruned time.Time
+}
+
+// EnableRetryAlarm makes Create log a warning through logger (slog.Default if nil) when more
+// than threshold creates for the same ID arrive within window, whatever their outcome. It warns
+// once as the count crosses the threshold, not on every create after it, and can warn again
+// once the rate has dropped back. Tracking is bounded: IDs without a create in the last window
+// are dropped at most once per window. A threshold or window <= 0 disables the alarm.
+// Call it before the store is in use.
+func (s *MemoryStore) EnableRetryAlarm(threshold int, window time.Duration, logger *slog.Logger) {
+	if threshold <= 0 || window <= 0 {
+		s.retries = nil
+		return
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	s.retries = &retryAlarm{threshold: threshold, window: window, logger: logger, recent: make(map[string][]time.Time)}
+}
+
+// observe records a create of id at now and logs when it takes the ID over the threshold.
+func (a *retryAlarm) observe(id string, now time.Time) {
+	a.mu.Lock()
+	cutoff := now.Add(-a.window)
+	if now.Sub(a.lastPruned) >= a.window {
+		a.prune(cutoff)
+		a.lastPruned = now
+	}
+
+	times := a.recent[id]
+	for len(times) > 0 && !times[0].After(cutoff) {
+		times = times[1:]
+	}
+	crossed := len(times) == a.threshold
+	times = append(times, now)
+	if len(times) > a.threshold+1 {
+		times = times[1:]
+	}
+	a.recent[id] = times
+	a.mu.Unlock()
+
+	if crossed {
+		a.logger.Warn("transaction created repeatedly; possible client retry storm",
+			"id", id,
+			"creates", a.threshold+1,
+			"window", a.window.String())
+	}
+}
+
+// prune drops IDs whose latest create is at or before cutoff. Callers must hold a.mu.
+func (a *retryAlarm) prune(cutoff time.Time) {
+	for id, times := range a.recent {
+		if !times[len(times)-1].After(cutoff) {
+			delete(a.recent, id)
+		}
+	}
+}
@@ -1,14 +1,94 @@
 package store
 
 import (
+	"time"
+
 	"github.com/synctera/tech-challenge/internal/model"
 )
 
 // Store defines the interface for transaction storage.
 type Store interface {
+	// Create stores a transaction under a new ID. Returns ErrDuplicate or a *ConflictError
+	// (matching ErrConflict) if the ID is taken, and ErrCapacityExceeded if a new ID would
+	// exceed the store's capacity.
 	Create(txn model.Transaction) error
 	Get(id string) (model.Transaction, error)
+	// GetMany returns the transactions stored under ids and the IDs that were not found,
+	// read as one consistent snapshot.
+	GetMany(ids []string) (found []model.Transaction, missing []string, err error)
 	List(limit, offset int) ([]model.Transaction, error)
+	// Count returns the number of stored transactions.
+	Count() int
+
+	// Query returns every transaction for which match returns true, in list order.
+	// A nil match returns everything. Unlike List it has no row cap, so filters never drop matches.
+	Query(match func(model.Transaction) bool) ([]model.Transaction, error)
+	// ListPage returns the transactions for which match returns true, in list order, skipping the
+	// first offset matches and returning at most limit, along with the total number of matches.
+	// A nil match matches everything. Page and total come from one consistent pass.
+	ListPage(match func(model.Transaction) bool, limit, offset int) (page []model.Transaction, total int, err error)
+	// QueryAccount is Query restricted to transactions with the given AccountID.
+	QueryAccount(accountID string, match func(model.Transaction) bool) ([]model.Transaction, error)
+	// ListBetween pages through transactions with start <= effective_at <= end in list order,
+	// skipping soft-deleted ones. A zero start or end leaves that side open.
+	ListBetween(start, end time.Time, limit, offset int) ([]model.Transaction, error)
+	// CountByCurrency counts transactions with start <= effective_at <= end by uppercased
+	// currency code, skipping soft-deleted ones. A zero start or end leaves that side open.
+	CountByCurrency(start, end time.Time) (map[string]int, error)
+	// DistinctCurrencies returns the sorted, deduplicated uppercased currency codes of the
+	// stored transactions, skipping soft-deleted ones.
+	DistinctCurrencies() []string
+
+	// ForEach calls fn with each transaction in store order, soft-deleted ones included,
+	// stopping early when fn returns false. Unlike Query it doesn't build the whole result.
+	ForEach(fn func(model.Transaction) bool) error
+
+	// Upsert is Create with create-or-replace semantics: an existing transaction with a different
+	// payload is replaced instead of reported as ErrConflict, keeping its server-maintained
+	// metadata and recording an amount change in amount_history. created reports whether the ID
+	// was new. Returns ErrInvalidAmountHistory if the stored history can't be appended to.
+	Upsert(txn model.Transaction) (created bool, err error)
+
+	// CompareAndSwap replaces the transaction stored under id with newTxn only if the
+	// stored value currently equals expected. Returns ErrPreconditionFailed if it changed.
+	CompareAndSwap(id string, expected, newTxn model.Transaction) error
+
+	// UpdateMetadata merges patch into the metadata of the transaction stored under id
+	// (see model.MergeMetadata). A non-nil empty patch clears the metadata instead, keeping
+	// only the server-maintained keys (see model.ClearMetadata). Returns ErrNotFound if id is
+	// unknown, and ErrTooManyMetadataEntries, writing nothing, if the merged metadata would
+	// exceed model.MaxMetadataEntries.
+	UpdateMetadata(id string, patch map[string]*string) error
+	// UpdateMetadataIfVersion is UpdateMetadata, but only if the stored Version still equals
+	// version. Returns ErrPreconditionFailed if the transaction changed in the meantime.
+	UpdateMetadataIfVersion(id string, version int, patch map[string]*string) error
+
+	// UpdateAmount sets the amount of the transaction stored under id, recording the prior
+	// amount in metadata[amount_history]. Returns ErrNotFound if id is unknown and
+	// ErrInvalidAmountHistory if the stored history can't be appended to.
+	UpdateAmount(id string, amount int64) error
+	// UpdateAmountIfVersion is UpdateAmount, but only if the stored Version still equals
+	// version. Returns ErrPreconditionFailed if the transaction changed in the meantime.
+	UpdateAmountIfVersion(id string, version int, amount int64) error
+
+	// Reverse atomically stores reversal and records its ID under the original's
+	// metadata[reversed_by]. Returns ErrNotFound, ErrAlreadyReversed, or ErrConflict (reversal ID taken).
+	Reverse(originalID string, reversal model.Transaction) error
+
+	// Delete soft-deletes the transaction stored under id by setting its Deleted flag; the record
+	// is kept. Deleting an already deleted transaction is a no-op. Returns ErrNotFound if id is unknown.
+	Delete(id string) error
+
+	// DeleteWhere removes every transaction for which match returns true and returns how
+	// many were removed. A nil match deletes nothing.
+	DeleteWhere(match func(model.Transaction) bool) (int, error)
+
+	// CreateWithIdempotencyKey is Create, or Upsert when upsert is set, guarded by a client
+	// Idempotency-Key, with the key check, the write and the key binding done atomically.
+	// Returns a *ReplayError (matching ErrIdempotentReplay) holding the stored transaction if key
+	// is already bound to txn.ID, ErrIdempotencyKeyReused if it is bound to another ID, and
+	// otherwise what Create or Upsert would. created reports whether txn's ID was new.
+	CreateWithIdempotencyKey(key string, txn model.Transaction, upsert bool) (created bool, err error)
 }
 
 // Common errors.
@@ -20,4 +100,42 @@ const (
 	ErrNotFound  StoreError = "transaction not found"
 	ErrConflict  StoreError = "conflict"
 	ErrDuplicate StoreError = "duplicate"
+
+	ErrPreconditionFailed StoreError = "transaction changed since it was read"
+	ErrIDMismatch         StoreError = "transaction ID does not match"
+	ErrAlreadyReversed    StoreError = "transaction already reversed"
+	ErrCapacityExceeded   StoreError = "store is at capacity"
+	// ErrIdempotentReplay is matched by the *ReplayError CreateWithIdempotencyKey returns.
+	ErrIdempotentReplay     StoreError = "idempotency key already used for this transaction"
+	ErrIdempotencyKeyReused StoreError = "idempotency key already used for a different transaction"
+	// ErrInvalidAmountHistory is returned by UpdateAmount when the stored amount_history can't be
+	// parsed, so appending to it would overwrite the trail.
+	ErrInvalidAmountHistory StoreError = "stored amount_history is not a valid list"
+	// ErrTooManyMetadataEntries is returned by UpdateMetadata when the merged metadata would
+	// have more than model.MaxMetadataEntries keys.
+	ErrTooManyMetadataEntries StoreError = "metadata has too many entries"
 )
+
+// ConflictError is the ErrConflict that Create returns when the ID is taken by a transaction
+// with different data. It matches ErrConflict with errors.Is, and carries a copy of the stored
+// transaction so callers can report what differs without a second, racy Get.
+type ConflictError struct {
+	Existing model.Transaction
+}
+
+func (e *ConflictError) Error() string { return string(ErrConflict) }
+
+// Is makes errors.Is(err, ErrConflict) hold.
+func (e *ConflictError) Is(target error) bool { return target == ErrConflict }
+
+// ReplayError is the ErrIdempotentReplay that CreateWithIdempotencyKey returns when the key
+// was already used to store the same ID. It carries a copy of the stored transaction, which
+// the caller answers with instead of the retried payload.
+type ReplayError struct {
+	Original model.Transaction
+}
+
+func (e *ReplayError) Error() string { return string(ErrIdempotentReplay) }
+
+// Is makes errors.Is(err, ErrIdempotentReplay) hold.
+func (e *ReplayError) Is(target error) bool { return target == ErrIdempotentReplay }
@@ -0,0 +1,57 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// broadcaster fans newly created transactions out to subscribers. Sends never block: the
+// store publishes under its write lock, so one stalled reader must not hold up ingestion.
+type broadcaster struct {
+	mu   sync.Mutex
+	subs map[chan model.Transaction]struct{}
+}
+
+// Subscribe returns a channel that receives every transaction created from now on (by Create,
+// a new ID in Upsert, Reverse or an import), in creation order, and a cancel function that
+// unsubscribes. buffer is how many events the channel holds; a subscriber that falls that far
+// behind is dropped and its channel closed, so it finds out it missed events instead of silently
+// skipping them. Cancel closes the channel too and is safe to call more than once.
+func (s *MemoryStore) Subscribe(buffer int) (<-chan model.Transaction, func()) {
+	ch := make(chan model.Transaction, max(buffer, 1))
+	b := &s.subscribers
+	b.mu.Lock()
+	if b.subs == nil {
+		b.subs = make(map[chan model.Transaction]struct{})
+	}
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.drop(ch)
+	}
+}
+
+// publish sends txn to every subscriber, dropping those whose buffer is full.
+func (b *broadcaster) publish(txn model.Transaction) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs {
+		select {
+		case ch <- txn.Clone():
+		default:
+			b.drop(ch)
+		}
+	}
+}
+
+// drop unsubscribes ch and closes it, unless that already happened. Callers must hold b.mu.
+func (b *broadcaster) drop(ch chan model.Transaction) {
+	if _, ok := b.subs[ch]; ok {
+		delete(b.subs, ch)
+		close(ch)
+	}
+}
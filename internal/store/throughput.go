@@ -0,0 +1,76 @@
+package store
+
+import (
+	"sync"
+	"time"
+)
+
+// throughputSpan is the longest window Throughput reports; older creates are evicted.
+const throughputSpan = 15 * time.Minute
+
+// Throughput counts transactions stored within the trailing 1, 5 and 15 minutes, for a quick
+// sense of ingestion rate without a metrics stack.
+type Throughput struct {
+	LastMinute         uint64 `json:"1m"`
+	LastFiveMinutes    uint64 `json:"5m"`
+	LastFifteenMinutes uint64 `json:"15m"`
+}
+
+// throughputRing counts creates per second in a ring of one bucket per second of
+// throughputSpan, so memory stays fixed however fast transactions arrive. A bucket is reused
+// once its second is more than throughputSpan old, which is what evicts old creates.
+type throughputRing struct {
+	mu      sync.Mutex
+	buckets [throughputSpan / time.Second]struct {
+		second int64 // Unix second the count belongs to
+		count  uint64
+	}
+}
+
+// record counts one create at now.
+func (r *throughputRing) record(now time.Time) {
+	sec := now.Unix()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b := &r.buckets[r.index(sec)]
+	if b.second != sec {
+		b.second, b.count = sec, 0
+	}
+	b.count++
+}
+
+// snapshot sums the buckets that fall within each window ending at now.
+func (r *throughputRing) snapshot(now time.Time) Throughput {
+	sec := now.Unix()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var t Throughput
+	for _, b := range r.buckets {
+		age := sec - b.second
+		if b.count == 0 || age < 0 || age >= int64(throughputSpan/time.Second) {
+			continue
+		}
+		if age < 60 {
+			t.LastMinute += b.count
+		}
+		if age < 5*60 {
+			t.LastFiveMinutes += b.count
+		}
+		t.LastFifteenMinutes += b.count
+	}
+	return t
+}
+
+func (r *throughputRing) index(sec int64) int64 {
+	n := int64(len(r.buckets))
+	return (sec%n + n) % n
+}
+
+// Throughput returns how many transactions were stored in the trailing 1, 5 and 15 minutes by
+// the store's clock, to the second. Every new transaction counts, including upserted IDs and
+// reversals, but duplicates and conflicts do not. Like Stats, it is not cleared by Reset.
+func (s *MemoryStore) Throughput() Throughput {
+	return s.throughput.snapshot(s.clock.Now())
+}
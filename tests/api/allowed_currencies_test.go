@@ -0,0 +1,75 @@
+package api_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+)
+
+func currencyTxn(id, currency string) string {
+	return fmt.Sprintf(`{"id":%q,"account_id":"acct-1","amount":100,"currency":%q,"direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`, id, currency)
+}
+
+// Test: TestCreateTransaction_allowedCurrencies
+// What: with an allow-list, listed currencies are accepted in any case and others are
+// rejected with a message naming the allowed set
+// Input: AllowedCurrencies=[USD, CAD]; creates in USD, cad and EUR
+// Output: 201, 201, then 400 whose body mentions EUR and "USD, CAD"
+func TestCreateTransaction_allowedCurrencies(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.AllowedCurrencies = []string{"USD", "CAD"}
+	srv := newTestServerWithConfig(t, cfg)
+
+	for i, currency := range []string{"USD", "cad"} {
+		resp := postTxn(t, srv, currencyTxn(fmt.Sprintf("txn-%d", i), currency))
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("%s: expected 201, got %d", currency, resp.StatusCode)
+		}
+	}
+
+	resp := postTxn(t, srv, currencyTxn("txn-eur", "EUR"))
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("EUR: expected 400, got %d", resp.StatusCode)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "EUR") || !strings.Contains(string(body), "USD, CAD") {
+		t.Errorf("expected the error to name EUR and the allowed set, got %q", body)
+	}
+}
+
+// Test: TestCreateTransaction_noAllowedCurrencies
+// What: without an allow-list every currency is accepted
+// Input: the default config; creates in USD, EUR and JPY
+// Output: 201 for each
+func TestCreateTransaction_noAllowedCurrencies(t *testing.T) {
+	srv := newTestServer(t)
+	for _, currency := range []string{"USD", "EUR", "JPY"} {
+		resp := postTxn(t, srv, currencyTxn("txn-"+currency, currency))
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("%s: expected 201, got %d", currency, resp.StatusCode)
+		}
+	}
+}
+
+// Test: TestValidateCurrencyAllowed
+// What: the allow-list check ignores case and an empty list allows anything
+// Input: usd against [USD], GBP against [USD], GBP against nil
+// Output: nil, an error, nil
+func TestValidateCurrencyAllowed(t *testing.T) {
+	if err := api.ValidateCurrencyAllowed("usd", []string{"USD"}); err != nil {
+		t.Errorf("usd against [USD]: unexpected error %v", err)
+	}
+	if err := api.ValidateCurrencyAllowed("GBP", []string{"USD"}); err == nil {
+		t.Error("GBP against [USD]: expected an error")
+	}
+	if err := api.ValidateCurrencyAllowed("GBP", nil); err != nil {
+		t.Errorf("GBP against nil: unexpected error %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+)
+
+// Test: TestAmountUnitHeader_defaultMinor
+// What: by default, get, list, and create responses declare amounts in minor units
+// Input: default handler; POST, GET /transactions, GET /transactions/{id}
+// Output: X-Amount-Unit: minor on every response
+func TestAmountUnitHeader_defaultMinor(t *testing.T) {
+	srv := newTestServer(t)
+
+	create := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	create.Body.Close()
+	list := getTxns(t, srv, "")
+	list.Body.Close()
+	get := getTxnByID(t, srv, "txn-1")
+	get.Body.Close()
+
+	for name, resp := range map[string]*http.Response{"create": create, "list": list, "get": get} {
+		if got := resp.Header.Get("X-Amount-Unit"); got != api.AmountUnitMinor {
+			t.Errorf("%s: expected X-Amount-Unit %q, got %q", name, api.AmountUnitMinor, got)
+		}
+	}
+}
+
+// Test: TestAmountUnitHeader_disabled
+// What: an empty AmountUnit omits the header
+// Input: handler configured with AmountUnit=""; GET /transactions and GET /transactions/{id}
+// Output: no X-Amount-Unit header
+func TestAmountUnitHeader_disabled(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.AmountUnit = ""
+	srv := newTestServerWithConfig(t, cfg)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+
+	list := getTxns(t, srv, "")
+	list.Body.Close()
+	get := getTxnByID(t, srv, "txn-1")
+	get.Body.Close()
+
+	if got := list.Header.Get("X-Amount-Unit"); got != "" {
+		t.Errorf("list: expected no X-Amount-Unit header, got %q", got)
+	}
+	if got := get.Header.Get("X-Amount-Unit"); got != "" {
+		t.Errorf("get: expected no X-Amount-Unit header, got %q", got)
+	}
+}
+
+// Test: TestAmountUnitHeader_notOnErrors
+// What: error responses don't carry the amount unit header since they contain no amounts
+// Input: GET /transactions/missing
+// Output: HTTP 404 without X-Amount-Unit
+func TestAmountUnitHeader_notOnErrors(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := getTxnByID(t, srv, "missing")
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("X-Amount-Unit"); got != "" {
+		t.Errorf("expected no X-Amount-Unit header on error, got %q", got)
+	}
+}
@@ -0,0 +1,95 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+func newAuditedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	log, err := store.OpenFileMutationLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatalf("OpenFileMutationLog failed: %v", err)
+	}
+	t.Cleanup(func() { log.Close() })
+	s := store.NewMemoryStore()
+	s.EnableMutationLog(log)
+
+	mux := http.NewServeMux()
+	api.NewHandler(s).RegisterRoutes(mux, nil)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// Test: TestAudit_createAndDelete
+// What: GET /audit lists a transaction's create and delete, oldest first
+// Input: POST txn-1, DELETE txn-1, GET /audit?id=txn-1
+// Output: HTTP 200, application/json, [create v1, delete v2]
+func TestAudit_createAndDelete(t *testing.T) {
+	srv := newAuditedServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	deleteTxn(t, srv, "txn-1").Body.Close()
+
+	resp, err := http.Get(srv.URL + "/audit?id=txn-1")
+	if err != nil {
+		t.Fatalf("GET /audit failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json, got %q", ct)
+	}
+
+	var events []struct {
+		Type    string `json:"type"`
+		ID      string `json:"id"`
+		Version int    `json:"version"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&events); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(events) != 2 || events[0].Type != "create" || events[0].Version != 1 ||
+		events[1].Type != "delete" || events[1].Version != 2 {
+		t.Errorf("expected create v1 then delete v2, got %+v", events)
+	}
+}
+
+// Test: TestAudit_errors
+// What: GET /audit needs an id and a store with a mutation log; unknown IDs get an empty list
+// Input: GET /audit without id; GET /audit?id=nope; GET /audit?id=txn-1 on a server without a log
+// Output: 400; 200 with []; 501
+func TestAudit_errors(t *testing.T) {
+	srv := newAuditedServer(t)
+	for _, tc := range []struct {
+		url    string
+		status int
+		body   string
+	}{
+		{srv.URL + "/audit", http.StatusBadRequest, ""},
+		{srv.URL + "/audit?id=nope", http.StatusOK, "[]\n"},
+		{newTestServer(t).URL + "/audit?id=txn-1", http.StatusNotImplemented, ""},
+	} {
+		resp, err := http.Get(tc.url)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", tc.url, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != tc.status {
+			t.Errorf("%s: expected %d, got %d", tc.url, tc.status, resp.StatusCode)
+		}
+		if tc.body != "" && string(body) != tc.body {
+			t.Errorf("%s: expected body %q, got %q", tc.url, tc.body, body)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+)
+
+func postValidate(t *testing.T, srv *httptest.Server, body string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(srv.URL+"/transactions/_validate", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST /transactions/_validate failed: %v", err)
+	}
+	return resp
+}
+
+// Test: TestValidateTransactions_perIndexResults
+// What: each array element gets its own result, in order, and nothing is stored
+// Input: [valid, missing currency, bad direction, valid with +02:00 offset]
+// Output: total 4, valid 2; results 0 and 3 valid, 1 and 2 invalid with the validation
+// message; the store is still empty
+func TestValidateTransactions_perIndexResults(t *testing.T) {
+	srv := newTestServer(t)
+	body := `[
+		{"id":"a","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"},
+		{"id":"b","account_id":"acct-1","amount":100,"direction":"debit","effective_at":"2024-01-01T00:00:00Z"},
+		{"id":"c","account_id":"acct-1","amount":100,"currency":"USD","direction":"sideways","effective_at":"2024-01-01T00:00:00Z"},
+		{"id":"d","account_id":"acct-1","amount":5,"currency":"EUR","direction":"credit","effective_at":"2024-01-01T02:00:00+02:00"}
+	]`
+
+	var report api.BatchValidationReport
+	json.Unmarshal(readBody(t, postValidate(t, srv, body)), &report)
+	if report.Total != 4 || report.Valid != 2 || len(report.Results) != 4 {
+		t.Fatalf("expected 4 results with 2 valid, got %+v", report)
+	}
+	for i, want := range []bool{true, false, false, true} {
+		got := report.Results[i]
+		if got.Index != i || got.Valid != want {
+			t.Errorf("result %d: expected index %d valid=%v, got %+v", i, i, want, got)
+		}
+		if want != (got.Error == "") {
+			t.Errorf("result %d: error %q does not match valid=%v", i, got.Error, want)
+		}
+	}
+	if !strings.Contains(report.Results[1].Error, "currency") {
+		t.Errorf("expected result 1 to name currency, got %q", report.Results[1].Error)
+	}
+
+	var stored []json.RawMessage
+	json.Unmarshal(readBody(t, getTxns(t, srv, "")), &stored)
+	if len(stored) != 0 {
+		t.Errorf("expected nothing stored, got %d transactions", len(stored))
+	}
+}
+
+// Test: TestValidateTransactions_usesHandlerPolicy
+// What: the handler's currency allow-list applies, as it would on create
+// Input: AllowedCurrencies=[USD]; an array of one EUR transaction
+// Output: one invalid result naming the allowed set
+func TestValidateTransactions_usesHandlerPolicy(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.AllowedCurrencies = []string{"USD"}
+	srv := newTestServerWithConfig(t, cfg)
+
+	var report api.BatchValidationReport
+	json.Unmarshal(readBody(t, postValidate(t, srv, `[{"id":"a","account_id":"acct-1","amount":1,"currency":"EUR","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}]`)), &report)
+	if len(report.Results) != 1 || report.Results[0].Valid || !strings.Contains(report.Results[0].Error, "allowed: USD") {
+		t.Errorf("expected EUR rejected by the allow-list, got %+v", report)
+	}
+}
+
+// Test: TestValidateTransactions_badBody
+// What: a body that isn't a well-formed JSON array is a 400
+// Input: an object, a truncated array, an array with malformed JSON; then []
+// Output: 400, 400, 400; then 200 with total 0 and an empty results array
+func TestValidateTransactions_badBody(t *testing.T) {
+	srv := newTestServer(t)
+	for _, body := range []string{`{"id":"a"}`, `[{"id":"a"}`, `[{"id":}]`} {
+		resp := postValidate(t, srv, body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", body, resp.StatusCode)
+		}
+	}
+	if got := string(readBody(t, postValidate(t, srv, `[]`))); !strings.Contains(got, `"results":[]`) {
+		t.Errorf("expected an empty results array, got %s", got)
+	}
+}
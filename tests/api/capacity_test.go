@@ -0,0 +1,66 @@
+package api_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+func newCapacityServer(t *testing.T, capacity int) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	api.NewHandler(store.NewMemoryStoreWithCapacity(capacity)).RegisterRoutes(mux, nil)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// Test: TestCreateTransaction_storeFull
+// What: a create that would exceed the store capacity is rejected with 507, while a retry of a stored transaction still succeeds
+// Input: capacity 1; POST txn-1, POST txn-2, POST txn-1 again
+// Output: 201, 507, 200
+func TestCreateTransaction_storeFull(t *testing.T) {
+	srv := newCapacityServer(t, 1)
+	first := `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`
+	second := `{"id":"txn-2","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`
+
+	for i, tc := range []struct {
+		body string
+		want int
+	}{{first, http.StatusCreated}, {second, http.StatusInsufficientStorage}, {first, http.StatusOK}} {
+		resp := postTxn(t, srv, tc.body)
+		resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Errorf("request %d: expected %d, got %d", i+1, tc.want, resp.StatusCode)
+		}
+	}
+}
+
+// Test: TestImportTransactions_storeFull
+// What: an import that fills the store stops with 507, naming the line and keeping the lines before it
+// Input: capacity 2; NDJSON with txn-1, txn-2, txn-3
+// Output: 507 naming line 3 and 2 imported; txn-2 is stored
+func TestImportTransactions_storeFull(t *testing.T) {
+	srv := newCapacityServer(t, 2)
+	body := `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}
+{"id":"txn-2","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}
+{"id":"txn-3","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-03T00:00:00Z"}
+`
+	resp := postImport(t, srv, api.ContentTypeNDJSON, body)
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusInsufficientStorage || !strings.Contains(string(msg), "line 3") || !strings.Contains(string(msg), "2 transactions") {
+		t.Errorf("expected 507 naming line 3 and 2 imported, got %d %q", resp.StatusCode, msg)
+	}
+
+	get := getTxnByID(t, srv, "txn-2")
+	get.Body.Close()
+	if get.StatusCode != http.StatusOK {
+		t.Errorf("expected txn-2 to be stored, got %d", get.StatusCode)
+	}
+}
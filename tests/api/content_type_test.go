@@ -0,0 +1,68 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+)
+
+const contentTypeTxn = `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`
+
+func postWithContentType(t *testing.T, srv *httptest.Server, contentType string) int {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/transactions", strings.NewReader(contentTypeTxn))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST /transactions failed: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// Test: TestCreateTransaction_contentType
+// What: create accepts only JSON content types, with or without parameters
+// Input: a valid body sent as text/plain, form data, a malformed header, no header,
+// application/json, and application/json; charset=utf-8
+// Output: 415, 415, 415, 415, then 201 and 200 (a duplicate of the first create)
+func TestCreateTransaction_contentType(t *testing.T) {
+	srv := newTestServer(t)
+	for _, tc := range []struct {
+		contentType string
+		want        int
+	}{
+		{"text/plain", http.StatusUnsupportedMediaType},
+		{"application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"application/json;;", http.StatusUnsupportedMediaType},
+		{"", http.StatusUnsupportedMediaType},
+		{"application/json", http.StatusCreated},
+		{"Application/JSON; charset=utf-8", http.StatusOK},
+	} {
+		if got := postWithContentType(t, srv, tc.contentType); got != tc.want {
+			t.Errorf("Content-Type %q: expected %d, got %d", tc.contentType, tc.want, got)
+		}
+	}
+}
+
+// Test: TestCreateTransaction_allowMissingContentType
+// What: with AllowMissingContentType, a create without a Content-Type is decoded as JSON,
+// but a wrong one is still rejected
+// Input: AllowMissingContentType=true; a valid body with no header, then with text/plain
+// Output: 201, then 415
+func TestCreateTransaction_allowMissingContentType(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.AllowMissingContentType = true
+	srv := newTestServerWithConfig(t, cfg)
+
+	if got := postWithContentType(t, srv, ""); got != http.StatusCreated {
+		t.Errorf("no Content-Type: expected 201, got %d", got)
+	}
+	if got := postWithContentType(t, srv, "text/plain"); got != http.StatusUnsupportedMediaType {
+		t.Errorf("text/plain: expected 415, got %d", got)
+	}
+}
@@ -0,0 +1,113 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func getCounts(t *testing.T, srv *httptest.Server, query string) (int, map[string]int) {
+	t.Helper()
+	resp, err := http.Get(srv.URL + "/transactions/counts?" + query)
+	if err != nil {
+		t.Fatalf("GET /transactions/counts failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var counts map[string]int
+	if resp.StatusCode == http.StatusOK {
+		if err := json.NewDecoder(resp.Body).Decode(&counts); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+	}
+	return resp.StatusCode, counts
+}
+
+// Test: TestTransactionCounts_byCurrency
+// What: GET /transactions/counts returns the number of transactions per currency
+// Input: 3 USD and 2 EUR transactions
+// Output: HTTP 200, {"USD":3,"EUR":2}
+func TestTransactionCounts_byCurrency(t *testing.T) {
+	srv := newTestServer(t)
+	seedN(t, srv, 3, "USD")
+	seedN(t, srv, 2, "EUR")
+
+	status, counts := getCounts(t, srv, "")
+	if status != http.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if len(counts) != 2 || counts["USD"] != 3 || counts["EUR"] != 2 {
+		t.Errorf(`expected {"USD":3,"EUR":2}, got %v`, counts)
+	}
+}
+
+// Test: TestTransactionCounts_dateFiltered
+// What: start_date and end_date limit the counts, with end_date inclusive
+// Input: USD on Jan 1, USD and EUR on Jan 2 (late in the day), EUR on Jan 3; start_date=end_date=2024-01-02
+// Output: {"USD":1,"EUR":1}
+func TestTransactionCounts_dateFiltered(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"a","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T12:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"b","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"c","account_id":"acct-1","amount":100,"currency":"EUR","direction":"debit","effective_at":"2024-01-02T23:59:59Z"}`)
+	seedTxn(t, srv, `{"id":"d","account_id":"acct-1","amount":100,"currency":"EUR","direction":"debit","effective_at":"2024-01-03T12:00:00Z"}`)
+
+	_, counts := getCounts(t, srv, "start_date=2024-01-02&end_date=2024-01-02")
+	if len(counts) != 2 || counts["USD"] != 1 || counts["EUR"] != 1 {
+		t.Errorf(`expected {"USD":1,"EUR":1}, got %v`, counts)
+	}
+}
+
+// Test: TestTransactionCounts_emptyStore
+// What: an empty store returns an empty JSON object, not null
+// Input: no transactions
+// Output: HTTP 200, body {}
+func TestTransactionCounts_emptyStore(t *testing.T) {
+	srv := newTestServer(t)
+
+	status, counts := getCounts(t, srv, "")
+	if status != http.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if counts == nil || len(counts) != 0 {
+		t.Errorf("expected {}, got %#v", counts)
+	}
+}
+
+// Test: TestTransactionCounts_invalidDate
+// What: date filters are validated like on the list endpoint
+// Input: start_date=yesterday
+// Output: HTTP 400
+func TestTransactionCounts_invalidDate(t *testing.T) {
+	srv := newTestServer(t)
+
+	if status, _ := getCounts(t, srv, "start_date=yesterday"); status != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", status)
+	}
+}
+
+// Test: TestTransactionCurrencies
+// What: GET /transactions/currencies returns the distinct currencies sorted, and [] for an empty store
+// Input: an empty store; then 2 USD, 1 EUR and 1 JPY transactions
+// Output: HTTP 200 [] ; then HTTP 200 ["EUR","JPY","USD"]
+func TestTransactionCurrencies(t *testing.T) {
+	srv := newTestServer(t)
+
+	if body := strings.TrimSpace(getBody(t, srv.URL+"/transactions/currencies")); body != "[]" {
+		t.Errorf("expected [], got %s", body)
+	}
+
+	seedN(t, srv, 2, "USD")
+	seedN(t, srv, 1, "EUR")
+	seedN(t, srv, 1, "JPY")
+	var currencies []string
+	if err := json.Unmarshal([]byte(getBody(t, srv.URL+"/transactions/currencies")), &currencies); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !reflect.DeepEqual(currencies, []string{"EUR", "JPY", "USD"}) {
+		t.Errorf("expected [EUR JPY USD], got %v", currencies)
+	}
+}
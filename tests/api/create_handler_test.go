@@ -2,9 +2,12 @@ package api_test
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/synctera/tech-challenge/internal/api"
 	"github.com/synctera/tech-challenge/internal/model"
 )
 
@@ -14,7 +17,7 @@ import (
 // Output: HTTP 201, response body contains the created transaction
 func TestCreateTransaction_success(t *testing.T) {
 	srv := newTestServer(t)
-	body := `{"id":"txn-1","amount":1000,"currency":"USD","effective_at":"2024-01-15T12:00:00Z"}`
+	body := `{"id":"txn-1","account_id":"acct-1","amount":1000,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z"}`
 
 	resp := postTxn(t, srv, body)
 	defer resp.Body.Close()
@@ -38,7 +41,7 @@ func TestCreateTransaction_success(t *testing.T) {
 // Output: first call HTTP 201, second call HTTP 200
 func TestCreateTransaction_idempotentRetry(t *testing.T) {
 	srv := newTestServer(t)
-	body := `{"id":"txn-1","amount":1000,"currency":"USD","effective_at":"2024-01-15T12:00:00Z"}`
+	body := `{"id":"txn-1","account_id":"acct-1","amount":1000,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z"}`
 
 	resp1 := postTxn(t, srv, body)
 	resp1.Body.Close()
@@ -59,8 +62,8 @@ func TestCreateTransaction_idempotentRetry(t *testing.T) {
 // Output: second call returns HTTP 409
 func TestCreateTransaction_conflict(t *testing.T) {
 	srv := newTestServer(t)
-	original := `{"id":"txn-1","amount":1000,"currency":"USD","effective_at":"2024-01-15T12:00:00Z"}`
-	conflicting := `{"id":"txn-1","amount":9999,"currency":"USD","effective_at":"2024-01-15T12:00:00Z"}`
+	original := `{"id":"txn-1","account_id":"acct-1","amount":1000,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z"}`
+	conflicting := `{"id":"txn-1","account_id":"acct-1","amount":9999,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z"}`
 
 	resp1 := postTxn(t, srv, original)
 	resp1.Body.Close()
@@ -73,6 +76,47 @@ func TestCreateTransaction_conflict(t *testing.T) {
 	}
 }
 
+// Test: TestCreateTransaction_conflictBodyListsDifferences
+// What: a 409 body names each differing field with the submitted and stored values, and nothing else
+// Input: original (amount=1000, metadata k=v), then same id with amount=9999 and metadata k=w
+// Output: HTTP 409, application/json, conflict = {"amount":{"submitted":9999,"stored":1000},"metadata":{...}}
+func TestCreateTransaction_conflictBodyListsDifferences(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":1000,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z","metadata":{"k":"v"}}`)
+
+	resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":9999,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z","metadata":{"k":"w"}}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json, got %q", ct)
+	}
+
+	var body struct {
+		Error    string                     `json:"error"`
+		Conflict map[string]json.RawMessage `json:"conflict"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Error == "" {
+		t.Error("expected an error message")
+	}
+	want := map[string]string{
+		"amount":   `{"submitted":9999,"stored":1000}`,
+		"metadata": `{"submitted":{"k":"w"},"stored":{"k":"v"}}`,
+	}
+	if len(body.Conflict) != len(want) {
+		t.Errorf("expected only %v to differ, got %v", want, body.Conflict)
+	}
+	for field, diff := range want {
+		if got := string(body.Conflict[field]); got != diff {
+			t.Errorf("%s: expected %s, got %s", field, diff, got)
+		}
+	}
+}
+
 // Test: TestCreateTransaction_invalidJSON
 // What: POST with a malformed JSON body returns 400 Bad Request
 // Input: body="{not valid json"
@@ -94,7 +138,7 @@ func TestCreateTransaction_invalidJSON(t *testing.T) {
 // Output: HTTP 400
 func TestCreateTransaction_missingID(t *testing.T) {
 	srv := newTestServer(t)
-	body := `{"amount":1000,"currency":"USD","effective_at":"2024-01-15T12:00:00Z"}`
+	body := `{"account_id":"acct-1","amount":1000,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z"}`
 
 	resp := postTxn(t, srv, body)
 	defer resp.Body.Close()
@@ -110,7 +154,7 @@ func TestCreateTransaction_missingID(t *testing.T) {
 // Output: HTTP 400
 func TestCreateTransaction_missingCurrency(t *testing.T) {
 	srv := newTestServer(t)
-	body := `{"id":"txn-1","amount":1000,"effective_at":"2024-01-15T12:00:00Z"}`
+	body := `{"id":"txn-1","account_id":"acct-1","amount":1000,"direction":"debit","effective_at":"2024-01-15T12:00:00Z"}`
 
 	resp := postTxn(t, srv, body)
 	defer resp.Body.Close()
@@ -120,13 +164,33 @@ func TestCreateTransaction_missingCurrency(t *testing.T) {
 	}
 }
 
+// Test: TestCreateTransaction_missingAccountID
+// What: POST without an "account_id" field returns 400 Bad Request naming the field
+// Input: JSON body with id, amount, currency, direction, effective_at but no account_id
+// Output: HTTP 400, body mentions account_id
+func TestCreateTransaction_missingAccountID(t *testing.T) {
+	srv := newTestServer(t)
+	body := `{"id":"txn-1","amount":1000,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z"}`
+
+	resp := postTxn(t, srv, body)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+	msg, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(msg), "account_id") {
+		t.Errorf("expected body to mention account_id, got %q", msg)
+	}
+}
+
 // Test: TestCreateTransaction_missingEffectiveAt
 // What: POST without an "effective_at" field returns 400 Bad Request
 // Input: JSON body with id, amount, currency but no effective_at
 // Output: HTTP 400
 func TestCreateTransaction_missingEffectiveAt(t *testing.T) {
 	srv := newTestServer(t)
-	body := `{"id":"txn-1","amount":1000,"currency":"USD"}`
+	body := `{"id":"txn-1","account_id":"acct-1","amount":1000,"currency":"USD","direction":"debit"}`
 
 	resp := postTxn(t, srv, body)
 	defer resp.Body.Close()
@@ -142,7 +206,7 @@ func TestCreateTransaction_missingEffectiveAt(t *testing.T) {
 // Output: HTTP 400
 func TestCreateTransaction_negativeAmount(t *testing.T) {
 	srv := newTestServer(t)
-	body := `{"id":"txn-1","amount":-100,"currency":"USD","effective_at":"2024-01-15T12:00:00Z"}`
+	body := `{"id":"txn-1","account_id":"acct-1","amount":-100,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z"}`
 
 	resp := postTxn(t, srv, body)
 	defer resp.Body.Close()
@@ -158,7 +222,7 @@ func TestCreateTransaction_negativeAmount(t *testing.T) {
 // Output: HTTP 201
 func TestCreateTransaction_zeroAmountAllowed(t *testing.T) {
 	srv := newTestServer(t)
-	body := `{"id":"txn-1","amount":0,"currency":"USD","effective_at":"2024-01-15T12:00:00Z"}`
+	body := `{"id":"txn-1","account_id":"acct-1","amount":0,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z"}`
 
 	resp := postTxn(t, srv, body)
 	defer resp.Body.Close()
@@ -174,7 +238,7 @@ func TestCreateTransaction_zeroAmountAllowed(t *testing.T) {
 // Output: HTTP 201, response body decodes to a Transaction with matching fields
 func TestCreateTransaction_responseBodyContainsTransaction(t *testing.T) {
 	srv := newTestServer(t)
-	body := `{"id":"txn-abc","amount":4200,"currency":"EUR","effective_at":"2024-06-01T00:00:00Z"}`
+	body := `{"id":"txn-abc","account_id":"acct-1","amount":4200,"currency":"EUR","direction":"debit","effective_at":"2024-06-01T00:00:00Z"}`
 
 	resp := postTxn(t, srv, body)
 	defer resp.Body.Close()
@@ -195,7 +259,7 @@ func TestCreateTransaction_responseBodyContainsTransaction(t *testing.T) {
 // Output: HTTP 201, response body contains Metadata["source"]="mobile"
 func TestCreateTransaction_withMetadata(t *testing.T) {
 	srv := newTestServer(t)
-	body := `{"id":"txn-1","amount":100,"currency":"USD","effective_at":"2024-01-01T00:00:00Z","metadata":{"source":"mobile"}}`
+	body := `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","metadata":{"source":"mobile"}}`
 
 	resp := postTxn(t, srv, body)
 	defer resp.Body.Close()
@@ -210,3 +274,70 @@ func TestCreateTransaction_withMetadata(t *testing.T) {
 		t.Errorf("expected metadata source=mobile, got %v", got.Metadata)
 	}
 }
+
+// Test: TestCreateTransaction_oversizedMetadataRejected
+// What: POST with a metadata value over the size limit returns 400 with a descriptive message
+// Input: JSON body with metadata.note of MaxMetadataValueLength+1 characters
+// Output: HTTP 400, body mentions the offending key
+func TestCreateTransaction_oversizedMetadataRejected(t *testing.T) {
+	srv := newTestServer(t)
+	body := `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","metadata":{"note":"` +
+		strings.Repeat("x", api.MaxMetadataValueLength+1) + `"}}`
+
+	resp := postTxn(t, srv, body)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+	msg, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(msg), `"note"`) {
+		t.Errorf("expected error to name the metadata key, got %q", msg)
+	}
+}
+
+// Test: TestCreateTransaction_normalizesEffectiveAtToUTC
+// What: an effective_at with a non-UTC offset is stored and returned as the same instant in UTC
+// Input: effective_at="2024-01-15T22:00:00-05:00", then GET and a UTC date filter for 2024-01-16
+// Output: create and GET both return "2024-01-16T03:00:00Z"; end_date=2024-01-15 excludes it
+func TestCreateTransaction_normalizesEffectiveAtToUTC(t *testing.T) {
+	srv := newTestServer(t)
+	body := `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-15T22:00:00-05:00"}`
+
+	resp := postTxn(t, srv, body)
+	defer resp.Body.Close()
+	var created map[string]any
+	json.NewDecoder(resp.Body).Decode(&created)
+	if created["effective_at"] != "2024-01-16T03:00:00Z" {
+		t.Errorf("expected create response effective_at 2024-01-16T03:00:00Z, got %v", created["effective_at"])
+	}
+
+	get := getTxnByID(t, srv, "txn-1")
+	defer get.Body.Close()
+	var stored map[string]any
+	json.NewDecoder(get.Body).Decode(&stored)
+	if stored["effective_at"] != "2024-01-16T03:00:00Z" {
+		t.Errorf("expected stored effective_at 2024-01-16T03:00:00Z, got %v", stored["effective_at"])
+	}
+
+	if ids := listIDs(t, srv.URL+"/transactions?end_date=2024-01-15"); len(ids) != 0 {
+		t.Errorf("expected UTC end_date 2024-01-15 to exclude the transaction, got %v", ids)
+	}
+}
+
+// Test: TestCreateTransaction_sameInstantDifferentOffsetIsDuplicate
+// What: re-posting the same instant with a different offset is an idempotent duplicate, not a conflict
+// Input: effective_at "2024-01-15T12:00:00Z", then the same payload with "2024-01-15T07:00:00-05:00"
+// Output: HTTP 201, then HTTP 200
+func TestCreateTransaction_sameInstantDifferentOffsetIsDuplicate(t *testing.T) {
+	srv := newTestServer(t)
+
+	first := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z"}`)
+	first.Body.Close()
+	retry := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-15T07:00:00-05:00"}`)
+	retry.Body.Close()
+
+	if first.StatusCode != http.StatusCreated || retry.StatusCode != http.StatusOK {
+		t.Errorf("expected 201 then 200, got %d then %d", first.StatusCode, retry.StatusCode)
+	}
+}
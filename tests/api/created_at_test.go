@@ -0,0 +1,121 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/clock"
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// newClockedServer returns a test server whose store stamps CreatedAt from clk.
+func newClockedServer(t *testing.T, clk clock.Clock) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	api.NewHandler(store.NewMemoryStoreWithClock(clk)).RegisterRoutes(mux, nil)
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func decodeTxn(t *testing.T, resp *http.Response) model.Transaction {
+	t.Helper()
+	defer resp.Body.Close()
+	var txn model.Transaction
+	if err := json.NewDecoder(resp.Body).Decode(&txn); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return txn
+}
+
+const createdAtTxn = `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","created_at":"1999-01-01T00:00:00Z"}`
+
+// Test: TestCreateTransaction_createdAtAssigned
+// What: the response carries a server-assigned created_at, ignoring the client's value
+// Input: clock at 2024-06-01 12:00 UTC; POST with created_at=1999-01-01
+// Output: HTTP 201, created_at is the clock time; GET returns the same value
+func TestCreateTransaction_createdAtAssigned(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	srv := newClockedServer(t, clock.NewFake(now))
+
+	resp := postTxn(t, srv, createdAtTxn)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	if created := decodeTxn(t, resp); !created.CreatedAt.Equal(now) {
+		t.Errorf("expected created_at %v, got %v", now, created.CreatedAt)
+	}
+	if got := decodeTxn(t, getTxnByID(t, srv, "txn-1")); !got.CreatedAt.Equal(now) {
+		t.Errorf("expected GET created_at %v, got %v", now, got.CreatedAt)
+	}
+}
+
+// Test: TestCreateTransaction_createdAtStableAcrossRetries
+// What: idempotent retries, with or without an Idempotency-Key, return the original created_at
+// Input: POST at t0; advance clock 1h; re-POST identical body; re-POST with an Idempotency-Key
+// Output: HTTP 200 for the retry, created_at stays t0 in every response
+func TestCreateTransaction_createdAtStableAcrossRetries(t *testing.T) {
+	t0 := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(t0)
+	srv := newClockedServer(t, clk)
+
+	first := decodeTxn(t, postTxn(t, srv, createdAtTxn))
+	clk.Advance(time.Hour)
+
+	resp := postTxn(t, srv, createdAtTxn)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200 for idempotent retry, got %d", resp.StatusCode)
+	}
+	retry := decodeTxn(t, resp)
+	if !first.CreatedAt.Equal(t0) || !retry.CreatedAt.Equal(t0) {
+		t.Errorf("expected created_at %v on both, got %v and %v", t0, first.CreatedAt, retry.CreatedAt)
+	}
+
+	keyed := decodeTxn(t, postTxnWithKey(t, srv, "key-1", createdAtTxn))
+	if !keyed.CreatedAt.Equal(t0) {
+		t.Errorf("expected Idempotency-Key retry created_at %v, got %v", t0, keyed.CreatedAt)
+	}
+}
+
+// Test: TestListTransactions_filterByCreatedAt
+// What: created_after and created_before are exclusive bounds on created_at
+// Input: a at 10:00, b at 11:00, c at 12:00; created_after=10:00, created_before=12:00, both
+// Output: [b c], [a b], [b]
+func TestListTransactions_filterByCreatedAt(t *testing.T) {
+	base := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(base)
+	srv := newClockedServer(t, clk)
+	for _, id := range []string{"a", "b", "c"} {
+		seedTxn(t, srv, `{"id":"`+id+`","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+		clk.Advance(time.Hour)
+	}
+
+	url := srv.URL + "/transactions?"
+	assertIDs(t, listIDs(t, url+"created_after=2024-06-01T10:00:00Z"), "b", "c")
+	assertIDs(t, listIDs(t, url+"created_before=2024-06-01T12:00:00Z"), "a", "b")
+	assertIDs(t, listIDs(t, url+"created_after=2024-06-01T10:00:00Z&created_before=2024-06-01T12:00:00Z"), "b")
+}
+
+// Test: TestListTransactions_invalidCreatedFilters
+// What: malformed or empty created_* ranges are rejected
+// Input: created_after=2024-06-01 (not RFC3339); created_after equal to created_before
+// Output: HTTP 400 for each
+func TestListTransactions_invalidCreatedFilters(t *testing.T) {
+	srv := newTestServer(t)
+
+	for _, query := range []string{
+		"created_after=2024-06-01",
+		"created_after=2024-06-01T10:00:00Z&created_before=2024-06-01T10:00:00Z",
+	} {
+		resp := getTxns(t, srv, query)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, resp.StatusCode)
+		}
+	}
+}
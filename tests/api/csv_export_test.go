@@ -0,0 +1,153 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+var wantCSVHeader = []string{"id", "account_id", "amount", "currency", "direction", "effective_at", "metadata"}
+
+func readCSV(t *testing.T, resp *http.Response) [][]string {
+	t.Helper()
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/csv" {
+		t.Errorf("expected Content-Type text/csv, got %q", ct)
+	}
+	if cd := resp.Header.Get("Content-Disposition"); cd != "attachment; filename=transactions.csv" {
+		t.Errorf("unexpected Content-Disposition %q", cd)
+	}
+	rows, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("response is not valid CSV: %v", err)
+	}
+	return rows
+}
+
+// Test: TestListTransactions_csvFormat
+// What: format=csv returns a CSV attachment with a header row, honoring filters and pagination
+// Input: 3 USD + 1 EUR transactions; format=csv&currency=USD&limit=2
+// Output: header + 2 rows, first row id=USD-000, metadata JSON-encoded where present
+func TestListTransactions_csvFormat(t *testing.T) {
+	srv := newTestServer(t)
+	seedN(t, srv, 3, "USD")
+	seedN(t, srv, 1, "EUR")
+	seedTxn(t, srv, `{"id":"USD-meta","account_id":"acct-1","amount":1,"currency":"USD","direction":"credit","effective_at":"2023-12-31T00:00:00Z","metadata":{"source":"mobile"}}`)
+
+	rows := readCSV(t, getTxns(t, srv, "format=csv&currency=USD&limit=2"))
+	if len(rows) != 3 {
+		t.Fatalf("expected header + 2 rows, got %d rows", len(rows))
+	}
+	if !reflect.DeepEqual(rows[0], wantCSVHeader) {
+		t.Errorf("expected header %v, got %v", wantCSVHeader, rows[0])
+	}
+	want := []string{"USD-meta", "acct-1", "1", "USD", "credit", "2023-12-31T00:00:00Z", `{"source":"mobile"}`}
+	if !reflect.DeepEqual(rows[1], want) {
+		t.Errorf("expected first row %v, got %v", want, rows[1])
+	}
+	if rows[2][0] != "USD-000" || rows[2][6] != "" {
+		t.Errorf("unexpected second row %v", rows[2])
+	}
+}
+
+// Test: TestListTransactions_csvAcceptHeader
+// What: Accept: text/csv selects CSV without a format param
+// Input: 2 transactions, Accept: text/csv
+// Output: header + 2 rows
+func TestListTransactions_csvAcceptHeader(t *testing.T) {
+	srv := newTestServer(t)
+	seedN(t, srv, 2, "USD")
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/transactions", nil)
+	req.Header.Set("Accept", "text/csv")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /transactions failed: %v", err)
+	}
+
+	if rows := readCSV(t, resp); len(rows) != 3 {
+		t.Errorf("expected header + 2 rows, got %d", len(rows))
+	}
+}
+
+// Test: TestListTransactions_invalidFormat
+// What: an unknown format value is rejected
+// Input: format=xml
+// Output: HTTP 400
+func TestListTransactions_invalidFormat(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := getTxns(t, srv, "format=xml")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestWriteCSV_roundTripsThroughValidateCSV
+// What: exported CSV is accepted by the validate-csv parser
+// Input: export of 3 seeded transactions fed into api.ValidateCSV
+// Output: total=3, valid=3
+func TestWriteCSV_roundTripsThroughValidateCSV(t *testing.T) {
+	srv := newTestServer(t)
+	seedN(t, srv, 3, "USD")
+
+	resp := getTxns(t, srv, "format=csv")
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+
+	report, err := api.ValidateCSV(strings.NewReader(string(body)), api.DefaultConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Total != 3 || report.Valid != 3 {
+		t.Errorf("expected 3/3 valid, got %+v", report)
+	}
+}
+
+// Test: TestWriteCSV_escapesFormulas
+// What: cells a spreadsheet would evaluate as formulas are exported with a leading quote
+// Input: a transaction with account_id =HYPERLINK("http://x"), currency +USD, metadata
+// {"note":"@SUM(A1)"}, and ID -txn; a second, ordinary transaction
+// Output: id '-txn, account_id '=HYPERLINK("http://x"), currency '+USD; the metadata JSON
+// starts with { and is left alone; the ordinary row is unchanged
+func TestWriteCSV_escapesFormulas(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	err := api.WriteCSV(&buf, []model.Transaction{
+		{ID: "-txn", AccountID: `=HYPERLINK("http://x")`, Amount: 100, Currency: "+USD", Direction: model.DirectionDebit,
+			EffectiveAt: at, Metadata: map[string]string{"note": "@SUM(A1)"}},
+		{ID: "txn-2", AccountID: "acct-1", Amount: 100, Currency: "USD", Direction: model.DirectionDebit, EffectiveAt: at},
+	})
+	if err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(rows) != 3 {
+		t.Fatalf("expected a header and 2 rows, got %v, %v", rows, err)
+	}
+	if got := rows[1][:4]; got[0] != "'-txn" || got[1] != `'=HYPERLINK("http://x")` || got[3] != "'+USD" {
+		t.Errorf("expected formula cells to be quoted, got %q", got)
+	}
+	if rows[1][6] != `{"note":"@SUM(A1)"}` {
+		t.Errorf("expected metadata JSON unchanged, got %q", rows[1][6])
+	}
+	if got := strings.Join(rows[2], ","); got != "txn-2,acct-1,100,USD,debit,2024-01-01T00:00:00Z," {
+		t.Errorf("expected the ordinary row unchanged, got %q", got)
+	}
+}
@@ -0,0 +1,249 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/clock"
+)
+
+func postCSV(t *testing.T, url, contentType, body string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(url+"/transactions/validate-csv", contentType, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST /transactions/validate-csv failed: %v", err)
+	}
+	return resp
+}
+
+const mixedCSV = `id,account_id,amount,currency,direction,effective_at
+txn-1,acct-1,100,USD,debit,2024-01-01T00:00:00Z
+txn-2,acct-1,abc,USD,debit,2024-01-02T00:00:00Z
+txn-3,acct-1,300,,debit,2024-01-03T00:00:00Z
+txn-4,acct-2,400,EUR,credit,2024-01-04T00:00:00Z
+txn-5,acct-1,500,USD,debit,not-a-date
+`
+
+// Test: TestValidateCSV_mixedRows
+// What: ValidateCSV reports every invalid row with its file line number and counts valid rows
+// Input: header plus 5 rows; rows on lines 3 (bad amount), 4 (missing currency), 6 (bad date) are invalid
+// Output: total=5, valid=2, errors on lines 3, 4, 6
+func TestValidateCSV_mixedRows(t *testing.T) {
+	report, err := api.ValidateCSV(strings.NewReader(mixedCSV), api.DefaultConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Total != 5 || report.Valid != 2 {
+		t.Errorf("expected total=5 valid=2, got total=%d valid=%d", report.Total, report.Valid)
+	}
+
+	wantLines := []int{3, 4, 6}
+	if len(report.Errors) != len(wantLines) {
+		t.Fatalf("expected %d errors, got %+v", len(wantLines), report.Errors)
+	}
+	for i, line := range wantLines {
+		if report.Errors[i].Line != line {
+			t.Errorf("error %d: expected line %d, got %d (%s)", i, line, report.Errors[i].Line, report.Errors[i].Message)
+		}
+		if report.Errors[i].Message == "" {
+			t.Errorf("error %d: expected a message", i)
+		}
+	}
+}
+
+// Test: TestValidateTransactionsCSV_deploymentPolicy
+// What: the CSV pre-flight applies the currency allow-list and the zero-amount setting, as a
+// create would
+// Input: AllowedCurrencies=[USD] and AllowZeroAmount=false; rows with USD 100, EUR 100 and USD 0
+// Output: total=3, valid=1, errors on lines 3 and 4
+func TestValidateTransactionsCSV_deploymentPolicy(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.AllowedCurrencies = []string{"USD"}
+	cfg.AllowZeroAmount = false
+	srv := newTestServerWithConfig(t, cfg)
+
+	resp := postCSV(t, srv.URL, "text/csv", `id,account_id,amount,currency,direction,effective_at
+txn-1,acct-1,100,USD,debit,2024-01-01T00:00:00Z
+txn-2,acct-1,100,EUR,debit,2024-01-02T00:00:00Z
+txn-3,acct-1,0,USD,debit,2024-01-03T00:00:00Z
+`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var report api.CSVValidationReport
+	json.NewDecoder(resp.Body).Decode(&report)
+	if report.Total != 3 || report.Valid != 1 || len(report.Errors) != 2 ||
+		report.Errors[0].Line != 3 || report.Errors[1].Line != 4 {
+		t.Errorf("expected errors on lines 3 and 4 only, got %+v", report)
+	}
+}
+
+// effectiveAtCSV has rows dated a day before, a day after and 60 days after 2024-06-01.
+const effectiveAtCSV = `id,account_id,amount,currency,direction,effective_at
+txn-1,acct-1,100,USD,debit,2024-05-31T12:00:00Z
+txn-2,acct-1,100,USD,debit,2024-06-02T12:00:00Z
+txn-3,acct-1,100,USD,debit,2024-07-31T12:00:00Z
+`
+
+// Test: TestValidateCSV_requirePastEffectiveAt
+// What: the CSV pre-flight rejects future effective_at under RequirePastEffectiveAt, as a
+// create would
+// Input: RequirePastEffectiveAt with the clock at 2024-06-01; rows a day before, a day after
+// and 60 days after
+// Output: total=3, valid=1, errors on lines 3 and 4
+func TestValidateCSV_requirePastEffectiveAt(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.Clock = clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	cfg.RequirePastEffectiveAt = true
+
+	report, err := api.ValidateCSV(strings.NewReader(effectiveAtCSV), cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Total != 3 || report.Valid != 1 || len(report.Errors) != 2 ||
+		report.Errors[0].Line != 3 || report.Errors[1].Line != 4 {
+		t.Errorf("expected errors on lines 3 and 4 only, got %+v", report)
+	}
+}
+
+// Test: TestValidateCSV_maxFutureEffectiveAtDays
+// What: the CSV pre-flight applies MaxFutureEffectiveAtDays, as a create would
+// Input: MaxFutureEffectiveAtDays=30 with the clock at 2024-06-01; rows a day before, a day
+// after and 60 days after
+// Output: total=3, valid=2, one error on line 4 naming the 30-day window
+func TestValidateCSV_maxFutureEffectiveAtDays(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.Clock = clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	cfg.MaxFutureEffectiveAtDays = 30
+
+	report, err := api.ValidateCSV(strings.NewReader(effectiveAtCSV), cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Total != 3 || report.Valid != 2 || len(report.Errors) != 1 || report.Errors[0].Line != 4 ||
+		!strings.Contains(report.Errors[0].Message, "30 days") {
+		t.Errorf("expected one 30-day error on line 4, got %+v", report)
+	}
+}
+
+// Test: TestValidateCSV_missingHeaderColumn
+// What: a header without a required column fails the whole file
+// Input: header "id,amount,currency" (no effective_at)
+// Output: non-nil error
+func TestValidateCSV_missingHeaderColumn(t *testing.T) {
+	_, err := api.ValidateCSV(strings.NewReader("id,amount,currency\ntxn-1,100,USD\n"), api.DefaultConfig())
+	if err == nil {
+		t.Error("expected error for missing effective_at column, got nil")
+	}
+}
+
+// Test: TestValidateCSV_metadataColumn
+// What: an optional metadata column is parsed as a JSON object and bad JSON is reported per row
+// Input: two rows, one with valid metadata JSON and one with malformed JSON
+// Output: total=2, valid=1, one error on line 3
+func TestValidateCSV_metadataColumn(t *testing.T) {
+	body := "id,account_id,amount,currency,direction,effective_at,metadata\n" +
+		`txn-1,acct-1,100,USD,debit,2024-01-01T00:00:00Z,"{""source"":""mobile""}"` + "\n" +
+		`txn-2,acct-1,100,USD,debit,2024-01-01T00:00:00Z,"{not json}"` + "\n"
+
+	report, err := api.ValidateCSV(strings.NewReader(body), api.DefaultConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Total != 2 || report.Valid != 1 || len(report.Errors) != 1 || report.Errors[0].Line != 3 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+// Test: TestValidateTransactionsCSV_endpoint
+// What: POST /transactions/validate-csv returns the JSON report and does not store anything
+// Input: mixed CSV posted with Content-Type text/csv, then GET /transactions
+// Output: HTTP 200 with total=5, valid=2, 3 errors; list endpoint still empty
+func TestValidateTransactionsCSV_endpoint(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := postCSV(t, srv.URL, "text/csv", mixedCSV)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var report api.CSVValidationReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	if report.Total != 5 || report.Valid != 2 || len(report.Errors) != 3 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	list := getTxns(t, srv, "")
+	defer list.Body.Close()
+	var txns []json.RawMessage
+	json.NewDecoder(list.Body).Decode(&txns)
+	if len(txns) != 0 {
+		t.Errorf("validate-csv must not store transactions, found %d", len(txns))
+	}
+}
+
+// Test: TestValidateTransactionsCSV_wrongContentType
+// What: the endpoint only accepts text/csv bodies
+// Input: CSV body posted with Content-Type application/json
+// Output: HTTP 415
+func TestValidateTransactionsCSV_wrongContentType(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := postCSV(t, srv.URL, "application/json", mixedCSV)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("expected 415, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestValidateCSV_badQuote
+// What: a row with malformed quoting is reported as a row error instead of aborting the file
+// Input: header plus 3 rows; the row on line 3 has a stray quote in its first field
+// Output: total=3, valid=2, one error on line 3
+func TestValidateCSV_badQuote(t *testing.T) {
+	body := "id,account_id,amount,currency,direction,effective_at\n" +
+		"txn-1,acct-1,100,USD,debit,2024-01-01T00:00:00Z\n" +
+		"tx\"n-2,acct-1,100,USD,debit,2024-01-02T00:00:00Z\n" +
+		"txn-3,acct-1,300,USD,debit,2024-01-03T00:00:00Z\n"
+
+	report, err := api.ValidateCSV(strings.NewReader(body), api.DefaultConfig())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Total != 3 || report.Valid != 2 || len(report.Errors) != 1 || report.Errors[0].Line != 3 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+// Test: TestValidateTransactionsCSV_badQuoteEndpoint
+// What: POST /transactions/validate-csv answers a malformed-quote row with a report, not a crash
+// Input: CSV whose only row opens a quoted first field and never closes it
+// Output: HTTP 200 with total=1, valid=0, one error
+func TestValidateTransactionsCSV_badQuoteEndpoint(t *testing.T) {
+	srv := newTestServer(t)
+
+	body := "id,account_id,amount,currency,direction,effective_at\n" +
+		"\"txn-1,acct-1,100,USD,debit,2024-01-01T00:00:00Z\n"
+	resp := postCSV(t, srv.URL, "text/csv", body)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var report api.CSVValidationReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	if report.Total != 1 || report.Valid != 0 || len(report.Errors) != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
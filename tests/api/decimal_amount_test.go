@@ -0,0 +1,79 @@
+package api_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+)
+
+// Test: TestCreateTransaction_decimalAmount
+// What: with AcceptDecimalAmounts, a decimal-string amount is stored in the currency's minor units and integers still work
+// Input: "12.34" USD, "100" JPY, and the integer 500 USD
+// Output: HTTP 201 with amounts 1234, 100 and 500
+func TestCreateTransaction_decimalAmount(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.AcceptDecimalAmounts = true
+	srv := newTestServerWithConfig(t, cfg)
+
+	tests := []struct {
+		id, amount, currency string
+		want                 int64
+	}{
+		{"txn-1", `"12.34"`, "USD", 1234},
+		{"txn-2", `"100"`, "JPY", 100},
+		{"txn-3", `500`, "USD", 500},
+	}
+	for _, tt := range tests {
+		resp := postTxn(t, srv, `{"id":"`+tt.id+`","account_id":"acct-1","amount":`+tt.amount+`,"currency":"`+tt.currency+`","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+		if resp.StatusCode != http.StatusCreated {
+			resp.Body.Close()
+			t.Fatalf("%s %s: expected 201, got %d", tt.amount, tt.currency, resp.StatusCode)
+		}
+		if got := decodeTxn(t, resp).Amount; got != tt.want {
+			t.Errorf("%s %s: expected amount %d, got %d", tt.amount, tt.currency, tt.want, got)
+		}
+	}
+}
+
+// Test: TestCreateTransaction_decimalAmountOverPrecision
+// What: a decimal amount with more places than the currency allows is rejected, not rounded
+// Input: "12.345" USD and "1.5" JPY with AcceptDecimalAmounts
+// Output: HTTP 400 naming the amount field and the currency's allowed places
+func TestCreateTransaction_decimalAmountOverPrecision(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.AcceptDecimalAmounts = true
+	srv := newTestServerWithConfig(t, cfg)
+
+	for _, tt := range []struct{ amount, currency, want string }{
+		{"12.345", "USD", "USD allows 2"},
+		{"1.5", "JPY", "JPY allows 0"},
+	} {
+		resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":"`+tt.amount+`","currency":"`+tt.currency+`","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+		msg, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s %s: expected 400, got %d", tt.amount, tt.currency, resp.StatusCode)
+		}
+		if !strings.Contains(string(msg), "amount: ") || !strings.Contains(string(msg), tt.want) {
+			t.Errorf("%s %s: expected the error to name amount and %q, got %q", tt.amount, tt.currency, tt.want, msg)
+		}
+	}
+}
+
+// Test: TestCreateTransaction_decimalAmountOffByDefault
+// What: without AcceptDecimalAmounts a string amount is still a schema violation
+// Input: "amount":"12.34" on the default config
+// Output: HTTP 400 "amount: must be an integer, got string"
+func TestCreateTransaction_decimalAmountOffByDefault(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":"12.34","currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	defer resp.Body.Close()
+	msg, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(msg), "amount: must be an integer, got string") {
+		t.Errorf("expected 400 with the schema error, got %d %q", resp.StatusCode, msg)
+	}
+}
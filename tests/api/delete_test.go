@@ -0,0 +1,77 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func deleteTxns(t *testing.T, srv *httptest.Server, query string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/transactions?"+query, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE /transactions failed: %v", err)
+	}
+	return resp
+}
+
+// Test: TestDeleteTransactions_dateRange
+// What: DELETE /transactions removes only the transactions matching the filters
+// Input: transactions on 2023-12-30, 2023-12-31 and 2024-01-02; end_date=2023-12-31
+// Output: HTTP 200 {"deleted":2}; GET /transactions lists only the 2024 transaction
+func TestDeleteTransactions_dateRange(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"old-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2023-12-30T10:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"old-2","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2023-12-31T23:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"new-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`)
+
+	resp := deleteTxns(t, srv, "end_date=2023-12-31")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var body struct {
+		Deleted int `json:"deleted"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+	if body.Deleted != 2 {
+		t.Errorf("expected deleted=2, got %d", body.Deleted)
+	}
+
+	assertIDs(t, listIDs(t, srv.URL+"/transactions"), "new-1")
+}
+
+// Test: TestDeleteTransactions_requiresFilter
+// What: a DELETE without any filter is rejected so it can't purge the whole store
+// Input: 2 transactions; DELETE /transactions with no query, and with only limit=10
+// Output: HTTP 400 both times; both transactions are still listed
+func TestDeleteTransactions_requiresFilter(t *testing.T) {
+	srv := newTestServer(t)
+	seedN(t, srv, 2, "USD")
+
+	for _, query := range []string{"", "limit=10"} {
+		resp := deleteTxns(t, srv, query)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("query %q: expected 400, got %d", query, resp.StatusCode)
+		}
+	}
+
+	assertIDs(t, listIDs(t, srv.URL+"/transactions"), "USD-000", "USD-001")
+}
+
+// Test: TestDeleteTransactions_invalidFilter
+// What: filters are validated the same way as on the list endpoint
+// Input: DELETE /transactions?end_date=not-a-date
+// Output: HTTP 400
+func TestDeleteTransactions_invalidFilter(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := deleteTxns(t, srv, "end_date=not-a-date")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
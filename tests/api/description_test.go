@@ -0,0 +1,51 @@
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// Test: TestListTransactions_descriptionPrefix
+// What: description_prefix matches descriptions starting with the prefix, ignoring case
+// Input: descriptions "Coffee Shop", "coffee beans", "Iced Coffee" and none; description_prefix=COFFEE, then =Tea
+// Output: the two coffee-first transactions in order; then an empty list
+func TestListTransactions_descriptionPrefix(t *testing.T) {
+	srv := newTestServer(t)
+	for i, description := range []string{"Coffee Shop", "coffee beans", "Iced Coffee", ""} {
+		seedTxn(t, srv, fmt.Sprintf(`{"id":"txn-%d","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-0%dT00:00:00Z","description":%q}`, i+1, i+1, description))
+	}
+
+	var result []model.Transaction
+	json.Unmarshal(readBody(t, getTxns(t, srv, "description_prefix=COFFEE")), &result)
+	if len(result) != 2 || result[0].ID != "txn-1" || result[1].ID != "txn-2" {
+		t.Errorf("expected [txn-1 txn-2], got %+v", result)
+	}
+	if result[0].Description != "Coffee Shop" {
+		t.Errorf("expected the description to be returned, got %q", result[0].Description)
+	}
+
+	if body := strings.TrimSpace(string(readBody(t, getTxns(t, srv, "description_prefix=Tea")))); body != "[]" {
+		t.Errorf("expected an empty list, got %s", body)
+	}
+}
+
+// Test: TestCreateTransaction_descriptionValidated
+// What: a description over MaxDescriptionLength or with a control character is rejected
+// Input: a 257-character description; a description with a newline
+// Output: HTTP 400 each
+func TestCreateTransaction_descriptionValidated(t *testing.T) {
+	srv := newTestServer(t)
+
+	for _, description := range []string{strings.Repeat("x", 257), `Coffee\nShop`} {
+		resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","description":"`+description+`"}`)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%.20q: expected 400, got %d", description, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+type envelopeResult struct {
+	Data       []model.Transaction `json:"data"`
+	Pagination struct {
+		Limit  int `json:"limit"`
+		Offset int `json:"offset"`
+		Total  int `json:"total"`
+	} `json:"pagination"`
+}
+
+// Test: TestListTransactions_bareArrayByDefault
+// What: without envelope (or with envelope=false) the response is still a bare JSON array
+// Input: 2 transactions; GET /transactions and GET /transactions?envelope=false
+// Output: both bodies start with '[' and decode to 2 transactions
+func TestListTransactions_bareArrayByDefault(t *testing.T) {
+	srv := newTestServer(t)
+	seedN(t, srv, 2, "USD")
+
+	for _, query := range []string{"", "envelope=false"} {
+		resp := getTxns(t, srv, query)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		var txns []model.Transaction
+		if !bytes.HasPrefix(body, []byte("[")) || json.Unmarshal(body, &txns) != nil || len(txns) != 2 {
+			t.Errorf("%q: expected a bare array of 2 transactions, got %s", query, body)
+		}
+	}
+}
+
+// Test: TestListTransactions_envelope
+// What: envelope=true wraps the page with limit, offset, and the filtered total
+// Input: 5 USD + 2 EUR transactions; envelope=true&currency=USD&limit=2&offset=1
+// Output: data [USD-001, USD-002], pagination {limit 2, offset 1, total 5}
+func TestListTransactions_envelope(t *testing.T) {
+	srv := newTestServer(t)
+	seedN(t, srv, 5, "USD")
+	seedN(t, srv, 2, "EUR")
+
+	resp := getTxns(t, srv, "envelope=true&currency=USD&limit=2&offset=1")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var result envelopeResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode envelope: %v", err)
+	}
+	assertIDs(t, txnIDs(result.Data), "USD-001", "USD-002")
+	if p := result.Pagination; p.Limit != 2 || p.Offset != 1 || p.Total != 5 {
+		t.Errorf("expected pagination {2 1 5}, got %+v", p)
+	}
+}
+
+// Test: TestListTransactions_envelopeDateRangeTotal
+// What: a date-only query still reports the filtered total when enveloped
+// Input: 3 transactions on 2024-01-01 and 1 on 2024-02-02; envelope=true&end_date=2024-01-31&limit=1
+// Output: one transaction in data, total 3
+func TestListTransactions_envelopeDateRangeTotal(t *testing.T) {
+	srv := newTestServer(t)
+	seedN(t, srv, 3, "USD")
+	seedTxn(t, srv, `{"id":"feb","account_id":"acct-1","amount":1,"currency":"USD","direction":"debit","effective_at":"2024-02-02T00:00:00Z"}`)
+
+	resp := getTxns(t, srv, "envelope=true&end_date=2024-01-31&limit=1")
+	defer resp.Body.Close()
+
+	var result envelopeResult
+	json.NewDecoder(resp.Body).Decode(&result)
+	if len(result.Data) != 1 || result.Pagination.Total != 3 {
+		t.Errorf("expected 1 item with total 3, got %d items, total %d", len(result.Data), result.Pagination.Total)
+	}
+}
+
+// Test: TestListTransactions_envelopeEmpty
+// What: an empty result is an empty data array, not null
+// Input: empty store, envelope=true
+// Output: body has "data":[] and total 0
+func TestListTransactions_envelopeEmpty(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := getTxns(t, srv, "envelope=true")
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !bytes.Contains(body, []byte(`"data":[]`)) || !bytes.Contains(body, []byte(`"total":0`)) {
+		t.Errorf("expected empty data array and total 0, got %s", body)
+	}
+}
+
+// Test: TestListTransactions_invalidEnvelope
+// What: a non-boolean envelope value is rejected
+// Input: envelope=maybe
+// Output: HTTP 400
+func TestListTransactions_invalidEnvelope(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := getTxns(t, srv, "envelope=maybe")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
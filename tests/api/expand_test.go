@@ -0,0 +1,104 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/clock"
+)
+
+// Test: TestGetTransaction_defaultHasNoComputed
+// What: without expand, GET /transactions/{id} keeps the plain transaction shape
+// Input: one seeded transaction, GET /transactions/txn-1
+// Output: HTTP 200, body has no "computed" key
+func TestGetTransaction_defaultHasNoComputed(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":1234,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+
+	resp := getTxnByID(t, srv, "txn-1")
+	defer resp.Body.Close()
+
+	var body map[string]json.RawMessage
+	json.NewDecoder(resp.Body).Decode(&body)
+	if _, ok := body["computed"]; ok {
+		t.Errorf("expected no computed object by default, got %s", body["computed"])
+	}
+}
+
+// Test: TestGetTransaction_expandComputed
+// What: expand=computed adds age_days and amount_formatted alongside the normal fields
+// Input: txn-1 (1234 USD, effective 2024-01-01), clock at 2024-01-11T06:00:00Z, GET ?expand=computed
+// Output: HTTP 200, id=txn-1, computed.age_days=10, computed.amount_formatted="12.34"
+func TestGetTransaction_expandComputed(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.Clock = clock.NewFake(time.Date(2024, 1, 11, 6, 0, 0, 0, time.UTC))
+	srv := newTestServerWithConfig(t, cfg)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":1234,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+
+	resp, err := http.Get(srv.URL + "/transactions/txn-1?expand=computed")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var body struct {
+		ID       string             `json:"id"`
+		Computed api.ComputedFields `json:"computed"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+	if body.ID != "txn-1" {
+		t.Errorf("expected transaction fields alongside computed, got id %q", body.ID)
+	}
+	if body.Computed.AgeDays != 10 || body.Computed.AmountFormatted != "12.34" {
+		t.Errorf("expected age_days=10 amount_formatted=12.34, got %+v", body.Computed)
+	}
+}
+
+// Test: TestGetTransaction_expandComputedFutureDated
+// What: age_days is negative for a transaction dated after the injected clock
+// Input: txn-1 effective 2024-01-11, clock at 2024-01-01T00:00:00Z, GET ?expand=computed
+// Output: HTTP 200, computed.age_days=-10
+func TestGetTransaction_expandComputedFutureDated(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.Clock = clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	srv := newTestServerWithConfig(t, cfg)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":1234,"currency":"USD","direction":"debit","effective_at":"2024-01-11T00:00:00Z"}`)
+
+	resp, err := http.Get(srv.URL + "/transactions/txn-1?expand=computed")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Computed api.ComputedFields `json:"computed"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+	if body.Computed.AgeDays != -10 {
+		t.Errorf("expected age_days=-10, got %d", body.Computed.AgeDays)
+	}
+}
+
+// Test: TestGetTransaction_invalidExpand
+// What: an unknown expand value is rejected
+// Input: GET /transactions/txn-1?expand=everything
+// Output: HTTP 400
+func TestGetTransaction_invalidExpand(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":1234,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+
+	resp, err := http.Get(srv.URL + "/transactions/txn-1?expand=everything")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,106 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const fullTxn = `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T09:00:00+09:00","metadata":{"order_id":"42"},"tags":["vip"],"description":"Coffee"}`
+
+// snakeToCamel converts effective_at to effectiveAt.
+func snakeToCamel(key string) string {
+	parts := strings.Split(key, "_")
+	for i := 1; i < len(parts); i++ {
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+	return strings.Join(parts, "")
+}
+
+// Test: TestFieldCase_snakeByDefault
+// What: without field_case, keys stay snake_case
+// Input: txn-1 with every optional field; GET /transactions/txn-1
+// Output: effective_at and account_id present; effectiveAt absent
+func TestFieldCase_snakeByDefault(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, fullTxn)
+
+	fields := decodeRaw(t, readBody(t, getTxnByID(t, srv, "txn-1")))
+	if fields["effective_at"] == nil || fields["account_id"] == nil || fields["effectiveAt"] != nil {
+		t.Errorf("expected snake_case keys, got %v", fields)
+	}
+}
+
+// Test: TestFieldCase_camelMatchesEveryField
+// What: field_case=camel renames every key of the default encoding, and nothing else changes
+// Input: txn-1 with every optional field, soft-deleted so deleted is set too; GET with and without field_case=camel
+// Output: the same number of keys, each snake key present under its camelCase name with the same value
+func TestFieldCase_camelMatchesEveryField(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, fullTxn)
+	deleteTxn(t, srv, "txn-1").Body.Close()
+
+	snake := decodeRaw(t, readBody(t, getTxnByID(t, srv, "txn-1")))
+	resp, err := http.Get(srv.URL + "/transactions/txn-1?field_case=camel")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	camel := decodeRaw(t, readBody(t, resp))
+
+	if len(camel) != len(snake) {
+		t.Errorf("expected %d keys, got %d: %v", len(snake), len(camel), camel)
+	}
+	for key, value := range snake {
+		if got := camel[snakeToCamel(key)]; string(got) != string(value) {
+			t.Errorf("%s: expected %s as %s, got %s", key, value, snakeToCamel(key), got)
+		}
+	}
+	// Metadata keys are client data, not field names
+	if string(camel["metadata"]) != `{"order_id":"42"}` {
+		t.Errorf("expected metadata keys unchanged, got %s", camel["metadata"])
+	}
+}
+
+// Test: TestFieldCase_camelListAndComputed
+// What: field_case=camel also applies to lists, the envelope, and expand=computed
+// Input: txn-1; GET /transactions?field_case=camel&envelope=true, GET /transactions/txn-1?field_case=camel&expand=computed
+// Output: data[0] has effectiveAt; the computed object has ageDays and amountFormatted
+func TestFieldCase_camelListAndComputed(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, fullTxn)
+
+	var envelope struct {
+		Data []map[string]json.RawMessage `json:"data"`
+	}
+	json.Unmarshal(readBody(t, getTxns(t, srv, "field_case=camel&envelope=true")), &envelope)
+	if len(envelope.Data) != 1 || envelope.Data[0]["effectiveAt"] == nil {
+		t.Errorf("expected camelCase list items, got %v", envelope.Data)
+	}
+
+	resp, err := http.Get(srv.URL + "/transactions/txn-1?field_case=camel&expand=computed")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	var computed struct {
+		Computed map[string]json.RawMessage `json:"computed"`
+	}
+	json.Unmarshal(readBody(t, resp), &computed)
+	if computed.Computed["ageDays"] == nil || computed.Computed["amountFormatted"] == nil {
+		t.Errorf("expected camelCase computed keys, got %v", computed.Computed)
+	}
+}
+
+// Test: TestFieldCase_invalid
+// What: an unknown field_case is rejected
+// Input: GET /transactions?field_case=kebab
+// Output: HTTP 400
+func TestFieldCase_invalid(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := getTxns(t, srv, "field_case=kebab")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
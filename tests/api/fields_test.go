@@ -0,0 +1,108 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+)
+
+// decodeList decodes a JSON array of transactions into raw key/value maps.
+func decodeList(t *testing.T, body []byte) []map[string]json.RawMessage {
+	t.Helper()
+	var list []map[string]json.RawMessage
+	if err := json.Unmarshal(body, &list); err != nil {
+		t.Fatalf("failed to decode list %s: %v", body, err)
+	}
+	return list
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Test: TestListFields_projectsSubset
+// What: fields= keeps only the named fields, with the same values as the full encoding, in the
+// array, envelope and NDJSON responses
+// Input: txn-1 with every optional field; GET /transactions?fields=id,amount,metadata (plain,
+// envelope=true, and Accept: application/x-ndjson)
+// Output: each transaction has exactly id, amount and metadata, matching the unprojected values
+func TestListFields_projectsSubset(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, fullTxn)
+	full := decodeList(t, readBody(t, getTxns(t, srv, "")))[0]
+
+	check := func(name string, txn map[string]json.RawMessage) {
+		t.Helper()
+		if keys := sortedKeys(txn); !slices.Equal(keys, []string{"amount", "id", "metadata"}) {
+			t.Errorf("%s: expected keys [amount id metadata], got %v", name, keys)
+		}
+		for key, value := range txn {
+			if string(value) != string(full[key]) {
+				t.Errorf("%s: %s: expected %s, got %s", name, key, full[key], value)
+			}
+		}
+	}
+
+	check("array", decodeList(t, readBody(t, getTxns(t, srv, "fields=id,amount,metadata")))[0])
+
+	var envelope struct {
+		Data []map[string]json.RawMessage `json:"data"`
+	}
+	json.Unmarshal(readBody(t, getTxns(t, srv, "fields=id,amount,metadata&envelope=true")), &envelope)
+	if len(envelope.Data) != 1 {
+		t.Fatalf("expected 1 enveloped transaction, got %d", len(envelope.Data))
+	}
+	check("envelope", envelope.Data[0])
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/transactions?fields=id,amount,metadata", nil)
+	req.Header.Set("Accept", api.ContentTypeNDJSON)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	check("ndjson", decodeRaw(t, readBody(t, resp)))
+}
+
+// Test: TestListFields_composesWithFieldCase
+// What: with field_case=camel, fields are still named in snake_case but written in camelCase
+// Input: txn-1; GET /transactions?fields=id,effective_at&field_case=camel
+// Output: keys id and effectiveAt only
+func TestListFields_composesWithFieldCase(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, fullTxn)
+
+	txn := decodeList(t, readBody(t, getTxns(t, srv, "fields=id,effective_at&field_case=camel")))[0]
+	if keys := sortedKeys(txn); !slices.Equal(keys, []string{"effectiveAt", "id"}) {
+		t.Errorf("expected keys [effectiveAt id], got %v", keys)
+	}
+}
+
+// Test: TestListFields_rejectsInvalid
+// What: unknown or empty field lists, and fields with CSV, are rejected
+// Input: fields=id,balance; fields=,; fields=id&format=csv
+// Output: HTTP 400 each; the unknown-field message names "balance" and lists the valid fields
+func TestListFields_rejectsInvalid(t *testing.T) {
+	srv := newTestServer(t)
+
+	for _, query := range []string{"fields=id,balance", "fields=,", "fields=id&format=csv"} {
+		resp := getTxns(t, srv, query)
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, resp.StatusCode)
+		}
+		if query == "fields=id,balance" && (!strings.Contains(string(body), `"balance"`) || !strings.Contains(string(body), "effective_at")) {
+			t.Errorf("expected the error to name balance and list valid fields, got %q", body)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package api_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// Test: TestMetrics_filterSelectivity
+// What: GET /metrics reports, per combination of active filters, the fraction of scanned rows
+// each list request matched, plus scanned and matched row totals
+// Input: 3 USD debits and 1 EUR credit; GET ?currency=EUR, GET ?currency=USD&direction=debit,
+// an unfiltered GET, then GET /metrics
+// Output: currency has one observation of 0.25 (in le=0.25, not le=0.1) with 4 scanned and 1
+// matched; currency,direction has one observation of 0.75; there is no unfiltered series
+func TestMetrics_filterSelectivity(t *testing.T) {
+	srv := newTestServer(t)
+	for i, c := range []struct{ currency, direction string }{
+		{"USD", "debit"}, {"USD", "debit"}, {"USD", "debit"}, {"EUR", "credit"},
+	} {
+		seedTxn(t, srv, fmt.Sprintf(`{"id":"txn-%d","account_id":"acct-1","amount":100,"currency":%q,"direction":%q,"effective_at":"2024-01-0%dT00:00:00Z"}`,
+			i, c.currency, c.direction, i+1))
+	}
+	for _, query := range []string{"currency=EUR", "currency=USD&direction=debit", ""} {
+		readBody(t, getTxns(t, srv, query))
+	}
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	text := string(body)
+
+	for _, want := range []string{
+		`transaction_filter_selectivity_bucket{filters="currency",le="0.1"} 0`,
+		`transaction_filter_selectivity_bucket{filters="currency",le="0.25"} 1`,
+		`transaction_filter_selectivity_sum{filters="currency"} 0.25`,
+		`transaction_filter_selectivity_count{filters="currency"} 1`,
+		`transaction_filter_rows_scanned_total{filters="currency"} 4`,
+		`transaction_filter_rows_matched_total{filters="currency"} 1`,
+		`transaction_filter_selectivity_sum{filters="currency,direction"} 0.75`,
+		`transaction_filter_rows_matched_total{filters="currency,direction"} 3`,
+	} {
+		if !strings.Contains(text, want+"\n") {
+			t.Errorf("expected metrics to contain %q, got:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, `transaction_filter_selectivity_count{filters=""}`) {
+		t.Error("expected no series for the unfiltered list")
+	}
+}
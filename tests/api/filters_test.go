@@ -1,6 +1,8 @@
 package api_test
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -25,11 +27,11 @@ var filterTestData = []model.Transaction{
 }
 
 // Test: TestApplyFilters_noFilters
-// What: ApplyFilters with all nil/empty args returns all transactions unchanged
+// What: ApplyFilters with a zero Filter returns all transactions unchanged
 // Input: filterTestData (4 transactions), no currency/date/amount filters
 // Output: all 4 transactions
 func TestApplyFilters_noFilters(t *testing.T) {
-	result := api.ApplyFilters(filterTestData, "", nil, nil, nil, nil)
+	result := api.ApplyFilters(filterTestData, api.Filter{})
 	if len(result) != len(filterTestData) {
 		t.Errorf("expected %d results with no filters, got %d", len(filterTestData), len(result))
 	}
@@ -40,7 +42,7 @@ func TestApplyFilters_noFilters(t *testing.T) {
 // Input: empty []model.Transaction, currency="USD"
 // Output: empty slice
 func TestApplyFilters_emptyInput(t *testing.T) {
-	result := api.ApplyFilters([]model.Transaction{}, "USD", nil, nil, nil, nil)
+	result := api.ApplyFilters([]model.Transaction{}, api.Filter{Currencies: api.ParseCurrencies("USD")})
 	if len(result) != 0 {
 		t.Errorf("expected empty result for empty input, got %d", len(result))
 	}
@@ -51,7 +53,7 @@ func TestApplyFilters_emptyInput(t *testing.T) {
 // Input: filterTestData, currency="USD"
 // Output: 2 USD transactions (usd-jan-low, usd-feb-high)
 func TestApplyFilters_byCurrency(t *testing.T) {
-	result := api.ApplyFilters(filterTestData, "USD", nil, nil, nil, nil)
+	result := api.ApplyFilters(filterTestData, api.Filter{Currencies: api.ParseCurrencies("USD")})
 	if len(result) != 2 {
 		t.Errorf("expected 2 USD transactions, got %d", len(result))
 	}
@@ -67,19 +69,54 @@ func TestApplyFilters_byCurrency(t *testing.T) {
 // Input: filterTestData, currency="usd" (lowercase)
 // Output: 2 transactions (same as "USD")
 func TestApplyFilters_byCurrencyCaseInsensitive(t *testing.T) {
-	result := api.ApplyFilters(filterTestData, "usd", nil, nil, nil, nil)
+	result := api.ApplyFilters(filterTestData, api.Filter{Currencies: api.ParseCurrencies("usd")})
 	if len(result) != 2 {
 		t.Errorf("expected 2 results for lowercase 'usd', got %d", len(result))
 	}
 }
 
+// Test: TestApplyFilters_byMultipleCurrencies
+// What: a comma-separated currency list matches any listed currency, case-insensitively
+// Input: filterTestData, currency="usd, EUR"
+// Output: 3 transactions (2 USD + 1 EUR); the GBP one is excluded
+func TestApplyFilters_byMultipleCurrencies(t *testing.T) {
+	result := api.ApplyFilters(filterTestData, api.Filter{Currencies: api.ParseCurrencies("usd, EUR")})
+	if len(result) != 3 {
+		t.Fatalf("expected 3 USD/EUR transactions, got %d", len(result))
+	}
+	for _, txn := range result {
+		if txn.Currency == "GBP" {
+			t.Errorf("GBP transaction %q should be excluded", txn.ID)
+		}
+	}
+}
+
+// Test: TestParseCurrencies
+// What: ParseCurrencies uppercases, trims, and drops blank entries
+// Input: "", "usd", " usd ,EUR,,"
+// Output: nil, {USD}, {USD, EUR}
+func TestParseCurrencies(t *testing.T) {
+	if got := api.ParseCurrencies(""); got != nil {
+		t.Errorf("expected nil for empty input, got %v", got)
+	}
+	if got := api.ParseCurrencies("usd"); len(got) != 1 {
+		t.Errorf("expected {USD}, got %v", got)
+	}
+	got := api.ParseCurrencies(" usd ,EUR,,")
+	_, usd := got["USD"]
+	_, eur := got["EUR"]
+	if len(got) != 2 || !usd || !eur {
+		t.Errorf("expected {USD, EUR}, got %v", got)
+	}
+}
+
 // Test: TestApplyFilters_byStartDate
 // What: ApplyFilters with a start date excludes transactions before that date
 // Input: filterTestData, startDate=2024-02-01
 // Output: 2 transactions (Feb and Mar; Jan filtered out)
 func TestApplyFilters_byStartDate(t *testing.T) {
 	startDate := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
-	result := api.ApplyFilters(filterTestData, "", &startDate, nil, nil, nil)
+	result := api.ApplyFilters(filterTestData, api.Filter{StartDate: &startDate})
 
 	if len(result) != 2 {
 		t.Errorf("expected 2 results after start_date=2024-02-01, got %d", len(result))
@@ -92,7 +129,7 @@ func TestApplyFilters_byStartDate(t *testing.T) {
 // Output: 2 transactions (Jan 10 + Jan 20)
 func TestApplyFilters_byEndDate(t *testing.T) {
 	endDate := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
-	result := api.ApplyFilters(filterTestData, "", nil, &endDate, nil, nil)
+	result := api.ApplyFilters(filterTestData, api.Filter{EndDate: &endDate})
 
 	if len(result) != 2 {
 		t.Errorf("expected 2 Jan results, got %d", len(result))
@@ -110,7 +147,7 @@ func TestApplyFilters_endDateIsInclusive(t *testing.T) {
 		makeFilterTxn("excluded", "USD", 100, 2024, 1, 12),
 	}
 
-	result := api.ApplyFilters(txns, "", nil, &endDate, nil, nil)
+	result := api.ApplyFilters(txns, api.Filter{EndDate: &endDate})
 	if len(result) != 1 {
 		t.Errorf("expected 1 result (inclusive end date), got %d", len(result))
 	}
@@ -126,7 +163,7 @@ func TestApplyFilters_endDateIsInclusive(t *testing.T) {
 func TestApplyFilters_byDateRange(t *testing.T) {
 	start := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
 	end := time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC)
-	result := api.ApplyFilters(filterTestData, "", &start, &end, nil, nil)
+	result := api.ApplyFilters(filterTestData, api.Filter{StartDate: &start, EndDate: &end})
 
 	if len(result) != 2 {
 		t.Errorf("expected 2 results in date range, got %d", len(result))
@@ -139,7 +176,7 @@ func TestApplyFilters_byDateRange(t *testing.T) {
 // Output: 2 transactions (eur-jan-mid=5000, usd-feb-high=50000)
 func TestApplyFilters_byMinAmount(t *testing.T) {
 	min := int64(1000)
-	result := api.ApplyFilters(filterTestData, "", nil, nil, &min, nil)
+	result := api.ApplyFilters(filterTestData, api.Filter{MinAmount: &min})
 
 	if len(result) != 2 {
 		t.Errorf("expected 2 results with min_amount=1000, got %d", len(result))
@@ -152,7 +189,7 @@ func TestApplyFilters_byMinAmount(t *testing.T) {
 // Output: 2 transactions (usd-jan-low=500, gbp-mar-low=300)
 func TestApplyFilters_byMaxAmount(t *testing.T) {
 	max := int64(1000)
-	result := api.ApplyFilters(filterTestData, "", nil, nil, nil, &max)
+	result := api.ApplyFilters(filterTestData, api.Filter{MaxAmount: &max})
 
 	if len(result) != 2 {
 		t.Errorf("expected 2 results with max_amount=1000, got %d", len(result))
@@ -166,13 +203,50 @@ func TestApplyFilters_byMaxAmount(t *testing.T) {
 func TestApplyFilters_byExactAmountRange(t *testing.T) {
 	min := int64(500)
 	max := int64(500)
-	result := api.ApplyFilters(filterTestData, "", nil, nil, &min, &max)
+	result := api.ApplyFilters(filterTestData, api.Filter{MinAmount: &min, MaxAmount: &max})
 
 	if len(result) != 1 || result[0].ID != "usd-jan-low" {
 		t.Errorf("expected only 'usd-jan-low' for exact amount 500, got %d results", len(result))
 	}
 }
 
+// Test: TestFilterMatches_amountBoundModes
+// What: inclusive bounds keep a transaction whose amount equals the bound; exclusive bounds drop it
+// Input: transactions with amounts 99, 100, 101 against min/max=100 in each mode
+// Output: min inclusive {100,101}, min exclusive {101}, max inclusive {99,100}, max exclusive {99}
+func TestFilterMatches_amountBoundModes(t *testing.T) {
+	bound := int64(100)
+	txns := []model.Transaction{
+		makeFilterTxn("99", "USD", 99, 2024, 1, 1),
+		makeFilterTxn("100", "USD", 100, 2024, 1, 1),
+		makeFilterTxn("101", "USD", 101, 2024, 1, 1),
+	}
+
+	tests := []struct {
+		name   string
+		filter api.Filter
+		want   []string
+	}{
+		{"min inclusive", api.Filter{MinAmount: &bound}, []string{"100", "101"}},
+		{"min exclusive", api.Filter{MinAmount: &bound, MinExclusive: true}, []string{"101"}},
+		{"max inclusive", api.Filter{MaxAmount: &bound}, []string{"99", "100"}},
+		{"max exclusive", api.Filter{MaxAmount: &bound, MaxExclusive: true}, []string{"99"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := api.ApplyFilters(txns, tt.filter)
+			got := make([]string, len(result))
+			for i, txn := range result {
+				got[i] = txn.ID
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
 // Test: TestApplyFilters_combined
 // What: ApplyFilters ANDs all active filters — only transactions matching every filter are returned
 // Input: filterTestData, currency="USD", start=2024-01-01, end=2024-01-31, minAmount=100, maxAmount=600
@@ -182,7 +256,7 @@ func TestApplyFilters_combined(t *testing.T) {
 	end := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
 	min := int64(100)
 	max := int64(600)
-	result := api.ApplyFilters(filterTestData, "USD", &start, &end, &min, &max)
+	result := api.ApplyFilters(filterTestData, api.Filter{Currencies: api.ParseCurrencies("USD"), StartDate: &start, EndDate: &end, MinAmount: &min, MaxAmount: &max})
 
 	if len(result) != 1 {
 		t.Errorf("expected 1 result with combined filters, got %d", len(result))
@@ -197,8 +271,227 @@ func TestApplyFilters_combined(t *testing.T) {
 // Input: filterTestData, currency="JPY" (not present in data)
 // Output: empty slice
 func TestApplyFilters_noMatches(t *testing.T) {
-	result := api.ApplyFilters(filterTestData, "JPY", nil, nil, nil, nil)
+	result := api.ApplyFilters(filterTestData, api.Filter{Currencies: api.ParseCurrencies("JPY")})
 	if len(result) != 0 {
 		t.Errorf("expected 0 results for JPY filter, got %d", len(result))
 	}
 }
+
+// matchedIDs returns the IDs of the transactions f.Matches, in order.
+func matchedIDs(txns []model.Transaction, f api.Filter) []string {
+	var ids []string
+	for _, txn := range txns {
+		if f.Matches(txn) {
+			ids = append(ids, txn.ID)
+		}
+	}
+	return ids
+}
+
+// Test: TestFilterMatches_directionTreatsMissingAsDebit
+// What: Filter.Matches checks direction and counts older data without a direction as a debit
+// Input: one debit, one credit, one with no direction; Direction="debit"
+// Output: the debit and the direction-less transaction
+func TestFilterMatches_directionTreatsMissingAsDebit(t *testing.T) {
+	txns := []model.Transaction{
+		{ID: "debit", Direction: model.DirectionDebit},
+		{ID: "credit", Direction: model.DirectionCredit},
+		{ID: "legacy"},
+	}
+
+	if got := matchedIDs(txns, api.Filter{Direction: model.DirectionDebit}); !reflect.DeepEqual(got, []string{"debit", "legacy"}) {
+		t.Errorf("expected [debit legacy], got %v", got)
+	}
+}
+
+// Test: TestFilterMatches_emptyDirection
+// What: an empty direction disables the filter
+// Input: filterTestData (4 transactions), Direction=""
+// Output: all 4 transactions
+func TestFilterMatches_emptyDirection(t *testing.T) {
+	if got := matchedIDs(filterTestData, api.Filter{}); len(got) != len(filterTestData) {
+		t.Errorf("expected %d, got %v", len(filterTestData), got)
+	}
+}
+
+// Test: TestApplyFilters_byAccountID
+// What: account_id is an exact, case-sensitive match; older data without an account never matches
+// Input: transactions in acct-1, ACCT-1, acct-2 and one with no account; AccountID="acct-1"
+// Output: only the acct-1 transaction
+func TestApplyFilters_byAccountID(t *testing.T) {
+	txns := []model.Transaction{
+		{ID: "a", AccountID: "acct-1"},
+		{ID: "b", AccountID: "ACCT-1"},
+		{ID: "c", AccountID: "acct-2"},
+		{ID: "legacy"},
+	}
+
+	result := api.ApplyFilters(txns, api.Filter{AccountID: "acct-1"})
+	if len(result) != 1 || result[0].ID != "a" {
+		t.Errorf("expected [a], got %+v", result)
+	}
+}
+
+var searchTestData = []model.Transaction{
+	{ID: "txn-mobile-1", Metadata: map[string]string{"channel": "web"}},
+	{ID: "txn-2", Metadata: map[string]string{"source": "Mobile App"}},
+	{ID: "txn-3", Metadata: map[string]string{"source": "pos"}},
+	{ID: "txn-4"},
+}
+
+// Test: TestFilterMatches_searchMetadataValue
+// What: Search matches a substring of a metadata value case-insensitively
+// Input: searchTestData, Search="APP"
+// Output: only txn-2 (metadata source="Mobile App")
+func TestFilterMatches_searchMetadataValue(t *testing.T) {
+	if got := matchedIDs(searchTestData, api.Filter{Search: "APP"}); !reflect.DeepEqual(got, []string{"txn-2"}) {
+		t.Errorf("expected [txn-2], got %v", got)
+	}
+}
+
+// Test: TestFilterMatches_searchID
+// What: Search matches a substring of the transaction ID as well as metadata values
+// Input: searchTestData, Search="mobile"
+// Output: txn-mobile-1 (ID match) and txn-2 (metadata match)
+func TestFilterMatches_searchID(t *testing.T) {
+	if got := matchedIDs(searchTestData, api.Filter{Search: "mobile"}); !reflect.DeepEqual(got, []string{"txn-mobile-1", "txn-2"}) {
+		t.Errorf("expected [txn-mobile-1 txn-2], got %v", got)
+	}
+}
+
+// Test: TestFilterMatches_searchNoMatch
+// What: a Search that appears nowhere matches nothing; metadata keys are not searched
+// Input: searchTestData, Search="channel" (only present as a key)
+// Output: no matches
+func TestFilterMatches_searchNoMatch(t *testing.T) {
+	if got := matchedIDs(searchTestData, api.Filter{Search: "channel"}); len(got) != 0 {
+		t.Errorf("expected no matches, got %v", got)
+	}
+}
+
+// Test: TestFilterMatches_emptySearch
+// What: an empty Search disables the filter
+// Input: searchTestData, Search=""
+// Output: all 4 transactions
+func TestFilterMatches_emptySearch(t *testing.T) {
+	if got := matchedIDs(searchTestData, api.Filter{Search: ""}); len(got) != len(searchTestData) {
+		t.Errorf("expected %d, got %v", len(searchTestData), got)
+	}
+}
+
+// Test: TestFilterMatches_eachDimension
+// What: Filter.Matches checks each filter field on its own, with boundaries inclusive
+// Input: usd-jan-low (USD, 500, 2024-01-10, metadata source=app) against one-field filters
+// Output: match/no-match per case as listed in the table
+func TestFilterMatches_eachDimension(t *testing.T) {
+	txn := makeFilterTxn("usd-jan-low", "USD", 500, 2024, 1, 10)
+	txn.Direction = model.DirectionCredit
+	txn.Metadata = map[string]string{"source": "app"}
+	txn.Tags = []string{"refund", "vip"}
+
+	day := func(d int) *time.Time {
+		v := time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC)
+		return &v
+	}
+	amount := func(a int64) *int64 { return &a }
+
+	tests := []struct {
+		name   string
+		filter api.Filter
+		want   bool
+	}{
+		{"zero filter", api.Filter{}, true},
+		{"currency match", api.Filter{Currencies: api.ParseCurrencies("usd")}, true},
+		{"currency mismatch", api.Filter{Currencies: api.ParseCurrencies("EUR")}, false},
+		{"start date on day", api.Filter{StartDate: day(10)}, true},
+		{"start date after", api.Filter{StartDate: day(11)}, false},
+		{"end date on day", api.Filter{EndDate: day(10)}, true},
+		{"end date before", api.Filter{EndDate: day(9)}, false},
+		{"min amount equal", api.Filter{MinAmount: amount(500)}, true},
+		{"min amount above", api.Filter{MinAmount: amount(501)}, false},
+		{"max amount equal", api.Filter{MaxAmount: amount(500)}, true},
+		{"max amount below", api.Filter{MaxAmount: amount(499)}, false},
+		{"direction match", api.Filter{Direction: model.DirectionCredit}, true},
+		{"direction mismatch", api.Filter{Direction: model.DirectionDebit}, false},
+		{"search metadata", api.Filter{Search: "APP"}, true},
+		{"search id", api.Filter{Search: "jan"}, true},
+		{"search miss", api.Filter{Search: "web"}, false},
+		{"has metadata key", api.Filter{HasMetadata: "source"}, true},
+		{"has metadata absent key", api.Filter{HasMetadata: "channel"}, false},
+		{"missing metadata absent key", api.Filter{MissingMetadata: "channel"}, true},
+		{"missing metadata present key", api.Filter{MissingMetadata: "source"}, false},
+		{"tag present", api.Filter{Tags: []string{"refund"}}, true},
+		{"all tags present", api.Filter{Tags: []string{"refund", "vip"}}, true},
+		{"one tag absent", api.Filter{Tags: []string{"refund", "other"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Matches(txn); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test: TestFilter_DateRangeOnly
+// What: only filters without currency, amount, direction, search, metadata keys or tags qualify for the date fast path
+// Input: an empty filter, a date-only filter, and filters with each other field set
+// Output: true for the first two, false for the rest
+func TestFilter_DateRangeOnly(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	amount := int64(100)
+
+	cases := []struct {
+		name   string
+		filter api.Filter
+		want   bool
+	}{
+		{"empty", api.Filter{}, true},
+		{"dates", api.Filter{StartDate: &start, EndDate: &start}, true},
+		{"account", api.Filter{StartDate: &start, AccountID: "acct-1"}, false},
+		{"currency", api.Filter{StartDate: &start, Currencies: api.ParseCurrencies("USD")}, false},
+		{"min amount", api.Filter{MinAmount: &amount}, false},
+		{"max amount", api.Filter{MaxAmount: &amount}, false},
+		{"direction", api.Filter{Direction: model.DirectionDebit}, false},
+		{"search", api.Filter{Search: "x"}, false},
+		{"has metadata", api.Filter{HasMetadata: "x"}, false},
+		{"missing metadata", api.Filter{MissingMetadata: "x"}, false},
+		{"tags", api.Filter{Tags: []string{"x"}}, false},
+	}
+	for _, tc := range cases {
+		if got := tc.filter.DateRangeOnly(); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+// Test: TestFilterMatches_nilMetadata
+// What: a transaction without metadata lacks every key
+// Input: txn with nil metadata; HasMetadata="source", then MissingMetadata="source"
+// Output: no match, then match
+func TestFilterMatches_nilMetadata(t *testing.T) {
+	txn := makeFilterTxn("bare", "USD", 100, 2024, 1, 1)
+
+	if (api.Filter{HasMetadata: "source"}).Matches(txn) {
+		t.Error("expected nil metadata not to have the key")
+	}
+	if !(api.Filter{MissingMetadata: "source"}).Matches(txn) {
+		t.Error("expected nil metadata to be missing the key")
+	}
+}
+
+// Test: TestValidateMetadataKeyFilters
+// What: has_metadata and missing_metadata may be combined unless they name the same key
+// Input: ("", ""), ("source", ""), ("source", "channel"), ("source", "source")
+// Output: nil, nil, nil, error
+func TestValidateMetadataKeyFilters(t *testing.T) {
+	for _, ok := range [][2]string{{"", ""}, {"source", ""}, {"source", "channel"}} {
+		if err := api.ValidateMetadataKeyFilters(ok[0], ok[1]); err != nil {
+			t.Errorf("%q/%q: unexpected error %v", ok[0], ok[1], err)
+		}
+	}
+	if err := api.ValidateMetadataKeyFilters("source", "source"); err == nil {
+		t.Error("expected an error for the same key in both filters")
+	}
+}
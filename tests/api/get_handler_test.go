@@ -2,9 +2,12 @@ package api_test
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/synctera/tech-challenge/internal/api"
 	"github.com/synctera/tech-challenge/internal/model"
 )
 
@@ -14,7 +17,7 @@ import (
 // Output: HTTP 200, response body contains the transaction
 func TestGetTransaction_success(t *testing.T) {
 	srv := newTestServer(t)
-	seedTxn(t, srv, `{"id":"txn-1","amount":1000,"currency":"USD","effective_at":"2024-01-15T12:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":1000,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z"}`)
 
 	resp := getTxnByID(t, srv, "txn-1")
 	defer resp.Body.Close()
@@ -47,13 +50,44 @@ func TestGetTransaction_notFound(t *testing.T) {
 	}
 }
 
+// Test: TestGetTransaction_invalidID
+// What: GET /transactions/{id} rejects IDs that could never have been stored
+// Input: lookups by "a%2Fb" (an encoded slash) and by a 129-character ID
+// Output: HTTP 400 for both
+func TestGetTransaction_invalidID(t *testing.T) {
+	srv := newTestServer(t)
+
+	for _, id := range []string{"a%2Fb", strings.Repeat("x", api.MaxIDLength+1)} {
+		resp := getTxnByID(t, srv, id)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("id %q: expected 400, got %d", id, resp.StatusCode)
+		}
+	}
+}
+
+// Test: TestCreateTransaction_invalidID
+// What: POST /transactions rejects an ID containing a slash, so it can't be stored unreachable
+// Input: POST with id "a/b"
+// Output: HTTP 400; nothing is stored
+func TestCreateTransaction_invalidID(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := postTxn(t, srv, `{"id":"a/b","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+	assertIDs(t, listIDs(t, srv.URL+"/transactions"))
+}
+
 // Test: TestGetTransaction_responseBodyFields
 // What: GET /transactions/{id} returns all fields of the stored transaction intact
 // Input: transaction with id="txn-42", amount=4200, currency="EUR", effective_at="2024-06-01T00:00:00Z"
 // Output: HTTP 200, decoded body has matching ID, Amount, and Currency
 func TestGetTransaction_responseBodyFields(t *testing.T) {
 	srv := newTestServer(t)
-	seedTxn(t, srv, `{"id":"txn-42","amount":4200,"currency":"EUR","effective_at":"2024-06-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"txn-42","account_id":"acct-1","amount":4200,"currency":"EUR","direction":"debit","effective_at":"2024-06-01T00:00:00Z"}`)
 
 	resp := getTxnByID(t, srv, "txn-42")
 	defer resp.Body.Close()
@@ -80,9 +114,9 @@ func TestGetTransaction_responseBodyFields(t *testing.T) {
 // Output: HTTP 200, response body contains txn-2 with amount=200
 func TestGetTransaction_correctTransactionAmongMany(t *testing.T) {
 	srv := newTestServer(t)
-	seedTxn(t, srv, `{"id":"txn-1","amount":100,"currency":"USD","effective_at":"2024-01-01T00:00:00Z"}`)
-	seedTxn(t, srv, `{"id":"txn-2","amount":200,"currency":"EUR","effective_at":"2024-01-02T00:00:00Z"}`)
-	seedTxn(t, srv, `{"id":"txn-3","amount":300,"currency":"GBP","effective_at":"2024-01-03T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"txn-2","account_id":"acct-1","amount":200,"currency":"EUR","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"txn-3","account_id":"acct-1","amount":300,"currency":"GBP","direction":"debit","effective_at":"2024-01-03T00:00:00Z"}`)
 
 	resp := getTxnByID(t, srv, "txn-2")
 	defer resp.Body.Close()
@@ -104,7 +138,7 @@ func TestGetTransaction_correctTransactionAmongMany(t *testing.T) {
 // Output: HTTP 200, response body contains Metadata["source"]="mobile"
 func TestGetTransaction_withMetadata(t *testing.T) {
 	srv := newTestServer(t)
-	seedTxn(t, srv, `{"id":"txn-meta","amount":500,"currency":"USD","effective_at":"2024-01-01T00:00:00Z","metadata":{"source":"mobile"}}`)
+	seedTxn(t, srv, `{"id":"txn-meta","account_id":"acct-1","amount":500,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","metadata":{"source":"mobile"}}`)
 
 	resp := getTxnByID(t, srv, "txn-meta")
 	defer resp.Body.Close()
@@ -122,7 +156,7 @@ func TestGetTransaction_withMetadata(t *testing.T) {
 // Output: HTTP 200, Content-Type header is "application/json"
 func TestGetTransaction_contentTypeJSON(t *testing.T) {
 	srv := newTestServer(t)
-	seedTxn(t, srv, `{"id":"txn-1","amount":100,"currency":"USD","effective_at":"2024-01-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
 
 	resp := getTxnByID(t, srv, "txn-1")
 	defer resp.Body.Close()
@@ -132,3 +166,63 @@ func TestGetTransaction_contentTypeJSON(t *testing.T) {
 		t.Errorf("expected Content-Type application/json, got %q", ct)
 	}
 }
+
+// Test: TestGetTransaction_etag
+// What: GET /transactions/{id} returns an ETag header matching Transaction.ETag
+// Input: one seeded transaction, GET without If-None-Match
+// Output: HTTP 200, ETag header equals the decoded transaction's ETag()
+func TestGetTransaction_etag(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":1000,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z"}`)
+
+	resp := getTxnByID(t, srv, "txn-1")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var got model.Transaction
+	json.NewDecoder(resp.Body).Decode(&got)
+	if etag := resp.Header.Get("ETag"); etag == "" || etag != got.ETag() {
+		t.Errorf("expected ETag %s, got %q", got.ETag(), etag)
+	}
+}
+
+// Test: TestGetTransaction_ifNoneMatch
+// What: a matching If-None-Match returns 304 with no body; a stale one returns 200
+// Input: ETag from a first GET, then GETs with If-None-Match set to that ETag and to "stale"
+// Output: HTTP 304 with empty body, then HTTP 200
+func TestGetTransaction_ifNoneMatch(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":1000,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z"}`)
+
+	first := getTxnByID(t, srv, "txn-1")
+	first.Body.Close()
+	etag := first.Header.Get("ETag")
+
+	conditionalGet := func(ifNoneMatch string) *http.Response {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/transactions/txn-1", nil)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("GET failed: %v", err)
+		}
+		return resp
+	}
+
+	resp := conditionalGet(etag)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotModified {
+		t.Fatalf("expected 304, got %d", resp.StatusCode)
+	}
+	if len(body) != 0 {
+		t.Errorf("expected empty body on 304, got %q", body)
+	}
+
+	resp = conditionalGet(`"stale"`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 for stale ETag, got %d", resp.StatusCode)
+	}
+}
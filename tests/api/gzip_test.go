@@ -0,0 +1,178 @@
+package api_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// newGzipServer serves the API behind GzipMiddleware, as main does.
+func newGzipServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	api.NewHandler(store.NewMemoryStore()).RegisterRoutes(mux, nil)
+	srv := httptest.NewServer(api.GzipMiddleware(api.DefaultGzipMinSize)(mux))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// getEncoded sends a GET with an explicit Accept-Encoding, so the client transport
+// leaves the response body compressed for the test to inspect.
+func getEncoded(t *testing.T, url, acceptEncoding string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	client := &http.Client{Transport: &http.Transport{DisableCompression: true}}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func seedGzipTxns(t *testing.T, srv *httptest.Server, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		seedTxn(t, srv, fmt.Sprintf(`{"id":"txn-%03d","account_id":"acct-1","amount":%d,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`, i, i))
+	}
+}
+
+// Test: TestGzip_compressesLargeList
+// What: a list response over the threshold is gzipped when the client accepts gzip
+// Input: 50 transactions, GET /transactions with Accept-Encoding: gzip
+// Output: Content-Encoding gzip, Content-Type application/json, body decompresses to 50 transactions
+func TestGzip_compressesLargeList(t *testing.T) {
+	srv := newGzipServer(t)
+	seedGzipTxns(t, srv, 50)
+
+	resp := getEncoded(t, srv.URL+"/transactions", "gzip")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("expected Content-Encoding gzip, got %q", got)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected Content-Type application/json, got %q", got)
+	}
+	if got := resp.Header.Get("Vary"); got != "Accept-Encoding" {
+		t.Errorf("expected Vary Accept-Encoding, got %q", got)
+	}
+
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		t.Fatalf("body is not gzip: %v", err)
+	}
+	var txns []model.Transaction
+	if err := json.NewDecoder(zr).Decode(&txns); err != nil {
+		t.Fatalf("failed to decode decompressed body: %v", err)
+	}
+	if len(txns) != 50 {
+		t.Errorf("expected 50 transactions, got %d", len(txns))
+	}
+}
+
+// Test: TestGzip_notAccepted
+// What: clients that don't send Accept-Encoding: gzip (or send q=0) get an uncompressed body
+// Input: 50 transactions, GET /transactions with no Accept-Encoding and with "gzip;q=0"
+// Output: no Content-Encoding, body is plain JSON
+func TestGzip_notAccepted(t *testing.T) {
+	srv := newGzipServer(t)
+	seedGzipTxns(t, srv, 50)
+
+	for _, accept := range []string{"", "gzip;q=0"} {
+		resp := getEncoded(t, srv.URL+"/transactions", accept)
+		if got := resp.Header.Get("Content-Encoding"); got != "" {
+			t.Errorf("Accept-Encoding %q: expected no Content-Encoding, got %q", accept, got)
+		}
+		var txns []model.Transaction
+		if err := json.NewDecoder(resp.Body).Decode(&txns); err != nil || len(txns) != 50 {
+			t.Errorf("Accept-Encoding %q: expected 50 plain JSON transactions, got %d (err %v)", accept, len(txns), err)
+		}
+	}
+}
+
+// Test: TestGzip_smallResponseUncompressed
+// What: responses under the threshold are sent as-is even when gzip is accepted
+// Input: one transaction, GET /transactions/{id} with Accept-Encoding: gzip
+// Output: no Content-Encoding, plain JSON body with the transaction
+func TestGzip_smallResponseUncompressed(t *testing.T) {
+	srv := newGzipServer(t)
+	seedGzipTxns(t, srv, 1)
+
+	resp := getEncoded(t, srv.URL+"/transactions/txn-000", "gzip")
+	if got := resp.Header.Get("Content-Encoding"); got != "" {
+		t.Fatalf("expected no Content-Encoding, got %q", got)
+	}
+	var txn model.Transaction
+	if err := json.NewDecoder(resp.Body).Decode(&txn); err != nil || txn.ID != "txn-000" {
+		t.Errorf("expected plain JSON for txn-000, got %+v (err %v)", txn, err)
+	}
+}
+
+// Test: TestGzip_skipsCompressedContentTypes
+// What: already-compressed content types pass through unchanged
+// Input: handler writing 4KB of image/png through the middleware, Accept-Encoding: gzip
+// Output: no Content-Encoding, body identical to what the handler wrote
+func TestGzip_skipsCompressedContentTypes(t *testing.T) {
+	body := bytes.Repeat([]byte{0x89}, 4096)
+	h := api.GzipMiddleware(api.DefaultGzipMinSize)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(body)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Fatalf("expected no Content-Encoding, got %q", got)
+	}
+	if !bytes.Equal(rec.Body.Bytes(), body) {
+		t.Errorf("expected body to pass through unchanged")
+	}
+}
+
+// Test: TestGzip_setsContentTypeWhenHandlerDoesNot
+// What: the content type is sniffed from the uncompressed bytes, not the gzip stream
+// Input: handler writing 4KB of plain text without a Content-Type, Accept-Encoding: gzip
+// Output: Content-Encoding gzip, Content-Type text/plain, body decompresses to the original
+func TestGzip_setsContentTypeWhenHandlerDoesNot(t *testing.T) {
+	body := bytes.Repeat([]byte("hello world\n"), 400)
+	h := api.GzipMiddleware(api.DefaultGzipMinSize)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(body)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Content-Encoding"); got != "gzip" {
+		t.Fatalf("expected Content-Encoding gzip, got %q", got)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/plain; charset=utf-8" {
+		t.Errorf("expected sniffed text/plain, got %q", got)
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatalf("body is not gzip: %v", err)
+	}
+	got, _ := io.ReadAll(zr)
+	if !bytes.Equal(got, body) {
+		t.Errorf("decompressed body does not match what the handler wrote")
+	}
+}
@@ -0,0 +1,209 @@
+package api_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+func histTxn(amount int64, ts string) model.Transaction {
+	at, _ := time.Parse(time.RFC3339, ts)
+	return model.Transaction{Amount: amount, EffectiveAt: at}
+}
+
+// Sorted by effective_at and spanning the Jan/Feb 2024 month boundary.
+// 2024-01-29 is a Monday, so Jan 29 - Feb 4 is one week.
+var histogramData = []model.Transaction{
+	histTxn(100, "2024-01-30T09:00:00Z"),
+	histTxn(200, "2024-01-31T08:00:00Z"),
+	histTxn(300, "2024-01-31T23:59:59Z"),
+	histTxn(400, "2024-02-01T00:00:00Z"),
+	histTxn(500, "2024-02-05T12:00:00Z"),
+}
+
+func assertBuckets(t *testing.T, got []api.HistogramBucket, want []api.HistogramBucket) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d buckets %+v, got %d %+v", len(want), want, len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("bucket %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
+
+func mustBuildHistogram(t *testing.T, txns []model.Transaction, interval string) []api.HistogramBucket {
+	t.Helper()
+	buckets, err := api.BuildHistogram(txns, interval)
+	if err != nil {
+		t.Fatalf("BuildHistogram failed: %v", err)
+	}
+	return buckets
+}
+
+// Test: TestBuildHistogram_day
+// What: daily buckets split at midnight UTC, including across the month boundary
+// Input: histogramData, interval=day
+// Output: Jan 30 (1, 100), Jan 31 (2, 500), Feb 1 (1, 400), Feb 5 (1, 500)
+func TestBuildHistogram_day(t *testing.T) {
+	assertBuckets(t, mustBuildHistogram(t, histogramData, api.IntervalDay), []api.HistogramBucket{
+		{Bucket: "2024-01-30", Count: 1, Sum: 100},
+		{Bucket: "2024-01-31", Count: 2, Sum: 500},
+		{Bucket: "2024-02-01", Count: 1, Sum: 400},
+		{Bucket: "2024-02-05", Count: 1, Sum: 500},
+	})
+}
+
+// Test: TestBuildHistogram_month
+// What: monthly buckets put Jan 31 23:59:59 in January and Feb 1 00:00:00 in February
+// Input: histogramData, interval=month
+// Output: 2024-01-01 (3, 600), 2024-02-01 (2, 900)
+func TestBuildHistogram_month(t *testing.T) {
+	assertBuckets(t, mustBuildHistogram(t, histogramData, api.IntervalMonth), []api.HistogramBucket{
+		{Bucket: "2024-01-01", Count: 3, Sum: 600},
+		{Bucket: "2024-02-01", Count: 2, Sum: 900},
+	})
+}
+
+// Test: TestBuildHistogram_week
+// What: weekly buckets start on Monday and ignore month boundaries
+// Input: histogramData, interval=week
+// Output: week of 2024-01-29 (4, 1000), week of 2024-02-05 (1, 500)
+func TestBuildHistogram_week(t *testing.T) {
+	assertBuckets(t, mustBuildHistogram(t, histogramData, api.IntervalWeek), []api.HistogramBucket{
+		{Bucket: "2024-01-29", Count: 4, Sum: 1000},
+		{Bucket: "2024-02-05", Count: 1, Sum: 500},
+	})
+}
+
+// Test: TestBuildHistogram_empty
+// What: no transactions produce an empty (non-nil) bucket list
+// Input: nil slice, interval=day
+// Output: empty slice
+func TestBuildHistogram_empty(t *testing.T) {
+	got := mustBuildHistogram(t, nil, api.IntervalDay)
+	if got == nil || len(got) != 0 {
+		t.Errorf("expected empty non-nil slice, got %#v", got)
+	}
+}
+
+// Test: TestBuildHistogram_mixedCurrencies
+// What: USD cents and whole JPY in the same period land in separate buckets, never one sum
+// Input: 100 USD, 500 jpy and 250 USD on Jan 30, then 700 JPY on Jan 31; interval=day
+// Output: Jan 30 JPY (1, 500), Jan 30 USD (2, 350), Jan 31 JPY (1, 700)
+func TestBuildHistogram_mixedCurrencies(t *testing.T) {
+	txns := []model.Transaction{
+		histTxn(100, "2024-01-30T09:00:00Z"),
+		histTxn(500, "2024-01-30T10:00:00Z"),
+		histTxn(250, "2024-01-30T11:00:00Z"),
+		histTxn(700, "2024-01-31T09:00:00Z"),
+	}
+	txns[0].Currency, txns[1].Currency, txns[2].Currency, txns[3].Currency = "USD", "jpy", "USD", "JPY"
+
+	assertBuckets(t, mustBuildHistogram(t, txns, api.IntervalDay), []api.HistogramBucket{
+		{Bucket: "2024-01-30", Currency: "JPY", Count: 1, Sum: 500},
+		{Bucket: "2024-01-30", Currency: "USD", Count: 2, Sum: 350},
+		{Bucket: "2024-01-31", Currency: "JPY", Count: 1, Sum: 700},
+	})
+}
+
+// Test: TestBuildHistogram_overflow
+// What: a bucket whose sum exceeds math.MaxInt64 is reported instead of wrapping negative
+// Input: math.MaxInt64 and 1 on the same day
+// Output: error wrapping model.ErrAmountOverflow
+func TestBuildHistogram_overflow(t *testing.T) {
+	txns := []model.Transaction{
+		histTxn(math.MaxInt64, "2024-01-30T09:00:00Z"),
+		histTxn(1, "2024-01-30T10:00:00Z"),
+	}
+	if _, err := api.BuildHistogram(txns, api.IntervalDay); !errors.Is(err, model.ErrAmountOverflow) {
+		t.Errorf("expected ErrAmountOverflow, got %v", err)
+	}
+}
+
+// Test: TestBuildHistogram_nearMaxInt64
+// What: sums that land exactly on math.MaxInt64 are still accepted
+// Input: math.MaxInt64-1 and 1 on the same day
+// Output: one bucket with sum math.MaxInt64
+func TestBuildHistogram_nearMaxInt64(t *testing.T) {
+	txns := []model.Transaction{
+		histTxn(math.MaxInt64-1, "2024-01-30T09:00:00Z"),
+		histTxn(1, "2024-01-30T10:00:00Z"),
+	}
+	assertBuckets(t, mustBuildHistogram(t, txns, api.IntervalDay), []api.HistogramBucket{
+		{Bucket: "2024-01-30", Count: 2, Sum: math.MaxInt64},
+	})
+}
+
+// Test: TestTransactionHistogram_overflow
+// What: the endpoint returns a 500 with a clear message when a bucket sum overflows
+// Input: two transactions of math.MaxInt64 on the same day; interval=day
+// Output: HTTP 500, body mentions the overflow
+func TestTransactionHistogram_overflow(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"a","account_id":"acct-1","amount":9223372036854775807,"currency":"USD","direction":"debit","effective_at":"2024-01-05T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"b","account_id":"acct-1","amount":9223372036854775807,"currency":"USD","direction":"debit","effective_at":"2024-01-05T01:00:00Z"}`)
+
+	resp, err := http.Get(srv.URL + "/transactions/histogram?interval=day")
+	if err != nil {
+		t.Fatalf("GET /transactions/histogram failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", resp.StatusCode)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "overflows") {
+		t.Errorf("expected overflow message, got %q", body)
+	}
+}
+
+// Test: TestTransactionHistogram_endpoint
+// What: GET /transactions/histogram buckets the filtered set
+// Input: two USD and one EUR transaction in January; interval=month&currency=USD
+// Output: HTTP 200, one USD bucket 2024-01-01 with count=2
+func TestTransactionHistogram_endpoint(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"a","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-05T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"b","account_id":"acct-1","amount":200,"currency":"EUR","direction":"debit","effective_at":"2024-01-06T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"c","account_id":"acct-1","amount":300,"currency":"USD","direction":"debit","effective_at":"2024-01-07T00:00:00Z"}`)
+
+	resp, err := http.Get(srv.URL + "/transactions/histogram?interval=month&currency=USD")
+	if err != nil {
+		t.Fatalf("GET /transactions/histogram failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	var got []api.HistogramBucket
+	json.NewDecoder(resp.Body).Decode(&got)
+	assertBuckets(t, got, []api.HistogramBucket{{Bucket: "2024-01-01", Currency: "USD", Count: 2, Sum: 400}})
+}
+
+// Test: TestTransactionHistogram_invalidInterval
+// What: an unsupported interval is rejected
+// Input: interval=year
+// Output: HTTP 400
+func TestTransactionHistogram_invalidInterval(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/transactions/histogram?interval=year")
+	if err != nil {
+		t.Fatalf("GET /transactions/histogram failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,182 @@
+package api_test
+
+import (
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/clock"
+)
+
+var hmacSecret = []byte("s3cret")
+
+// hmacNow is the server clock in the HMAC tests; requests are signed at it unless a test says otherwise.
+var hmacNow = time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// newSigned wraps a handler that echoes the request body in HMACAuthMiddleware, with the
+// clock pinned at hmacNow.
+func newSigned() http.Handler {
+	echo := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	})
+	return api.HMACAuthMiddlewareWithClock(hmacSecret, clock.NewFake(hmacNow))(echo)
+}
+
+// signRequest returns the hex signature of a request signed at signedAt.
+func signRequest(secret []byte, method, target string, signedAt time.Time, body string) string {
+	return hex.EncodeToString(api.Sign(secret, method, target, unixTimestamp(signedAt), []byte(body)))
+}
+
+func unixTimestamp(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+// sendSigned sends method target with body and the given signature, stamped at signedAt.
+func sendSigned(h http.Handler, method, target, body, signature string, signedAt time.Time) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if signature != "" {
+		req.Header.Set(api.SignatureHeader, signature)
+	}
+	req.Header.Set(api.SignatureTimestampHeader, unixTimestamp(signedAt))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func postSigned(h http.Handler, body, signature string) *httptest.ResponseRecorder {
+	return sendSigned(h, http.MethodPost, "/transactions", body, signature, hmacNow)
+}
+
+// Test: TestHMACAuth_validSignature
+// What: a request signed with the shared secret passes, and the handler can still read the body
+// Input: POST /transactions with body {"id":"txn-1"}, signed over method, URI, timestamp and body
+// Output: HTTP 200 echoing the original body
+func TestHMACAuth_validSignature(t *testing.T) {
+	body := `{"id":"txn-1"}`
+	rec := postSigned(newSigned(), body, signRequest(hmacSecret, http.MethodPost, "/transactions", hmacNow, body))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if rec.Body.String() != body {
+		t.Errorf("expected the handler to read %q, got %q", body, rec.Body.String())
+	}
+}
+
+// Test: TestHMACAuth_tamperedBody
+// What: a body changed after signing is rejected
+// Input: signature for {"amount":100} sent with body {"amount":900}
+// Output: HTTP 401
+func TestHMACAuth_tamperedBody(t *testing.T) {
+	signature := signRequest(hmacSecret, http.MethodPost, "/transactions", hmacNow, `{"amount":100}`)
+	if rec := postSigned(newSigned(), `{"amount":900}`, signature); rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
+
+// Test: TestHMACAuth_signatureBoundToRequest
+// What: a signature only authorizes the method and URI it was made for
+// Input: a valid signature for GET /transactions/txn-1 sent on DELETE /transactions/txn-1,
+// and on GET /transactions/txn-2
+// Output: HTTP 401 both times
+func TestHMACAuth_signatureBoundToRequest(t *testing.T) {
+	signature := signRequest(hmacSecret, http.MethodGet, "/transactions/txn-1", hmacNow, "")
+	for _, req := range []struct{ method, target string }{
+		{http.MethodDelete, "/transactions/txn-1"},
+		{http.MethodGet, "/transactions/txn-2"},
+	} {
+		if rec := sendSigned(newSigned(), req.method, req.target, "", signature, hmacNow); rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s: expected 401, got %d", req.method, req.target, rec.Code)
+		}
+	}
+}
+
+// Test: TestHMACAuth_staleTimestamp
+// What: a correctly signed request is rejected once its timestamp is outside SignatureMaxAge,
+// so a captured request can't be replayed later
+// Input: requests signed one second inside the window, and one second beyond it in the past
+// and the future
+// Output: HTTP 200 inside the window; 401 outside it
+func TestHMACAuth_staleTimestamp(t *testing.T) {
+	body := `{"id":"txn-1"}`
+	for _, tc := range []struct {
+		offset time.Duration
+		want   int
+	}{
+		{-api.SignatureMaxAge + time.Second, http.StatusOK},
+		{-api.SignatureMaxAge - time.Second, http.StatusUnauthorized},
+		{api.SignatureMaxAge + time.Second, http.StatusUnauthorized},
+	} {
+		signedAt := hmacNow.Add(tc.offset)
+		signature := signRequest(hmacSecret, http.MethodPost, "/transactions", signedAt, body)
+		if rec := sendSigned(newSigned(), http.MethodPost, "/transactions", body, signature, signedAt); rec.Code != tc.want {
+			t.Errorf("signed %s from now: expected %d, got %d", tc.offset, tc.want, rec.Code)
+		}
+	}
+}
+
+// Test: TestHMACAuth_bodyTooLarge
+// What: the middleware stops buffering at MaxSignedBodyBytes instead of reading any size of body
+// Input: a body one byte over MaxSignedBodyBytes, signed correctly
+// Output: HTTP 413, and the handler never runs
+func TestHMACAuth_bodyTooLarge(t *testing.T) {
+	body := strings.Repeat("a", api.MaxSignedBodyBytes+1)
+	rec := postSigned(newSigned(), body, signRequest(hmacSecret, http.MethodPost, "/transactions", hmacNow, body))
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected 413, got %d", rec.Code)
+	}
+}
+
+// Test: TestHMACAuth_missingOrMalformedSignature
+// What: a request without a signature or timestamp, or with a signature that isn't hex, is rejected
+// Input: no X-Signature header; X-Signature "not-hex"; signature made with a different secret;
+// a valid signature with no X-Signature-Timestamp header
+// Output: HTTP 401 each time
+func TestHMACAuth_missingOrMalformedSignature(t *testing.T) {
+	body := `{"id":"txn-1"}`
+	wrongKey := signRequest([]byte("other"), http.MethodPost, "/transactions", hmacNow, body)
+	for _, signature := range []string{"", "not-hex", wrongKey} {
+		if rec := postSigned(newSigned(), body, signature); rec.Code != http.StatusUnauthorized {
+			t.Errorf("signature %q: expected 401, got %d", signature, rec.Code)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/transactions", strings.NewReader(body))
+	req.Header.Set(api.SignatureHeader, signRequest(hmacSecret, http.MethodPost, "/transactions", hmacNow, body))
+	rec := httptest.NewRecorder()
+	newSigned().ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("no timestamp: expected 401, got %d", rec.Code)
+	}
+}
+
+// Test: TestChain_order
+// What: Chain runs middlewares in the order listed and skips nil entries
+// Input: Chain(a, nil, b) around a handler; each appends its name to a header
+// Output: header order a, b, handler
+func TestChain_order(t *testing.T) {
+	tag := func(name string) api.Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("X-Order", name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("X-Order", "handler")
+	})
+
+	rec := httptest.NewRecorder()
+	api.Chain(tag("a"), nil, tag("b"))(final).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := strings.Join(rec.Header().Values("X-Order"), ","); got != "a,b,handler" {
+		t.Errorf("expected a,b,handler, got %s", got)
+	}
+}
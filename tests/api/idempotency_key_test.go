@@ -0,0 +1,155 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+func postTxnWithKey(t *testing.T, srv *httptest.Server, key, body string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/transactions", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", key)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST /transactions failed: %v", err)
+	}
+	return resp
+}
+
+// Test: TestCreateTransaction_idempotencyKeyRepeat
+// What: a retry with the same Idempotency-Key and ID returns the original transaction even though the payload changed
+// Input: POST with key "k-1" at 12:00, retry with key "k-1" and a regenerated timestamp 12:05
+// Output: first HTTP 201, retry HTTP 200 with the original effective_at; no 409
+func TestCreateTransaction_idempotencyKeyRepeat(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp1 := postTxnWithKey(t, srv, "k-1", `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T12:00:00Z"}`)
+	resp1.Body.Close()
+	if resp1.StatusCode != http.StatusCreated {
+		t.Fatalf("first request: expected 201, got %d", resp1.StatusCode)
+	}
+
+	resp2 := postTxnWithKey(t, srv, "k-1", `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T12:05:00Z"}`)
+	defer resp2.Body.Close()
+	if resp2.StatusCode != http.StatusOK {
+		t.Fatalf("retry: expected 200, got %d", resp2.StatusCode)
+	}
+
+	var got model.Transaction
+	json.NewDecoder(resp2.Body).Decode(&got)
+	if got.EffectiveAt.Minute() != 0 {
+		t.Errorf("expected original effective_at 12:00, got %v", got.EffectiveAt)
+	}
+}
+
+// Test: TestCreateTransaction_idempotencyKeyReuseConflict
+// What: reusing an Idempotency-Key for a different transaction ID is rejected
+// Input: POST txn-1 with key "k-1", then POST txn-2 with key "k-1"
+// Output: second request HTTP 409, and txn-2 is not stored
+func TestCreateTransaction_idempotencyKeyReuseConflict(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp1 := postTxnWithKey(t, srv, "k-1", `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T12:00:00Z"}`)
+	resp1.Body.Close()
+
+	resp2 := postTxnWithKey(t, srv, "k-1", `{"id":"txn-2","account_id":"acct-1","amount":500,"currency":"EUR","direction":"debit","effective_at":"2024-01-02T12:00:00Z"}`)
+	defer resp2.Body.Close()
+	if resp2.StatusCode != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", resp2.StatusCode)
+	}
+
+	get := getTxnByID(t, srv, "txn-2")
+	defer get.Body.Close()
+	if get.StatusCode != http.StatusNotFound {
+		t.Errorf("txn-2 should not have been stored, got %d", get.StatusCode)
+	}
+}
+
+// Test: TestCreateTransaction_idempotencyKeyTooLong
+// What: an oversized Idempotency-Key is rejected before touching the store
+// Input: key of 256 characters
+// Output: HTTP 400
+func TestCreateTransaction_idempotencyKeyTooLong(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := postTxnWithKey(t, srv, strings.Repeat("k", 256), `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T12:00:00Z"}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestCreateTransaction_noKeyStillUsesPayloadIdempotency
+// What: without an Idempotency-Key, a changed payload for the same ID is still a conflict
+// Input: POST txn-1 twice without a key, with different timestamps
+// Output: second request HTTP 409
+func TestCreateTransaction_noKeyStillUsesPayloadIdempotency(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T12:00:00Z"}`)
+
+	resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T12:05:00Z"}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected 409, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestCreateTransaction_idempotencyKeyConcurrentDifferentIDs
+// What: concurrent requests sharing an Idempotency-Key with different IDs store exactly one
+// transaction, and every other request gets a 409 with nothing stored
+// Input: 8 concurrent POSTs with key "k-1" and IDs txn-0..txn-7
+// Output: one HTTP 201 and seven HTTP 409; GET /transactions returns only the 201's transaction
+func TestCreateTransaction_idempotencyKeyConcurrentDifferentIDs(t *testing.T) {
+	srv := newTestServer(t)
+
+	const n = 8
+	var statuses [n]int
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := postTxnWithKey(t, srv, "k-1", fmt.Sprintf(`{"id":"txn-%d","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T12:00:00Z"}`, i))
+			resp.Body.Close()
+			statuses[i] = resp.StatusCode
+		}()
+	}
+	wg.Wait()
+
+	winner := ""
+	for i, status := range statuses {
+		switch status {
+		case http.StatusCreated:
+			if winner != "" {
+				t.Fatalf("expected one 201, got %v", statuses)
+			}
+			winner = fmt.Sprintf("txn-%d", i)
+		case http.StatusConflict:
+		default:
+			t.Fatalf("expected only 201 and 409, got %v", statuses)
+		}
+	}
+	if winner == "" {
+		t.Fatalf("expected one 201, got %v", statuses)
+	}
+
+	list := getTxns(t, srv, "")
+	defer list.Body.Close()
+	var txns []model.Transaction
+	json.NewDecoder(list.Body).Decode(&txns)
+	if len(txns) != 1 || txns[0].ID != winner {
+		t.Errorf("expected only %s stored, got %v", winner, txns)
+	}
+}
@@ -0,0 +1,225 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+func postImport(t *testing.T, srv *httptest.Server, contentType, body string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(srv.URL+"/transactions/_import", contentType, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST /transactions/_import failed: %v", err)
+	}
+	return resp
+}
+
+func decodeImportReport(t *testing.T, resp *http.Response) api.ImportReport {
+	t.Helper()
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var report api.ImportReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	return report
+}
+
+// Test: TestImportTransactions_mixedRows
+// What: an import counts created, duplicate, conflicting and invalid lines, with line numbers for the failures
+// Input: txn-1 already stored; NDJSON with new txn-2, identical txn-1, a blank line, txn-1 with a
+// different amount, a line missing currency, and new txn-3
+// Output: created=2 duplicate=1 conflict=1 invalid=1; errors on lines 4 and 5; txn-2 and txn-3 are fetchable
+func TestImportTransactions_mixedRows(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+
+	body := `{"id":"txn-2","account_id":"acct-1","amount":200,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}
+{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}
+
+{"id":"txn-1","account_id":"acct-1","amount":999,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}
+{"id":"txn-4","account_id":"acct-1","amount":400,"direction":"debit","effective_at":"2024-01-04T00:00:00Z"}
+{"id":"txn-3","account_id":"acct-1","amount":300,"currency":"EUR","direction":"credit","effective_at":"2024-01-03T00:00:00Z"}
+`
+	report := decodeImportReport(t, postImport(t, srv, api.ContentTypeNDJSON, body))
+
+	if report.Created != 2 || report.Duplicate != 1 || report.Conflict != 1 || report.Invalid != 1 {
+		t.Errorf("expected created=2 duplicate=1 conflict=1 invalid=1, got %+v", report)
+	}
+	if len(report.Errors) != 2 || report.Errors[0].Line != 4 || report.Errors[1].Line != 5 {
+		t.Errorf("expected errors on lines 4 and 5, got %+v", report.Errors)
+	}
+	for _, id := range []string{"txn-2", "txn-3"} {
+		resp := getTxnByID(t, srv, id)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected %s to be stored, got %d", id, resp.StatusCode)
+		}
+	}
+}
+
+// Test: TestImportTransactions_reimportIsSafe
+// What: re-running an import of an NDJSON export reports only duplicates, and deleted records stay deleted
+// Input: export (Accept: application/x-ndjson, include_deleted=true) of txn-1 and soft-deleted txn-2;
+// import it into a fresh server twice
+// Output: first import created=2 and txn-2 is deleted; second created=0 duplicate=2
+func TestImportTransactions_reimportIsSafe(t *testing.T) {
+	src := newTestServer(t)
+	seedTxn(t, src, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	seedTxn(t, src, `{"id":"txn-2","account_id":"acct-1","amount":200,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`)
+	deleteTxn(t, src, "txn-2").Body.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, src.URL+"/transactions?include_deleted=true", nil)
+	req.Header.Set("Accept", api.ContentTypeNDJSON)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("export failed: %v", err)
+	}
+	dump := string(readBody(t, resp))
+
+	dst := newTestServer(t)
+	first := decodeImportReport(t, postImport(t, dst, api.ContentTypeNDJSON, dump))
+	if first.Created != 2 || first.Duplicate != 0 {
+		t.Errorf("first import: expected created=2, got %+v", first)
+	}
+	get := getTxnByID(t, dst, "txn-2")
+	defer get.Body.Close()
+	var txn model.Transaction
+	json.NewDecoder(get.Body).Decode(&txn)
+	if !txn.Deleted {
+		t.Errorf("expected txn-2 to be imported as deleted, got %+v", txn)
+	}
+
+	second := decodeImportReport(t, postImport(t, dst, api.ContentTypeNDJSON, dump))
+	if second.Created != 0 || second.Duplicate != 2 || second.Conflict != 0 || second.Invalid != 0 {
+		t.Errorf("second import: expected duplicate=2 only, got %+v", second)
+	}
+}
+
+// Test: TestImportTransactions_rejectsBadRequests
+// What: the import needs an NDJSON content type, and a line over the size cap stops it with 400
+// Input: a JSON content type; an NDJSON body whose second line is MaxImportLineBytes long
+// Output: 415; 400 naming line 2
+func TestImportTransactions_rejectsBadRequests(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := postImport(t, srv, "application/json", `{}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("expected 415, got %d", resp.StatusCode)
+	}
+
+	long := `{"id":"txn-1","account_id":"acct-1","amount":1,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}` +
+		"\n" + strings.Repeat(" ", api.MaxImportLineBytes) + "\n"
+	resp = postImport(t, srv, api.ContentTypeNDJSON, long)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(body), "line 2") {
+		t.Errorf("expected 400 naming line 2, got %d %q", resp.StatusCode, body)
+	}
+}
+
+// Test: TestImportTransactions_exportKeepsTimeZone
+// What: re-importing a store export over HTTP keeps each transaction's time_zone
+// Input: a store holding txn-1 at 12:00Z with time_zone -05:00, exported with ExportTo and
+// posted to /transactions/_import
+// Output: 1 created; GET txn-1 returns effective_at 12:00Z and time_zone -05:00
+func TestImportTransactions_exportKeepsTimeZone(t *testing.T) {
+	src := store.NewMemoryStore()
+	txn := model.Transaction{ID: "txn-1", AccountID: "acct-1", Amount: 100, Currency: "USD", Direction: model.DirectionDebit,
+		EffectiveAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), TimeZone: "-05:00"}
+	if err := src.Create(txn); err != nil {
+		t.Fatal(err)
+	}
+	var dump strings.Builder
+	if err := src.ExportTo(&dump); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+
+	srv := newTestServer(t)
+	if report := decodeImportReport(t, postImport(t, srv, api.ContentTypeNDJSON, dump.String())); report.Created != 1 {
+		t.Fatalf("expected 1 created, got %+v", report)
+	}
+
+	get := getTxnByID(t, srv, "txn-1")
+	defer get.Body.Close()
+	var got model.Transaction
+	json.NewDecoder(get.Body).Decode(&got)
+	if !got.EffectiveAt.Equal(txn.EffectiveAt) || got.TimeZone != "-05:00" {
+		t.Errorf("expected 12:00Z with time_zone -05:00, got %v with %q", got.EffectiveAt, got.TimeZone)
+	}
+}
+
+// Test: TestImportTransactions_exportRoundTrip
+// What: a `server export` dump of reversed and amount-corrected transactions imports cleanly,
+// keeping the server-maintained metadata
+// Input: a store with txn-1 reversed by txn-1-reversal and txn-2 corrected from 100 to 150,
+// exported with ExportTo and posted to /transactions/_import on a fresh server
+// Output: 3 created, none invalid; each imported transaction equals the exported one, with
+// reverses, reversed_by and amount_history intact
+func TestImportTransactions_exportRoundTrip(t *testing.T) {
+	src := store.NewMemoryStore()
+	base := model.Transaction{AccountID: "acct-1", Amount: 100, Currency: "USD", Direction: model.DirectionDebit,
+		EffectiveAt: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	txn1, txn2 := base, base
+	txn1.ID, txn2.ID = "txn-1", "txn-2"
+	if err := src.Create(txn1); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Create(txn2); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Reverse("txn-1", txn1.Reversal("txn-1-reversal", base.EffectiveAt.Add(time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.UpdateAmount("txn-2", 150); err != nil {
+		t.Fatal(err)
+	}
+	var dump strings.Builder
+	if err := src.ExportTo(&dump); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+
+	srv := newTestServer(t)
+	report := decodeImportReport(t, postImport(t, srv, api.ContentTypeNDJSON, dump.String()))
+	if report.Created != 3 || report.Invalid != 0 {
+		t.Fatalf("expected 3 created and none invalid, got %+v", report)
+	}
+
+	for _, id := range []string{"txn-1", "txn-1-reversal", "txn-2"} {
+		want, _ := src.Get(id)
+		get := getTxnByID(t, srv, id)
+		var got model.Transaction
+		json.NewDecoder(get.Body).Decode(&got)
+		get.Body.Close()
+		if !got.Equal(want) {
+			t.Errorf("%s: expected %+v, got %+v", id, want, got)
+		}
+	}
+}
+
+// Test: TestImportTransactions_malformedServerMetadata
+// What: import accepts the server-maintained metadata keys only when they are well formed
+// Input: NDJSON with an amount_history that isn't a list and a reversed_by that isn't an ID
+// Output: 0 created, 2 invalid
+func TestImportTransactions_malformedServerMetadata(t *testing.T) {
+	srv := newTestServer(t)
+	body := `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","metadata":{"amount_history":"oops"}}` + "\n" +
+		`{"id":"txn-2","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","metadata":{"reversed_by":"not an id"}}` + "\n"
+
+	report := decodeImportReport(t, postImport(t, srv, api.ContentTypeNDJSON, body))
+	if report.Created != 0 || report.Invalid != 2 {
+		t.Errorf("expected 0 created and 2 invalid, got %+v", report)
+	}
+}
@@ -0,0 +1,100 @@
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+// linkedPage is the links=true response, decoded only as far as the tests need.
+type linkedPage struct {
+	Data []struct {
+		ID    string `json:"id"`
+		Links struct {
+			Self struct {
+				Href string `json:"href"`
+			} `json:"self"`
+		} `json:"_links"`
+	} `json:"data"`
+	Links map[string]struct {
+		Href string `json:"href"`
+	} `json:"_links"`
+}
+
+func getLinkedPage(t *testing.T, resp *http.Response) linkedPage {
+	t.Helper()
+	var page linkedPage
+	if err := json.Unmarshal(readBody(t, resp), &page); err != nil {
+		t.Fatalf("failed to decode page: %v", err)
+	}
+	return page
+}
+
+// Test: TestListLinks_itemAndPageLinks
+// What: links=true adds a self link to each transaction and self/next/prev links to the page
+// Input: 5 transactions; GET /transactions?links=true&limit=2&offset=2&currency=USD, then follow next
+// Output: each item links to /transactions/{id}; self is the request URL; prev is offset=0 and next
+// is offset=4, both keeping limit=2 and currency=USD; next returns the last transaction and no next link
+func TestListLinks_itemAndPageLinks(t *testing.T) {
+	srv := newTestServer(t)
+	for day := 1; day <= 5; day++ {
+		seedTxn(t, srv, fmt.Sprintf(`{"id":"txn-%d","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-0%dT00:00:00Z"}`, day, day))
+	}
+
+	page := getLinkedPage(t, getTxns(t, srv, "links=true&limit=2&offset=2&currency=USD"))
+	if len(page.Data) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(page.Data))
+	}
+	for _, txn := range page.Data {
+		if want := "/transactions/" + txn.ID; txn.Links.Self.Href != want {
+			t.Errorf("expected self link %s, got %s", want, txn.Links.Self.Href)
+		}
+	}
+	if got := page.Links["self"].Href; got != "/transactions?links=true&limit=2&offset=2&currency=USD" {
+		t.Errorf("unexpected self link %s", got)
+	}
+	for rel, offset := range map[string]string{"prev": "0", "next": "4"} {
+		u, err := url.Parse(page.Links[rel].Href)
+		if err != nil || u.Path != "/transactions" {
+			t.Fatalf("%s: unexpected link %q", rel, page.Links[rel].Href)
+		}
+		q := u.Query()
+		if q.Get("offset") != offset || q.Get("limit") != "2" || q.Get("currency") != "USD" {
+			t.Errorf("%s: expected offset=%s limit=2 currency=USD, got %s", rel, offset, u.RawQuery)
+		}
+	}
+
+	resp, err := http.Get(srv.URL + page.Links["next"].Href)
+	if err != nil {
+		t.Fatalf("GET next failed: %v", err)
+	}
+	last := getLinkedPage(t, resp)
+	if len(last.Data) != 1 || last.Data[0].ID != "txn-5" {
+		t.Errorf("expected the next page to hold txn-5, got %+v", last.Data)
+	}
+	if _, ok := last.Links["next"]; ok {
+		t.Errorf("expected no next link on the last page, got %s", last.Links["next"].Href)
+	}
+}
+
+// Test: TestListLinks_firstPageAndInvalid
+// What: the first page has no prev link, and a non-boolean links value is rejected
+// Input: 1 transaction; GET /transactions?links=true; GET /transactions?links=maybe
+// Output: self link only; HTTP 400
+func TestListLinks_firstPageAndInvalid(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+
+	page := getLinkedPage(t, getTxns(t, srv, "links=true"))
+	if len(page.Links) != 1 || page.Links["self"].Href != "/transactions?links=true" {
+		t.Errorf("expected only a self link, got %+v", page.Links)
+	}
+
+	resp := getTxns(t, srv, "links=maybe")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
@@ -2,10 +2,17 @@ package api_test
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/synctera/tech-challenge/internal/api"
 	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
 )
 
 // Test: TestListTransactions_emptyStore
@@ -35,8 +42,8 @@ func TestListTransactions_emptyStore(t *testing.T) {
 // Output: HTTP 200, 2 transactions in the response body
 func TestListTransactions_returnsAllByDefault(t *testing.T) {
 	srv := newTestServer(t)
-	seedTxn(t, srv, `{"id":"txn-1","amount":100,"currency":"USD","effective_at":"2024-01-01T00:00:00Z"}`)
-	seedTxn(t, srv, `{"id":"txn-2","amount":200,"currency":"USD","effective_at":"2024-01-02T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"txn-2","account_id":"acct-1","amount":200,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`)
 
 	resp := getTxns(t, srv, "")
 	defer resp.Body.Close()
@@ -54,9 +61,9 @@ func TestListTransactions_returnsAllByDefault(t *testing.T) {
 // Output: response contains [txn-1(Jan), txn-2(Feb), txn-3(Mar)]
 func TestListTransactions_orderedChronologically(t *testing.T) {
 	srv := newTestServer(t)
-	seedTxn(t, srv, `{"id":"txn-3","amount":300,"currency":"USD","effective_at":"2024-03-01T00:00:00Z"}`)
-	seedTxn(t, srv, `{"id":"txn-1","amount":100,"currency":"USD","effective_at":"2024-01-01T00:00:00Z"}`)
-	seedTxn(t, srv, `{"id":"txn-2","amount":200,"currency":"USD","effective_at":"2024-02-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"txn-3","account_id":"acct-1","amount":300,"currency":"USD","direction":"debit","effective_at":"2024-03-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"txn-2","account_id":"acct-1","amount":200,"currency":"USD","direction":"debit","effective_at":"2024-02-01T00:00:00Z"}`)
 
 	resp := getTxns(t, srv, "")
 	defer resp.Body.Close()
@@ -82,9 +89,9 @@ func TestListTransactions_orderedChronologically(t *testing.T) {
 func TestListTransactions_paginationLimit(t *testing.T) {
 	srv := newTestServer(t)
 	for _, body := range []string{
-		`{"id":"a","amount":100,"currency":"USD","effective_at":"2024-01-01T00:00:00Z"}`,
-		`{"id":"b","amount":200,"currency":"USD","effective_at":"2024-01-02T00:00:00Z"}`,
-		`{"id":"c","amount":300,"currency":"USD","effective_at":"2024-01-03T00:00:00Z"}`,
+		`{"id":"a","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`,
+		`{"id":"b","account_id":"acct-1","amount":200,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`,
+		`{"id":"c","account_id":"acct-1","amount":300,"currency":"USD","direction":"debit","effective_at":"2024-01-03T00:00:00Z"}`,
 	} {
 		seedTxn(t, srv, body)
 	}
@@ -106,9 +113,9 @@ func TestListTransactions_paginationLimit(t *testing.T) {
 func TestListTransactions_paginationOffset(t *testing.T) {
 	srv := newTestServer(t)
 	for _, body := range []string{
-		`{"id":"a","amount":100,"currency":"USD","effective_at":"2024-01-01T00:00:00Z"}`,
-		`{"id":"b","amount":200,"currency":"USD","effective_at":"2024-01-02T00:00:00Z"}`,
-		`{"id":"c","amount":300,"currency":"USD","effective_at":"2024-01-03T00:00:00Z"}`,
+		`{"id":"a","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`,
+		`{"id":"b","account_id":"acct-1","amount":200,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`,
+		`{"id":"c","account_id":"acct-1","amount":300,"currency":"USD","direction":"debit","effective_at":"2024-01-03T00:00:00Z"}`,
 	} {
 		seedTxn(t, srv, body)
 	}
@@ -132,9 +139,9 @@ func TestListTransactions_paginationOffset(t *testing.T) {
 // Output: 2 transactions, all with Currency="USD"
 func TestListTransactions_filterByCurrency(t *testing.T) {
 	srv := newTestServer(t)
-	seedTxn(t, srv, `{"id":"usd-1","amount":100,"currency":"USD","effective_at":"2024-01-01T00:00:00Z"}`)
-	seedTxn(t, srv, `{"id":"eur-1","amount":200,"currency":"EUR","effective_at":"2024-01-02T00:00:00Z"}`)
-	seedTxn(t, srv, `{"id":"usd-2","amount":300,"currency":"USD","effective_at":"2024-01-03T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"usd-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"eur-1","account_id":"acct-1","amount":200,"currency":"EUR","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"usd-2","account_id":"acct-1","amount":300,"currency":"USD","direction":"debit","effective_at":"2024-01-03T00:00:00Z"}`)
 
 	resp := getTxns(t, srv, "currency=USD")
 	defer resp.Body.Close()
@@ -151,15 +158,35 @@ func TestListTransactions_filterByCurrency(t *testing.T) {
 	}
 }
 
+// Test: TestListTransactions_filterByMultipleCurrencies
+// What: GET /transactions?currency=USD,EUR returns transactions in either currency
+// Input: USD, EUR, and GBP transactions, query param currency=USD,EUR
+// Output: 2 transactions (usd-1, eur-1); gbp-1 excluded
+func TestListTransactions_filterByMultipleCurrencies(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"usd-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"eur-1","account_id":"acct-1","amount":200,"currency":"EUR","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"gbp-1","account_id":"acct-1","amount":300,"currency":"GBP","direction":"debit","effective_at":"2024-01-03T00:00:00Z"}`)
+
+	resp := getTxns(t, srv, "currency=USD,EUR")
+	defer resp.Body.Close()
+
+	var result []model.Transaction
+	json.NewDecoder(resp.Body).Decode(&result)
+	if len(result) != 2 || result[0].ID != "usd-1" || result[1].ID != "eur-1" {
+		t.Errorf("expected [usd-1 eur-1], got %+v", result)
+	}
+}
+
 // Test: TestListTransactions_filterByDateRange
 // What: GET /transactions?start_date=...&end_date=... returns only transactions within that window
 // Input: 3 transactions (Jan, Feb, Mar), query params start_date=2024-01-10&end_date=2024-02-20
 // Output: 2 transactions (Jan and Feb)
 func TestListTransactions_filterByDateRange(t *testing.T) {
 	srv := newTestServer(t)
-	seedTxn(t, srv, `{"id":"jan","amount":100,"currency":"USD","effective_at":"2024-01-15T12:00:00Z"}`)
-	seedTxn(t, srv, `{"id":"feb","amount":200,"currency":"USD","effective_at":"2024-02-15T12:00:00Z"}`)
-	seedTxn(t, srv, `{"id":"mar","amount":300,"currency":"USD","effective_at":"2024-03-15T12:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"jan","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"feb","account_id":"acct-1","amount":200,"currency":"USD","direction":"debit","effective_at":"2024-02-15T12:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"mar","account_id":"acct-1","amount":300,"currency":"USD","direction":"debit","effective_at":"2024-03-15T12:00:00Z"}`)
 
 	resp := getTxns(t, srv, "start_date=2024-01-10&end_date=2024-02-20")
 	defer resp.Body.Close()
@@ -177,9 +204,9 @@ func TestListTransactions_filterByDateRange(t *testing.T) {
 // Output: 1 transaction (amount=500, id="mid")
 func TestListTransactions_filterByAmountRange(t *testing.T) {
 	srv := newTestServer(t)
-	seedTxn(t, srv, `{"id":"low","amount":100,"currency":"USD","effective_at":"2024-01-01T00:00:00Z"}`)
-	seedTxn(t, srv, `{"id":"mid","amount":500,"currency":"USD","effective_at":"2024-01-02T00:00:00Z"}`)
-	seedTxn(t, srv, `{"id":"high","amount":9000,"currency":"USD","effective_at":"2024-01-03T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"low","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"mid","account_id":"acct-1","amount":500,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"high","account_id":"acct-1","amount":9000,"currency":"USD","direction":"debit","effective_at":"2024-01-03T00:00:00Z"}`)
 
 	resp := getTxns(t, srv, "min_amount=200&max_amount=1000")
 	defer resp.Body.Close()
@@ -221,6 +248,46 @@ func TestListTransactions_limitTooHigh(t *testing.T) {
 	}
 }
 
+// Test: TestListTransactions_nonIntegerPagination
+// What: a limit or offset that is present but not an integer is a 400 naming the parameter, not a silent default
+// Input: limit=abc; offset=1.5; limit= (present but empty)
+// Output: HTTP 400 "limit must be an integer", HTTP 400 "offset must be an integer"; HTTP 200 for the empty limit, which counts as absent
+func TestListTransactions_nonIntegerPagination(t *testing.T) {
+	srv := newTestServer(t)
+
+	for query, want := range map[string]string{"limit=abc": "limit must be an integer", "offset=1.5": "offset must be an integer"} {
+		resp := getTxns(t, srv, query)
+		msg, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(msg), want) {
+			t.Errorf("%s: expected 400 %q, got %d %q", query, want, resp.StatusCode, msg)
+		}
+	}
+
+	resp := getTxns(t, srv, "limit=")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("limit=: expected 200, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestListTransactions_absentLimitUsesDefault
+// What: without a limit the configured default page size applies
+// Input: Pagination.DefaultLimit=2, three transactions, no limit parameter
+// Output: two transactions
+func TestListTransactions_absentLimitUsesDefault(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.Pagination.DefaultLimit = 2
+	srv := newTestServerWithConfig(t, cfg)
+	seedN(t, srv, 3, "USD")
+
+	var result []model.Transaction
+	json.Unmarshal(readBody(t, getTxns(t, srv, "")), &result)
+	if len(result) != 2 {
+		t.Errorf("expected the default limit of 2, got %d transactions", len(result))
+	}
+}
+
 // Test: TestListTransactions_negativeOffset
 // What: GET /transactions?offset=-1 returns 400 Bad Request (offset must be >= 0)
 // Input: query param offset=-1
@@ -311,6 +378,83 @@ func TestListTransactions_minAmountGreaterThanMax(t *testing.T) {
 	}
 }
 
+// Test: TestListTransactions_exclusiveAmountBound
+// What: min_amount_exclusive keeps only amounts strictly greater than the bound
+// Input: transactions with amounts 100 and 200, query param min_amount_exclusive=100
+// Output: 1 transaction (amount 200)
+func TestListTransactions_exclusiveAmountBound(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"at-100","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"at-200","account_id":"acct-1","amount":200,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`)
+
+	resp := getTxns(t, srv, "min_amount_exclusive=100")
+	defer resp.Body.Close()
+
+	var result []model.Transaction
+	json.NewDecoder(resp.Body).Decode(&result)
+	if len(result) != 1 || result[0].ID != "at-200" {
+		t.Errorf("expected [at-200], got %+v", result)
+	}
+}
+
+// Test: TestListTransactions_inclusiveAndExclusiveBoundConflict
+// What: supplying both the inclusive and exclusive form of the same bound is rejected
+// Input: query params max_amount=100&max_amount_exclusive=100
+// Output: HTTP 400
+func TestListTransactions_inclusiveAndExclusiveBoundConflict(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := getTxns(t, srv, "max_amount=100&max_amount_exclusive=100")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestListTransactions_exactAmount
+// What: amount=N keeps only transactions with exactly that amount
+// Input: transactions with amounts 100, 200 and 300, query param amount=200
+// Output: 1 transaction (amount 200)
+func TestListTransactions_exactAmount(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"at-100","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"at-200","account_id":"acct-1","amount":200,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"at-300","account_id":"acct-1","amount":300,"currency":"USD","direction":"debit","effective_at":"2024-01-03T00:00:00Z"}`)
+
+	resp := getTxns(t, srv, "amount=200")
+	defer resp.Body.Close()
+
+	var result []model.Transaction
+	json.NewDecoder(resp.Body).Decode(&result)
+	if len(result) != 1 || result[0].ID != "at-200" {
+		t.Errorf("expected [at-200], got %+v", result)
+	}
+}
+
+// Test: TestListTransactions_exactAmountRejectsBoundsAndInvalid
+// What: amount cannot be combined with any range bound, and must be an integer
+// Input: amount=100 with each of min_amount, max_amount, min_amount_exclusive, max_amount_exclusive; amount=abc
+// Output: HTTP 400 each; the combination errors name the conflicting parameter
+func TestListTransactions_exactAmountRejectsBoundsAndInvalid(t *testing.T) {
+	srv := newTestServer(t)
+
+	for _, bound := range []string{"min_amount", "max_amount", "min_amount_exclusive", "max_amount_exclusive"} {
+		resp := getTxns(t, srv, "amount=100&"+bound+"=50")
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(body), bound) {
+			t.Errorf("%s: expected 400 naming it, got %d %q", bound, resp.StatusCode, body)
+		}
+	}
+
+	resp := getTxns(t, srv, "amount=abc")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("amount=abc: expected 400, got %d", resp.StatusCode)
+	}
+}
+
 // Test: TestListTransactions_sameTimestampOrderedByID
 // What: GET /transactions with same-timestamp transactions returns them sorted alphabetically by ID
 // Input: 3 transactions with identical effective_at, seeded in order: zzz, aaa, mmm
@@ -318,9 +462,9 @@ func TestListTransactions_minAmountGreaterThanMax(t *testing.T) {
 func TestListTransactions_sameTimestampOrderedByID(t *testing.T) {
 	srv := newTestServer(t)
 	ts := "2024-05-01T12:00:00Z"
-	seedTxn(t, srv, `{"id":"zzz","amount":100,"currency":"USD","effective_at":"`+ts+`"}`)
-	seedTxn(t, srv, `{"id":"aaa","amount":200,"currency":"USD","effective_at":"`+ts+`"}`)
-	seedTxn(t, srv, `{"id":"mmm","amount":300,"currency":"USD","effective_at":"`+ts+`"}`)
+	seedTxn(t, srv, `{"id":"zzz","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"`+ts+`"}`)
+	seedTxn(t, srv, `{"id":"aaa","account_id":"acct-1","amount":200,"currency":"USD","direction":"debit","effective_at":"`+ts+`"}`)
+	seedTxn(t, srv, `{"id":"mmm","account_id":"acct-1","amount":300,"currency":"USD","direction":"debit","effective_at":"`+ts+`"}`)
 
 	resp := getTxns(t, srv, "")
 	defer resp.Body.Close()
@@ -338,3 +482,192 @@ func TestListTransactions_sameTimestampOrderedByID(t *testing.T) {
 		}
 	}
 }
+
+// Test: TestListTransactions_filterByDirection
+// What: GET /transactions?direction=credit returns only credits
+// Input: 3 transactions (2 debits, 1 credit), query param direction=credit
+// Output: 1 transaction (id="refund")
+func TestListTransactions_filterByDirection(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"buy-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"refund","account_id":"acct-1","amount":100,"currency":"USD","direction":"credit","effective_at":"2024-01-02T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"buy-2","account_id":"acct-1","amount":300,"currency":"USD","direction":"debit","effective_at":"2024-01-03T00:00:00Z"}`)
+
+	resp := getTxns(t, srv, "direction=credit")
+	defer resp.Body.Close()
+
+	var result []model.Transaction
+	json.NewDecoder(resp.Body).Decode(&result)
+	if len(result) != 1 || result[0].ID != "refund" {
+		t.Errorf("expected only 'refund', got %+v", result)
+	}
+}
+
+// Test: TestListTransactions_filterByAccountID
+// What: GET /transactions?account_id= returns only that account's transactions and combines with other filters
+// Input: acct-1 (USD, EUR) and acct-2 (USD); account_id=acct-1, then account_id=acct-1&currency=USD
+// Output: [a-usd, a-eur], then [a-usd]
+func TestListTransactions_filterByAccountID(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"a-usd","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"b-usd","account_id":"acct-2","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"a-eur","account_id":"acct-1","amount":100,"currency":"EUR","direction":"debit","effective_at":"2024-01-03T00:00:00Z"}`)
+
+	assertIDs(t, listIDs(t, srv.URL+"/transactions?account_id=acct-1"), "a-usd", "a-eur")
+	assertIDs(t, listIDs(t, srv.URL+"/transactions?account_id=acct-1&currency=USD"), "a-usd")
+	assertIDs(t, listIDs(t, srv.URL+"/transactions?account_id=acct-3"))
+}
+
+// Test: TestGetTransaction_legacyWithoutAccountID
+// What: data stored before account_id existed still reads back, without an account_id key
+// Input: transaction without AccountID stored directly in the store; GET by id and GET list
+// Output: HTTP 200 for both, response JSON has no account_id
+func TestGetTransaction_legacyWithoutAccountID(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(model.Transaction{ID: "legacy", Amount: 100, Currency: "USD", Direction: model.DirectionDebit,
+		EffectiveAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+	mux := http.NewServeMux()
+	api.NewHandler(s).RegisterRoutes(mux, nil)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp := getTxnByID(t, srv, "legacy")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var raw map[string]any
+	json.NewDecoder(resp.Body).Decode(&raw)
+	if _, ok := raw["account_id"]; ok || raw["id"] != "legacy" {
+		t.Errorf("expected legacy transaction without account_id, got %v", raw)
+	}
+	assertIDs(t, listIDs(t, srv.URL+"/transactions"), "legacy")
+}
+
+// Test: TestListTransactions_invalidDirection
+// What: GET /transactions?direction=sideways returns 400 Bad Request
+// Input: query param direction=sideways
+// Output: HTTP 400
+func TestListTransactions_invalidDirection(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := getTxns(t, srv, "direction=sideways")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestListTransactions_search
+// What: GET /transactions?q=mobile returns transactions whose ID or metadata values contain "mobile"
+// Input: 3 transactions, one with metadata source=Mobile, one with "mobile" in the ID, one with neither
+// Output: 2 transactions
+func TestListTransactions_search(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","metadata":{"source":"Mobile"}}`)
+	seedTxn(t, srv, `{"id":"mobile-2","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"txn-3","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-03T00:00:00Z","metadata":{"source":"web"}}`)
+
+	resp := getTxns(t, srv, "q=mobile")
+	defer resp.Body.Close()
+
+	var result []model.Transaction
+	json.NewDecoder(resp.Body).Decode(&result)
+	if len(result) != 2 || result[0].ID != "txn-1" || result[1].ID != "mobile-2" {
+		t.Errorf("expected [txn-1 mobile-2], got %+v", result)
+	}
+}
+
+// Test: TestListTransactions_metadataPresence
+// What: has_metadata keeps transactions with the key; missing_metadata keeps the rest, including ones with no metadata
+// Input: tagged (source=web), other (channel=pos), bare (no metadata)
+// Output: has_metadata=source -> [tagged]; missing_metadata=source -> [other bare]
+func TestListTransactions_metadataPresence(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"tagged","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","metadata":{"source":"web"}}`)
+	seedTxn(t, srv, `{"id":"other","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z","metadata":{"channel":"pos"}}`)
+	seedTxn(t, srv, `{"id":"bare","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-03T00:00:00Z"}`)
+
+	assertIDs(t, listIDs(t, srv.URL+"/transactions?has_metadata=source"), "tagged")
+	assertIDs(t, listIDs(t, srv.URL+"/transactions?missing_metadata=source"), "other", "bare")
+}
+
+// Test: TestListTransactions_metadataPresenceSameKey
+// What: asking for a key to be both present and missing is rejected
+// Input: has_metadata=source&missing_metadata=source
+// Output: HTTP 400
+func TestListTransactions_metadataPresenceSameKey(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := getTxns(t, srv, "has_metadata=source&missing_metadata=source")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestListTransactions_filtersBeyondTenThousandRows
+// What: filtering covers the whole store rather than the first 10,000 rows
+// Input: 10,000 EUR transactions followed by 5 later USD ones; currency=USD
+// Output: all 5 USD transactions returned
+func TestListTransactions_filtersBeyondTenThousandRows(t *testing.T) {
+	s := store.NewMemoryStore()
+	base := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 10005; i++ {
+		currency := "EUR"
+		if i >= 10000 {
+			currency = "USD"
+		}
+		_ = s.Create(model.Transaction{
+			ID:          fmt.Sprintf("txn-%05d", i),
+			Amount:      100,
+			Currency:    currency,
+			Direction:   model.DirectionDebit,
+			EffectiveAt: base.Add(time.Duration(i) * time.Second),
+		})
+	}
+	mux := http.NewServeMux()
+	api.NewHandler(s).RegisterRoutes(mux, nil)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp := getTxns(t, srv, "currency=USD")
+	defer resp.Body.Close()
+
+	var result []model.Transaction
+	json.NewDecoder(resp.Body).Decode(&result)
+	if len(result) != 5 {
+		t.Fatalf("expected 5 USD transactions, got %d", len(result))
+	}
+	if result[0].ID != "txn-10000" {
+		t.Errorf("expected first match txn-10000, got %s", result[0].ID)
+	}
+}
+
+// Test: TestListTransactions_dateRangeFastPathMatchesFilter
+// What: a date-only query (served by Store.ListBetween) returns the same page as the
+// full-scan path, including rows late on the end date
+// Input: debit txns around the range edges; the same query with and without a no-op direction=debit
+// Output: identical ID lists for each limit/offset
+func TestListTransactions_dateRangeFastPathMatchesFilter(t *testing.T) {
+	srv := newTestServer(t)
+	for _, seed := range []struct{ id, at string }{
+		{"before", "2024-01-01T23:59:59Z"},
+		{"start", "2024-01-02T00:00:00Z"},
+		{"mid-a", "2024-01-03T12:00:00Z"},
+		{"mid-b", "2024-01-03T12:00:00Z"},
+		{"late", "2024-01-04T23:59:59Z"},
+		{"after", "2024-01-05T00:00:01Z"},
+	} {
+		seedTxn(t, srv, `{"id":"`+seed.id+`","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"`+seed.at+`"}`)
+	}
+
+	base := srv.URL + "/transactions?start_date=2024-01-02&end_date=2024-01-04"
+	for _, page := range []string{"", "&limit=2", "&limit=2&offset=2", "&offset=10"} {
+		fast := listIDs(t, base+page)
+		full := listIDs(t, base+page+"&direction=debit")
+		assertIDs(t, fast, full...)
+	}
+	assertIDs(t, listIDs(t, base), "start", "mid-a", "mid-b", "late")
+}
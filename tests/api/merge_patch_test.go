@@ -0,0 +1,138 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+const mergePatchSeed = `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z","metadata":{"source":"web","note":"x"}}`
+
+func mergePatchTxn(t *testing.T, srv *httptest.Server, id, body string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPatch, srv.URL+"/transactions/"+id, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("PATCH /transactions/%s failed: %v", id, err)
+	}
+	return resp
+}
+
+// Test: TestMergePatch_scalarFields
+// What: a merge patch updates scalar fields in one call, keeps the rest, and re-sorts the
+// transaction when effective_at moves
+// Input: txn-1 (2024-01-02) and txn-2 (2024-01-03); merge patch txn-1 with a new amount,
+// description and effective_at of 2024-01-04
+// Output: HTTP 200 with amount 250, the description, version 2, the client metadata unchanged
+// and an amount_history entry for 100; the list now returns txn-2 before txn-1
+func TestMergePatch_scalarFields(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, mergePatchSeed)
+	seedTxn(t, srv, `{"id":"txn-2","account_id":"acct-1","amount":5,"currency":"USD","direction":"credit","effective_at":"2024-01-03T00:00:00Z"}`)
+
+	resp := mergePatchTxn(t, srv, "txn-1", `{"amount":250,"description":"Coffee","effective_at":"2024-01-04T00:00:00Z"}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	var patched model.Transaction
+	json.NewDecoder(resp.Body).Decode(&patched)
+	if patched.Amount != 250 || patched.Description != "Coffee" || patched.Version != 2 {
+		t.Errorf("expected amount 250, description Coffee and version 2, got %+v", patched)
+	}
+	if !patched.EffectiveAt.Equal(time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected effective_at 2024-01-04, got %v", patched.EffectiveAt)
+	}
+	var history []model.AmountChange
+	if err := json.Unmarshal([]byte(patched.Metadata[model.MetadataAmountHistory]), &history); err != nil || len(history) != 1 || history[0].Amount != 100 {
+		t.Errorf("expected one amount_history entry for 100, got %q", patched.Metadata[model.MetadataAmountHistory])
+	}
+	delete(patched.Metadata, model.MetadataAmountHistory)
+	if want := map[string]string{"source": "web", "note": "x"}; !reflect.DeepEqual(patched.Metadata, want) {
+		t.Errorf("expected metadata %v, got %v", want, patched.Metadata)
+	}
+
+	list := getTxns(t, srv, "")
+	defer list.Body.Close()
+	var txns []model.Transaction
+	json.NewDecoder(list.Body).Decode(&txns)
+	if len(txns) != 2 || txns[0].ID != "txn-2" || txns[1].ID != "txn-1" {
+		t.Errorf("expected txn-2 then txn-1 after moving effective_at, got %v", txns)
+	}
+}
+
+// Test: TestMergePatch_nullDeletesMetadataKey
+// What: null in a nested metadata object deletes that key and leaves the others
+// Input: txn-1 with metadata {source:web, note:x}; merge patch {"metadata":{"note":null,"order":"A-1"}}
+// Output: HTTP 200 and a later GET shows metadata {source:web, order:A-1}
+func TestMergePatch_nullDeletesMetadataKey(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, mergePatchSeed)
+
+	resp := mergePatchTxn(t, srv, "txn-1", `{"metadata":{"note":null,"order":"A-1"}}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	get := getTxnByID(t, srv, "txn-1")
+	defer get.Body.Close()
+	var stored model.Transaction
+	json.NewDecoder(get.Body).Decode(&stored)
+	if want := map[string]string{"source": "web", "order": "A-1"}; !reflect.DeepEqual(stored.Metadata, want) {
+		t.Errorf("expected metadata %v, got %v", want, stored.Metadata)
+	}
+}
+
+// Test: TestMergePatch_rejected
+// What: merge patches whose result is invalid, or that touch fields clients can't set, are
+// rejected and leave the transaction unchanged; unknown IDs are 404
+// Input: txn-1; merge patches removing currency, with a negative amount, changing the id,
+// setting version, a non-object body, and naming amount_history or reversed_by; a patch of an
+// unknown ID
+// Output: 400 for each of the first seven and the stored amount is still 100; 404 for the last
+func TestMergePatch_rejected(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, mergePatchSeed)
+
+	for _, body := range []string{
+		`{"currency":null}`,
+		`{"amount":-5}`,
+		`{"id":"txn-9"}`,
+		`{"version":7}`,
+		`[1,2]`,
+		`{"metadata":{"amount_history":null}}`,
+		`{"metadata":{"reversed_by":"txn-2"}}`,
+	} {
+		resp := mergePatchTxn(t, srv, "txn-1", body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", body, resp.StatusCode)
+		}
+	}
+
+	get := getTxnByID(t, srv, "txn-1")
+	defer get.Body.Close()
+	var stored model.Transaction
+	json.NewDecoder(get.Body).Decode(&stored)
+	if stored.Amount != 100 || stored.Version != 1 {
+		t.Errorf("expected the transaction unchanged, got %+v", stored)
+	}
+
+	resp := mergePatchTxn(t, srv, "missing", `{"amount":1}`)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound || !strings.Contains(string(body), "not found") {
+		t.Errorf("expected 404, got %d: %s", resp.StatusCode, body)
+	}
+}
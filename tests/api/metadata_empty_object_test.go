@@ -0,0 +1,99 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const noMetadataTxn = `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`
+
+// decodeRaw decodes a JSON object response into raw fields, so tests can tell an omitted
+// field from an empty one.
+func decodeRaw(t *testing.T, body []byte) map[string]json.RawMessage {
+	t.Helper()
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		t.Fatalf("failed to decode %s: %v", body, err)
+	}
+	return fields
+}
+
+func readBody(t *testing.T, resp *http.Response) []byte {
+	t.Helper()
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("failed to read body: %v", err)
+	}
+	return body
+}
+
+// Test: TestMetadataEmptyObject_omittedByDefault
+// What: without the flag, a transaction with no metadata has no metadata field
+// Input: txn-1 without metadata; GET /transactions/txn-1 and GET /transactions
+// Output: neither response contains a metadata key
+func TestMetadataEmptyObject_omittedByDefault(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, noMetadataTxn)
+
+	if fields := decodeRaw(t, readBody(t, getTxnByID(t, srv, "txn-1"))); fields["metadata"] != nil {
+		t.Errorf("expected no metadata field, got %s", fields["metadata"])
+	}
+	if body := readBody(t, getTxns(t, srv, "")); strings.Contains(string(body), `"metadata"`) {
+		t.Errorf("expected no metadata field in the list, got %s", body)
+	}
+}
+
+// Test: TestMetadataEmptyObject_getAndList
+// What: metadata_empty_object=true writes missing metadata as {} on every JSON response shape
+// Input: txn-1 without metadata, txn-2 with {"k":"v"}; GET by ID (plain and expand=computed), list, envelope
+// Output: txn-1 has "metadata":{} everywhere; txn-2 keeps its metadata
+func TestMetadataEmptyObject_getAndList(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, noMetadataTxn)
+	seedTxn(t, srv, `{"id":"txn-2","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z","metadata":{"k":"v"}}`)
+
+	for _, query := range []string{"?metadata_empty_object=true", "?metadata_empty_object=true&expand=computed"} {
+		resp, err := http.Get(srv.URL + "/transactions/txn-1" + query)
+		if err != nil {
+			t.Fatalf("GET failed: %v", err)
+		}
+		if got := string(decodeRaw(t, readBody(t, resp))["metadata"]); got != "{}" {
+			t.Errorf("%s: expected metadata {}, got %q", query, got)
+		}
+	}
+
+	var list []map[string]json.RawMessage
+	json.Unmarshal(readBody(t, getTxns(t, srv, "metadata_empty_object=true")), &list)
+	if len(list) != 2 || string(list[0]["metadata"]) != "{}" || string(list[1]["metadata"]) != `{"k":"v"}` {
+		t.Errorf("expected metadata {} and {\"k\":\"v\"}, got %v", list)
+	}
+
+	var envelope struct {
+		Data []map[string]json.RawMessage `json:"data"`
+	}
+	json.Unmarshal(readBody(t, getTxns(t, srv, "metadata_empty_object=true&envelope=true")), &envelope)
+	if len(envelope.Data) != 2 || string(envelope.Data[0]["metadata"]) != "{}" {
+		t.Errorf("expected metadata {} in the envelope, got %v", envelope.Data)
+	}
+}
+
+// Test: TestMetadataEmptyObject_invalid
+// What: a non-boolean flag value is rejected
+// Input: GET /transactions?metadata_empty_object=yes-please
+// Output: HTTP 400
+func TestMetadataEmptyObject_invalid(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := getTxns(t, srv, "metadata_empty_object=yes-please")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
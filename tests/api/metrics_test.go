@@ -0,0 +1,120 @@
+package api_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// Test: TestMetrics_createOutcomeCounters
+// What: GET /metrics reports create, duplicate, and conflict counts in Prometheus text format
+// Input: one create, one identical retry, one conflicting retry, then GET /metrics
+// Output: HTTP 200, text/plain, counters created=1 duplicate=1 conflict=1
+func TestMetrics_createOutcomeCounters(t *testing.T) {
+	srv := newTestServer(t)
+	body := `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`
+	postTxn(t, srv, body).Body.Close()
+	postTxn(t, srv, body).Body.Close()
+	postTxn(t, srv, strings.Replace(body, `"amount":100`, `"amount":200`, 1)).Body.Close()
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("expected text/plain, got %q", ct)
+	}
+
+	raw, _ := io.ReadAll(resp.Body)
+	text := string(raw)
+	for _, line := range []string{
+		"# TYPE transactions_create_total counter",
+		`transactions_create_total{result="created"} 1`,
+		`transactions_create_total{result="duplicate"} 1`,
+		`transactions_create_total{result="conflict"} 1`,
+	} {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("expected metrics to contain %q, got:\n%s", line, text)
+		}
+	}
+}
+
+// Test: TestMetrics_requestSeries
+// What: GET /metrics exposes request counts, a latency histogram per route, and the stored transaction gauge
+// Input: one POST /transactions, one GET /transactions/{id} for a missing ID, then GET /metrics
+// Output: http_requests_total labeled by route pattern and status, duration histogram series, transactions_stored 1
+func TestMetrics_requestSeries(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	getTxnByID(t, srv, "missing").Body.Close()
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics failed: %v", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	text := string(raw)
+
+	for _, want := range []string{
+		"# TYPE http_requests_total counter",
+		`http_requests_total{method="POST",path="/transactions",status="201"} 1`,
+		`http_requests_total{method="GET",path="/transactions/{id}",status="404"} 1`,
+		"# TYPE http_request_duration_seconds histogram",
+		`http_request_duration_seconds_bucket{method="POST",path="/transactions",le="+Inf"} 1`,
+		`http_request_duration_seconds_count{method="POST",path="/transactions"} 1`,
+		"# TYPE transactions_stored gauge",
+		"transactions_stored 1",
+	} {
+		if !strings.Contains(text, want+"\n") {
+			t.Errorf("expected metrics to contain %q, got:\n%s", want, text)
+		}
+	}
+}
+
+// Test: TestMetrics_unknownMethodsCollapse
+// What: made-up HTTP methods on the method-less routes share one "other" series instead of
+// creating a series each
+// Input: requests with methods FOO and BAR to /transactions and BAZ to /transactions/txn-1, then GET /metrics
+// Output: method="other" series with counts 2 and 1; no series labelled FOO, BAR or BAZ
+func TestMetrics_unknownMethodsCollapse(t *testing.T) {
+	srv := newTestServer(t)
+	for _, req := range []struct{ method, path string }{
+		{"FOO", "/transactions"}, {"BAR", "/transactions"}, {"BAZ", "/transactions/txn-1"},
+	} {
+		r, _ := http.NewRequest(req.method, srv.URL+req.path, nil)
+		resp, err := http.DefaultClient.Do(r)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", req.method, req.path, err)
+		}
+		resp.Body.Close()
+	}
+
+	resp, err := http.Get(srv.URL + "/metrics")
+	if err != nil {
+		t.Fatalf("GET /metrics failed: %v", err)
+	}
+	defer resp.Body.Close()
+	raw, _ := io.ReadAll(resp.Body)
+	text := string(raw)
+
+	for _, want := range []string{
+		`http_requests_total{method="other",path="/transactions",status="405"} 2`,
+		`http_requests_total{method="other",path="/transactions/{id}",status="405"} 1`,
+	} {
+		if !strings.Contains(text, want+"\n") {
+			t.Errorf("expected metrics to contain %q, got:\n%s", want, text)
+		}
+	}
+	for _, method := range []string{"FOO", "BAR", "BAZ"} {
+		if strings.Contains(text, `method="`+method+`"`) {
+			t.Errorf("expected no series for method %s", method)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+type mgetResult struct {
+	Found   []model.Transaction `json:"found"`
+	Missing []string            `json:"missing"`
+}
+
+func postMGet(t *testing.T, srv *httptest.Server, body string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(srv.URL+"/transactions/_mget", "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("POST /transactions/_mget failed: %v", err)
+	}
+	return resp
+}
+
+func decodeMGet(t *testing.T, resp *http.Response) mgetResult {
+	t.Helper()
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var result mgetResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return result
+}
+
+func seedMGet(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := newTestServer(t)
+	for _, id := range []string{"id1", "id2"} {
+		seedTxn(t, srv, `{"id":"`+id+`","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	}
+	return srv
+}
+
+// Test: TestMGetTransactions_allFound
+// What: every requested ID exists, so all are returned and none are missing
+// Input: id1, id2 stored; POST /transactions/_mget {"ids":["id2","id1"]}
+// Output: HTTP 200, found [id2, id1], missing []
+func TestMGetTransactions_allFound(t *testing.T) {
+	srv := seedMGet(t)
+
+	result := decodeMGet(t, postMGet(t, srv, `{"ids":["id2","id1"]}`))
+	if got := txnIDs(result.Found); !reflect.DeepEqual(got, []string{"id2", "id1"}) {
+		t.Errorf("expected found [id2 id1], got %v", got)
+	}
+	if result.Missing == nil || len(result.Missing) != 0 {
+		t.Errorf("expected missing to be an empty array, got %v", result.Missing)
+	}
+}
+
+// Test: TestMGetTransactions_partial
+// What: unknown IDs are listed under missing instead of failing the request
+// Input: id1, id2 stored; POST /transactions/_mget {"ids":["id1","id3","id2"]}
+// Output: HTTP 200, found [id1, id2], missing [id3]
+func TestMGetTransactions_partial(t *testing.T) {
+	srv := seedMGet(t)
+
+	result := decodeMGet(t, postMGet(t, srv, `{"ids":["id1","id3","id2"]}`))
+	if got := txnIDs(result.Found); !reflect.DeepEqual(got, []string{"id1", "id2"}) {
+		t.Errorf("expected found [id1 id2], got %v", got)
+	}
+	if !reflect.DeepEqual(result.Missing, []string{"id3"}) {
+		t.Errorf("expected missing [id3], got %v", result.Missing)
+	}
+}
+
+// Test: TestMGetTransactions_overCap
+// What: requesting more than MaxMGetIDs IDs is rejected
+// Input: POST /transactions/_mget with MaxMGetIDs+1 IDs
+// Output: HTTP 400
+func TestMGetTransactions_overCap(t *testing.T) {
+	srv := newTestServer(t)
+
+	ids := make([]string, api.MaxMGetIDs+1)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%q", fmt.Sprintf("id%d", i))
+	}
+	resp := postMGet(t, srv, `{"ids":[`+strings.Join(ids, ",")+`]}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestMGetTransactions_badRequest
+// What: malformed JSON and an empty ID list are rejected
+// Input: POST /transactions/_mget with "not json", {}, and {"ids":[]}
+// Output: HTTP 400 for each
+func TestMGetTransactions_badRequest(t *testing.T) {
+	srv := newTestServer(t)
+
+	for _, body := range []string{"not json", `{}`, `{"ids":[]}`} {
+		resp := postMGet(t, srv, body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("body %q: expected 400, got %d", body, resp.StatusCode)
+		}
+	}
+}
+
+func txnIDs(txns []model.Transaction) []string {
+	out := make([]string, len(txns))
+	for i, txn := range txns {
+		out[i] = txn.ID
+	}
+	return out
+}
@@ -0,0 +1,99 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/clock"
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// syncIDs lists GET /transactions?modified_since=since and returns the IDs in response order.
+func syncIDs(t *testing.T, base string, since time.Time) []string {
+	t.Helper()
+	resp, err := http.Get(base + "/transactions?modified_since=" + since.Format(time.RFC3339))
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	var txns []model.Transaction
+	json.Unmarshal(readBody(t, resp), &txns)
+	ids := make([]string, len(txns))
+	for i, txn := range txns {
+		ids[i] = txn.ID
+	}
+	return ids
+}
+
+// Test: TestListTransactions_modifiedSince
+// What: modified_since returns what changed after T, oldest change first, so a transaction
+// created then updated shows up when syncing from before the update but not from after it
+// Input: txn-1 created at t0, txn-2 at t0+1h, txn-1's metadata patched at t0+2h; sync from
+// t0+30m, from t0+90m, and from t0+2h
+// Output: [txn-2 txn-1], [txn-1], []
+func TestListTransactions_modifiedSince(t *testing.T) {
+	t0 := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(t0)
+	srv := newClockedServer(t, clk)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	clk.Advance(time.Hour)
+	seedTxn(t, srv, `{"id":"txn-2","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`)
+	clk.Advance(time.Hour)
+	patched := decodeTxn(t, patchTxn(t, srv, "txn-1", `{"metadata":{"note":"x"}}`))
+	if !patched.ModifiedAt.Equal(t0.Add(2 * time.Hour)) {
+		t.Fatalf("expected modified_at %v after the patch, got %v", t0.Add(2*time.Hour), patched.ModifiedAt)
+	}
+
+	for _, tc := range []struct {
+		since time.Time
+		want  []string
+	}{
+		{t0.Add(30 * time.Minute), []string{"txn-2", "txn-1"}},
+		{t0.Add(90 * time.Minute), []string{"txn-1"}},
+		{patched.ModifiedAt, []string{}},
+	} {
+		if got := syncIDs(t, srv.URL, tc.since); !slices.Equal(got, tc.want) {
+			t.Errorf("since %v: expected %v, got %v", tc.since, tc.want, got)
+		}
+	}
+}
+
+// Test: TestListTransactions_modifiedSinceIncludesDeletions
+// What: a sync sees soft deletions without asking for include_deleted
+// Input: txn-1 created at t0 and soft-deleted at t0+1h; sync from t0+30m
+// Output: txn-1 with deleted=true
+func TestListTransactions_modifiedSinceIncludesDeletions(t *testing.T) {
+	t0 := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(t0)
+	srv := newClockedServer(t, clk)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	clk.Advance(time.Hour)
+	deleteTxn(t, srv, "txn-1").Body.Close()
+
+	resp, err := http.Get(srv.URL + "/transactions?modified_since=" + t0.Add(30*time.Minute).Format(time.RFC3339))
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	var txns []model.Transaction
+	json.Unmarshal(readBody(t, resp), &txns)
+	if len(txns) != 1 || txns[0].ID != "txn-1" || !txns[0].Deleted {
+		t.Errorf("expected the deleted txn-1, got %+v", txns)
+	}
+}
+
+// Test: TestListTransactions_modifiedSinceInvalid
+// What: a malformed timestamp, or another sort order, is rejected
+// Input: ?modified_since=yesterday; ?modified_since=2024-01-01T00:00:00Z&sort=inserted_desc
+// Output: 400 for both
+func TestListTransactions_modifiedSinceInvalid(t *testing.T) {
+	srv := newTestServer(t)
+	for _, query := range []string{"modified_since=yesterday", "modified_since=2024-01-01T00:00:00Z&sort=inserted_desc"} {
+		resp := getTxns(t, srv, query)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, resp.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package api_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+func getNDJSON(t *testing.T, srv *httptest.Server, query string) []model.Transaction {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/transactions?"+query, nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /transactions failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != api.ContentTypeNDJSON {
+		t.Fatalf("expected Content-Type %s, got %q", api.ContentTypeNDJSON, ct)
+	}
+
+	var txns []model.Transaction
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var txn model.Transaction
+		if err := json.Unmarshal(scanner.Bytes(), &txn); err != nil {
+			t.Fatalf("line %d is not a JSON transaction: %v", len(txns)+1, err)
+		}
+		txns = append(txns, txn)
+	}
+	return txns
+}
+
+func seedN(t *testing.T, srv *httptest.Server, n int, currency string) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		seedTxn(t, srv, fmt.Sprintf(`{"id":"%s-%03d","account_id":"acct-1","amount":%d,"currency":"%s","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`, currency, i, i, currency))
+	}
+}
+
+// Test: TestListTransactions_ndjson
+// What: Accept: application/x-ndjson streams one transaction per line, honoring filters and ordering
+// Input: 3 USD and 2 EUR transactions, currency=USD
+// Output: 3 lines, each decoding to a USD transaction, in id order
+func TestListTransactions_ndjson(t *testing.T) {
+	srv := newTestServer(t)
+	seedN(t, srv, 3, "USD")
+	seedN(t, srv, 2, "EUR")
+
+	txns := getNDJSON(t, srv, "currency=USD")
+	if len(txns) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(txns))
+	}
+	for i, txn := range txns {
+		if want := fmt.Sprintf("USD-%03d", i); txn.ID != want {
+			t.Errorf("line %d: expected %s, got %s", i+1, want, txn.ID)
+		}
+	}
+}
+
+// Test: TestListTransactions_ndjsonPaginatedByDefault
+// What: without AllowUnboundedExport, NDJSON honors limit like the JSON response
+// Input: 5 transactions, limit=2
+// Output: 2 lines
+func TestListTransactions_ndjsonPaginatedByDefault(t *testing.T) {
+	srv := newTestServer(t)
+	seedN(t, srv, 5, "USD")
+
+	if txns := getNDJSON(t, srv, "limit=2"); len(txns) != 2 {
+		t.Errorf("expected 2 lines, got %d", len(txns))
+	}
+}
+
+// Test: TestListTransactions_ndjsonUnboundedExport
+// What: with AllowUnboundedExport, NDJSON ignores limit and streams every match (more than one flush batch)
+// Input: 150 transactions, limit=2, AllowUnboundedExport=true
+// Output: 150 lines
+func TestListTransactions_ndjsonUnboundedExport(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.AllowUnboundedExport = true
+	srv := newTestServerWithConfig(t, cfg)
+	seedN(t, srv, 150, "USD")
+
+	if txns := getNDJSON(t, srv, "limit=2"); len(txns) != 150 {
+		t.Errorf("expected 150 lines, got %d", len(txns))
+	}
+}
+
+// Test: TestListTransactions_ndjsonUnboundedExportFiltered
+// What: an unbounded export streamed from the store still applies filters, in store order
+// Input: 300 USD and 3 EUR transactions (more than one store read chunk), currency=EUR, AllowUnboundedExport=true
+// Output: 3 lines, EUR-000 to EUR-002 in order
+func TestListTransactions_ndjsonUnboundedExportFiltered(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.AllowUnboundedExport = true
+	srv := newTestServerWithConfig(t, cfg)
+	seedN(t, srv, 300, "USD")
+	seedN(t, srv, 3, "EUR")
+
+	txns := getNDJSON(t, srv, "currency=EUR")
+	if len(txns) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(txns))
+	}
+	for i, txn := range txns {
+		if want := fmt.Sprintf("EUR-%03d", i); txn.ID != want {
+			t.Errorf("line %d: expected %s, got %s", i, want, txn.ID)
+		}
+	}
+}
+
+// Test: TestListTransactions_jsonByDefault
+// What: without the NDJSON Accept header the response is still a JSON array
+// Input: 1 transaction, no Accept header
+// Output: Content-Type application/json
+func TestListTransactions_jsonByDefault(t *testing.T) {
+	srv := newTestServer(t)
+	seedN(t, srv, 1, "USD")
+
+	resp := getTxns(t, srv, "")
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json, got %q", ct)
+	}
+}
@@ -0,0 +1,70 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+)
+
+type openAPIDoc struct {
+	OpenAPI string                    `json:"openapi"`
+	Paths   map[string]map[string]any `json:"paths"`
+}
+
+// Test: TestOpenAPISpec_parsesWithExpectedPaths
+// What: the embedded OpenAPI document is valid JSON and documents every transaction route
+// Input: api.OpenAPISpec()
+// Output: openapi version 3.x, paths include /transactions (get, post) and /transactions/{id} (get)
+func TestOpenAPISpec_parsesWithExpectedPaths(t *testing.T) {
+	var doc openAPIDoc
+	if err := json.Unmarshal(api.OpenAPISpec(), &doc); err != nil {
+		t.Fatalf("embedded spec is not valid JSON: %v", err)
+	}
+	if len(doc.OpenAPI) < 2 || doc.OpenAPI[:2] != "3." {
+		t.Errorf("expected OpenAPI 3.x, got %q", doc.OpenAPI)
+	}
+
+	expected := map[string][]string{
+		"/transactions":      {"get", "post"},
+		"/transactions/{id}": {"get"},
+	}
+	for path, methods := range expected {
+		ops, ok := doc.Paths[path]
+		if !ok {
+			t.Errorf("missing path %s", path)
+			continue
+		}
+		for _, m := range methods {
+			if _, ok := ops[m]; !ok {
+				t.Errorf("missing %s operation on %s", m, path)
+			}
+		}
+	}
+}
+
+// Test: TestServeOpenAPI_contentType
+// What: GET /openapi.json serves the embedded spec as JSON
+// Input: GET /openapi.json
+// Output: HTTP 200, Content-Type application/json, body decodes as an OpenAPI document
+func TestServeOpenAPI_contentType(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/openapi.json")
+	if err != nil {
+		t.Fatalf("GET /openapi.json failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected Content-Type application/json, got %q", ct)
+	}
+	var doc openAPIDoc
+	if err := json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+		t.Fatalf("failed to decode served spec: %v", err)
+	}
+}
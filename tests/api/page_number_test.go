@@ -0,0 +1,141 @@
+package api_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// seedPages stores txn-1 through txn-n on consecutive days, so list order is by number.
+func seedPages(t *testing.T, n int) string {
+	t.Helper()
+	srv := newTestServer(t)
+	for i := 1; i <= n; i++ {
+		seedTxn(t, srv, fmt.Sprintf(`{"id":"txn-%d","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-%02dT00:00:00Z"}`, i, i))
+	}
+	return srv.URL
+}
+
+// Test: TestPageToOffset
+// What: page numbers translate to offsets, and out-of-range values are rejected
+// Input: (page, page_size) of (1, 10), (3, 10), (0, 10), (1, 0), (1, 101) with a max of 100
+// Output: offsets 0 and 20, then three errors
+func TestPageToOffset(t *testing.T) {
+	for _, tc := range []struct {
+		page, pageSize, want int
+	}{{1, 10, 0}, {3, 10, 20}} {
+		if got, err := api.PageToOffset(tc.page, tc.pageSize, 100); err != nil || got != tc.want {
+			t.Errorf("page %d size %d: expected offset %d, got %d (%v)", tc.page, tc.pageSize, tc.want, got, err)
+		}
+	}
+	for _, tc := range [][2]int{{0, 10}, {1, 0}, {1, 101}} {
+		if _, err := api.PageToOffset(tc[0], tc[1], 100); err == nil {
+			t.Errorf("page %d size %d: expected an error", tc[0], tc[1])
+		}
+	}
+}
+
+// Test: TestListTransactions_pageNumber
+// What: page/page_size select the same rows as the equivalent limit/offset, and the envelope
+// reports the page numbers
+// Input: txn-1..txn-5; GET ?page=2&page_size=2, then with envelope=true
+// Output: txn-3 and txn-4; envelope pagination has page 2, page_size 2, total_pages 3,
+// offset 2 and total 5
+func TestListTransactions_pageNumber(t *testing.T) {
+	base := seedPages(t, 5)
+
+	resp, err := http.Get(base + "/transactions?page=2&page_size=2")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	var txns []model.Transaction
+	json.Unmarshal(readBody(t, resp), &txns)
+	if len(txns) != 2 || txns[0].ID != "txn-3" || txns[1].ID != "txn-4" {
+		t.Errorf("expected txn-3 and txn-4, got %v", txns)
+	}
+
+	resp, err = http.Get(base + "/transactions?page=2&page_size=2&envelope=true")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	var envelope struct {
+		Pagination map[string]int `json:"pagination"`
+	}
+	json.Unmarshal(readBody(t, resp), &envelope)
+	want := map[string]int{"limit": 2, "offset": 2, "total": 5, "page": 2, "page_size": 2, "total_pages": 3}
+	for k, v := range want {
+		if envelope.Pagination[k] != v {
+			t.Errorf("expected pagination %s=%d, got %v", k, v, envelope.Pagination)
+		}
+	}
+}
+
+// Test: TestListTransactions_pageNumberOmittedForOffset
+// What: an offset-paged envelope has no page number fields
+// Input: txn-1..txn-3; GET ?limit=2&envelope=true
+// Output: pagination has no page, page_size or total_pages
+func TestListTransactions_pageNumberOmittedForOffset(t *testing.T) {
+	base := seedPages(t, 3)
+	resp, err := http.Get(base + "/transactions?limit=2&envelope=true")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	if body := string(readBody(t, resp)); strings.Contains(body, "page") {
+		t.Errorf("expected no page number fields, got %s", body)
+	}
+}
+
+// Test: TestListTransactions_pageNumberInvalid
+// What: mixing page numbers with limit/offset, or out-of-range page values, is a 400
+// Input: ?page=2&offset=10, ?page_size=5&limit=5, ?page=0, ?page=x, ?page_size=1001 (max 1000)
+// Output: 400 for each, the first naming the conflict
+func TestListTransactions_pageNumberInvalid(t *testing.T) {
+	base := seedPages(t, 1)
+	for _, query := range []string{"page=2&offset=10", "page_size=5&limit=5", "page=0", "page=x", "page_size=1001"} {
+		resp, err := http.Get(base + "/transactions?" + query)
+		if err != nil {
+			t.Fatalf("GET failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, resp.StatusCode)
+		}
+		if query == "page=2&offset=10" && !strings.Contains(string(body), "cannot be combined") {
+			t.Errorf("%s: expected a mixing error, got %q", query, body)
+		}
+	}
+}
+
+// Test: TestListTransactions_pageNumberLinks
+// What: links for a page-numbered request keep paging by number
+// Input: txn-1..txn-5; GET ?page=2&page_size=2&links=true
+// Output: next link has page=3&page_size=2, prev has page=1, neither has offset
+func TestListTransactions_pageNumberLinks(t *testing.T) {
+	base := seedPages(t, 5)
+	resp, err := http.Get(base + "/transactions?page=2&page_size=2&links=true")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	var envelope struct {
+		Links struct {
+			Next, Prev struct {
+				Href string `json:"href"`
+			}
+		} `json:"_links"`
+	}
+	json.Unmarshal(readBody(t, resp), &envelope)
+	next, prev := envelope.Links.Next.Href, envelope.Links.Prev.Href
+	if !strings.Contains(next, "page=3") || !strings.Contains(next, "page_size=2") || strings.Contains(next, "offset") {
+		t.Errorf("unexpected next link %q", next)
+	}
+	if !strings.Contains(prev, "page=1") || strings.Contains(prev, "offset") {
+		t.Errorf("unexpected prev link %q", prev)
+	}
+}
@@ -1,6 +1,7 @@
 package api_test
 
 import (
+	"net/http"
 	"testing"
 	"time"
 
@@ -130,3 +131,40 @@ func TestApplyPagination_doesNotOverlap(t *testing.T) {
 		t.Errorf("expected 6 unique items across pages, got %d", len(seen))
 	}
 }
+
+// Test: TestPaginationConfig_maxLimit
+// What: a handler configured with MaxLimit=50 accepts limit=50 and rejects limit=60
+// Input: Pagination{DefaultLimit: 10, MaxLimit: 50}; GET /transactions?limit=50 and ?limit=60
+// Output: HTTP 200, then HTTP 400
+func TestPaginationConfig_maxLimit(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.Pagination = api.PaginationConfig{DefaultLimit: 10, MaxLimit: 50}
+	srv := newTestServerWithConfig(t, cfg)
+
+	ok := getTxns(t, srv, "limit=50")
+	ok.Body.Close()
+	if ok.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 for limit=50, got %d", ok.StatusCode)
+	}
+
+	tooHigh := getTxns(t, srv, "limit=60")
+	tooHigh.Body.Close()
+	if tooHigh.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for limit=60, got %d", tooHigh.StatusCode)
+	}
+}
+
+// Test: TestPaginationConfig_defaultLimit
+// What: requests without a limit use the configured DefaultLimit
+// Input: Pagination{DefaultLimit: 3, MaxLimit: 50}, 5 seeded transactions, GET /transactions
+// Output: 3 transactions
+func TestPaginationConfig_defaultLimit(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.Pagination = api.PaginationConfig{DefaultLimit: 3, MaxLimit: 50}
+	srv := newTestServerWithConfig(t, cfg)
+	seedN(t, srv, 5, "USD")
+
+	if got := listIDs(t, srv.URL+"/transactions"); len(got) != 3 {
+		t.Errorf("expected 3 transactions, got %d", len(got))
+	}
+}
@@ -1,50 +1,29 @@
 package api_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/synctera/tech-challenge/internal/api"
 )
 
-// --- ParseIntOrDefault ---
+// --- ParseIntStrict ---
 
-// Test: TestParseIntOrDefault_emptyStringReturnsDefault
-// What: ParseIntOrDefault returns the caller-supplied default when given an empty string
-// Input: s="", defaultVal=42
-// Output: 42
-func TestParseIntOrDefault_emptyStringReturnsDefault(t *testing.T) {
-	if got := api.ParseIntOrDefault("", 42); got != 42 {
-		t.Errorf("expected 42, got %d", got)
-	}
-}
-
-// Test: TestParseIntOrDefault_validIntegerParsed
-// What: ParseIntOrDefault parses a valid integer string and returns it
-// Input: s="100", defaultVal=0
-// Output: 100
-func TestParseIntOrDefault_validIntegerParsed(t *testing.T) {
-	if got := api.ParseIntOrDefault("100", 0); got != 100 {
-		t.Errorf("expected 100, got %d", got)
-	}
-}
-
-// Test: TestParseIntOrDefault_invalidStringReturnsDefault
-// What: ParseIntOrDefault returns the default when the string cannot be parsed as an integer
-// Input: s="abc", defaultVal=5
-// Output: 5
-func TestParseIntOrDefault_invalidStringReturnsDefault(t *testing.T) {
-	if got := api.ParseIntOrDefault("abc", 5); got != 5 {
-		t.Errorf("expected 5, got %d", got)
-	}
-}
-
-// Test: TestParseIntOrDefault_negativeIntegerParsed
-// What: ParseIntOrDefault correctly handles negative integer strings
-// Input: s="-10", defaultVal=0
-// Output: -10
-func TestParseIntOrDefault_negativeIntegerParsed(t *testing.T) {
-	if got := api.ParseIntOrDefault("-10", 0); got != -10 {
-		t.Errorf("expected -10, got %d", got)
+// Test: TestParseIntStrict
+// What: ParseIntStrict returns the default only when the value is absent, and an error when it is present but not an integer
+// Input: ("", 7), ("-10", 0), ("abc", 7), ("1.5", 7)
+// Output: 7, -10, then an error for each invalid value
+func TestParseIntStrict(t *testing.T) {
+	if got, err := api.ParseIntStrict("", 7); got != 7 || err != nil {
+		t.Errorf("absent: expected 7, nil; got %d, %v", got, err)
+	}
+	if got, err := api.ParseIntStrict("-10", 0); got != -10 || err != nil {
+		t.Errorf("expected -10, nil; got %d, %v", got, err)
+	}
+	for _, s := range []string{"abc", "1.5"} {
+		if _, err := api.ParseIntStrict(s, 7); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
 	}
 }
 
@@ -212,7 +191,7 @@ func TestParseAndValidateDateFilters_invalidEndReturnsError(t *testing.T) {
 // Input: minAmountStr="", maxAmountStr=""
 // Output: nil min, nil max, nil error
 func TestParseAndValidateAmountFilters_noFilters(t *testing.T) {
-	min, max, err := api.ParseAndValidateAmountFilters("", "")
+	min, max, err := api.ParseAndValidateAmountFilters("", "", false, false)
 	if err != nil || min != nil || max != nil {
 		t.Errorf("expected nil,nil,nil - got %v,%v,%v", min, max, err)
 	}
@@ -223,7 +202,7 @@ func TestParseAndValidateAmountFilters_noFilters(t *testing.T) {
 // Input: minAmountStr="100", maxAmountStr=""
 // Output: min=100, nil max, nil error
 func TestParseAndValidateAmountFilters_minOnly(t *testing.T) {
-	min, max, err := api.ParseAndValidateAmountFilters("100", "")
+	min, max, err := api.ParseAndValidateAmountFilters("100", "", false, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -240,7 +219,7 @@ func TestParseAndValidateAmountFilters_minOnly(t *testing.T) {
 // Input: minAmountStr="", maxAmountStr="500"
 // Output: nil min, max=500, nil error
 func TestParseAndValidateAmountFilters_maxOnly(t *testing.T) {
-	min, max, err := api.ParseAndValidateAmountFilters("", "500")
+	min, max, err := api.ParseAndValidateAmountFilters("", "500", false, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -257,7 +236,7 @@ func TestParseAndValidateAmountFilters_maxOnly(t *testing.T) {
 // Input: minAmountStr="100", maxAmountStr="500"
 // Output: nil error
 func TestParseAndValidateAmountFilters_validRange(t *testing.T) {
-	_, _, err := api.ParseAndValidateAmountFilters("100", "500")
+	_, _, err := api.ParseAndValidateAmountFilters("100", "500", false, false)
 	if err != nil {
 		t.Errorf("expected nil for valid range, got %v", err)
 	}
@@ -268,7 +247,7 @@ func TestParseAndValidateAmountFilters_validRange(t *testing.T) {
 // Input: minAmountStr="100", maxAmountStr="100"
 // Output: nil error
 func TestParseAndValidateAmountFilters_equalAmounts(t *testing.T) {
-	_, _, err := api.ParseAndValidateAmountFilters("100", "100")
+	_, _, err := api.ParseAndValidateAmountFilters("100", "100", false, false)
 	if err != nil {
 		t.Errorf("expected nil for equal min/max, got %v", err)
 	}
@@ -279,7 +258,7 @@ func TestParseAndValidateAmountFilters_equalAmounts(t *testing.T) {
 // Input: minAmountStr="500", maxAmountStr="100"
 // Output: non-nil error
 func TestParseAndValidateAmountFilters_minGreaterThanMaxReturnsError(t *testing.T) {
-	_, _, err := api.ParseAndValidateAmountFilters("500", "100")
+	_, _, err := api.ParseAndValidateAmountFilters("500", "100", false, false)
 	if err == nil {
 		t.Error("expected error when min > max, got nil")
 	}
@@ -290,7 +269,7 @@ func TestParseAndValidateAmountFilters_minGreaterThanMaxReturnsError(t *testing.
 // Input: minAmountStr="abc", maxAmountStr="100"
 // Output: non-nil error
 func TestParseAndValidateAmountFilters_invalidMinReturnsError(t *testing.T) {
-	_, _, err := api.ParseAndValidateAmountFilters("abc", "100")
+	_, _, err := api.ParseAndValidateAmountFilters("abc", "100", false, false)
 	if err == nil {
 		t.Error("expected error for invalid min_amount, got nil")
 	}
@@ -301,8 +280,30 @@ func TestParseAndValidateAmountFilters_invalidMinReturnsError(t *testing.T) {
 // Input: minAmountStr="100", maxAmountStr="xyz"
 // Output: non-nil error
 func TestParseAndValidateAmountFilters_invalidMaxReturnsError(t *testing.T) {
-	_, _, err := api.ParseAndValidateAmountFilters("100", "xyz")
+	_, _, err := api.ParseAndValidateAmountFilters("100", "xyz", false, false)
 	if err == nil {
 		t.Error("expected error for invalid max_amount, got nil")
 	}
 }
+
+// Test: TestParseAndValidateAmountFilters_exclusiveEqualBoundsReturnsError
+// What: ParseAndValidateAmountFilters rejects min == max when either bound is exclusive, since nothing can match
+// Input: minAmountStr="100", maxAmountStr="100", minExclusive=true
+// Output: non-nil error
+func TestParseAndValidateAmountFilters_exclusiveEqualBoundsReturnsError(t *testing.T) {
+	_, _, err := api.ParseAndValidateAmountFilters("100", "100", true, false)
+	if err == nil {
+		t.Error("expected error for empty exclusive range, got nil")
+	}
+}
+
+// Test: TestParseAndValidateAmountFilters_invalidExclusiveNamesParam
+// What: a non-numeric exclusive bound is reported under its _exclusive parameter name
+// Input: minAmountStr="abc", minExclusive=true
+// Output: error mentioning min_amount_exclusive
+func TestParseAndValidateAmountFilters_invalidExclusiveNamesParam(t *testing.T) {
+	_, _, err := api.ParseAndValidateAmountFilters("abc", "", true, false)
+	if err == nil || !strings.Contains(err.Error(), "min_amount_exclusive") {
+		t.Errorf("expected error naming min_amount_exclusive, got %v", err)
+	}
+}
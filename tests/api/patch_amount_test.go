@@ -0,0 +1,137 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestPatchAmount_correctsAndRecordsHistory
+// What: PATCH {"amount":N} changes the amount and records the prior value in metadata.amount_history
+// Input: txn-1 with amount 100; PATCH {"amount":150}, then GET
+// Output: HTTP 200; body and GET show amount 150 and an amount_history with one entry for 100
+func TestPatchAmount_correctsAndRecordsHistory(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+
+	resp := patchTxn(t, srv, "txn-1", `{"amount":150}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var patched model.Transaction
+	json.NewDecoder(resp.Body).Decode(&patched)
+
+	get := getTxnByID(t, srv, "txn-1")
+	defer get.Body.Close()
+	var stored model.Transaction
+	json.NewDecoder(get.Body).Decode(&stored)
+
+	for name, txn := range map[string]model.Transaction{"PATCH": patched, "GET": stored} {
+		if txn.Amount != 150 {
+			t.Errorf("%s: expected amount 150, got %d", name, txn.Amount)
+		}
+		var history []model.AmountChange
+		if err := json.Unmarshal([]byte(txn.Metadata[model.MetadataAmountHistory]), &history); err != nil {
+			t.Fatalf("%s: invalid amount_history %q: %v", name, txn.Metadata[model.MetadataAmountHistory], err)
+		}
+		if len(history) != 1 || history[0].Amount != 100 || history[0].ChangedAt.IsZero() {
+			t.Errorf("%s: expected one history entry for 100, got %+v", name, history)
+		}
+	}
+}
+
+// Test: TestPatchAmount_rejectsInvalid
+// What: amount patches are validated, and unknown IDs are 404
+// Input: PATCH on missing; a negative amount; amount with metadata; a metadata patch of amount_history
+// Output: 404; then 400 for each of the rest
+func TestPatchAmount_rejectsInvalid(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+
+	resp := patchTxn(t, srv, "missing", `{"amount":150}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp.StatusCode)
+	}
+
+	for _, body := range []string{
+		`{"amount":-1}`,
+		`{"amount":150,"metadata":{"note":"x"}}`,
+		`{"metadata":{"amount_history":"[]"}}`,
+	} {
+		resp := patchTxn(t, srv, "txn-1", body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", body, resp.StatusCode)
+		}
+	}
+}
+
+// Test: TestPatchAmount_clientHistoryRejected
+// What: amount_history can't be supplied on create, upsert or import, whatever its length
+// Input: create, upsert and import bodies carrying a 5000-character amount_history
+// Output: HTTP 400 for create and upsert; the import counts the line invalid; nothing is stored
+func TestPatchAmount_clientHistoryRejected(t *testing.T) {
+	srv := newTestServer(t)
+	body := `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","metadata":{"amount_history":"` +
+		strings.Repeat("x", 5000) + `"}}`
+
+	create := postTxn(t, srv, body)
+	create.Body.Close()
+	if create.StatusCode != http.StatusBadRequest {
+		t.Errorf("create: expected 400, got %d", create.StatusCode)
+	}
+	upsert, err := http.Post(srv.URL+"/transactions?mode=upsert", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("upsert failed: %v", err)
+	}
+	upsert.Body.Close()
+	if upsert.StatusCode != http.StatusBadRequest {
+		t.Errorf("upsert: expected 400, got %d", upsert.StatusCode)
+	}
+	report := decodeImportReport(t, postImport(t, srv, api.ContentTypeNDJSON, body+"\n"))
+	if report.Invalid != 1 || report.Created != 0 {
+		t.Errorf("import: expected 1 invalid, got %+v", report)
+	}
+
+	get := getTxnByID(t, srv, "txn-1")
+	get.Body.Close()
+	if get.StatusCode != http.StatusNotFound {
+		t.Errorf("expected nothing stored, got %d", get.StatusCode)
+	}
+}
+
+// Test: TestPatchAmount_corruptHistory
+// What: a stored amount_history that isn't a JSON list makes an amount patch a 409, not a 500
+// Input: a store seeded directly with amount_history "not json"; PATCH {"amount":150}
+// Output: HTTP 409; the amount and history are unchanged
+func TestPatchAmount_corruptHistory(t *testing.T) {
+	s := store.NewMemoryStore()
+	seeded := model.Transaction{ID: "txn-1", AccountID: "acct-1", Amount: 100, Currency: "USD", Direction: model.DirectionDebit,
+		EffectiveAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Metadata: map[string]string{model.MetadataAmountHistory: "not json"}}
+	if err := s.Create(seeded); err != nil {
+		t.Fatalf("seed failed: %v", err)
+	}
+	mux := http.NewServeMux()
+	api.NewHandler(s).RegisterRoutes(mux, nil)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	resp := patchTxn(t, srv, "txn-1", `{"amount":150}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected 409, got %d", resp.StatusCode)
+	}
+	stored, _ := s.Get("txn-1")
+	if stored.Amount != 100 || stored.Metadata[model.MetadataAmountHistory] != "not json" {
+		t.Errorf("expected the transaction unchanged, got %+v", stored)
+	}
+}
@@ -0,0 +1,266 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+func patchTxn(t *testing.T, srv *httptest.Server, id, body string) *http.Response {
+	t.Helper()
+	return patchTxnIfMatch(t, srv, id, "", body)
+}
+
+// patchTxnIfMatch is patchTxn with an If-Match header, omitted when ifMatch is empty.
+func patchTxnIfMatch(t *testing.T, srv *httptest.Server, id, ifMatch, body string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPatch, srv.URL+"/transactions/"+id, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("PATCH /transactions/%s failed: %v", id, err)
+	}
+	return resp
+}
+
+// Test: TestPatchMetadata_addOverwriteDelete
+// What: PATCH merges metadata: new keys are added, existing ones overwritten, null deletes
+// Input: txn-1 with metadata {source:web, note:x}; PATCH {"metadata":{"source":"mobile","note":null,"order":"A-1"}}
+// Output: HTTP 200, body and a later GET both show metadata {source:mobile, order:A-1}
+func TestPatchMetadata_addOverwriteDelete(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","metadata":{"source":"web","note":"x"}}`)
+
+	resp := patchTxn(t, srv, "txn-1", `{"metadata":{"source":"mobile","note":null,"order":"A-1"}}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	want := map[string]string{"source": "mobile", "order": "A-1"}
+	var patched model.Transaction
+	json.NewDecoder(resp.Body).Decode(&patched)
+	if !reflect.DeepEqual(patched.Metadata, want) {
+		t.Errorf("expected response metadata %v, got %v", want, patched.Metadata)
+	}
+
+	get := getTxnByID(t, srv, "txn-1")
+	defer get.Body.Close()
+	var stored model.Transaction
+	json.NewDecoder(get.Body).Decode(&stored)
+	if !reflect.DeepEqual(stored.Metadata, want) {
+		t.Errorf("expected stored metadata %v, got %v", want, stored.Metadata)
+	}
+	if stored.Amount != 100 || stored.Currency != "USD" {
+		t.Errorf("expected other fields untouched, got %+v", stored)
+	}
+}
+
+// Test: TestPatchMetadata_notFound
+// What: PATCH on an unknown ID returns 404
+// Input: empty store, PATCH /transactions/missing
+// Output: HTTP 404
+func TestPatchMetadata_notFound(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := patchTxn(t, srv, "missing", `{"metadata":{"k":"v"}}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestPatchMetadata_invalidBody
+// What: PATCH without a metadata object, or with invalid JSON, returns 400
+// Input: bodies `{}` and `not json`
+// Output: HTTP 400 for both
+func TestPatchMetadata_invalidBody(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+
+	for _, body := range []string{`{}`, `not json`} {
+		resp := patchTxn(t, srv, "txn-1", body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("body %q: expected 400, got %d", body, resp.StatusCode)
+		}
+	}
+}
+
+// Test: TestPatchMetadata_enforcesLimits
+// What: a patch that would push metadata past MaxMetadataEntries is rejected and nothing changes
+// Input: txn-1 with 1 metadata entry; PATCH adding MaxMetadataEntries new keys
+// Output: HTTP 400, stored metadata still has 1 entry
+func TestPatchMetadata_enforcesLimits(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","metadata":{"k":"v"}}`)
+
+	entries := make([]string, api.MaxMetadataEntries)
+	for i := range entries {
+		entries[i] = fmt.Sprintf(`"new%d":"v"`, i)
+	}
+	resp := patchTxn(t, srv, "txn-1", `{"metadata":{`+strings.Join(entries, ",")+`}}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+
+	get := getTxnByID(t, srv, "txn-1")
+	defer get.Body.Close()
+	var stored model.Transaction
+	json.NewDecoder(get.Body).Decode(&stored)
+	if len(stored.Metadata) != 1 {
+		t.Errorf("expected metadata to be unchanged, got %d entries", len(stored.Metadata))
+	}
+}
+
+// Test: TestPatchMetadata_ifMatch
+// What: a PATCH with the current ETag in If-Match applies; one with the ETag from before that
+// patch is rejected, so a concurrent writer can't silently overwrite the first change
+// Input: txn-1 (version 1); PATCH with If-Match = its ETag, then PATCH again with the same ETag
+// Output: first PATCH 200 with version 2 and a new ETag; second 409 and metadata unchanged
+func TestPatchMetadata_ifMatch(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	get := getTxnByID(t, srv, "txn-1")
+	get.Body.Close()
+	etag := get.Header.Get("ETag")
+
+	resp := patchTxnIfMatch(t, srv, "txn-1", etag, `{"metadata":{"owner":"alice"}}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var patched model.Transaction
+	json.NewDecoder(resp.Body).Decode(&patched)
+	if patched.Version != 2 {
+		t.Errorf("expected version 2 after the patch, got %d", patched.Version)
+	}
+	if resp.Header.Get("ETag") == etag {
+		t.Errorf("expected the ETag to change with the version, still %s", etag)
+	}
+
+	stale := patchTxnIfMatch(t, srv, "txn-1", etag, `{"metadata":{"owner":"bob"}}`)
+	stale.Body.Close()
+	if stale.StatusCode != http.StatusConflict {
+		t.Errorf("expected 409 for a stale If-Match, got %d", stale.StatusCode)
+	}
+
+	after := getTxnByID(t, srv, "txn-1")
+	defer after.Body.Close()
+	var stored model.Transaction
+	json.NewDecoder(after.Body).Decode(&stored)
+	if stored.Metadata["owner"] != "alice" || stored.Version != 2 {
+		t.Errorf("expected owner=alice at version 2, got %v at version %d", stored.Metadata, stored.Version)
+	}
+}
+
+// Test: TestPatchMetadata_bodyVersion
+// What: the body's version works like If-Match
+// Input: txn-1 (version 1); PATCH {"version":1,...}, then PATCH {"version":1,...} again
+// Output: first 200 with version 2; second 409
+func TestPatchMetadata_bodyVersion(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+
+	resp := patchTxn(t, srv, "txn-1", `{"version":1,"metadata":{"owner":"alice"}}`)
+	defer resp.Body.Close()
+	var patched model.Transaction
+	json.NewDecoder(resp.Body).Decode(&patched)
+	if resp.StatusCode != http.StatusOK || patched.Version != 2 {
+		t.Fatalf("expected 200 with version 2, got %d with version %d", resp.StatusCode, patched.Version)
+	}
+
+	stale := patchTxn(t, srv, "txn-1", `{"version":1,"metadata":{"owner":"bob"}}`)
+	stale.Body.Close()
+	if stale.StatusCode != http.StatusConflict {
+		t.Errorf("expected 409 for a stale version, got %d", stale.StatusCode)
+	}
+}
+
+// Test: TestPatchMetadata_omittedLeavesUnchanged
+// What: a patch that omits metadata, or sends it as null, does not touch the stored metadata
+// Input: txn-1 with metadata {source:web}; PATCH {"amount":200}, then {"metadata":null,"amount":300}
+// Output: both 200; a later GET shows amount 300 and metadata source still web
+func TestPatchMetadata_omittedLeavesUnchanged(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","metadata":{"source":"web"}}`)
+
+	for _, body := range []string{`{"amount":200}`, `{"metadata":null,"amount":300}`} {
+		resp := patchTxn(t, srv, "txn-1", body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", body, resp.StatusCode)
+		}
+	}
+
+	get := getTxnByID(t, srv, "txn-1")
+	defer get.Body.Close()
+	var stored model.Transaction
+	json.NewDecoder(get.Body).Decode(&stored)
+	if stored.Amount != 300 || stored.Metadata["source"] != "web" {
+		t.Errorf("expected amount 300 with source=web, got %d with %v", stored.Amount, stored.Metadata)
+	}
+}
+
+// Test: TestPatchMetadata_explicitEmptyClears
+// What: an explicit empty metadata object clears the client's metadata but keeps the
+// server-maintained amount_history
+// Input: txn-1 with metadata {source:web, note:x} and one amount correction; PATCH {"metadata":{}}
+// Output: HTTP 200; a later GET shows only the amount_history key
+func TestPatchMetadata_explicitEmptyClears(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","metadata":{"source":"web","note":"x"}}`)
+	correct := patchTxn(t, srv, "txn-1", `{"amount":200}`)
+	correct.Body.Close()
+
+	resp := patchTxn(t, srv, "txn-1", `{"metadata":{}}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	get := getTxnByID(t, srv, "txn-1")
+	defer get.Body.Close()
+	var stored model.Transaction
+	json.NewDecoder(get.Body).Decode(&stored)
+	if _, ok := stored.Metadata[model.MetadataAmountHistory]; !ok || len(stored.Metadata) != 1 {
+		t.Errorf("expected only %s to remain, got %v", model.MetadataAmountHistory, stored.Metadata)
+	}
+}
+
+// Test: TestPatchMetadata_explicitEmptyKeepsReversal
+// What: clearing the metadata of a reversed transaction keeps reversed_by, so it can't be
+// reversed a second time
+// Input: txn-1 with metadata {source:web} reversed; PATCH {"metadata":{}}; reverse txn-1 again
+// Output: HTTP 200 leaving only reversed_by; the second reverse is 409
+func TestPatchMetadata_explicitEmptyKeepsReversal(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","metadata":{"source":"web"}}`)
+	postReverse(t, srv, "txn-1", "").Body.Close()
+
+	resp := patchTxn(t, srv, "txn-1", `{"metadata":{}}`)
+	defer resp.Body.Close()
+	var stored model.Transaction
+	json.NewDecoder(resp.Body).Decode(&stored)
+	if resp.StatusCode != http.StatusOK || len(stored.Metadata) != 1 || stored.Metadata[model.MetadataReversedBy] != "txn-1-reversal" {
+		t.Errorf("expected 200 with only reversed_by left, got %d %v", resp.StatusCode, stored.Metadata)
+	}
+
+	again := postReverse(t, srv, "txn-1", `{"id":"another"}`)
+	again.Body.Close()
+	if again.StatusCode != http.StatusConflict {
+		t.Errorf("second reverse: expected 409, got %d", again.StatusCode)
+	}
+}
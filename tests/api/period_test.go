@@ -0,0 +1,73 @@
+package api_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/clock"
+)
+
+// Test: TestListTransactions_periodLast7d
+// What: period=last_7d covers today and the 6 whole UTC days before it, computed from the handler clock,
+// with the same day boundaries as start_date and end_date
+// Input: clock at 2024-03-15T10:00Z; transactions at 03-08T23:59:59, 03-09T00:00, 03-15T23:59, 03-16T06:00
+// Output: only the 03-09 and 03-15 transactions
+func TestListTransactions_periodLast7d(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.Clock = clock.NewFake(time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC))
+	srv := newTestServerWithConfig(t, cfg)
+	for i, at := range []string{"2024-03-08T23:59:59Z", "2024-03-09T00:00:00Z", "2024-03-15T23:59:00Z", "2024-03-16T06:00:00Z"} {
+		seedTxn(t, srv, fmt.Sprintf(`{"id":"txn-%d","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":%q}`, i+1, at))
+	}
+
+	assertIDs(t, listIDs(t, srv.URL+"/transactions?period=last_7d"), "txn-2", "txn-3")
+}
+
+// Test: TestListTransactions_periodErrors
+// What: period is rejected alongside explicit dates and when unknown
+// Input: period=today with start_date, period=today with end_date, period=last_week
+// Output: HTTP 400 each, with a message naming the problem
+func TestListTransactions_periodErrors(t *testing.T) {
+	srv := newTestServer(t)
+
+	for query, want := range map[string]string{
+		"period=today&start_date=2024-01-01": "cannot be combined",
+		"period=today&end_date=2024-01-01":   "cannot be combined",
+		"period=last_week":                   "period must be one of",
+	} {
+		resp := getTxns(t, srv, query)
+		msg, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(msg), want) {
+			t.Errorf("%s: expected 400 containing %q, got %d %q", query, want, resp.StatusCode, msg)
+		}
+	}
+}
+
+// Test: TestParsePeriod
+// What: each period maps to the expected inclusive UTC day range, whatever the offset of now
+// Input: now 2024-02-10T23:30-05:00 (2024-02-11 in UTC)
+// Output: today 02-11..02-11, last_7d 02-05..02-11, last_30d 01-13..02-11, this_month 02-01..02-29
+func TestParsePeriod(t *testing.T) {
+	now := time.Date(2024, 2, 10, 23, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+	for period, want := range map[string][2]string{
+		api.PeriodToday:     {"2024-02-11", "2024-02-11"},
+		api.PeriodLast7d:    {"2024-02-05", "2024-02-11"},
+		api.PeriodLast30d:   {"2024-01-13", "2024-02-11"},
+		api.PeriodThisMonth: {"2024-02-01", "2024-02-29"},
+	} {
+		start, end, err := api.ParsePeriod(period, now)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", period, err)
+			continue
+		}
+		if got := [2]string{start.Format(time.DateOnly), end.Format(time.DateOnly)}; got != want {
+			t.Errorf("%s: expected %v, got %v", period, want, got)
+		}
+	}
+}
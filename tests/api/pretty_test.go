@@ -0,0 +1,42 @@
+package api_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// Test: TestPrettyJSON
+// What: pretty=true indents JSON responses by two spaces; the default stays compact on one line
+// Input: one transaction; GET /transactions/txn-1 and GET /transactions?envelope=true, each with and without pretty=true
+// Output: pretty bodies have indented lines such as `  "id": "txn-1"`; default bodies have only the trailing newline
+func TestPrettyJSON(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","metadata":{"k":"v"}}`)
+
+	for _, path := range []string{"/transactions/txn-1", "/transactions?envelope=true"} {
+		sep := "?"
+		if strings.Contains(path, "?") {
+			sep = "&"
+		}
+
+		compact := getBody(t, srv.URL+path)
+		if strings.Count(compact, "\n") != 1 {
+			t.Errorf("%s: expected compact JSON on one line, got %q", path, compact)
+		}
+
+		pretty := getBody(t, srv.URL+path+sep+"pretty=true")
+		if strings.Count(pretty, "\n") < 5 || !strings.Contains(pretty, "\n  \"") {
+			t.Errorf("%s: expected two-space indented JSON, got %q", path, pretty)
+		}
+	}
+}
+
+func getBody(t *testing.T, url string) string {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", url, err)
+	}
+	return string(readBody(t, resp))
+}
@@ -0,0 +1,187 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/clock"
+)
+
+// testProxies are the load balancers the rate limit tests trust.
+var testProxies, _ = api.ParseTrustedProxies("192.168.1.0/24, 10.1.2.3")
+
+// newRateLimited wraps a 200 handler in a limiter whose clock is frozen, so buckets only
+// refill when a test advances clk. X-Forwarded-For is believed from testProxies.
+func newRateLimited(rps, burst int) http.Handler {
+	h, _ := newRateLimitedWithClock(rps, burst)
+	return h
+}
+
+func newRateLimitedWithClock(rps, burst int) (http.Handler, *clock.Fake) {
+	clk := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return api.RateLimitMiddlewareWithClock(rps, burst, testProxies, clk)(ok), clk
+}
+
+func doFrom(h http.Handler, remoteAddr, xff string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/transactions", nil)
+	req.RemoteAddr = remoteAddr
+	if xff != "" {
+		req.Header.Set("X-Forwarded-For", xff)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// Test: TestRateLimit_burstAllowedThen429
+// What: a client may send up to burst requests immediately; the next one gets 429 with Retry-After
+// Input: limiter with rps=1, burst=3; four requests from the same IP
+// Output: first three HTTP 200, fourth HTTP 429 with a positive Retry-After
+func TestRateLimit_burstAllowedThen429(t *testing.T) {
+	h := newRateLimited(1, 3)
+
+	for i := 0; i < 3; i++ {
+		if rec := doFrom(h, "10.0.0.1:1234", ""); rec.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i+1, rec.Code)
+		}
+	}
+
+	rec := doFrom(h, "10.0.0.1:1234", "")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after burst drained, got %d", rec.Code)
+	}
+	retryAfter, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+	if err != nil || retryAfter < 1 {
+		t.Errorf("expected positive Retry-After header, got %q", rec.Header().Get("Retry-After"))
+	}
+}
+
+// Test: TestRateLimit_refillsWithClock
+// What: a drained bucket refills as the injected clock advances, with no real waiting
+// Input: limiter with rps=1, burst=1; two requests, then the clock advanced 1s, then a third
+// Output: 200, 429, 200
+func TestRateLimit_refillsWithClock(t *testing.T) {
+	h, clk := newRateLimitedWithClock(1, 1)
+
+	if rec := doFrom(h, "10.0.0.1:1234", ""); rec.Code != http.StatusOK {
+		t.Fatalf("first request: expected 200, got %d", rec.Code)
+	}
+	if rec := doFrom(h, "10.0.0.1:1234", ""); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request: expected 429, got %d", rec.Code)
+	}
+
+	clk.Advance(time.Second)
+	if rec := doFrom(h, "10.0.0.1:1234", ""); rec.Code != http.StatusOK {
+		t.Errorf("after refill: expected 200, got %d", rec.Code)
+	}
+}
+
+// Test: TestRateLimit_perClient
+// What: buckets are tracked per client IP, so one client draining its bucket does not affect another
+// Input: limiter with rps=1, burst=1; two requests from 10.0.0.1 then one from 10.0.0.2
+// Output: second request from 10.0.0.1 is 429, request from 10.0.0.2 is 200
+func TestRateLimit_perClient(t *testing.T) {
+	h := newRateLimited(1, 1)
+
+	doFrom(h, "10.0.0.1:1234", "")
+	if rec := doFrom(h, "10.0.0.1:1234", ""); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 for drained client, got %d", rec.Code)
+	}
+	if rec := doFrom(h, "10.0.0.2:1234", ""); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 for a different client, got %d", rec.Code)
+	}
+}
+
+// Test: TestRateLimit_honorsXForwardedFor
+// What: requests arriving through a trusted proxy are keyed by the forwarded client address
+// Input: limiter with rps=1, burst=1; RemoteAddr a trusted proxy, different X-Forwarded-For values
+// Output: both requests HTTP 200
+func TestRateLimit_honorsXForwardedFor(t *testing.T) {
+	h := newRateLimited(1, 1)
+
+	if rec := doFrom(h, "192.168.1.1:80", "203.0.113.5"); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if rec := doFrom(h, "192.168.1.1:80", "203.0.113.6"); rec.Code != http.StatusOK {
+		t.Errorf("expected 200 for a different forwarded client, got %d", rec.Code)
+	}
+}
+
+// Test: TestRateLimit_spoofedXForwardedFor
+// What: a client can't reset its bucket by forging X-Forwarded-For, whether it connects
+// directly or through a trusted proxy that appends to the forged header
+// Input: limiter with rps=1, burst=1; 203.0.113.9 sends directly with a new X-Forwarded-For
+// each time; then via proxy 192.168.1.1 with XFF "<forged>, 203.0.113.7"
+// Output: direct: 200 then 429; proxied: 200 then 429
+func TestRateLimit_spoofedXForwardedFor(t *testing.T) {
+	h := newRateLimited(1, 1)
+
+	if rec := doFrom(h, "203.0.113.9:4000", "198.51.100.1"); rec.Code != http.StatusOK {
+		t.Fatalf("direct: expected 200, got %d", rec.Code)
+	}
+	if rec := doFrom(h, "203.0.113.9:4000", "198.51.100.2"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("direct with a new forged header: expected 429, got %d", rec.Code)
+	}
+
+	if rec := doFrom(h, "192.168.1.1:80", "198.51.100.3, 203.0.113.7"); rec.Code != http.StatusOK {
+		t.Fatalf("proxied: expected 200, got %d", rec.Code)
+	}
+	if rec := doFrom(h, "192.168.1.1:80", "198.51.100.4, 203.0.113.7"); rec.Code != http.StatusTooManyRequests {
+		t.Errorf("proxied with a new forged hop: expected 429, got %d", rec.Code)
+	}
+}
+
+// Test: TestClientIP
+// What: ClientIP uses the RemoteAddr host unless the connection is from a trusted proxy, and
+// then the right-most X-Forwarded-For hop that isn't a trusted proxy
+// Input: a direct client with and without X-Forwarded-For; a trusted proxy forwarding through
+// a second trusted proxy; a trusted proxy whose header lists only trusted hops; a trusted
+// proxy without the header
+// Output: the RemoteAddr host, the RemoteAddr host, the untrusted hop, the left-most hop,
+// the proxy's own address
+func TestClientIP(t *testing.T) {
+	for _, tc := range []struct {
+		remoteAddr, xff, want string
+	}{
+		{"10.0.0.1:5555", "", "10.0.0.1"},
+		{"10.0.0.1:5555", "203.0.113.5", "10.0.0.1"},
+		{"192.168.1.1:80", " 198.51.100.1 , 203.0.113.5 , 10.1.2.3", "203.0.113.5"},
+		{"192.168.1.1:80", "192.168.1.7, 10.1.2.3", "192.168.1.7"},
+		{"192.168.1.1:80", "", "192.168.1.1"},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tc.remoteAddr
+		if tc.xff != "" {
+			req.Header.Set("X-Forwarded-For", tc.xff)
+		}
+		if got := api.ClientIP(req, testProxies); got != tc.want {
+			t.Errorf("RemoteAddr %s, X-Forwarded-For %q: expected %s, got %q", tc.remoteAddr, tc.xff, tc.want, got)
+		}
+	}
+}
+
+// Test: TestParseTrustedProxies
+// What: trusted proxies are CIDRs or single IPs; anything else is an error
+// Input: "10.0.0.0/8, 192.168.1.1", "", "not-an-ip", "10.0.0.0/99"
+// Output: two prefixes; none; an error; an error
+func TestParseTrustedProxies(t *testing.T) {
+	prefixes, err := api.ParseTrustedProxies("10.0.0.0/8, 192.168.1.1")
+	if err != nil || len(prefixes) != 2 || prefixes[0].String() != "10.0.0.0/8" || prefixes[1].String() != "192.168.1.1/32" {
+		t.Errorf("expected [10.0.0.0/8 192.168.1.1/32], got %v, %v", prefixes, err)
+	}
+	if prefixes, err := api.ParseTrustedProxies(""); err != nil || len(prefixes) != 0 {
+		t.Errorf("expected no proxies, got %v, %v", prefixes, err)
+	}
+	for _, bad := range []string{"not-an-ip", "10.0.0.0/99"} {
+		if _, err := api.ParseTrustedProxies(bad); err == nil {
+			t.Errorf("%q: expected an error", bad)
+		}
+	}
+}
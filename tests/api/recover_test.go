@@ -0,0 +1,76 @@
+package api_test
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+)
+
+// newPanicky serves /panic with a handler that panics and / with "ok", behind the same
+// middleware order as main: recover, gzip, request ID.
+func newPanicky(t *testing.T, logs *bytes.Buffer) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/panic", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		panic("boom")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	traced := api.RequestIDMiddleware(mux)
+	compressed := api.GzipMiddleware(api.DefaultGzipMinSize)(traced)
+	logger := slog.New(slog.NewTextHandler(logs, nil))
+	srv := httptest.NewServer(api.RecoverMiddleware(logger)(compressed))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// Test: TestRecoverMiddleware_panicBecomes500
+// What: a panicking handler gets a 500 and a logged stack trace, and the server keeps serving
+// Input: GET /panic (with and without gzip), then GET /
+// Output: 500 "internal server error" with an X-Request-ID; the log has the panic value, request ID and stack; / returns 200 "ok"
+func TestRecoverMiddleware_panicBecomes500(t *testing.T) {
+	var logs bytes.Buffer
+	srv := newPanicky(t, &logs)
+
+	for _, encoding := range []string{"", "gzip"} {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/panic", nil)
+		req.Header.Set("Accept-Encoding", encoding)
+		resp, err := http.DefaultTransport.RoundTrip(req) // no transparent decompression
+		if err != nil {
+			t.Fatalf("GET /panic failed: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusInternalServerError {
+			t.Errorf("encoding %q: expected 500, got %d", encoding, resp.StatusCode)
+		}
+		if strings.TrimSpace(string(body)) != "internal server error" {
+			t.Errorf("encoding %q: expected the plain error body, got %q", encoding, body)
+		}
+		if id := resp.Header.Get(api.RequestIDHeader); id == "" || !strings.Contains(logs.String(), id) {
+			t.Errorf("encoding %q: expected request ID %q in the log, got %q", encoding, id, logs.String())
+		}
+	}
+	if !strings.Contains(logs.String(), "panic=boom") || !strings.Contains(logs.String(), "recover_test.go") {
+		t.Errorf("expected the panic value and stack in the log, got %q", logs.String())
+	}
+
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatalf("server stopped serving after a panic: %v", err)
+	}
+	defer resp.Body.Close()
+	if body, _ := io.ReadAll(resp.Body); resp.StatusCode != http.StatusOK || string(body) != "ok" {
+		t.Errorf("expected 200 ok, got %d %q", resp.StatusCode, body)
+	}
+}
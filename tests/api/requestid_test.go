@@ -0,0 +1,144 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+// newTraced wraps a handler that records the request ID it sees in its context and fails
+// requests to /fail with a plain-text 400, logging to logs.
+func newTraced(logs *bytes.Buffer, seen *string) http.Handler {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*seen = api.RequestIDFromContext(r.Context())
+		if r.URL.Path == "/fail" {
+			http.Error(w, "bad input", http.StatusBadRequest)
+			return
+		}
+		w.Write([]byte("ok"))
+	})
+	return api.Chain(api.RequestIDMiddleware, api.LoggingMiddleware(log.New(logs, "", 0)))(next)
+}
+
+// Test: TestRequestID_echoesProvided
+// What: a client-supplied X-Request-ID reaches the handler, the response header and the access log
+// Input: GET / with X-Request-ID: abc-123
+// Output: response header abc-123; the context held abc-123; the log line has request_id=abc-123
+func TestRequestID_echoesProvided(t *testing.T) {
+	var logs bytes.Buffer
+	var seen string
+	h := newTraced(&logs, &seen)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(api.RequestIDHeader, "abc-123")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(api.RequestIDHeader); got != "abc-123" {
+		t.Errorf("expected header abc-123, got %q", got)
+	}
+	if seen != "abc-123" {
+		t.Errorf("expected context ID abc-123, got %q", seen)
+	}
+	if !strings.Contains(logs.String(), "request_id=abc-123") || !strings.Contains(logs.String(), "status=200") {
+		t.Errorf("expected the log line to carry the ID and status, got %q", logs.String())
+	}
+}
+
+// Test: TestRequestID_generatedWhenAbsent
+// What: without a usable X-Request-ID the middleware generates a UUID, new for each request
+// Input: two GETs with no header, and one with an ID containing a space
+// Output: each response carries a distinct v4 UUID matching what the handler saw
+func TestRequestID_generatedWhenAbsent(t *testing.T) {
+	var logs bytes.Buffer
+	var seen string
+	h := newTraced(&logs, &seen)
+
+	got := map[string]bool{}
+	for _, provided := range []string{"", "", "has space"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if provided != "" {
+			req.Header.Set(api.RequestIDHeader, provided)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		id := rec.Header().Get(api.RequestIDHeader)
+		if !uuidPattern.MatchString(id) {
+			t.Errorf("expected a v4 UUID, got %q", id)
+		}
+		if id != seen {
+			t.Errorf("expected the handler to see %q, got %q", id, seen)
+		}
+		got[id] = true
+	}
+	if len(got) != 3 {
+		t.Errorf("expected 3 distinct IDs, got %v", got)
+	}
+}
+
+// Test: TestRequestID_inErrorBody
+// What: plain-text error bodies end with the request ID; successful bodies are untouched
+// Input: GET /fail and GET / with X-Request-ID: req-9
+// Output: /fail body is "bad input\nrequest_id: req-9\n"; / body is "ok"
+func TestRequestID_inErrorBody(t *testing.T) {
+	var logs bytes.Buffer
+	var seen string
+	h := newTraced(&logs, &seen)
+
+	for path, want := range map[string]string{"/fail": "bad input\nrequest_id: req-9\n", "/": "ok"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(api.RequestIDHeader, "req-9")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		if body, _ := io.ReadAll(rec.Body); string(body) != want {
+			t.Errorf("%s: expected body %q, got %q", path, want, body)
+		}
+	}
+}
+
+// Test: TestRequestID_inConflictBody
+// What: a create 409, which is JSON rather than plain text, carries the request ID in its body
+// Input: the transaction routes behind RequestIDMiddleware; txn-1 stored, then txn-1 with a
+// different amount sent with X-Request-ID: trace-409
+// Output: HTTP 409 whose body has request_id trace-409 alongside the conflict
+func TestRequestID_inConflictBody(t *testing.T) {
+	mux := http.NewServeMux()
+	api.NewHandler(store.NewMemoryStore()).RegisterRoutes(mux, api.RequestIDMiddleware)
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":1000,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z"}`)
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/transactions",
+		strings.NewReader(`{"id":"txn-1","account_id":"acct-1","amount":9999,"currency":"USD","direction":"debit","effective_at":"2024-01-15T12:00:00Z"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(api.RequestIDHeader, "trace-409")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST /transactions failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Conflict  map[string]json.RawMessage `json:"conflict"`
+		RequestID string                     `json:"request_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.StatusCode != http.StatusConflict || body.RequestID != "trace-409" || body.Conflict["amount"] == nil {
+		t.Errorf("expected 409 with request_id trace-409 and an amount conflict, got %d %+v", resp.StatusCode, body)
+	}
+}
@@ -0,0 +1,65 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+)
+
+func postReset(t *testing.T, srv *httptest.Server) *http.Response {
+	t.Helper()
+	resp, err := http.Post(srv.URL+"/transactions/_reset", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST /transactions/_reset failed: %v", err)
+	}
+	return resp
+}
+
+// Test: TestResetEndpoint_clearsTransactions
+// What: with EnableResetEndpoint set, POST /transactions/_reset empties the store
+// Input: 3 seeded transactions, then POST /transactions/_reset
+// Output: HTTP 204, then GET /transactions returns an empty array
+func TestResetEndpoint_clearsTransactions(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.EnableResetEndpoint = true
+	srv := newTestServerWithConfig(t, cfg)
+	seedN(t, srv, 3, "USD")
+
+	resp := postReset(t, srv)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", resp.StatusCode)
+	}
+
+	list := getTxns(t, srv, "")
+	defer list.Body.Close()
+	var got []json.RawMessage
+	if err := json.NewDecoder(list.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected empty list after reset, got %d transactions", len(got))
+	}
+}
+
+// Test: TestResetEndpoint_disabledByDefault
+// What: the reset endpoint is not routed unless explicitly enabled
+// Input: default config, 1 seeded transaction, POST /transactions/_reset
+// Output: non-204 response and the transaction is still listed
+func TestResetEndpoint_disabledByDefault(t *testing.T) {
+	srv := newTestServer(t)
+	seedN(t, srv, 1, "USD")
+
+	resp := postReset(t, srv)
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusNoContent {
+		t.Fatal("expected reset to be unavailable by default")
+	}
+
+	if ids := listIDs(t, srv.URL+"/transactions"); len(ids) != 1 {
+		t.Errorf("expected store to be untouched, got %v", ids)
+	}
+}
@@ -0,0 +1,191 @@
+package api_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/clock"
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+func postReverse(t *testing.T, srv *httptest.Server, id, body string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(srv.URL+"/transactions/"+id+"/reverse", "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatalf("POST /transactions/%s/reverse failed: %v", id, err)
+	}
+	return resp
+}
+
+// Test: TestReverseTransaction_success
+// What: reversing creates a linked opposite-direction transaction and marks the original
+// Input: debit txn-1 (amount 100); POST /transactions/txn-1/reverse with no body; clock at 2024-06-01
+// Output: HTTP 201 with txn-1-reversal (credit, 100, reverses=txn-1, effective now); txn-1 has reversed_by
+func TestReverseTransaction_success(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	cfg := api.DefaultConfig()
+	cfg.Clock = clock.NewFake(now)
+	srv := newTestServerWithConfig(t, cfg)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+
+	resp := postReverse(t, srv, "txn-1", "")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+
+	var reversal model.Transaction
+	json.NewDecoder(resp.Body).Decode(&reversal)
+	if reversal.ID != "txn-1-reversal" || reversal.Direction != model.DirectionCredit || reversal.Amount != 100 ||
+		reversal.Currency != "USD" || !reversal.EffectiveAt.Equal(now) || reversal.Metadata[model.MetadataReverses] != "txn-1" {
+		t.Errorf("unexpected reversal %+v", reversal)
+	}
+
+	get := getTxnByID(t, srv, "txn-1")
+	defer get.Body.Close()
+	var original model.Transaction
+	json.NewDecoder(get.Body).Decode(&original)
+	if original.Metadata[model.MetadataReversedBy] != "txn-1-reversal" {
+		t.Errorf("expected reversed_by=txn-1-reversal, got %v", original.Metadata)
+	}
+}
+
+// Test: TestReverseTransaction_customID
+// What: the optional body sets the reversal's ID
+// Input: POST /transactions/txn-1/reverse with {"id":"void-1"}
+// Output: HTTP 201, reversal ID is void-1
+func TestReverseTransaction_customID(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+
+	resp := postReverse(t, srv, "txn-1", `{"id":"void-1"}`)
+	defer resp.Body.Close()
+
+	var reversal model.Transaction
+	json.NewDecoder(resp.Body).Decode(&reversal)
+	if resp.StatusCode != http.StatusCreated || reversal.ID != "void-1" {
+		t.Errorf("expected 201 with id void-1, got %d %+v", resp.StatusCode, reversal)
+	}
+}
+
+// Test: TestReverseTransaction_longIDDefault
+// What: the default reversal ID of a maximum-length ID stays within MaxIDLength, and two long
+// IDs sharing a prefix get different reversal IDs
+// Input: two transactions with 128-character IDs that differ only in the last character;
+// reverse both with no body
+// Output: HTTP 201 for both; each reversal ID is at most 128 characters, ends in -reversal,
+// and they differ
+func TestReverseTransaction_longIDDefault(t *testing.T) {
+	srv := newTestServer(t)
+	prefix := strings.Repeat("a", api.MaxIDLength-1)
+
+	var reversalIDs []string
+	for _, id := range []string{prefix + "1", prefix + "2"} {
+		seedTxn(t, srv, `{"id":"`+id+`","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+		resp := postReverse(t, srv, id, "")
+		var reversal model.Transaction
+		json.NewDecoder(resp.Body).Decode(&reversal)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201, got %d", resp.StatusCode)
+		}
+		if len(reversal.ID) > api.MaxIDLength || !strings.HasSuffix(reversal.ID, "-reversal") {
+			t.Errorf("expected an ID of at most %d characters ending in -reversal, got %q", api.MaxIDLength, reversal.ID)
+		}
+		reversalIDs = append(reversalIDs, reversal.ID)
+	}
+	if reversalIDs[0] == reversalIDs[1] {
+		t.Errorf("expected distinct reversal IDs, got %q twice", reversalIDs[0])
+	}
+}
+
+// Test: TestReverseTransaction_doubleReversal
+// What: a transaction can only be reversed once
+// Input: reverse txn-1 twice (second time with a fresh reversal ID)
+// Output: second request returns HTTP 409
+func TestReverseTransaction_doubleReversal(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+
+	postReverse(t, srv, "txn-1", "").Body.Close()
+	resp := postReverse(t, srv, "txn-1", `{"id":"another"}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("expected 409, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestReverseTransaction_notFound
+// What: reversing an unknown transaction returns 404
+// Input: empty store, POST /transactions/missing/reverse
+// Output: HTTP 404
+func TestReverseTransaction_notFound(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := postReverse(t, srv, "missing", "")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestReverseTransaction_reversalKeysProtected
+// What: clients can't remove or forge the reversal links, so a reversed transaction stays reversed
+// Input: reverse txn-1; PATCH and merge-patch reversed_by to null; reverse again; create and
+// upsert transactions carrying reversed_by or reverses (import keeps them; see
+// TestImportTransactions_exportRoundTrip)
+// Output: both patches 400; second reverse 409; txn-1 still has reversed_by; both forged writes
+// are rejected
+func TestReverseTransaction_reversalKeysProtected(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	postReverse(t, srv, "txn-1", "").Body.Close()
+
+	patch := patchTxn(t, srv, "txn-1", `{"metadata":{"reversed_by":null}}`)
+	patch.Body.Close()
+	if patch.StatusCode != http.StatusBadRequest {
+		t.Errorf("PATCH reversed_by: expected 400, got %d", patch.StatusCode)
+	}
+	merge := mergePatchTxn(t, srv, "txn-1", `{"metadata":{"reversed_by":null}}`)
+	merge.Body.Close()
+	if merge.StatusCode != http.StatusBadRequest {
+		t.Errorf("merge patch reversed_by: expected 400, got %d", merge.StatusCode)
+	}
+
+	again := postReverse(t, srv, "txn-1", `{"id":"another"}`)
+	again.Body.Close()
+	if again.StatusCode != http.StatusConflict {
+		t.Errorf("second reverse: expected 409, got %d", again.StatusCode)
+	}
+	get := getTxnByID(t, srv, "txn-1")
+	defer get.Body.Close()
+	var original model.Transaction
+	json.NewDecoder(get.Body).Decode(&original)
+	if original.Metadata[model.MetadataReversedBy] != "txn-1-reversal" {
+		t.Errorf("expected reversed_by to survive, got %v", original.Metadata)
+	}
+
+	forged := `{"id":"txn-2","account_id":"acct-1","amount":100,"currency":"USD","direction":"credit","effective_at":"2024-01-01T00:00:00Z","metadata":{"reverses":"txn-9"}}`
+	create := postTxn(t, srv, forged)
+	create.Body.Close()
+	if create.StatusCode != http.StatusBadRequest {
+		t.Errorf("create with reverses: expected 400, got %d", create.StatusCode)
+	}
+	upsert, err := http.Post(srv.URL+"/transactions?mode=upsert", "application/json",
+		bytes.NewBufferString(`{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","metadata":{"reversed_by":"x"}}`))
+	if err != nil {
+		t.Fatalf("upsert failed: %v", err)
+	}
+	upsert.Body.Close()
+	if upsert.StatusCode != http.StatusBadRequest {
+		t.Errorf("upsert with reversed_by: expected 400, got %d", upsert.StatusCode)
+	}
+}
@@ -0,0 +1,50 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+)
+
+// Test: TestRoutes_collectionMethodNotAllowed
+// What: an unsupported method on /transactions returns 405 with an Allow header
+// Input: PATCH /transactions
+// Output: HTTP 405, Allow: "GET, POST, DELETE"
+func TestRoutes_collectionMethodNotAllowed(t *testing.T) {
+	srv := newTestServer(t)
+
+	req, _ := http.NewRequest(http.MethodPatch, srv.URL+"/transactions", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("PATCH /transactions failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Allow"); got != "GET, POST, DELETE" {
+		t.Errorf("expected Allow %q, got %q", "GET, POST, DELETE", got)
+	}
+}
+
+// Test: TestRoutes_itemMethodNotAllowed
+// What: an unsupported method on /transactions/{id} returns 405 listing only the implemented methods
+// Input: PUT /transactions/txn-1
+// Output: HTTP 405, Allow: "GET, PATCH, DELETE"
+func TestRoutes_itemMethodNotAllowed(t *testing.T) {
+	srv := newTestServer(t)
+
+	req, _ := http.NewRequest(http.MethodPut, srv.URL+"/transactions/txn-1", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("PUT /transactions/txn-1 failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("Allow"); got != "GET, PATCH, DELETE" {
+		t.Errorf("expected Allow %q, got %q", "GET, PATCH, DELETE", got)
+	}
+}
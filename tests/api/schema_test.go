@@ -0,0 +1,166 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+)
+
+const validTxnJSON = `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","metadata":{"note":"x"}}`
+
+func schemaFields(t *testing.T, body string) []string {
+	t.Helper()
+	errs, err := api.ValidateTransactionJSON([]byte(body))
+	if err != nil {
+		t.Fatalf("unexpected decode error: %v", err)
+	}
+	fields := make([]string, len(errs))
+	for i, e := range errs {
+		fields[i] = e.Field
+	}
+	return fields
+}
+
+// Test: TestValidateTransactionJSON_valid
+// What: a well-typed payload has no violations
+// Input: every field present with the right type, plus string metadata
+// Output: no SchemaErrors, nil error
+func TestValidateTransactionJSON_valid(t *testing.T) {
+	if fields := schemaFields(t, validTxnJSON); len(fields) != 0 {
+		t.Errorf("expected no violations, got %v", fields)
+	}
+}
+
+// Test: TestValidateTransactionJSON_wrongTypeAmount
+// What: a string amount is reported against the amount field with the expected and actual types
+// Input: "amount":"100"
+// Output: one violation: amount "must be an integer, got string"
+func TestValidateTransactionJSON_wrongTypeAmount(t *testing.T) {
+	body := strings.Replace(validTxnJSON, `"amount":100`, `"amount":"100"`, 1)
+
+	errs, _ := api.ValidateTransactionJSON([]byte(body))
+	want := []api.SchemaError{{Field: "amount", Message: "must be an integer, got string"}}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("expected %v, got %v", want, errs)
+	}
+}
+
+// Test: TestValidateTransactionJSON_missingRequired
+// What: every missing required field is reported, sorted by field name
+// Input: {"amount":100}
+// Output: violations for account_id, currency, direction, effective_at, id
+func TestValidateTransactionJSON_missingRequired(t *testing.T) {
+	got := schemaFields(t, `{"amount":100}`)
+	want := []string{"account_id", "currency", "direction", "effective_at", "id"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// Test: TestValidateTransactionJSON_fieldRules
+// What: type, enum, minimum, format, and nested metadata rules each name the offending field
+// Input: one bad field at a time
+// Output: exactly that field reported
+func TestValidateTransactionJSON_fieldRules(t *testing.T) {
+	cases := []struct{ from, to, field string }{
+		{`"amount":100`, `"amount":1.5`, "amount"},
+		{`"amount":100`, `"amount":-1`, "amount"},
+		{`"amount":100`, `"amount":99999999999999999999`, "amount"},
+		{`"direction":"debit"`, `"direction":"sideways"`, "direction"},
+		{`"effective_at":"2024-01-01T00:00:00Z"`, `"effective_at":"2024-01-01"`, "effective_at"},
+		{`"id":"txn-1"`, `"id":""`, "id"},
+		{`"id":"txn-1"`, `"id":7`, "id"},
+		{`"note":"x"`, `"note":5`, "metadata.note"},
+		{`"metadata":{"note":"x"}`, `"metadata":[]`, "metadata"},
+	}
+	for _, tc := range cases {
+		got := schemaFields(t, strings.Replace(validTxnJSON, tc.from, tc.to, 1))
+		if !reflect.DeepEqual(got, []string{tc.field}) {
+			t.Errorf("%s: expected [%s], got %v", tc.to, tc.field, got)
+		}
+	}
+}
+
+// Test: TestValidateTransactionJSON_notJSON
+// What: a body that isn't JSON returns an error rather than violations
+// Input: "{not json"
+// Output: non-nil error
+func TestValidateTransactionJSON_notJSON(t *testing.T) {
+	if _, err := api.ValidateTransactionJSON([]byte(`{not json`)); err == nil {
+		t.Error("expected an error for malformed JSON")
+	}
+}
+
+// Test: TestCreateTransaction_schemaErrorsListEachField
+// What: POST /transactions reports every schema violation in the 400 body
+// Input: "amount":"100" and no direction
+// Output: HTTP 400, body names amount and direction
+func TestCreateTransaction_schemaErrorsListEachField(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":"100","currency":"USD","effective_at":"2024-01-01T00:00:00Z"}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+	msg, _ := io.ReadAll(resp.Body)
+	for _, want := range []string{"amount: must be an integer, got string", "direction: is required"} {
+		if !strings.Contains(string(msg), want) {
+			t.Errorf("expected body to contain %q, got %q", want, msg)
+		}
+	}
+}
+
+// Test: TestParseInt64Number
+// What: only plain integers within the int64 range convert; each rejected form gets its own reason
+// Input: "100", "-5", "9223372036854775807", "1e3", "1.5", "9223372036854775808"
+// Output: the three values, then errors mentioning exponent, whole number, and out of range
+func TestParseInt64Number(t *testing.T) {
+	for s, want := range map[string]int64{"100": 100, "-5": -5, "9223372036854775807": 9223372036854775807} {
+		if got, err := api.ParseInt64Number(json.Number(s)); err != nil || got != want {
+			t.Errorf("%s: expected %d, got %d, %v", s, want, got, err)
+		}
+	}
+	for s, reason := range map[string]string{"1e3": "exponent", "1.5": "whole number", "9223372036854775808": "out of range"} {
+		_, err := api.ParseInt64Number(json.Number(s))
+		if err == nil || !strings.Contains(err.Error(), reason) {
+			t.Errorf("%s: expected an error mentioning %q, got %v", s, reason, err)
+		}
+	}
+}
+
+// Test: TestCreateTransaction_amountPrecision
+// What: amounts a JS client might serialize as floats are rejected with a 400 naming the problem
+// Input: amount 1e3, 1.5, and 1e30 written out in full (beyond int64)
+// Output: HTTP 400 each time, body names amount and the specific reason; nothing is stored
+func TestCreateTransaction_amountPrecision(t *testing.T) {
+	srv := newTestServer(t)
+
+	cases := map[string]string{
+		"1e3":                             "amount: must be a plain integer without an exponent",
+		"1.5":                             "amount: must be a whole number",
+		"1000000000000000000000000000000": "amount: is out of range for a 64-bit integer",
+	}
+	for amount, want := range cases {
+		resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":`+amount+`,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+		msg, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("amount %s: expected 400, got %d", amount, resp.StatusCode)
+		}
+		if !strings.Contains(string(msg), want) {
+			t.Errorf("amount %s: expected body to contain %q, got %q", amount, want, msg)
+		}
+	}
+
+	resp := getTxnByID(t, srv, "txn-1")
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected nothing stored, got %d", resp.StatusCode)
+	}
+}
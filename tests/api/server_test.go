@@ -0,0 +1,44 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/api"
+)
+
+// Test: TestNewServer_timeouts
+// What: NewServer sets every timeout, falling back to the defaults for non-positive values
+// Input: ReadHeader=1s, Read=0, Write=-1s, Idle=10s
+// Output: ReadHeaderTimeout 1s, ReadTimeout and WriteTimeout at their defaults, IdleTimeout 10s
+func TestNewServer_timeouts(t *testing.T) {
+	srv := api.NewServer(":0", http.NotFoundHandler(), api.ServerTimeouts{
+		ReadHeader: time.Second,
+		Write:      -time.Second,
+		Idle:       10 * time.Second,
+	})
+
+	for name, got := range map[string][2]time.Duration{
+		"ReadHeaderTimeout": {srv.ReadHeaderTimeout, time.Second},
+		"ReadTimeout":       {srv.ReadTimeout, api.DefaultReadTimeout},
+		"WriteTimeout":      {srv.WriteTimeout, api.DefaultWriteTimeout},
+		"IdleTimeout":       {srv.IdleTimeout, 10 * time.Second},
+	} {
+		if got[0] != got[1] {
+			t.Errorf("%s: expected %v, got %v", name, got[1], got[0])
+		}
+	}
+}
+
+// Test: TestNewServer_defaults
+// What: the zero ServerTimeouts yields a server with no timeout disabled
+// Input: ServerTimeouts{}
+// Output: all four timeouts are positive
+func TestNewServer_defaults(t *testing.T) {
+	srv := api.NewServer(":0", http.NotFoundHandler(), api.ServerTimeouts{})
+
+	if srv.ReadHeaderTimeout <= 0 || srv.ReadTimeout <= 0 || srv.WriteTimeout <= 0 || srv.IdleTimeout <= 0 {
+		t.Errorf("expected non-zero timeouts, got %+v", srv)
+	}
+}
@@ -0,0 +1,95 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func deleteTxn(t *testing.T, srv *httptest.Server, id string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodDelete, srv.URL+"/transactions/"+id, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("DELETE /transactions/%s failed: %v", id, err)
+	}
+	return resp
+}
+
+// Test: TestDeleteTransaction_hiddenFromDefaultListing
+// What: a soft-deleted transaction is left out of listings, including the date-range fast path
+// Input: USD-000..USD-002; DELETE /transactions/USD-001; list with no filter and with a date range
+// Output: HTTP 200 with deleted=true; both listings return USD-000 and USD-002 only
+func TestDeleteTransaction_hiddenFromDefaultListing(t *testing.T) {
+	srv := newTestServer(t)
+	seedN(t, srv, 3, "USD")
+
+	resp := deleteTxn(t, srv, "USD-001")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if txn := decodeTxn(t, resp); !txn.Deleted {
+		t.Error("expected the response to have deleted=true")
+	}
+
+	assertIDs(t, listIDs(t, srv.URL+"/transactions"), "USD-000", "USD-002")
+	assertIDs(t, listIDs(t, srv.URL+"/transactions?start_date=2024-01-01&end_date=2024-01-01&limit=1&offset=1"), "USD-002")
+}
+
+// Test: TestDeleteTransaction_includeDeleted
+// What: include_deleted=true lists soft-deleted transactions alongside live ones
+// Input: USD-000..USD-002 with USD-001 deleted; GET /transactions?include_deleted=true, and =maybe
+// Output: all 3 IDs in order; include_deleted=maybe is a 400
+func TestDeleteTransaction_includeDeleted(t *testing.T) {
+	srv := newTestServer(t)
+	seedN(t, srv, 3, "USD")
+	deleteTxn(t, srv, "USD-001").Body.Close()
+
+	assertIDs(t, listIDs(t, srv.URL+"/transactions?include_deleted=true"), "USD-000", "USD-001", "USD-002")
+
+	resp := getTxns(t, srv, "include_deleted=maybe")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestDeleteTransaction_preservesRecord
+// What: soft-deleting keeps the record readable by ID, and deleting again is not an error
+// Input: seed USD-000 with metadata; DELETE it twice; GET /transactions/USD-000
+// Output: both DELETEs 200; GET returns 200 with the original fields and deleted=true
+func TestDeleteTransaction_preservesRecord(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":250,"currency":"USD","direction":"credit","effective_at":"2024-01-01T00:00:00Z","metadata":{"order":"42"}}`)
+
+	for range 2 {
+		resp := deleteTxn(t, srv, "txn-1")
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+	}
+
+	resp := getTxnByID(t, srv, "txn-1")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	txn := decodeTxn(t, resp)
+	if !txn.Deleted || txn.Amount != 250 || txn.Direction != "credit" || txn.Metadata["order"] != "42" {
+		t.Errorf("expected the original record marked deleted, got %+v", txn)
+	}
+}
+
+// Test: TestDeleteTransaction_notFound
+// What: deleting an unknown ID is a 404
+// Input: DELETE /transactions/missing on an empty store
+// Output: HTTP 404
+func TestDeleteTransaction_notFound(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := deleteTxn(t, srv, "missing")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,147 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// seedOutOfOrder inserts three transactions whose insertion order (b, c, a)
+// differs from their effective_at order (a, b, c).
+func seedOutOfOrder(t *testing.T) string {
+	t.Helper()
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"b","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"c","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-03T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"a","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	return srv.URL
+}
+
+func listIDs(t *testing.T, url string) []string {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s failed: %v", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var txns []model.Transaction
+	if err := json.NewDecoder(resp.Body).Decode(&txns); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	ids := make([]string, len(txns))
+	for i, txn := range txns {
+		ids[i] = txn.ID
+	}
+	return ids
+}
+
+func assertIDs(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+// Test: TestListTransactions_defaultSortIsEffectiveAt
+// What: without a sort param, results are ordered by effective_at regardless of insertion order
+// Input: inserted b, c, a with effective_at order a < b < c
+// Output: [a, b, c]
+func TestListTransactions_defaultSortIsEffectiveAt(t *testing.T) {
+	base := seedOutOfOrder(t)
+	assertIDs(t, listIDs(t, base+"/transactions"), "a", "b", "c")
+}
+
+// Test: TestListTransactions_sortInsertedAsc
+// What: sort=inserted_asc returns transactions in the order they were created
+// Input: inserted b, c, a
+// Output: [b, c, a]
+func TestListTransactions_sortInsertedAsc(t *testing.T) {
+	base := seedOutOfOrder(t)
+	assertIDs(t, listIDs(t, base+"/transactions?sort=inserted_asc"), "b", "c", "a")
+}
+
+// Test: TestListTransactions_sortInsertedDesc
+// What: sort=inserted_desc returns the most recently created transactions first
+// Input: inserted b, c, a
+// Output: [a, c, b]
+func TestListTransactions_sortInsertedDesc(t *testing.T) {
+	base := seedOutOfOrder(t)
+	assertIDs(t, listIDs(t, base+"/transactions?sort=inserted_desc"), "a", "c", "b")
+}
+
+// Test: TestListTransactions_sortInsertedWithPagination
+// What: pagination is applied after reordering by insertion sequence
+// Input: inserted b, c, a; sort=inserted_asc&limit=1&offset=1
+// Output: [c]
+func TestListTransactions_sortInsertedWithPagination(t *testing.T) {
+	base := seedOutOfOrder(t)
+	assertIDs(t, listIDs(t, base+"/transactions?sort=inserted_asc&limit=1&offset=1"), "c")
+}
+
+// Test: TestListTransactions_sortReceived
+// What: sort=received returns insertion order and composes with filters and pagination
+// Input: inserted b, c, a, then EUR e and USD d dated before all of them;
+// sort=received alone, and with currency=USD&limit=2&offset=2
+// Output: [b, c, a, e, d]; the filtered page skips e and returns [a, d]
+func TestListTransactions_sortReceived(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"b","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"c","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-03T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"a","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"e","account_id":"acct-1","amount":100,"currency":"EUR","direction":"debit","effective_at":"2023-12-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"d","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2023-12-02T00:00:00Z"}`)
+
+	assertIDs(t, listIDs(t, srv.URL+"/transactions?sort=received"), "b", "c", "a", "e", "d")
+	assertIDs(t, listIDs(t, srv.URL+"/transactions?sort=received&currency=USD&limit=2&offset=2"), "a", "d")
+}
+
+// Test: TestListTransactions_invalidSort
+// What: an unknown sort value is rejected
+// Input: sort=amount
+// Output: HTTP 400
+func TestListTransactions_invalidSort(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := getTxns(t, srv, "sort=amount")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestCreateTransaction_responseIncludesSeq
+// What: the create response returns the server-assigned insertion sequence, and a retry returns the original one
+// Input: POST txn-1, POST txn-2, then retry txn-1
+// Output: txn-2 has seq=2; the retry of txn-1 reports seq=1
+func TestCreateTransaction_responseIncludesSeq(t *testing.T) {
+	srv := newTestServer(t)
+	body1 := `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`
+	seedTxn(t, srv, body1)
+
+	resp := postTxn(t, srv, `{"id":"txn-2","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	var created model.Transaction
+	json.NewDecoder(resp.Body).Decode(&created)
+	resp.Body.Close()
+	if created.Seq != 2 {
+		t.Errorf("expected seq=2, got %d", created.Seq)
+	}
+
+	retry := postTxn(t, srv, body1)
+	defer retry.Body.Close()
+	var dup model.Transaction
+	json.NewDecoder(retry.Body).Decode(&dup)
+	if dup.Seq != 1 {
+		t.Errorf("expected retry to report original seq=1, got %d", dup.Seq)
+	}
+}
@@ -0,0 +1,45 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/clock"
+)
+
+// Test: TestStats_createThroughput
+// What: GET /stats reports creates per trailing window from the store clock
+// Input: two creates, the clock moves 2 minutes, one create, then GET /stats
+// Output: HTTP 200, application/json, {"creates":{"1m":1,"5m":3,"15m":3}}
+func TestStats_createThroughput(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	srv := newClockedServer(t, fake)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"txn-2","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	fake.Advance(2 * time.Minute)
+	seedTxn(t, srv, `{"id":"txn-3","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+
+	resp, err := http.Get(srv.URL + "/stats")
+	if err != nil {
+		t.Fatalf("GET /stats failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected application/json, got %q", ct)
+	}
+
+	var body struct {
+		Creates map[string]int `json:"creates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body.Creates["1m"] != 1 || body.Creates["5m"] != 3 || body.Creates["15m"] != 3 {
+		t.Errorf(`expected {"1m":1,"5m":3,"15m":3}, got %v`, body.Creates)
+	}
+}
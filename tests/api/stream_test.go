@@ -0,0 +1,115 @@
+package api_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readEvent reads one server-sent event and returns its fields, skipping comment lines.
+func readEvent(t *testing.T, r *bufio.Reader) map[string]string {
+	t.Helper()
+	event := map[string]string{}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading event: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if len(event) > 0 {
+				return event
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		name, value, _ := strings.Cut(line, ": ")
+		event[name] = value
+	}
+}
+
+// Test: TestStreamTransactions_emitsCreates
+// What: GET /transactions/stream sends each new transaction matching the currency filter as an SSE event
+// Input: open the stream with currency=usd, then create a EUR and a USD transaction
+// Output: HTTP 200, text/event-stream; one "transaction" event with id 2 (the USD seq) and the
+// USD transaction as data
+func TestStreamTransactions_emitsCreates(t *testing.T) {
+	srv := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/transactions/stream?currency=usd", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /transactions/stream failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected text/event-stream, got %q", ct)
+	}
+
+	// The headers are flushed after subscribing, so these creates can't be missed
+	seedTxn(t, srv, `{"id":"txn-eur","account_id":"acct-1","amount":100,"currency":"EUR","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"txn-usd","account_id":"acct-1","amount":250,"currency":"USD","direction":"credit","effective_at":"2024-01-02T00:00:00Z"}`)
+
+	event := readEvent(t, bufio.NewReader(resp.Body))
+	if event["event"] != "transaction" || event["id"] != "2" {
+		t.Errorf("expected event transaction with id 2, got %v", event)
+	}
+	var txn map[string]any
+	if err := json.Unmarshal([]byte(event["data"]), &txn); err != nil {
+		t.Fatalf("failed to decode data %q: %v", event["data"], err)
+	}
+	if txn["id"] != "txn-usd" || txn["amount"] != float64(250) {
+		t.Errorf("expected txn-usd with amount 250, got %v", txn)
+	}
+}
+
+// Test: TestStreamTransactions_fields
+// What: fields projects each streamed transaction like it does the list, and an unknown field
+// is rejected before the stream opens
+// Input: open the stream with fields=id,amount and create a transaction; open another with
+// fields=bogus
+// Output: the event's data has only id and amount; the second request is HTTP 400
+func TestStreamTransactions_fields(t *testing.T) {
+	srv := newTestServer(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/transactions/stream?fields=id,amount", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /transactions/stream failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":250,"currency":"USD","direction":"credit","effective_at":"2024-01-02T00:00:00Z"}`)
+
+	event := readEvent(t, bufio.NewReader(resp.Body))
+	var txn map[string]any
+	if err := json.Unmarshal([]byte(event["data"]), &txn); err != nil {
+		t.Fatalf("failed to decode data %q: %v", event["data"], err)
+	}
+	if len(txn) != 2 || txn["id"] != "txn-1" || txn["amount"] != float64(250) {
+		t.Errorf("expected only id and amount, got %v", txn)
+	}
+
+	bad, err := http.Get(srv.URL + "/transactions/stream?fields=bogus")
+	if err != nil {
+		t.Fatalf("GET /transactions/stream failed: %v", err)
+	}
+	bad.Body.Close()
+	if bad.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for an unknown field, got %d", bad.StatusCode)
+	}
+}
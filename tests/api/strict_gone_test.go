@@ -0,0 +1,57 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+)
+
+// Test: TestGetTransaction_strictGone
+// What: with StrictGone, GET tells existing, soft-deleted and never-stored IDs apart
+// Input: StrictGone=true; txn-1 stored, txn-2 stored then soft-deleted; GET txn-1, txn-2,
+// missing, and txn-2 with include_deleted=true
+// Output: 200, 410, 404, 200
+func TestGetTransaction_strictGone(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.StrictGone = true
+	srv := newTestServerWithConfig(t, cfg)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	seedTxn(t, srv, `{"id":"txn-2","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z"}`)
+	deleteTxn(t, srv, "txn-2").Body.Close()
+
+	for _, tc := range []struct {
+		path string
+		want int
+	}{
+		{"/transactions/txn-1", http.StatusOK},
+		{"/transactions/txn-2", http.StatusGone},
+		{"/transactions/missing", http.StatusNotFound},
+		{"/transactions/txn-2?include_deleted=true", http.StatusOK},
+	} {
+		resp, err := http.Get(srv.URL + tc.path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", tc.path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.want {
+			t.Errorf("GET %s: expected %d, got %d", tc.path, tc.want, resp.StatusCode)
+		}
+	}
+}
+
+// Test: TestGetTransaction_deletedWithoutStrictGone
+// What: by default a soft-deleted transaction is still returned by ID
+// Input: the default config; txn-1 stored then soft-deleted; GET txn-1
+// Output: 200
+func TestGetTransaction_deletedWithoutStrictGone(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	deleteTxn(t, srv, "txn-1").Body.Close()
+
+	resp := getTxnByID(t, srv, "txn-1")
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+}
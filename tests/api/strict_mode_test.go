@@ -0,0 +1,137 @@
+package api_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/clock"
+)
+
+// Test: TestCreateTransaction_strictModeRejectsFuture
+// What: with RequirePastEffectiveAt enabled, an effective_at after now (beyond skew) is rejected
+// Input: fake clock at 2024-06-01T12:00Z, transaction effective one hour later
+// Output: HTTP 422
+func TestCreateTransaction_strictModeRejectsFuture(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.Clock = clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	cfg.RequirePastEffectiveAt = true
+	srv := newTestServerWithConfig(t, cfg)
+
+	resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-06-01T13:00:00Z"}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestCreateTransaction_strictModeAcceptsPast
+// What: with RequirePastEffectiveAt enabled, a past effective_at is accepted
+// Input: fake clock at 2024-06-01T12:00Z, transaction effective one day earlier
+// Output: HTTP 201
+func TestCreateTransaction_strictModeAcceptsPast(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.Clock = clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	cfg.RequirePastEffectiveAt = true
+	srv := newTestServerWithConfig(t, cfg)
+
+	resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-05-31T12:00:00Z"}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("expected 201, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestCreateTransaction_strictModeSkewTolerance
+// What: a timestamp slightly ahead of the server clock but within ClockSkew is accepted
+// Input: fake clock at 2024-06-01T12:00Z, skew 1m, transaction effective 30s later
+// Output: HTTP 201
+func TestCreateTransaction_strictModeSkewTolerance(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.Clock = clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	cfg.ClockSkew = time.Minute
+	cfg.RequirePastEffectiveAt = true
+	srv := newTestServerWithConfig(t, cfg)
+
+	resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-06-01T12:00:30Z"}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("expected 201, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestCreateTransaction_strictModeOffByDefault
+// What: the default config accepts future effective_at values
+// Input: default handler, transaction effective in the year 2999
+// Output: HTTP 201
+func TestCreateTransaction_strictModeOffByDefault(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2999-01-01T00:00:00Z"}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("expected 201, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestCreateTransaction_maxFutureDaysAcceptsInWindow
+// What: with MaxFutureEffectiveAtDays set, a date inside the window is accepted
+// Input: fake clock at 2024-06-01T12:00Z, 30-day window, transaction effective 2024-06-20
+// Output: HTTP 201
+func TestCreateTransaction_maxFutureDaysAcceptsInWindow(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.Clock = clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	cfg.MaxFutureEffectiveAtDays = 30
+	srv := newTestServerWithConfig(t, cfg)
+
+	resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-06-20T00:00:00Z"}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("expected 201, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestCreateTransaction_maxFutureDaysRejectsFarFuture
+// What: with MaxFutureEffectiveAtDays set, a far-future date is rejected with the allowed window in the message
+// Input: fake clock at 2024-06-01T12:00Z, 30-day window, transaction effective 9999-12-31
+// Output: HTTP 422, body mentions "30 days" and the latest allowed instant 2024-07-01T12:00:00Z
+func TestCreateTransaction_maxFutureDaysRejectsFarFuture(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.Clock = clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	cfg.MaxFutureEffectiveAtDays = 30
+	srv := newTestServerWithConfig(t, cfg)
+
+	resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"9999-12-31T00:00:00Z"}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected 422, got %d", resp.StatusCode)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "30 days") || !strings.Contains(string(body), "2024-07-01T12:00:00Z") {
+		t.Errorf("expected the allowed window in the message, got %q", body)
+	}
+}
+
+// Test: TestCreateTransaction_maxFutureDaysDisabledByDefault
+// What: without MaxFutureEffectiveAtDays, far-future dates are still accepted
+// Input: default config, transaction effective 9999-12-31
+// Output: HTTP 201
+func TestCreateTransaction_maxFutureDaysDisabledByDefault(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"9999-12-31T00:00:00Z"}`)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("expected 201, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,72 @@
+package api_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+)
+
+// Test: TestCreateTransaction_tagsNormalized
+// What: tags are lowercased, deduplicated and sorted on create, and returned that way
+// Input: tags ["Refund", " refund", "VIP"]
+// Output: HTTP 201; the response and a later GET both have tags ["refund", "vip"]
+func TestCreateTransaction_tagsNormalized(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","tags":["Refund"," refund","VIP"]}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	want := []string{"refund", "vip"}
+	if got := decodeTxn(t, resp).Tags; !slices.Equal(got, want) {
+		t.Errorf("create response: expected tags %v, got %v", want, got)
+	}
+	if got := decodeTxn(t, getTxnByID(t, srv, "txn-1")).Tags; !slices.Equal(got, want) {
+		t.Errorf("GET: expected tags %v, got %v", want, got)
+	}
+}
+
+// Test: TestCreateTransaction_tagsRetryIsIdempotent
+// What: a retry listing the same tags in another order or case is a duplicate, not a conflict
+// Input: create with ["refund","vip"], retry with ["VIP","Refund"]
+// Output: HTTP 200 on the retry
+func TestCreateTransaction_tagsRetryIsIdempotent(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","tags":["refund","vip"]}`)
+
+	resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","tags":["VIP","Refund"]}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestCreateTransaction_tagsMustBeStrings
+// What: the schema rejects non-string tags, naming the offending element
+// Input: tags ["ok", 7]
+// Output: HTTP 400
+func TestCreateTransaction_tagsMustBeStrings(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","tags":["ok",7]}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestListTransactions_tagFilter
+// What: tag=x keeps transactions with that tag (case-insensitive); repeated tag params are ANDed
+// Input: a [refund], b [refund vip], c [vip], d (no tags)
+// Output: tag=Refund -> [a b]; tag=refund&tag=vip -> [b]; tag=missing -> []
+func TestListTransactions_tagFilter(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"a","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z","tags":["refund"]}`)
+	seedTxn(t, srv, `{"id":"b","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-02T00:00:00Z","tags":["refund","vip"]}`)
+	seedTxn(t, srv, `{"id":"c","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-03T00:00:00Z","tags":["vip"]}`)
+	seedTxn(t, srv, `{"id":"d","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-04T00:00:00Z"}`)
+
+	assertIDs(t, listIDs(t, srv.URL+"/transactions?tag=Refund"), "a", "b")
+	assertIDs(t, listIDs(t, srv.URL+"/transactions?tag=refund&tag=vip"), "b")
+	assertIDs(t, listIDs(t, srv.URL+"/transactions?tag=missing"))
+}
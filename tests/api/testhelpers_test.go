@@ -12,26 +12,14 @@ import (
 
 func newTestServer(t *testing.T) *httptest.Server {
 	t.Helper()
-	h := api.NewHandler(store.NewMemoryStore())
+	return newTestServerWithConfig(t, api.DefaultConfig())
+}
+
+func newTestServerWithConfig(t *testing.T, cfg api.Config) *httptest.Server {
+	t.Helper()
+	h := api.NewHandlerWithConfig(store.NewMemoryStore(), cfg)
 	mux := http.NewServeMux()
-	mux.HandleFunc("/transactions", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodPost:
-			h.CreateTransaction(w, r)
-		case http.MethodGet:
-			h.ListTransactions(w, r)
-		default:
-			w.WriteHeader(http.StatusMethodNotAllowed)
-		}
-	})
-	mux.HandleFunc("/transactions/{id}", func(w http.ResponseWriter, r *http.Request) {
-		switch r.Method {
-		case http.MethodGet:
-			h.GetTransaction(w, r)
-		default:
-			w.WriteHeader(http.StatusMethodNotAllowed)
-		}
-	})
+	h.RegisterRoutes(mux, nil)
 	srv := httptest.NewServer(mux)
 	t.Cleanup(srv.Close)
 	return srv
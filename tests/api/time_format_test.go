@@ -0,0 +1,120 @@
+package api_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/api"
+)
+
+// fractionalTxn has sub-second precision and a non-UTC offset; it is stored as 2024-01-15T08:30:00.123456789Z.
+const fractionalTxn = `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-15T10:30:00.123456789+02:00"}`
+
+func newTimeFormatServer(t *testing.T, format string) *httptest.Server {
+	t.Helper()
+	cfg := api.DefaultConfig()
+	cfg.TimeFormat = format
+	return newTestServerWithConfig(t, cfg)
+}
+
+// effectiveAtOf returns the raw effective_at (or effectiveAt) of a single transaction or the first list element.
+func effectiveAtOf(t *testing.T, body []byte, key string) string {
+	t.Helper()
+	if len(body) > 0 && body[0] == '[' {
+		var list []json.RawMessage
+		if err := json.Unmarshal(body, &list); err != nil || len(list) == 0 {
+			t.Fatalf("expected a non-empty list, got %s", body)
+		}
+		body = list[0]
+	}
+	return string(decodeRaw(t, body)[key])
+}
+
+// Test: TestTimeFormat_unix
+// What: TimeFormat=unix writes effective_at as epoch seconds on create, GET, list, and camelCase responses
+// Input: unix server; create fractionalTxn, then GET it, list, and GET with field_case=camel
+// Output: effective_at (effectiveAt for camel) is the number 1705307400 everywhere; metadata stays omitted
+func TestTimeFormat_unix(t *testing.T) {
+	srv := newTimeFormatServer(t, api.TimeFormatUnix)
+	want := strconv.FormatInt(time.Date(2024, 1, 15, 8, 30, 0, 0, time.UTC).Unix(), 10)
+
+	created := postTxn(t, srv, fractionalTxn)
+	defer created.Body.Close()
+	body, _ := io.ReadAll(created.Body)
+	if got := effectiveAtOf(t, body, "effective_at"); got != want {
+		t.Errorf("create: expected %s, got %s", want, got)
+	}
+
+	get := readBody(t, getTxnByID(t, srv, "txn-1"))
+	if got := effectiveAtOf(t, get, "effective_at"); got != want {
+		t.Errorf("GET: expected %s, got %s", want, got)
+	}
+	if _, ok := decodeRaw(t, get)["metadata"]; ok {
+		t.Errorf("expected metadata to stay omitted, got %s", get)
+	}
+	if got := effectiveAtOf(t, readBody(t, getTxns(t, srv, "")), "effective_at"); got != want {
+		t.Errorf("list: expected %s, got %s", want, got)
+	}
+
+	resp, err := http.Get(srv.URL + "/transactions/txn-1?field_case=camel")
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	if got := effectiveAtOf(t, readBody(t, resp), "effectiveAt"); got != want {
+		t.Errorf("camel: expected %s, got %s", want, got)
+	}
+}
+
+// Test: TestTimeFormat_date
+// What: TimeFormat=date writes effective_at as the UTC calendar date, without touching the stored value
+// Input: date server; create a transaction at 2024-01-15T23:30:00-02:00 (2024-01-16 in UTC); GET, list,
+// then filter start_date=2024-01-16
+// Output: effective_at "2024-01-16" on GET and list; the date filter still matches it
+func TestTimeFormat_date(t *testing.T) {
+	srv := newTimeFormatServer(t, api.TimeFormatDate)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-15T23:30:00-02:00"}`)
+
+	if got := effectiveAtOf(t, readBody(t, getTxnByID(t, srv, "txn-1")), "effective_at"); got != `"2024-01-16"` {
+		t.Errorf("GET: expected \"2024-01-16\", got %s", got)
+	}
+	if got := effectiveAtOf(t, readBody(t, getTxns(t, srv, "start_date=2024-01-16")), "effective_at"); got != `"2024-01-16"` {
+		t.Errorf("list: expected \"2024-01-16\", got %s", got)
+	}
+}
+
+// Test: TestTimeFormat_rfc3339AndDefault
+// What: the rfc3339 preset drops fractional seconds; with no TimeFormat the full precision is kept
+// Input: fractionalTxn on an rfc3339 server and on a default server; GET it
+// Output: "2024-01-15T08:30:00Z" and "2024-01-15T08:30:00.123456789Z"
+func TestTimeFormat_rfc3339AndDefault(t *testing.T) {
+	for format, want := range map[string]string{
+		api.TimeFormatRFC3339: `"2024-01-15T08:30:00Z"`,
+		"":                    `"2024-01-15T08:30:00.123456789Z"`,
+	} {
+		srv := newTimeFormatServer(t, format)
+		seedTxn(t, srv, fractionalTxn)
+		if got := effectiveAtOf(t, readBody(t, getTxnByID(t, srv, "txn-1")), "effective_at"); got != want {
+			t.Errorf("format %q: expected %s, got %s", format, want, got)
+		}
+	}
+}
+
+// Test: TestValidateTimeFormat
+// What: only the empty format and the presets are accepted
+// Input: "", rfc3339, unix, date, iso8601
+// Output: nil for the first four, an error for iso8601
+func TestValidateTimeFormat(t *testing.T) {
+	for _, format := range []string{"", api.TimeFormatRFC3339, api.TimeFormatUnix, api.TimeFormatDate} {
+		if err := api.ValidateTimeFormat(format); err != nil {
+			t.Errorf("%q: unexpected error %v", format, err)
+		}
+	}
+	if err := api.ValidateTimeFormat("iso8601"); err == nil {
+		t.Error("expected an error for iso8601")
+	}
+}
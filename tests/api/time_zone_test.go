@@ -0,0 +1,58 @@
+package api_test
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// Test: TestCreateTransaction_preservesTimeZone
+// What: the offset effective_at was submitted with is returned as time_zone next to the UTC instant
+// Input: effective_at with -05:00, +09:00 and Z; then GET each by ID
+// Output: time_zone "-05:00", "+09:00" and absent; effective_at always in UTC, on create and on GET
+func TestCreateTransaction_preservesTimeZone(t *testing.T) {
+	srv := newTestServer(t)
+	for _, tc := range []struct {
+		id, effectiveAt, wantUTC, wantZone string
+	}{
+		{"txn-ny", "2024-01-15T22:00:00-05:00", "2024-01-16T03:00:00Z", "-05:00"},
+		{"txn-tokyo", "2024-01-16T12:00:00+09:00", "2024-01-16T03:00:00Z", "+09:00"},
+		{"txn-utc", "2024-01-16T03:00:00Z", "2024-01-16T03:00:00Z", ""},
+	} {
+		body := `{"id":"` + tc.id + `","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"` + tc.effectiveAt + `"}`
+		resp := postTxn(t, srv, body)
+		var created map[string]any
+		json.NewDecoder(resp.Body).Decode(&created)
+		resp.Body.Close()
+
+		get := getTxnByID(t, srv, tc.id)
+		var stored map[string]any
+		json.NewDecoder(get.Body).Decode(&stored)
+		get.Body.Close()
+
+		for name, got := range map[string]map[string]any{"create": created, "GET": stored} {
+			if got["effective_at"] != tc.wantUTC {
+				t.Errorf("%s %s: expected effective_at %s, got %v", tc.id, name, tc.wantUTC, got["effective_at"])
+			}
+			zone, _ := got["time_zone"].(string)
+			if zone != tc.wantZone {
+				t.Errorf("%s %s: expected time_zone %q, got %v", tc.id, name, tc.wantZone, got["time_zone"])
+			}
+		}
+	}
+}
+
+// Test: TestCreateTransaction_timeZoneIgnoredOnInput
+// What: a client-sent time_zone is replaced by the offset actually used in effective_at
+// Input: effective_at with +09:00 and "time_zone":"-05:00"
+// Output: time_zone "+09:00"
+func TestCreateTransaction_timeZoneIgnoredOnInput(t *testing.T) {
+	srv := newTestServer(t)
+	resp := postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-16T12:00:00+09:00","time_zone":"-05:00"}`)
+	defer resp.Body.Close()
+
+	var created map[string]any
+	json.NewDecoder(resp.Body).Decode(&created)
+	if created["time_zone"] != "+09:00" {
+		t.Errorf("expected time_zone +09:00, got %v", created["time_zone"])
+	}
+}
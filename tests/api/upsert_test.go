@@ -0,0 +1,96 @@
+package api_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+func postTxnMode(t *testing.T, srv *httptest.Server, mode, body string) *http.Response {
+	t.Helper()
+	resp, err := http.Post(srv.URL+"/transactions?mode="+mode, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST /transactions?mode=%s failed: %v", mode, err)
+	}
+	return resp
+}
+
+// Test: TestCreateUpsert_insertThenReplace
+// What: mode=upsert creates a new ID with 201 and replaces an existing one with different data with 200
+// Input: POST mode=upsert txn-1 amount 100; POST mode=upsert txn-1 amount 250; GET txn-1
+// Output: 201, then 200 with amount 250; GET returns amount 250
+func TestCreateUpsert_insertThenReplace(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp := postTxnMode(t, srv, "upsert", `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+
+	resp = postTxnMode(t, srv, "upsert", `{"id":"txn-1","account_id":"acct-1","amount":250,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var replaced model.Transaction
+	json.NewDecoder(resp.Body).Decode(&replaced)
+	if replaced.Amount != 250 {
+		t.Errorf("expected amount 250 in the response, got %d", replaced.Amount)
+	}
+
+	get := getTxnByID(t, srv, "txn-1")
+	defer get.Body.Close()
+	var stored model.Transaction
+	json.NewDecoder(get.Body).Decode(&stored)
+	if stored.Amount != 250 {
+		t.Errorf("expected stored amount 250, got %d", stored.Amount)
+	}
+}
+
+// Test: TestCreateUpsert_defaultModeConflicts
+// What: without mode, or with mode=create, a different payload for an existing ID is still a 409; an unknown mode is a 400
+// Input: txn-1 stored; POST txn-1 with a different amount with no mode and with mode=create; mode=merge
+// Output: 409, 409, 400
+func TestCreateUpsert_defaultModeConflicts(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	changed := `{"id":"txn-1","account_id":"acct-1","amount":250,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`
+
+	for mode, want := range map[string]int{"": http.StatusConflict, "create": http.StatusConflict, "merge": http.StatusBadRequest} {
+		resp := postTxnMode(t, srv, mode, changed)
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("mode %q: expected %d, got %d", mode, want, resp.StatusCode)
+		}
+	}
+}
+
+// Test: TestCreateUpsert_reversedStaysReversed
+// What: upserting a reversed transaction keeps its reversal link and audits the amount change
+// Input: txn-1 (amount 100) reversed; POST mode=upsert txn-1 with amount 999; reverse txn-1 again
+// Output: 200 with amount 999, metadata.reversed_by and an amount_history entry; the second reverse is 409
+func TestCreateUpsert_reversedStaysReversed(t *testing.T) {
+	srv := newTestServer(t)
+	seedTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	postReverse(t, srv, "txn-1", "").Body.Close()
+
+	resp := postTxnMode(t, srv, "upsert", `{"id":"txn-1","account_id":"acct-1","amount":999,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	defer resp.Body.Close()
+	var replaced model.Transaction
+	json.NewDecoder(resp.Body).Decode(&replaced)
+	if resp.StatusCode != http.StatusOK || replaced.Amount != 999 ||
+		replaced.Metadata[model.MetadataReversedBy] != "txn-1-reversal" || replaced.Metadata[model.MetadataAmountHistory] == "" {
+		t.Errorf("expected 200 keeping reversed_by and recording the amount, got %d %+v", resp.StatusCode, replaced)
+	}
+
+	again := postReverse(t, srv, "txn-1", `{"id":"another"}`)
+	again.Body.Close()
+	if again.StatusCode != http.StatusConflict {
+		t.Errorf("second reverse: expected 409, got %d", again.StatusCode)
+	}
+}
@@ -1,6 +1,8 @@
 package api_test
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -17,8 +19,10 @@ import (
 func TestValidateTransaction_valid(t *testing.T) {
 	txn := model.Transaction{
 		ID:          "txn-1",
+		AccountID:   "acct-1",
 		Amount:      100,
 		Currency:    "USD",
+		Direction:   model.DirectionDebit,
 		EffectiveAt: time.Now(),
 	}
 	if err := api.ValidateTransaction(txn); err != nil {
@@ -31,18 +35,77 @@ func TestValidateTransaction_valid(t *testing.T) {
 // Input: Transaction with empty ID field, all other fields valid
 // Output: non-nil error
 func TestValidateTransaction_missingID(t *testing.T) {
-	txn := model.Transaction{Amount: 100, Currency: "USD", EffectiveAt: time.Now()}
+	txn := model.Transaction{AccountID: "acct-1", Amount: 100, Currency: "USD", Direction: model.DirectionDebit, EffectiveAt: time.Now()}
 	if err := api.ValidateTransaction(txn); err == nil {
 		t.Error("expected error for missing ID, got nil")
 	}
 }
 
+// Test: TestValidateTransaction_accountIDLength
+// What: ValidateTransaction caps account_id at MaxAccountIDLength
+// Input: account IDs of MaxAccountIDLength and MaxAccountIDLength+1 characters
+// Output: nil error for the first; an error for the second
+func TestValidateTransaction_accountIDLength(t *testing.T) {
+	txn := model.Transaction{ID: "txn-1", AccountID: strings.Repeat("a", api.MaxAccountIDLength), Amount: 100, Currency: "USD", Direction: model.DirectionDebit, EffectiveAt: time.Now()}
+	if err := api.ValidateTransaction(txn); err != nil {
+		t.Errorf("expected a %d-character account_id to pass, got %v", api.MaxAccountIDLength, err)
+	}
+	txn.AccountID += "a"
+	if err := api.ValidateTransaction(txn); err == nil {
+		t.Errorf("expected an error for a %d-character account_id, got nil", len(txn.AccountID))
+	}
+}
+
+// Test: TestValidateTransaction_invalidID
+// What: ValidateTransaction rejects IDs outside the allowed charset or length
+// Input: Transactions with IDs "a/b" and 129 'x' characters, all other fields valid
+// Output: non-nil error for both
+func TestValidateTransaction_invalidID(t *testing.T) {
+	for _, id := range []string{"a/b", strings.Repeat("x", api.MaxIDLength+1)} {
+		txn := model.Transaction{ID: id, AccountID: "acct-1", Amount: 100, Currency: "USD", Direction: model.DirectionDebit, EffectiveAt: time.Now()}
+		if err := api.ValidateTransaction(txn); err == nil {
+			t.Errorf("expected error for id %q, got nil", id)
+		}
+	}
+}
+
+// Test: TestIsValidID
+// What: IsValidID accepts letters, digits, '-' and '_' up to MaxIDLength and rejects anything else
+// Input: typical IDs and a max-length ID; then empty, slash, space, dot, newline, non-ASCII, too long
+// Output: true for the first group, false for the rest
+func TestIsValidID(t *testing.T) {
+	valid := []string{"txn-1", "TXN_2024_01", "0", strings.Repeat("a", api.MaxIDLength)}
+	for _, id := range valid {
+		if !api.IsValidID(id) {
+			t.Errorf("expected %q to be valid", id)
+		}
+	}
+
+	invalid := []string{"", "a/b", "a b", "a.b", "a\nb", "café", strings.Repeat("a", api.MaxIDLength+1)}
+	for _, id := range invalid {
+		if api.IsValidID(id) {
+			t.Errorf("expected %q to be invalid", id)
+		}
+	}
+}
+
+// Test: TestValidateTransaction_missingAccountID
+// What: ValidateTransaction rejects a transaction with no account ID
+// Input: Transaction with empty AccountID field, all other fields valid
+// Output: non-nil error
+func TestValidateTransaction_missingAccountID(t *testing.T) {
+	txn := model.Transaction{ID: "txn-1", Amount: 100, Currency: "USD", Direction: model.DirectionDebit, EffectiveAt: time.Now()}
+	if err := api.ValidateTransaction(txn); err == nil {
+		t.Error("expected error for missing account ID, got nil")
+	}
+}
+
 // Test: TestValidateTransaction_missingCurrency
 // What: ValidateTransaction rejects a transaction with no currency
 // Input: Transaction with empty Currency field, all other fields valid
 // Output: non-nil error
 func TestValidateTransaction_missingCurrency(t *testing.T) {
-	txn := model.Transaction{ID: "txn-1", Amount: 100, EffectiveAt: time.Now()}
+	txn := model.Transaction{ID: "txn-1", AccountID: "acct-1", Amount: 100, Direction: model.DirectionDebit, EffectiveAt: time.Now()}
 	if err := api.ValidateTransaction(txn); err == nil {
 		t.Error("expected error for missing currency, got nil")
 	}
@@ -53,7 +116,7 @@ func TestValidateTransaction_missingCurrency(t *testing.T) {
 // Input: Transaction with EffectiveAt unset (zero time.Time), all other fields valid
 // Output: non-nil error
 func TestValidateTransaction_missingEffectiveAt(t *testing.T) {
-	txn := model.Transaction{ID: "txn-1", Amount: 100, Currency: "USD"}
+	txn := model.Transaction{ID: "txn-1", AccountID: "acct-1", Amount: 100, Currency: "USD", Direction: model.DirectionDebit}
 	if err := api.ValidateTransaction(txn); err == nil {
 		t.Error("expected error for missing effective_at, got nil")
 	}
@@ -64,7 +127,7 @@ func TestValidateTransaction_missingEffectiveAt(t *testing.T) {
 // Input: Transaction with Amount = -1, all other fields valid
 // Output: non-nil error
 func TestValidateTransaction_negativeAmount(t *testing.T) {
-	txn := model.Transaction{ID: "txn-1", Amount: -1, Currency: "USD", EffectiveAt: time.Now()}
+	txn := model.Transaction{ID: "txn-1", AccountID: "acct-1", Amount: -1, Currency: "USD", Direction: model.DirectionDebit, EffectiveAt: time.Now()}
 	if err := api.ValidateTransaction(txn); err == nil {
 		t.Error("expected error for negative amount, got nil")
 	}
@@ -75,12 +138,123 @@ func TestValidateTransaction_negativeAmount(t *testing.T) {
 // Input: Transaction with Amount = 0, all other fields valid
 // Output: nil error
 func TestValidateTransaction_zeroAmountAllowed(t *testing.T) {
-	txn := model.Transaction{ID: "txn-1", Amount: 0, Currency: "USD", EffectiveAt: time.Now()}
+	txn := model.Transaction{ID: "txn-1", AccountID: "acct-1", Amount: 0, Currency: "USD", Direction: model.DirectionDebit, EffectiveAt: time.Now()}
 	if err := api.ValidateTransaction(txn); err != nil {
 		t.Errorf("expected nil error for zero amount, got %v", err)
 	}
 }
 
+// Test: TestValidateTransaction_missingDirection
+// What: ValidateTransaction rejects a transaction with no direction
+// Input: Transaction with empty Direction field, all other fields valid
+// Output: non-nil error
+func TestValidateTransaction_missingDirection(t *testing.T) {
+	txn := model.Transaction{ID: "txn-1", AccountID: "acct-1", Amount: 100, Currency: "USD", EffectiveAt: time.Now()}
+	if err := api.ValidateTransaction(txn); err == nil {
+		t.Error("expected error for missing direction, got nil")
+	}
+}
+
+// Test: TestValidateTransaction_invalidDirection
+// What: ValidateTransaction rejects a direction other than debit or credit
+// Input: Transaction with Direction="refund", all other fields valid
+// Output: non-nil error
+func TestValidateTransaction_invalidDirection(t *testing.T) {
+	txn := model.Transaction{ID: "txn-1", AccountID: "acct-1", Amount: 100, Currency: "USD", Direction: "refund", EffectiveAt: time.Now()}
+	if err := api.ValidateTransaction(txn); err == nil {
+		t.Error("expected error for invalid direction, got nil")
+	}
+}
+
+// Test: TestValidateTransaction_creditAllowed
+// What: ValidateTransaction accepts a credit
+// Input: Transaction with Direction="credit", all other fields valid
+// Output: nil error
+func TestValidateTransaction_creditAllowed(t *testing.T) {
+	txn := model.Transaction{ID: "txn-1", AccountID: "acct-1", Amount: 100, Currency: "USD", Direction: model.DirectionCredit, EffectiveAt: time.Now()}
+	if err := api.ValidateTransaction(txn); err != nil {
+		t.Errorf("expected nil error for credit, got %v", err)
+	}
+}
+
+// Test: TestValidateTransaction_smallMetadataAllowed
+// What: ValidateTransaction accepts a normal, small metadata map
+// Input: Transaction with 2 short metadata entries, all other fields valid
+// Output: nil error
+func TestValidateTransaction_smallMetadataAllowed(t *testing.T) {
+	txn := model.Transaction{ID: "txn-1", AccountID: "acct-1", Amount: 100, Currency: "USD", Direction: model.DirectionDebit, EffectiveAt: time.Now(),
+		Metadata: map[string]string{"source": "mobile", "order": "A-1"}}
+	if err := api.ValidateTransaction(txn); err != nil {
+		t.Errorf("expected nil error for small metadata, got %v", err)
+	}
+}
+
+// Test: TestValidateTransaction_tooManyMetadataKeys
+// What: ValidateTransaction rejects metadata with more than MaxMetadataEntries entries
+// Input: Transaction with MaxMetadataEntries+1 metadata entries
+// Output: non-nil error
+func TestValidateTransaction_tooManyMetadataKeys(t *testing.T) {
+	metadata := make(map[string]string, api.MaxMetadataEntries+1)
+	for i := 0; i <= api.MaxMetadataEntries; i++ {
+		metadata[fmt.Sprintf("k%d", i)] = "v"
+	}
+	txn := model.Transaction{ID: "txn-1", AccountID: "acct-1", Amount: 100, Currency: "USD", Direction: model.DirectionDebit, EffectiveAt: time.Now(), Metadata: metadata}
+	if err := api.ValidateTransaction(txn); err == nil {
+		t.Error("expected error for too many metadata entries, got nil")
+	}
+}
+
+// Test: TestValidateTransaction_oversizedMetadataKey
+// What: ValidateTransaction rejects a metadata key longer than MaxMetadataKeyLength
+// Input: Transaction with one metadata key of MaxMetadataKeyLength+1 characters
+// Output: non-nil error
+func TestValidateTransaction_oversizedMetadataKey(t *testing.T) {
+	key := strings.Repeat("k", api.MaxMetadataKeyLength+1)
+	txn := model.Transaction{ID: "txn-1", AccountID: "acct-1", Amount: 100, Currency: "USD", Direction: model.DirectionDebit, EffectiveAt: time.Now(),
+		Metadata: map[string]string{key: "v"}}
+	if err := api.ValidateTransaction(txn); err == nil {
+		t.Error("expected error for oversized metadata key, got nil")
+	}
+}
+
+// Test: TestValidateTransaction_oversizedMetadataValue
+// What: ValidateTransaction rejects a metadata value longer than MaxMetadataValueLength
+// Input: Transaction with one metadata value of MaxMetadataValueLength+1 characters
+// Output: non-nil error
+func TestValidateTransaction_oversizedMetadataValue(t *testing.T) {
+	txn := model.Transaction{ID: "txn-1", AccountID: "acct-1", Amount: 100, Currency: "USD", Direction: model.DirectionDebit, EffectiveAt: time.Now(),
+		Metadata: map[string]string{"note": strings.Repeat("v", api.MaxMetadataValueLength+1)}}
+	if err := api.ValidateTransaction(txn); err == nil {
+		t.Error("expected error for oversized metadata value, got nil")
+	}
+}
+
+// Test: TestValidateTransaction_metadataText
+// What: ValidateTransaction rejects metadata keys or values that are invalid UTF-8 or hold control
+// characters, naming the key and the problem, and accepts ordinary text including non-ASCII
+// Input: value "\xff\xfe"; value "line1\nline2"; key "bad\x00key"; value "Café ☕ 東京"
+// Output: errors mentioning UTF-8, U+000A and U+0000; nil for the last
+func TestValidateTransaction_metadataText(t *testing.T) {
+	newTxn := func(metadata map[string]string) model.Transaction {
+		return model.Transaction{ID: "txn-1", AccountID: "acct-1", Amount: 100, Currency: "USD", Direction: model.DirectionDebit, EffectiveAt: time.Now(), Metadata: metadata}
+	}
+
+	for want, metadata := range map[string]map[string]string{
+		"UTF-8":  {"note": "\xff\xfe"},
+		"U+000A": {"note": "line1\nline2"},
+		"U+0000": {"bad\x00key": "v"},
+	} {
+		err := api.ValidateTransaction(newTxn(metadata))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected an error mentioning %s, got %v", metadata, want, err)
+		}
+	}
+
+	if err := api.ValidateTransaction(newTxn(map[string]string{"note": "Café ☕ 東京"})); err != nil {
+		t.Errorf("expected nil for printable non-ASCII text, got %v", err)
+	}
+}
+
 // --- ValidatePagination ---
 
 // Test: TestValidatePagination_validDefaults
@@ -88,7 +262,7 @@ func TestValidateTransaction_zeroAmountAllowed(t *testing.T) {
 // Input: limit=100, offset=0
 // Output: nil error
 func TestValidatePagination_validDefaults(t *testing.T) {
-	if err := api.ValidatePagination(100, 0); err != nil {
+	if err := api.ValidatePagination(100, 0, api.DefaultMaxLimit); err != nil {
 		t.Errorf("expected nil for default pagination, got %v", err)
 	}
 }
@@ -98,7 +272,7 @@ func TestValidatePagination_validDefaults(t *testing.T) {
 // Input: limit=1, offset=0
 // Output: nil error
 func TestValidatePagination_limitOne(t *testing.T) {
-	if err := api.ValidatePagination(1, 0); err != nil {
+	if err := api.ValidatePagination(1, 0, api.DefaultMaxLimit); err != nil {
 		t.Errorf("expected nil for limit=1, got %v", err)
 	}
 }
@@ -108,7 +282,7 @@ func TestValidatePagination_limitOne(t *testing.T) {
 // Input: limit=1000, offset=0
 // Output: nil error
 func TestValidatePagination_limitMax(t *testing.T) {
-	if err := api.ValidatePagination(1000, 0); err != nil {
+	if err := api.ValidatePagination(1000, 0, api.DefaultMaxLimit); err != nil {
 		t.Errorf("expected nil for limit=1000, got %v", err)
 	}
 }
@@ -118,7 +292,7 @@ func TestValidatePagination_limitMax(t *testing.T) {
 // Input: limit=0, offset=0
 // Output: non-nil error
 func TestValidatePagination_zeroLimit(t *testing.T) {
-	if err := api.ValidatePagination(0, 0); err == nil {
+	if err := api.ValidatePagination(0, 0, api.DefaultMaxLimit); err == nil {
 		t.Error("expected error for limit=0, got nil")
 	}
 }
@@ -128,7 +302,7 @@ func TestValidatePagination_zeroLimit(t *testing.T) {
 // Input: limit=-1, offset=0
 // Output: non-nil error
 func TestValidatePagination_negativeLimit(t *testing.T) {
-	if err := api.ValidatePagination(-1, 0); err == nil {
+	if err := api.ValidatePagination(-1, 0, api.DefaultMaxLimit); err == nil {
 		t.Error("expected error for limit=-1, got nil")
 	}
 }
@@ -138,7 +312,7 @@ func TestValidatePagination_negativeLimit(t *testing.T) {
 // Input: limit=1001, offset=0
 // Output: non-nil error
 func TestValidatePagination_limitTooHigh(t *testing.T) {
-	if err := api.ValidatePagination(1001, 0); err == nil {
+	if err := api.ValidatePagination(1001, 0, api.DefaultMaxLimit); err == nil {
 		t.Error("expected error for limit=1001, got nil")
 	}
 }
@@ -148,7 +322,7 @@ func TestValidatePagination_limitTooHigh(t *testing.T) {
 // Input: limit=10, offset=-1
 // Output: non-nil error
 func TestValidatePagination_negativeOffset(t *testing.T) {
-	if err := api.ValidatePagination(10, -1); err == nil {
+	if err := api.ValidatePagination(10, -1, api.DefaultMaxLimit); err == nil {
 		t.Error("expected error for offset=-1, got nil")
 	}
 }
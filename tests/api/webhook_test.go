@@ -0,0 +1,215 @@
+package api_test
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/api"
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// webhookDelivery is one request received by a test webhook endpoint.
+type webhookDelivery struct {
+	body       []byte
+	requestURI string
+	timestamp  string
+	signature  string
+}
+
+// newWebhookReceiver returns an endpoint that records deliveries and answers with statuses in
+// turn, then 200 once they run out.
+func newWebhookReceiver(t *testing.T, statuses ...int) (*httptest.Server, <-chan webhookDelivery) {
+	t.Helper()
+	deliveries := make(chan webhookDelivery, 16)
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		deliveries <- webhookDelivery{body: body, requestURI: r.URL.RequestURI(),
+			timestamp: r.Header.Get(api.SignatureTimestampHeader), signature: r.Header.Get(api.SignatureHeader)}
+		if i := int(calls.Add(1)) - 1; i < len(statuses) {
+			w.WriteHeader(statuses[i])
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, deliveries
+}
+
+func nextDelivery(t *testing.T, deliveries <-chan webhookDelivery) webhookDelivery {
+	t.Helper()
+	select {
+	case d := <-deliveries:
+		return d
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for a webhook delivery")
+		return webhookDelivery{}
+	}
+}
+
+// Test: TestWebhook_signedPayload
+// What: a created transaction is POSTed to the webhook with an X-Signature that verifies
+// under the shared secret
+// Input: a notifier with secret "s3cret" on a MemoryStore; Create txn-1
+// Output: the endpoint receives txn-1's JSON, and Sign over POST, its request URI, the
+// timestamp header and the body under "s3cret" equals the signature
+func TestWebhook_signedPayload(t *testing.T) {
+	receiver, deliveries := newWebhookReceiver(t)
+	s := store.NewMemoryStore()
+	notifier, err := api.NewWebhookNotifier(s, api.WebhookConfig{URLs: []string{receiver.URL}, Secret: []byte("s3cret")})
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier failed: %v", err)
+	}
+	defer notifier.Close()
+
+	if err := s.Create(makeFilterTxn("txn-1", "USD", 100, 2024, 1, 1)); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	d := nextDelivery(t, deliveries)
+	var txn map[string]any
+	if err := json.Unmarshal(d.body, &txn); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if txn["id"] != "txn-1" || txn["amount"] != float64(100) {
+		t.Errorf("expected txn-1 with amount 100, got %v", txn)
+	}
+	signature, err := hex.DecodeString(d.signature)
+	if err != nil || !hmac.Equal(signature, api.Sign([]byte("s3cret"), http.MethodPost, d.requestURI, d.timestamp, d.body)) {
+		t.Errorf("signature %q does not verify", d.signature)
+	}
+}
+
+// Test: TestWebhook_retriesTransientFailures
+// What: 5xx responses are retried with backoff; a 4xx is given up on at once
+// Input: endpoint answers 503, 500, then 200; a second endpoint answers 400; Create txn-1
+// Output: the first endpoint gets 3 identical deliveries; the second gets exactly 1
+func TestWebhook_retriesTransientFailures(t *testing.T) {
+	flaky, flakyDeliveries := newWebhookReceiver(t, http.StatusServiceUnavailable, http.StatusInternalServerError)
+	rejecting, rejectingDeliveries := newWebhookReceiver(t, http.StatusBadRequest)
+	s := store.NewMemoryStore()
+	notifier, err := api.NewWebhookNotifier(s, api.WebhookConfig{
+		URLs:    []string{flaky.URL, rejecting.URL},
+		Backoff: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier failed: %v", err)
+	}
+	defer notifier.Close()
+
+	_ = s.Create(makeFilterTxn("txn-1", "USD", 100, 2024, 1, 1))
+
+	first := nextDelivery(t, flakyDeliveries)
+	for i := 2; i <= 3; i++ {
+		if d := nextDelivery(t, flakyDeliveries); string(d.body) != string(first.body) {
+			t.Errorf("attempt %d: expected the same payload, got %s", i, d.body)
+		}
+	}
+	nextDelivery(t, rejectingDeliveries)
+	select {
+	case d := <-rejectingDeliveries:
+		t.Errorf("expected no retry after 400, got %s", d.body)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// Test: TestWebhook_slowEndpointDoesNotBlockCreate
+// What: creates return promptly while the webhook endpoint hangs; overflow is dropped
+// Input: an endpoint that blocks until the test ends, queue size 1; 20 creates
+// Output: all 20 creates succeed within a second
+func TestWebhook_slowEndpointDoesNotBlockCreate(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	defer close(release)
+
+	s := store.NewMemoryStore()
+	notifier, err := api.NewWebhookNotifier(s, api.WebhookConfig{URLs: []string{slow.URL}, QueueSize: 1})
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier failed: %v", err)
+	}
+	defer notifier.Close()
+
+	start := time.Now()
+	for i := range 20 {
+		if err := s.Create(makeFilterTxn(string(rune('a'+i)), "USD", 100, 2024, 1, 1)); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected creates not to wait on the webhook, took %s", elapsed)
+	}
+}
+
+// Test: TestWebhook_deliversAfterBurst
+// What: a burst that outruns the store subscription doesn't stop delivery for good; the
+// notifier resubscribes and later creates are still sent
+// Input: 5000 back-to-back creates with a one-slot queue, then creates until one is delivered
+// Output: a transaction created after the burst reaches the endpoint within 5s
+func TestWebhook_deliversAfterBurst(t *testing.T) {
+	late := make(chan struct{})
+	var once sync.Once
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var txn model.Transaction
+		if json.NewDecoder(r.Body).Decode(&txn) == nil && strings.HasPrefix(txn.ID, "late-") {
+			once.Do(func() { close(late) })
+		}
+	}))
+	defer receiver.Close()
+	// The burst overflows the queue, and every dropped payload is logged
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+
+	s := store.NewMemoryStore()
+	notifier, err := api.NewWebhookNotifier(s, api.WebhookConfig{URLs: []string{receiver.URL}, QueueSize: 1})
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier failed: %v", err)
+	}
+	defer notifier.Close()
+
+	for i := range 5000 {
+		if err := s.Create(makeFilterTxn(fmt.Sprintf("burst-%d", i), "USD", 100, 2024, 1, 1)); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	// Later creates may land in the endpoint's full queue, so keep creating until one gets through
+	deadline := time.After(5 * time.Second)
+	for i := 0; ; i++ {
+		if err := s.Create(makeFilterTxn(fmt.Sprintf("late-%d", i), "USD", 100, 2024, 1, 1)); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+		select {
+		case <-late:
+			return
+		case <-deadline:
+			t.Fatal("no webhook was delivered after the burst")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+// Test: TestNewWebhookNotifier_invalidConfig
+// What: the notifier refuses configurations it could never deliver with
+// Input: no URLs; a relative URL; an ftp URL
+// Output: an error each time
+func TestNewWebhookNotifier_invalidConfig(t *testing.T) {
+	for _, urls := range [][]string{nil, {"/hooks"}, {"ftp://example.com/hook"}} {
+		if n, err := api.NewWebhookNotifier(store.NewMemoryStore(), api.WebhookConfig{URLs: urls}); err == nil {
+			n.Close()
+			t.Errorf("URLs %v: expected an error", urls)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/api"
+)
+
+const zeroAmountTxn = `{"id":"txn-0","account_id":"acct-1","amount":0,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`
+
+// Test: TestCreateTransaction_zeroAmountAllowedByDefault
+// What: zero-amount transactions are accepted unless the policy forbids them
+// Input: the default config; create with amount 0
+// Output: 201
+func TestCreateTransaction_zeroAmountAllowedByDefault(t *testing.T) {
+	srv := newTestServer(t)
+	resp := postTxn(t, srv, zeroAmountTxn)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("expected 201, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestCreateTransaction_zeroAmountRejected
+// What: with AllowZeroAmount off, a zero amount is rejected on create and on an amount
+// correction, while non-zero amounts still work
+// Input: AllowZeroAmount=false; create with amount 0, create txn-1 with amount 100, PATCH
+// txn-1 {"amount":0}
+// Output: 400, 201, 400
+func TestCreateTransaction_zeroAmountRejected(t *testing.T) {
+	cfg := api.DefaultConfig()
+	cfg.AllowZeroAmount = false
+	srv := newTestServerWithConfig(t, cfg)
+
+	resp := postTxn(t, srv, zeroAmountTxn)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("zero amount: expected 400, got %d", resp.StatusCode)
+	}
+
+	resp = postTxn(t, srv, `{"id":"txn-1","account_id":"acct-1","amount":100,"currency":"USD","direction":"debit","effective_at":"2024-01-01T00:00:00Z"}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("non-zero amount: expected 201, got %d", resp.StatusCode)
+	}
+
+	resp = patchTxn(t, srv, "txn-1", `{"amount":0}`)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("correction to zero: expected 400, got %d", resp.StatusCode)
+	}
+}
+
+// Test: TestValidateAmount
+// What: the zero-amount policy check
+// Input: 0 with allowZero true and false, 1 with allowZero false
+// Output: nil, an error, nil
+func TestValidateAmount(t *testing.T) {
+	if err := api.ValidateAmount(0, true); err != nil {
+		t.Errorf("0 allowed: unexpected error %v", err)
+	}
+	if err := api.ValidateAmount(0, false); err == nil {
+		t.Error("0 not allowed: expected an error")
+	}
+	if err := api.ValidateAmount(1, false); err != nil {
+		t.Errorf("1: unexpected error %v", err)
+	}
+}
@@ -0,0 +1,42 @@
+package model_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// Test: TestAddAmounts
+// What: AddAmounts returns the exact sum or ErrAmountOverflow at both ends of the int64 range
+// Input: pairs near math.MaxInt64 and math.MinInt64, plus mixed-sign pairs that can't overflow
+// Output: the sum, or ErrAmountOverflow for the out-of-range cases
+func TestAddAmounts(t *testing.T) {
+	tests := []struct {
+		a, b     int64
+		want     int64
+		overflow bool
+	}{
+		{1, 2, 3, false},
+		{math.MaxInt64 - 1, 1, math.MaxInt64, false},
+		{math.MaxInt64, 1, 0, true},
+		{math.MaxInt64, math.MaxInt64, 0, true},
+		{math.MinInt64 + 1, -1, math.MinInt64, false},
+		{math.MinInt64, -1, 0, true},
+		{math.MaxInt64, math.MinInt64, -1, false},
+	}
+
+	for _, tt := range tests {
+		got, err := model.AddAmounts(tt.a, tt.b)
+		if tt.overflow {
+			if !errors.Is(err, model.ErrAmountOverflow) {
+				t.Errorf("AddAmounts(%d, %d): expected ErrAmountOverflow, got %d, %v", tt.a, tt.b, got, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("AddAmounts(%d, %d) = %d, %v; want %d", tt.a, tt.b, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package model_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// Test: TestFormatMinorUnits
+// What: FormatMinorUnits places the decimal point by the currency's minor-unit exponent
+// Input: amounts in USD (2 decimals), JPY (0), KWD (3), including small, zero, and negative values
+// Output: the expected major-unit strings
+func TestFormatMinorUnits(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		want     string
+	}{
+		{1234, "USD", "12.34"},
+		{5, "usd", "0.05"},
+		{0, "USD", "0.00"},
+		{-150, "EUR", "-1.50"},
+		{100, "JPY", "100"},
+		{5, "KWD", "0.005"},
+		{12345, "KWD", "12.345"},
+		{math.MinInt64, "USD", "-92233720368547758.08"},
+	}
+
+	for _, tt := range tests {
+		if got := model.FormatMinorUnits(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("FormatMinorUnits(%d, %s) = %q, want %q", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+// Test: TestNormalizeAmount
+// What: NormalizeAmount divides by the currency's minor unit, so JPY amounts are already major units
+// Input: 1234 USD, 100 JPY, 5 KWD, -250 eur
+// Output: 12.34, 100, 0.005, -2.5
+func TestNormalizeAmount(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		want     float64
+	}{
+		{1234, "USD", 12.34},
+		{100, "JPY", 100},
+		{5, "KWD", 0.005},
+		{-250, "eur", -2.5},
+	}
+	for _, tt := range tests {
+		if got := model.NormalizeAmount(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("NormalizeAmount(%d, %s): expected %v, got %v", tt.amount, tt.currency, tt.want, got)
+		}
+	}
+}
+
+// Test: TestParseMajorUnits
+// What: ParseMajorUnits scales a decimal string by the currency's minor unit without rounding
+// Input: "12.34" USD, "12.3" usd, "100" JPY, "0.005" KWD, "0" EUR
+// Output: 1234, 1230, 100, 5, 0
+func TestParseMajorUnits(t *testing.T) {
+	tests := []struct {
+		s, currency string
+		want        int64
+	}{
+		{"12.34", "USD", 1234},
+		{"12.3", "usd", 1230},
+		{"100", "JPY", 100},
+		{"0.005", "KWD", 5},
+		{"0", "EUR", 0},
+	}
+	for _, tt := range tests {
+		if got, err := model.ParseMajorUnits(tt.s, tt.currency); err != nil || got != tt.want {
+			t.Errorf("ParseMajorUnits(%q, %s) = %d, %v; want %d", tt.s, tt.currency, got, err, tt.want)
+		}
+	}
+}
+
+// Test: TestParseMajorUnits_rejects
+// What: malformed, signed, over-precise and out-of-range strings are errors, not rounded
+// Input: "12.345" USD, "1.5" JPY, "", ".5", "12.", "-1", "1e3", "12,34", "92233720368547758.08" USD
+// Output: an error for each
+func TestParseMajorUnits_rejects(t *testing.T) {
+	tests := []struct{ s, currency string }{
+		{"12.345", "USD"},
+		{"1.5", "JPY"},
+		{"", "USD"},
+		{".5", "USD"},
+		{"12.", "USD"},
+		{"-1", "USD"},
+		{"1e3", "USD"},
+		{"12,34", "USD"},
+		{"92233720368547758.08", "USD"},
+	}
+	for _, tt := range tests {
+		if got, err := model.ParseMajorUnits(tt.s, tt.currency); err == nil {
+			t.Errorf("ParseMajorUnits(%q, %s) = %d, expected an error", tt.s, tt.currency, got)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/model"
+)
+
+// Test: TestLessByEffectiveAtThenID
+// What: the comparator orders by effective_at first and breaks timestamp ties by ID
+// Input: pairs with earlier/later timestamps, the same timestamp in different zones, and identical keys
+// Output: the earlier timestamp sorts first whatever the IDs; equal instants sort by ID; a txn is not less than itself
+func TestLessByEffectiveAtThenID(t *testing.T) {
+	early := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	late := early.Add(time.Second)
+	sameInstant := early.In(time.FixedZone("EST", -5*60*60))
+
+	tests := []struct {
+		name string
+		a, b model.Transaction
+		want bool
+	}{
+		{"earlier first", model.Transaction{ID: "z", EffectiveAt: early}, model.Transaction{ID: "a", EffectiveAt: late}, true},
+		{"later second", model.Transaction{ID: "a", EffectiveAt: late}, model.Transaction{ID: "z", EffectiveAt: early}, false},
+		{"tie broken by id", model.Transaction{ID: "a", EffectiveAt: early}, model.Transaction{ID: "b", EffectiveAt: early}, true},
+		{"tie reversed", model.Transaction{ID: "b", EffectiveAt: early}, model.Transaction{ID: "a", EffectiveAt: early}, false},
+		{"tie across zones", model.Transaction{ID: "a", EffectiveAt: sameInstant}, model.Transaction{ID: "b", EffectiveAt: early}, true},
+		{"same key", model.Transaction{ID: "a", EffectiveAt: early}, model.Transaction{ID: "a", EffectiveAt: early}, false},
+	}
+
+	for _, tt := range tests {
+		if got := model.LessByEffectiveAtThenID(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+// Test: TestSortTransactions
+// What: SortTransactions puts a shuffled slice into the default list order
+// Input: c@t1, b@t0, a@t1, d@t0
+// Output: b, d, a, c
+func TestSortTransactions(t *testing.T) {
+	t0 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+	txns := []model.Transaction{
+		{ID: "c", EffectiveAt: t1},
+		{ID: "b", EffectiveAt: t0},
+		{ID: "a", EffectiveAt: t1},
+		{ID: "d", EffectiveAt: t0},
+	}
+
+	model.SortTransactions(txns)
+
+	want := []string{"b", "d", "a", "c"}
+	for i, txn := range txns {
+		if txn.ID != want[i] {
+			t.Fatalf("position %d: expected %s, got %s", i, want[i], txn.ID)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package model_test
 
 import (
+	"slices"
 	"testing"
 	"time"
 
@@ -140,3 +141,263 @@ func TestEqual_differentMetadataKeySameEmptyValue(t *testing.T) {
 		t.Fatal("transactions with different metadata keys should not be equal even if values are empty strings")
 	}
 }
+
+// Test: TestEqual_ignoresSeq
+// What: Transaction.Equal ignores the server-assigned insertion sequence
+// Input: two transactions identical except Seq (1 vs 2)
+// Output: true
+func TestEqual_ignoresSeq(t *testing.T) {
+	a := model.Transaction{ID: "txn-1", Amount: 100, Currency: "USD", EffectiveAt: t0, Seq: 1}
+	b := model.Transaction{ID: "txn-1", Amount: 100, Currency: "USD", EffectiveAt: t0, Seq: 2}
+	if !a.Equal(b) {
+		t.Fatal("Seq is server-assigned and should not affect equality")
+	}
+}
+
+// Test: TestEqual_ignoresCreatedAt
+// What: Transaction.Equal ignores the server-assigned CreatedAt so idempotent retries still match
+// Input: two transactions identical except CreatedAt (zero vs t0)
+// Output: true
+func TestEqual_ignoresCreatedAt(t *testing.T) {
+	a := model.Transaction{ID: "txn-1", Amount: 100, Currency: "USD", EffectiveAt: t0}
+	b := model.Transaction{ID: "txn-1", Amount: 100, Currency: "USD", EffectiveAt: t0, CreatedAt: t0}
+	if !a.Equal(b) {
+		t.Fatal("transactions differing only in CreatedAt should be equal")
+	}
+}
+
+// Test: TestEqual_differentDirection
+// What: Transaction.Equal returns false when directions differ
+// Input: two transactions identical except Direction ("debit" vs "credit")
+// Output: false
+func TestEqual_differentDirection(t *testing.T) {
+	a := model.Transaction{ID: "txn-1", Amount: 100, Currency: "USD", Direction: model.DirectionDebit, EffectiveAt: t0}
+	b := model.Transaction{ID: "txn-1", Amount: 100, Currency: "USD", Direction: model.DirectionCredit, EffectiveAt: t0}
+	if a.Equal(b) {
+		t.Fatal("transactions with different directions should not be equal")
+	}
+}
+
+// Test: TestEqual_differentAccountID
+// What: Transaction.Equal returns false when account IDs differ
+// Input: two transactions identical except AccountID ("acct-1" vs "acct-2")
+// Output: false
+func TestEqual_differentAccountID(t *testing.T) {
+	a := model.Transaction{ID: "txn-1", AccountID: "acct-1", Amount: 100, Currency: "USD", EffectiveAt: t0}
+	b := model.Transaction{ID: "txn-1", AccountID: "acct-2", Amount: 100, Currency: "USD", EffectiveAt: t0}
+	if a.Equal(b) {
+		t.Fatal("transactions with different account IDs should not be equal")
+	}
+}
+
+// Test: TestEqual_differentDescription
+// What: Transaction.Equal returns false when descriptions differ, and Clone keeps the description
+// Input: a transaction with description "Coffee Shop", its Clone, and a copy with "Coffee Shop #2"
+// Output: the clone is equal; the copy is not
+func TestEqual_differentDescription(t *testing.T) {
+	a := model.Transaction{ID: "txn-1", Amount: 100, Currency: "USD", EffectiveAt: t0, Description: "Coffee Shop"}
+	b := a
+	b.Description = "Coffee Shop #2"
+	if !a.Equal(a.Clone()) {
+		t.Error("a clone should keep the description and be equal")
+	}
+	if a.Equal(b) {
+		t.Error("transactions with different descriptions should not be equal")
+	}
+}
+
+// Test: TestWithDefaults_missingDirection
+// What: WithDefaults treats older data without a direction as a debit and leaves set directions alone
+// Input: one transaction with no Direction, one with Direction="credit"
+// Output: "debit" and "credit" respectively
+func TestWithDefaults_missingDirection(t *testing.T) {
+	old := model.Transaction{ID: "txn-1"}
+	if got := old.WithDefaults().Direction; got != model.DirectionDebit {
+		t.Errorf("expected missing direction to default to debit, got %q", got)
+	}
+	credit := model.Transaction{ID: "txn-2", Direction: model.DirectionCredit}
+	if got := credit.WithDefaults().Direction; got != model.DirectionCredit {
+		t.Errorf("expected credit to be preserved, got %q", got)
+	}
+}
+
+// Test: TestETag_deterministic
+// What: ETag is stable for equal transactions regardless of metadata insertion order or Seq
+// Input: two equal transactions with the same metadata built in different orders and different Seq
+// Output: identical quoted ETags
+func TestETag_deterministic(t *testing.T) {
+	a := model.Transaction{ID: "txn-1", Amount: 100, Currency: "USD", EffectiveAt: t0, Seq: 1, Metadata: map[string]string{"a": "1", "b": "2"}}
+	b := model.Transaction{ID: "txn-1", Amount: 100, Currency: "USD", EffectiveAt: t0, Seq: 7, Metadata: map[string]string{"b": "2", "a": "1"}}
+	if a.ETag() != b.ETag() {
+		t.Errorf("expected equal ETags, got %s and %s", a.ETag(), b.ETag())
+	}
+	if tag := a.ETag(); len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		t.Errorf("expected a quoted ETag, got %s", tag)
+	}
+}
+
+// Test: TestETag_changesWithFields
+// What: ETag differs when any hashed field changes
+// Input: a base transaction (version 1) and copies with account_id, amount, currency, effective_at,
+// metadata, description, or version changed
+// Output: every copy's ETag differs from the base
+func TestETag_changesWithFields(t *testing.T) {
+	base := model.Transaction{ID: "txn-1", Amount: 100, Currency: "USD", EffectiveAt: t0, Metadata: map[string]string{"k": "v"}, Version: 1}
+
+	account, amount, currency, effectiveAt, metadata, description, version := base, base, base, base, base, base, base
+	account.AccountID = "acct-2"
+	amount.Amount = 101
+	currency.Currency = "EUR"
+	effectiveAt.EffectiveAt = t0.Add(time.Second)
+	metadata.Metadata = map[string]string{"k": "w"}
+	description.Description = "Coffee Shop"
+	version.Version = 2
+
+	for name, txn := range map[string]model.Transaction{"account_id": account, "amount": amount, "currency": currency, "effective_at": effectiveAt, "metadata": metadata, "description": description, "version": version} {
+		if txn.ETag() == base.ETag() {
+			t.Errorf("expected ETag to change when %s changes", name)
+		}
+	}
+}
+
+// Test: TestContentHash_canonical
+// What: ContentHash ignores metadata order, time zone offset, empty-vs-nil metadata and server fields
+// Input: pairs of Equal transactions that differ only in those representations
+// Output: each pair hashes the same, and the hash is 64 hex characters
+func TestContentHash_canonical(t *testing.T) {
+	base := model.Transaction{ID: "txn-1", AccountID: "acct-1", Amount: 100, Currency: "USD", Direction: model.DirectionDebit, EffectiveAt: t0, Metadata: map[string]string{"a": "1", "b": "2"}}
+
+	reordered := base
+	reordered.Metadata = map[string]string{"b": "2", "a": "1"}
+	offset := base
+	offset.EffectiveAt = t0.In(time.FixedZone("EST", -5*60*60))
+	server := base
+	server.Seq, server.CreatedAt, server.Version = 9, t0.Add(time.Hour), 4
+	noMeta, emptyMeta := base, base
+	noMeta.Metadata, emptyMeta.Metadata = nil, map[string]string{}
+
+	pairs := map[string][2]model.Transaction{
+		"metadata order": {base, reordered},
+		"time zone":      {base, offset},
+		"server fields":  {base, server},
+		"empty metadata": {noMeta, emptyMeta},
+	}
+	for name, p := range pairs {
+		if !p[0].Equal(p[1]) {
+			t.Fatalf("%s: fixture pair is not Equal", name)
+		}
+		if p[0].ContentHash() != p[1].ContentHash() {
+			t.Errorf("%s: expected equal hashes", name)
+		}
+	}
+	if h := base.ContentHash(); len(h) != 64 {
+		t.Errorf("expected a 64-character hex SHA-256, got %q", h)
+	}
+}
+
+// Test: TestContentHash_changesWithFields
+// What: ContentHash differs when any business field changes
+// Input: a base transaction and copies with account_id, amount, currency, direction, effective_at, metadata, or description changed
+// Output: every copy's hash differs from the base
+func TestContentHash_changesWithFields(t *testing.T) {
+	base := model.Transaction{ID: "txn-1", AccountID: "acct-1", Amount: 100, Currency: "USD", Direction: model.DirectionDebit, EffectiveAt: t0, Metadata: map[string]string{"k": "v"}}
+
+	account, amount, currency, direction, effectiveAt, metadata, description := base, base, base, base, base, base, base
+	account.AccountID = "acct-2"
+	amount.Amount = 101
+	currency.Currency = "EUR"
+	direction.Direction = model.DirectionCredit
+	effectiveAt.EffectiveAt = t0.Add(time.Nanosecond)
+	metadata.Metadata = map[string]string{"k": "w"}
+	description.Description = "Coffee Shop"
+
+	for name, txn := range map[string]model.Transaction{"account_id": account, "amount": amount, "currency": currency, "direction": direction, "effective_at": effectiveAt, "metadata": metadata, "description": description} {
+		if txn.ContentHash() == base.ContentHash() {
+			t.Errorf("expected hash to change when %s changes", name)
+		}
+	}
+}
+
+// Test: TestNormalizeTags
+// What: tags are trimmed, lowercased, deduplicated and sorted; blanks are dropped
+// Input: ["Refund", " refund ", "VIP", "", "apple"], and an all-blank list
+// Output: ["apple", "refund", "vip"], and nil
+func TestNormalizeTags(t *testing.T) {
+	got := model.NormalizeTags([]string{"Refund", " refund ", "VIP", "", "apple"})
+	want := []string{"apple", "refund", "vip"}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := model.NormalizeTags([]string{" ", ""}); got != nil {
+		t.Errorf("expected nil for blank tags, got %#v", got)
+	}
+}
+
+// Test: TestEqual_tags
+// What: Equal and ContentHash compare tags, and Clone copies them
+// Input: a transaction with tags [refund], the same with [refund vip], and a clone whose tag is then modified
+// Output: the first two differ; modifying the clone leaves the original unchanged
+func TestEqual_tags(t *testing.T) {
+	a := model.Transaction{ID: "txn-1", Amount: 100, Currency: "USD", EffectiveAt: t0, Tags: []string{"refund"}}
+	b := a
+	b.Tags = []string{"refund", "vip"}
+	if a.Equal(b) || a.ContentHash() == b.ContentHash() || a.ETag() == b.ETag() {
+		t.Error("expected different tags to make transactions unequal")
+	}
+
+	c := a.Clone()
+	c.Tags[0] = "changed"
+	if a.Tags[0] != "refund" {
+		t.Errorf("expected Clone to copy tags, original is now %v", a.Tags)
+	}
+}
+
+// Test: TestTimeZoneOf
+// What: TimeZoneOf formats a time's UTC offset, leaving UTC empty
+// Input: times at -05:00, +09:00, +05:30 and UTC
+// Output: "-05:00", "+09:00", "+05:30", ""
+func TestTimeZoneOf(t *testing.T) {
+	for _, tc := range []struct {
+		offset int
+		want   string
+	}{
+		{-5 * 3600, "-05:00"},
+		{9 * 3600, "+09:00"},
+		{5*3600 + 1800, "+05:30"},
+		{0, ""},
+	} {
+		at := t0.In(time.FixedZone("", tc.offset))
+		if got := model.TimeZoneOf(at); got != tc.want {
+			t.Errorf("offset %d: expected %q, got %q", tc.offset, tc.want, got)
+		}
+	}
+}
+
+// Test: TestLocalEffectiveAt
+// What: LocalEffectiveAt shows the UTC EffectiveAt in the stored TimeZone, falling back to UTC
+// Input: t0 (12:00 UTC) with TimeZone "-05:00", "", and "bogus"
+// Output: 07:00 -05:00 for the instant t0; UTC for the empty and malformed zones
+func TestLocalEffectiveAt(t *testing.T) {
+	local := model.Transaction{EffectiveAt: t0, TimeZone: "-05:00"}.LocalEffectiveAt()
+	if !local.Equal(t0) || local.Format(time.RFC3339) != "2024-01-01T07:00:00-05:00" {
+		t.Errorf("expected 2024-01-01T07:00:00-05:00, got %s", local.Format(time.RFC3339))
+	}
+	for _, zone := range []string{"", "bogus"} {
+		got := model.Transaction{EffectiveAt: t0, TimeZone: zone}.LocalEffectiveAt()
+		if got.Location() != time.UTC || !got.Equal(t0) {
+			t.Errorf("zone %q: expected %s in UTC, got %s", zone, t0, got)
+		}
+	}
+}
+
+// Test: TestEqual_ignoresTimeZone
+// What: Transaction.Equal ignores TimeZone, so retries with another offset for the same instant match
+// Input: two transactions differing only in TimeZone ("" vs "-05:00")
+// Output: true
+func TestEqual_ignoresTimeZone(t *testing.T) {
+	a := model.Transaction{ID: "txn-1", Amount: 100, Currency: "USD", EffectiveAt: t0}
+	b := model.Transaction{ID: "txn-1", Amount: 100, Currency: "USD", EffectiveAt: t0, TimeZone: "-05:00"}
+	if !a.Equal(b) {
+		t.Fatal("transactions differing only in TimeZone should be equal")
+	}
+}
@@ -0,0 +1,119 @@
+package store_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestCachingStore_hitReturnsCachedValue
+// What: once read, a transaction is served from the cache without consulting the backend
+// Input: Get "a" through the cache, then change "a" directly on the backend, then Get again
+// Output: the second Get still returns the original metadata
+func TestCachingStore_hitReturnsCachedValue(t *testing.T) {
+	inner := store.NewMemoryStore()
+	c := store.NewCachingStore(inner, 10)
+	_ = c.Create(makeTxn("a", 100, "USD", jan(1)))
+
+	if _, err := c.Get("a"); err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	v := "backend"
+	_ = inner.UpdateMetadata("a", map[string]*string{"src": &v}) // bypasses the cache
+
+	got, _ := c.Get("a")
+	if got.Metadata != nil {
+		t.Errorf("expected the cached copy without metadata, got %v", got.Metadata)
+	}
+}
+
+// Test: TestCachingStore_updateInvalidates
+// What: a write through the decorator drops the cached entry, so the next Get sees it
+// Input: Get "a", UpdateMetadata("a") through the cache, Get again
+// Output: the second Get returns the new metadata
+func TestCachingStore_updateInvalidates(t *testing.T) {
+	c := store.NewCachingStore(store.NewMemoryStore(), 10)
+	_ = c.Create(makeTxn("a", 100, "USD", jan(1)))
+	_, _ = c.Get("a")
+
+	v := "x"
+	if err := c.UpdateMetadata("a", map[string]*string{"k": &v}); err != nil {
+		t.Fatalf("UpdateMetadata failed: %v", err)
+	}
+	if got, _ := c.Get("a"); got.Metadata["k"] != "x" {
+		t.Errorf("expected updated metadata, got %v", got.Metadata)
+	}
+}
+
+// Test: TestCachingStore_reverseAndDeleteInvalidate
+// What: Reverse drops the original from the cache, and DeleteWhere empties it
+// Input: cache "a"; reverse it; Get "a"; cache "b"; delete everything; Get "b"
+// Output: "a" has reversed_by set; "b" is ErrNotFound and the cache is empty
+func TestCachingStore_reverseAndDeleteInvalidate(t *testing.T) {
+	c := store.NewCachingStore(store.NewMemoryStore(), 10)
+	a := makeTxn("a", 100, "USD", jan(1))
+	_ = c.Create(a)
+	_ = c.Create(makeTxn("b", 100, "USD", jan(2)))
+	_, _ = c.Get("a")
+
+	_ = c.Reverse("a", a.Reversal("a-rev", jan(3)))
+	if got, _ := c.Get("a"); got.Metadata[model.MetadataReversedBy] != "a-rev" {
+		t.Errorf("expected reversed_by=a-rev, got %v", got.Metadata)
+	}
+
+	_, _ = c.Get("b")
+	_, _ = c.DeleteWhere(func(model.Transaction) bool { return true })
+	if _, err := c.Get("b"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected ErrNotFound after delete, got %v", err)
+	}
+	if c.Len() != 0 {
+		t.Errorf("expected an empty cache, got %d entries", c.Len())
+	}
+}
+
+// Test: TestCachingStore_evictsLeastRecentlyUsed
+// What: with a full cache, reading a new ID evicts the least recently used entry
+// Input: size 2; Get a, b, a, c
+// Output: cache holds 2 entries; b was evicted (a backend change to b is visible), a was kept
+func TestCachingStore_evictsLeastRecentlyUsed(t *testing.T) {
+	inner := store.NewMemoryStore()
+	c := store.NewCachingStore(inner, 2)
+	for _, id := range []string{"a", "b", "c"} {
+		_ = c.Create(makeTxn(id, 100, "USD", jan(1)))
+	}
+	for _, id := range []string{"a", "b", "a", "c"} {
+		_, _ = c.Get(id)
+	}
+	if c.Len() != 2 {
+		t.Fatalf("expected 2 cached entries, got %d", c.Len())
+	}
+
+	v := "backend"
+	_ = inner.UpdateMetadata("a", map[string]*string{"k": &v})
+	_ = inner.UpdateMetadata("b", map[string]*string{"k": &v})
+	if got, _ := c.Get("a"); got.Metadata != nil {
+		t.Errorf("expected a to still be cached, got %v", got.Metadata)
+	}
+	if got, _ := c.Get("b"); got.Metadata["k"] != "backend" {
+		t.Errorf("expected b to have been evicted, got %v", got.Metadata)
+	}
+}
+
+// Test: TestCachingStore_returnsCopies
+// What: callers can't modify the cached copy through a returned value
+// Input: Get "a", set a metadata key on the result, Get again
+// Output: the second result has no metadata
+func TestCachingStore_returnsCopies(t *testing.T) {
+	c := store.NewCachingStore(store.NewMemoryStore(), 10)
+	txn := makeTxn("a", 100, "USD", jan(1))
+	txn.Metadata = map[string]string{"k": "v"}
+	_ = c.Create(txn)
+
+	got, _ := c.Get("a")
+	got.Metadata["k"] = "changed"
+	if again, _ := c.Get("a"); again.Metadata["k"] != "v" {
+		t.Errorf("expected the cache to be unaffected, got %v", again.Metadata)
+	}
+}
@@ -0,0 +1,128 @@
+package store_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+func makeAccountTxn(id, accountID string, effectiveAt int) model.Transaction {
+	txn := makeTxn(id, 100, "USD", jan(effectiveAt))
+	txn.AccountID = accountID
+	return txn
+}
+
+// Test: TestQueryAccount_onlyThatAccountInOrder
+// What: QueryAccount returns only the account's transactions, in (effective_at, id) order
+// Input: a1 (day 3), a2 (day 1) in acct-1; b1 (day 2) in acct-2
+// Output: acct-1 -> [a2, a1]; acct-2 -> [b1]; unknown account -> empty
+func TestQueryAccount_onlyThatAccountInOrder(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeAccountTxn("a1", "acct-1", 3))
+	_ = s.Create(makeAccountTxn("b1", "acct-2", 2))
+	_ = s.Create(makeAccountTxn("a2", "acct-1", 1))
+
+	got, err := s.QueryAccount("acct-1", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ids(got), []string{"a2", "a1"}) {
+		t.Errorf("acct-1: expected [a2 a1], got %v", ids(got))
+	}
+	if got, _ := s.QueryAccount("acct-2", nil); !reflect.DeepEqual(ids(got), []string{"b1"}) {
+		t.Errorf("acct-2: expected [b1], got %v", ids(got))
+	}
+	if got, _ := s.QueryAccount("nope", nil); len(got) != 0 {
+		t.Errorf("unknown account: expected empty, got %v", ids(got))
+	}
+}
+
+// Test: TestQueryAccount_appliesPredicate
+// What: the predicate further filters the account's transactions
+// Input: a1 (day 1), a2 (day 2) in acct-1; predicate keeps day 2 only
+// Output: [a2]
+func TestQueryAccount_appliesPredicate(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeAccountTxn("a1", "acct-1", 1))
+	_ = s.Create(makeAccountTxn("a2", "acct-1", 2))
+
+	got, _ := s.QueryAccount("acct-1", func(txn model.Transaction) bool { return txn.EffectiveAt.Equal(jan(2)) })
+	if !reflect.DeepEqual(ids(got), []string{"a2"}) {
+		t.Errorf("expected [a2], got %v", ids(got))
+	}
+}
+
+// Test: TestQueryAccount_indexFollowsUpdates
+// What: the account index reflects metadata updates, reversals, moves between accounts, and Reset
+// Input: a1 in acct-1; UpdateMetadata, Reverse, CompareAndSwap to acct-2 with a new date, then Reset
+// Output: each step's QueryAccount results match Query filtered by account
+func TestQueryAccount_indexFollowsUpdates(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeAccountTxn("a1", "acct-1", 1))
+	_ = s.Create(makeAccountTxn("a2", "acct-1", 2))
+
+	// Metadata changes are visible through the index
+	v := "x"
+	_ = s.UpdateMetadata("a1", map[string]*string{"k": &v})
+	got, _ := s.QueryAccount("acct-1", nil)
+	if len(got) != 2 || got[0].Metadata["k"] != "x" {
+		t.Fatalf("expected updated metadata on a1, got %+v", got)
+	}
+
+	// The reversal lands in the same account and the original is marked
+	a2, _ := s.Get("a2")
+	_ = s.Reverse("a2", a2.Reversal("a2-rev", jan(5)))
+	got, _ = s.QueryAccount("acct-1", nil)
+	if !reflect.DeepEqual(ids(got), []string{"a1", "a2", "a2-rev"}) {
+		t.Fatalf("expected [a1 a2 a2-rev], got %v", ids(got))
+	}
+	if got[1].Metadata[model.MetadataReversedBy] != "a2-rev" {
+		t.Errorf("expected a2 to be marked reversed in the index, got %+v", got[1])
+	}
+
+	// Moving a transaction to another account removes it from the old one
+	a1, _ := s.Get("a1")
+	moved := a1.Clone()
+	moved.AccountID = "acct-2"
+	moved.EffectiveAt = jan(9)
+	if err := s.CompareAndSwap("a1", a1, moved); err != nil {
+		t.Fatalf("CompareAndSwap failed: %v", err)
+	}
+	for account, want := range map[string][]string{"acct-1": {"a2", "a2-rev"}, "acct-2": {"a1"}} {
+		got, _ := s.QueryAccount(account, nil)
+		query, _ := s.Query(func(txn model.Transaction) bool { return txn.AccountID == account })
+		if !reflect.DeepEqual(ids(got), want) || !reflect.DeepEqual(ids(query), want) {
+			t.Errorf("%s: expected %v, got index %v, scan %v", account, want, ids(got), ids(query))
+		}
+	}
+
+	s.Reset()
+	if got, _ := s.QueryAccount("acct-1", nil); len(got) != 0 {
+		t.Errorf("expected empty index after Reset, got %v", ids(got))
+	}
+}
+
+// Test: TestCreate_withoutAccountIDReadsBack
+// What: data stored before account_id existed still reads back and is listed, just not under any account
+// Input: transaction with no AccountID stored directly
+// Output: Get and Query return it unchanged; QueryAccount("") returns nothing
+func TestCreate_withoutAccountIDReadsBack(t *testing.T) {
+	s := store.NewMemoryStore()
+	legacy := makeTxn("legacy", 100, "USD", jan(1))
+	if err := s.Create(legacy); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := s.Get("legacy")
+	if err != nil || !got.Equal(legacy) {
+		t.Errorf("expected legacy transaction back, got %+v (err %v)", got, err)
+	}
+	if all, _ := s.Query(nil); len(all) != 1 {
+		t.Errorf("expected legacy transaction in Query, got %v", ids(all))
+	}
+	if none, _ := s.QueryAccount("", nil); len(none) != 0 {
+		t.Errorf("expected no account index entry for legacy data, got %v", ids(none))
+	}
+}
@@ -0,0 +1,205 @@
+package store_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// seedBench fills a store with n transactions, each with a small metadata map so Clone has work to do.
+func seedBench(b *testing.B, n int) *store.MemoryStore {
+	b.Helper()
+	s := store.NewMemoryStore()
+	for i := 0; i < n; i++ {
+		txn := makeTxn(fmt.Sprintf("t%06d", i), int64(i), "USD", jan(1).Add(time.Duration(i)*time.Second))
+		txn.Metadata = map[string]string{"source": "bench", "index": fmt.Sprint(i)}
+		if err := s.Create(txn); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return s
+}
+
+// BenchmarkList_10k measures a full 10k-row List, including the per-row Clone.
+func BenchmarkList_10k(b *testing.B) {
+	s := seedBench(b, 10000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.List(10000, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkCreate_duringList measures how long a writer waits while readers continuously
+// List 10k rows. The time per op is dominated by how long List holds the read lock.
+func BenchmarkCreate_duringList(b *testing.B) {
+	s := seedBench(b, 10000)
+
+	var stop atomic.Bool
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for !stop.Load() {
+			_, _ = s.List(10000, 0)
+		}
+	}()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		txn := model.Transaction{ID: fmt.Sprintf("w%09d", i), Amount: 1, Currency: "USD", EffectiveAt: jan(2)}
+		if err := s.Create(txn); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+
+	stop.Store(true)
+	<-done
+}
+
+// benchStart and benchEnd bound a one-hour window in the middle of the 100k-row seed (one row per second).
+var benchStart, benchEnd = jan(1).Add(13 * time.Hour), jan(1).Add(14 * time.Hour)
+
+// BenchmarkListBetween_100k pages through a one-hour window using the binary-search fast path.
+func BenchmarkListBetween_100k(b *testing.B) {
+	s := seedBench(b, 100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.ListBetween(benchStart, benchEnd, 100, 0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkQueryDateRange_100k is the same page via a full-scan Query, for comparison
+// with BenchmarkListBetween_100k.
+func BenchmarkQueryDateRange_100k(b *testing.B) {
+	s := seedBench(b, 100000)
+	match := func(txn model.Transaction) bool {
+		return !txn.EffectiveAt.Before(benchStart) && !txn.EffectiveAt.After(benchEnd)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		matched, err := s.Query(match)
+		if err != nil {
+			b.Fatal(err)
+		}
+		_ = matched[:min(100, len(matched))]
+	}
+}
+
+// benchIngest creates the transactions into a fresh store per iteration, with effective_at
+// offsets (in seconds from jan(1)) taken from order. The "slice" sub-benchmark is the
+// baseline: it inserts the same transactions into a plain sorted slice the way the store's
+// index used to, with a binary search and a shift of everything after the insertion point. It
+// skips the store's maps and locking, so it flatters the slice. Out of order it is quadratic
+// and takes minutes at 100k, so -short skips it. The three orders below bracket the cost of
+// bulk ingestion.
+func benchIngest(b *testing.B, order []int) {
+	b.Helper()
+	txns := make([]model.Transaction, len(order))
+	for i, sec := range order {
+		txns[i] = makeTxn(fmt.Sprintf("t%06d", i), 100, "USD", jan(1).Add(time.Duration(sec)*time.Second))
+	}
+	b.Run("store", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			s := store.NewMemoryStore()
+			for _, txn := range txns {
+				if err := s.Create(txn); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("slice", func(b *testing.B) {
+		if testing.Short() {
+			b.Skip("sorted-slice baseline is slow; skipped with -short")
+		}
+		for i := 0; i < b.N; i++ {
+			var sorted []model.Transaction
+			for _, txn := range txns {
+				at, _ := slices.BinarySearchFunc(sorted, txn, func(a, b model.Transaction) int {
+					if model.LessByEffectiveAtThenID(a, b) {
+						return -1
+					}
+					if model.LessByEffectiveAtThenID(b, a) {
+						return 1
+					}
+					return 0
+				})
+				sorted = slices.Insert(sorted, at, txn)
+			}
+		}
+	})
+}
+
+// BenchmarkCreate_100kSequential ingests 100k transactions in effective_at order, the common
+// case for a live feed: every insert lands at the end of the last chunk, as it would at the
+// end of the slice.
+func BenchmarkCreate_100kSequential(b *testing.B) {
+	order := make([]int, 100000)
+	for i := range order {
+		order[i] = i
+	}
+	benchIngest(b, order)
+}
+
+// BenchmarkCreate_100kRandom ingests 100k transactions in shuffled effective_at order, e.g.
+// a backfill merged from several sources: the slice shifts half its length on average, the
+// index half a chunk.
+func BenchmarkCreate_100kRandom(b *testing.B) {
+	benchIngest(b, rand.New(rand.NewPCG(1, 2)).Perm(100000))
+}
+
+// BenchmarkCreate_100kReverse ingests 100k transactions newest first: the slice's worst case,
+// shifting its whole length on every insert, while the index shifts one chunk.
+func BenchmarkCreate_100kReverse(b *testing.B) {
+	order := make([]int, 100000)
+	for i := range order {
+		order[i] = len(order) - i
+	}
+	benchIngest(b, order)
+}
+
+// BenchmarkGet_parallel measures Get throughput from many goroutines while one writer keeps
+// updating, with the default RWMutex path and with EnableLockFreeGets.
+func BenchmarkGet_parallel(b *testing.B) {
+	for _, lockFree := range []bool{false, true} {
+		b.Run(fmt.Sprintf("lockFree=%t", lockFree), func(b *testing.B) {
+			s := seedBench(b, 10000)
+			if lockFree {
+				s.EnableLockFreeGets()
+			}
+
+			var stop atomic.Bool
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				for i := 0; !stop.Load(); i++ {
+					_ = s.UpdateAmount(fmt.Sprintf("t%06d", i%10000), int64(i))
+				}
+			}()
+
+			b.SetParallelism(8)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for i := 0; pb.Next(); i++ {
+					if _, err := s.Get(fmt.Sprintf("t%06d", i%10000)); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+			b.StopTimer()
+			stop.Store(true)
+			<-done
+		})
+	}
+}
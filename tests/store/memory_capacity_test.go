@@ -0,0 +1,72 @@
+package store_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestCapacity_rejectsNewIDsWhenFull
+// What: once the store holds its capacity, creating a new ID fails and nothing is stored
+// Input: capacity 2; create "a", "b", then "c"
+// Output: nil, nil, ErrCapacityExceeded; Count stays 2 and "c" is not found
+func TestCapacity_rejectsNewIDsWhenFull(t *testing.T) {
+	s := store.NewMemoryStoreWithCapacity(2)
+
+	if err := s.Create(makeTxn("a", 100, "USD", jan(1))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Create(makeTxn("b", 100, "USD", jan(2))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Create(makeTxn("c", 100, "USD", jan(3))); !errors.Is(err, store.ErrCapacityExceeded) {
+		t.Fatalf("expected ErrCapacityExceeded, got %v", err)
+	}
+
+	if s.Count() != 2 {
+		t.Errorf("expected 2 stored, got %d", s.Count())
+	}
+	if _, err := s.Get("c"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected c not to be stored, got %v", err)
+	}
+}
+
+// Test: TestCapacity_duplicatesAndConflictsStillReported
+// What: a full store still reports retries of stored IDs as duplicates or conflicts, not capacity
+// Input: capacity 1 holding "a"; create identical "a", then "a" with a different amount
+// Output: ErrDuplicate, then ErrConflict
+func TestCapacity_duplicatesAndConflictsStillReported(t *testing.T) {
+	s := store.NewMemoryStoreWithCapacity(1)
+	txn := makeTxn("a", 100, "USD", jan(1))
+	_ = s.Create(txn)
+
+	if err := s.Create(txn); !errors.Is(err, store.ErrDuplicate) {
+		t.Errorf("expected ErrDuplicate, got %v", err)
+	}
+	if err := s.Create(makeTxn("a", 999, "USD", jan(1))); !errors.Is(err, store.ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+}
+
+// Test: TestCapacity_upsertAndReverse
+// What: Upsert and Reverse also respect the cap, but Upsert can still replace a stored ID
+// Input: capacity 1 holding "a"; Upsert "a" with a new amount; Upsert "b"; Reverse "a"
+// Output: nil; ErrCapacityExceeded; ErrCapacityExceeded and "a" is not marked reversed
+func TestCapacity_upsertAndReverse(t *testing.T) {
+	s := store.NewMemoryStoreWithCapacity(1)
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+
+	if _, err := s.Upsert(makeTxn("a", 200, "USD", jan(1))); err != nil {
+		t.Errorf("expected replacing a to succeed, got %v", err)
+	}
+	if _, err := s.Upsert(makeTxn("b", 100, "USD", jan(2))); !errors.Is(err, store.ErrCapacityExceeded) {
+		t.Errorf("expected ErrCapacityExceeded from Upsert, got %v", err)
+	}
+	if err := s.Reverse("a", makeTxn("a-reversal", 200, "USD", jan(2))); !errors.Is(err, store.ErrCapacityExceeded) {
+		t.Errorf("expected ErrCapacityExceeded from Reverse, got %v", err)
+	}
+	if got, _ := s.Get("a"); got.Metadata["reversed_by"] != "" {
+		t.Errorf("expected a not to be marked reversed, got %v", got.Metadata)
+	}
+}
@@ -0,0 +1,112 @@
+package store_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestCompareAndSwap_success
+// What: CompareAndSwap replaces the stored transaction when it still equals the expected value
+// Input: store with "a" (amount=100); read it, swap in amount=200
+// Output: nil error, Get returns amount=200 with the original Seq
+func TestCompareAndSwap_success(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+
+	read, _ := s.Get("a")
+	updated := read
+	updated.Amount = 200
+
+	if err := s.CompareAndSwap("a", read, updated); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	got, _ := s.Get("a")
+	if got.Amount != 200 {
+		t.Errorf("expected amount 200, got %d", got.Amount)
+	}
+	if got.Seq != read.Seq {
+		t.Errorf("expected Seq to be preserved (%d), got %d", read.Seq, got.Seq)
+	}
+}
+
+// Test: TestCompareAndSwap_staleRead
+// What: CompareAndSwap fails when the stored value changed between the read and the swap
+// Input: two readers read "a"; the first swaps amount=200, the second tries to swap amount=300 from its stale read
+// Output: second swap returns ErrPreconditionFailed, stored amount stays 200
+func TestCompareAndSwap_staleRead(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+
+	readA, _ := s.Get("a")
+	readB, _ := s.Get("a")
+
+	first := readA
+	first.Amount = 200
+	if err := s.CompareAndSwap("a", readA, first); err != nil {
+		t.Fatalf("first swap: unexpected error %v", err)
+	}
+
+	second := readB
+	second.Amount = 300
+	if err := s.CompareAndSwap("a", readB, second); !errors.Is(err, store.ErrPreconditionFailed) {
+		t.Fatalf("expected ErrPreconditionFailed, got %v", err)
+	}
+
+	got, _ := s.Get("a")
+	if got.Amount != 200 {
+		t.Errorf("expected amount to remain 200, got %d", got.Amount)
+	}
+}
+
+// Test: TestCompareAndSwap_notFound
+// What: CompareAndSwap returns ErrNotFound for an unknown ID
+// Input: empty store, swap "missing"
+// Output: ErrNotFound
+func TestCompareAndSwap_notFound(t *testing.T) {
+	s := store.NewMemoryStore()
+	txn := makeTxn("missing", 100, "USD", jan(1))
+
+	if err := s.CompareAndSwap("missing", txn, txn); !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// Test: TestCompareAndSwap_idMismatch
+// What: CompareAndSwap refuses to store a replacement under a different ID
+// Input: store with "a"; swap "a" with a transaction whose ID is "b"
+// Output: ErrIDMismatch
+func TestCompareAndSwap_idMismatch(t *testing.T) {
+	s := store.NewMemoryStore()
+	a := makeTxn("a", 100, "USD", jan(1))
+	_ = s.Create(a)
+
+	if err := s.CompareAndSwap("a", a, makeTxn("b", 100, "USD", jan(1))); !errors.Is(err, store.ErrIDMismatch) {
+		t.Fatalf("expected ErrIDMismatch, got %v", err)
+	}
+}
+
+// Test: TestCompareAndSwap_keepsOrderOnEffectiveAtChange
+// What: moving a transaction's effective_at re-sorts it in List results
+// Input: store with a (jan 1), b (jan 2), c (jan 3); swap a to jan 5
+// Output: List returns [b, c, a]
+func TestCompareAndSwap_keepsOrderOnEffectiveAtChange(t *testing.T) {
+	s := store.NewMemoryStore()
+	a := makeTxn("a", 100, "USD", jan(1))
+	_ = s.Create(a)
+	_ = s.Create(makeTxn("b", 100, "USD", jan(2)))
+	_ = s.Create(makeTxn("c", 100, "USD", jan(3)))
+
+	moved := a
+	moved.EffectiveAt = jan(5)
+	if err := s.CompareAndSwap("a", a, moved); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	list, _ := s.List(10, 0)
+	if len(list) != 3 || list[0].ID != "b" || list[1].ID != "c" || list[2].ID != "a" {
+		t.Errorf("expected order [b c a], got %v", ids(list))
+	}
+}
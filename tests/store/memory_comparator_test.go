@@ -0,0 +1,71 @@
+package store_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestCreate_comparatorIgnoringMetadata
+// What: with a comparator that ignores metadata, a metadata-only difference is a duplicate and an amount difference is still a conflict
+// Input: store built with a comparator over everything but metadata; create "a" with metadata k=v, retry with k=w, then with a new amount
+// Output: ErrDuplicate (stored metadata kept), then ErrConflict
+func TestCreate_comparatorIgnoringMetadata(t *testing.T) {
+	s := store.NewMemoryStoreWithComparator(func(stored, submitted model.Transaction) bool {
+		stored.Metadata, submitted.Metadata = nil, nil
+		return stored.Equal(submitted)
+	})
+	original := makeTxn("a", 100, "USD", jan(1))
+	original.Metadata = map[string]string{"k": "v"}
+	_ = s.Create(original)
+
+	retry := makeTxn("a", 100, "USD", jan(1))
+	retry.Metadata = map[string]string{"k": "w"}
+	if err := s.Create(retry); !errors.Is(err, store.ErrDuplicate) {
+		t.Fatalf("expected ErrDuplicate, got %v", err)
+	}
+	if got, _ := s.Get("a"); got.Metadata["k"] != "v" {
+		t.Errorf("expected the stored metadata to be kept, got %v", got.Metadata)
+	}
+
+	if err := s.Create(makeTxn("a", 999, "USD", jan(1))); !errors.Is(err, store.ErrConflict) {
+		t.Errorf("expected ErrConflict for a different amount, got %v", err)
+	}
+}
+
+// Test: TestSignificantFields
+// What: a comparator over id, amount and currency treats effective_at and metadata differences as duplicates
+// Input: SignificantFields("id", "amount", "currency"); "a" created, then retried with a new date and metadata, then a new currency
+// Output: ErrDuplicate, then ErrConflict
+func TestSignificantFields(t *testing.T) {
+	same, err := store.SignificantFields("id", "amount", "currency")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := store.NewMemoryStoreWithComparator(same)
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+
+	moved := makeTxn("a", 100, "USD", jan(2))
+	moved.Metadata = map[string]string{"k": "v"}
+	if err := s.Create(moved); !errors.Is(err, store.ErrDuplicate) {
+		t.Errorf("expected ErrDuplicate, got %v", err)
+	}
+	if err := s.Create(makeTxn("a", 100, "EUR", jan(1))); !errors.Is(err, store.ErrConflict) {
+		t.Errorf("expected ErrConflict for a different currency, got %v", err)
+	}
+}
+
+// Test: TestSignificantFields_defaultsAndErrors
+// What: no names keeps the full comparison (nil comparator); an unknown name is rejected
+// Input: SignificantFields(); SignificantFields("amount", "colour")
+// Output: nil comparator and nil error; an error naming "colour"
+func TestSignificantFields_defaultsAndErrors(t *testing.T) {
+	if same, err := store.SignificantFields(); same != nil || err != nil {
+		t.Errorf("expected a nil comparator and no error, got %v", err)
+	}
+	if _, err := store.SignificantFields("amount", "colour"); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
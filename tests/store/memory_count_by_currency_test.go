@@ -0,0 +1,68 @@
+package store_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestCountByCurrency
+// What: CountByCurrency groups by uppercased currency and honors the effective_at bounds
+// Input: USD on days 1 and 3, usd on day 2, EUR on day 2; open range, then days 2..2
+// Output: {USD:3, EUR:1}, then {USD:1, EUR:1}
+func TestCountByCurrency(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+	_ = s.Create(makeTxn("b", 100, "usd", jan(2)))
+	_ = s.Create(makeTxn("c", 100, "EUR", jan(2)))
+	_ = s.Create(makeTxn("d", 100, "USD", jan(3)))
+
+	all, _ := s.CountByCurrency(time.Time{}, time.Time{})
+	if len(all) != 2 || all["USD"] != 3 || all["EUR"] != 1 {
+		t.Errorf("expected {USD:3 EUR:1}, got %v", all)
+	}
+
+	day2, _ := s.CountByCurrency(jan(2), jan(2))
+	if len(day2) != 2 || day2["USD"] != 1 || day2["EUR"] != 1 {
+		t.Errorf("expected {USD:1 EUR:1}, got %v", day2)
+	}
+}
+
+// Test: TestCountByCurrency_emptyRange
+// What: an empty store, or a range with no transactions, returns an empty non-nil map
+// Input: empty store; then one transaction and an inverted range
+// Output: empty map both times
+func TestCountByCurrency_emptyRange(t *testing.T) {
+	s := store.NewMemoryStore()
+	if got, _ := s.CountByCurrency(time.Time{}, time.Time{}); got == nil || len(got) != 0 {
+		t.Errorf("expected empty map, got %#v", got)
+	}
+
+	_ = s.Create(makeTxn("a", 100, "USD", jan(2)))
+	if got, _ := s.CountByCurrency(jan(3), jan(1)); len(got) != 0 {
+		t.Errorf("expected empty map for an inverted range, got %v", got)
+	}
+}
+
+// Test: TestDistinctCurrencies
+// What: DistinctCurrencies returns uppercased codes sorted and deduplicated, skipping soft-deleted transactions
+// Input: USD, usd, EUR, JPY, and a soft-deleted GBP; then an empty store
+// Output: [EUR JPY USD]; then an empty non-nil slice
+func TestDistinctCurrencies(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+	_ = s.Create(makeTxn("b", 100, "usd", jan(2)))
+	_ = s.Create(makeTxn("c", 100, "EUR", jan(2)))
+	_ = s.Create(makeTxn("d", 100, "JPY", jan(3)))
+	_ = s.Create(makeTxn("e", 100, "GBP", jan(3)))
+	_ = s.Delete("e")
+
+	if got := s.DistinctCurrencies(); !reflect.DeepEqual(got, []string{"EUR", "JPY", "USD"}) {
+		t.Errorf("expected [EUR JPY USD], got %v", got)
+	}
+	if got := store.NewMemoryStore().DistinctCurrencies(); got == nil || len(got) != 0 {
+		t.Errorf("expected an empty slice, got %#v", got)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/synctera/tech-challenge/internal/model"
 	"github.com/synctera/tech-challenge/internal/store"
 )
 
@@ -163,6 +164,68 @@ func TestCreate_concurrent(t *testing.T) {
 	}
 }
 
+// raceCreate creates a and b on a fresh store from two goroutines released together, and
+// returns the store and both results.
+func raceCreate(a, b model.Transaction) (*store.MemoryStore, [2]error) {
+	s := store.NewMemoryStore()
+	var errs [2]error
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i, txn := range []model.Transaction{a, b} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			errs[i] = s.Create(txn)
+		}()
+	}
+	close(start)
+	wg.Wait()
+	return s, errs
+}
+
+// Test: TestCreate_concurrentConflictingPayloads
+// What: two racing creates of one ID with different payloads always end with one winner and one
+// ErrConflict (never ErrDuplicate), and the stored transaction is the winner's
+// Input: 200 rounds, each racing id="race" amount=100 against id="race" amount=200 on a fresh store
+// Output: every round has exactly one nil and one ErrConflict; Get returns the winner's amount
+func TestCreate_concurrentConflictingPayloads(t *testing.T) {
+	for round := 0; round < 200; round++ {
+		a, b := makeTxn("race", 100, "USD", jan(1)), makeTxn("race", 200, "USD", jan(1))
+		s, errs := raceCreate(a, b)
+
+		winner := a
+		switch {
+		case errs[0] == nil && errors.Is(errs[1], store.ErrConflict):
+		case errs[1] == nil && errors.Is(errs[0], store.ErrConflict):
+			winner = b
+		default:
+			t.Fatalf("round %d: expected one nil and one ErrConflict, got %v and %v", round, errs[0], errs[1])
+		}
+		if got, _ := s.Get("race"); got.Amount != winner.Amount {
+			t.Fatalf("round %d: expected the winner's amount %d to be stored, got %d", round, winner.Amount, got.Amount)
+		}
+	}
+}
+
+// Test: TestCreate_concurrentIdenticalPayloads
+// What: two racing creates of one ID with identical payloads end with one success and one ErrDuplicate
+// Input: 200 rounds, each racing two identical id="race" creates on a fresh store
+// Output: every round has exactly one nil and one ErrDuplicate, and one transaction is stored
+func TestCreate_concurrentIdenticalPayloads(t *testing.T) {
+	for round := 0; round < 200; round++ {
+		txn := makeTxn("race", 100, "USD", jan(1))
+		s, errs := raceCreate(txn, txn)
+
+		if !(errs[0] == nil && errors.Is(errs[1], store.ErrDuplicate)) && !(errs[1] == nil && errors.Is(errs[0], store.ErrDuplicate)) {
+			t.Fatalf("round %d: expected one nil and one ErrDuplicate, got %v and %v", round, errs[0], errs[1])
+		}
+		if s.Count() != 1 {
+			t.Fatalf("round %d: expected 1 stored transaction, got %d", round, s.Count())
+		}
+	}
+}
+
 // Test: TestCreate_doesNotStoreOnConflict
 // What: Create does not overwrite the stored transaction when a conflicting request is rejected
 // Input: original (id="txn-1", amount=100), then conflicting (id="txn-1", amount=999)
@@ -199,3 +262,106 @@ func TestCreate_doesNotShareMetadataReference(t *testing.T) {
 		t.Error("Create should clone the transaction; mutating caller's Metadata after Create should not affect the store")
 	}
 }
+
+// Test: TestCreate_assignsInsertionSeq
+// What: Create assigns an increasing Seq in insertion order, independent of effective_at
+// Input: create "late" (jan 10), then "early" (jan 1), then a duplicate of "late"
+// Output: late.Seq=1, early.Seq=2; the duplicate does not consume a sequence number
+func TestCreate_assignsInsertionSeq(t *testing.T) {
+	s := store.NewMemoryStore()
+	late := makeTxn("late", 100, "USD", jan(10))
+	_ = s.Create(late)
+	_ = s.Create(makeTxn("early", 100, "USD", jan(1)))
+	_ = s.Create(late)
+
+	gotLate, _ := s.Get("late")
+	gotEarly, _ := s.Get("early")
+	if gotLate.Seq != 1 || gotEarly.Seq != 2 {
+		t.Errorf("expected seq late=1 early=2, got late=%d early=%d", gotLate.Seq, gotEarly.Seq)
+	}
+}
+
+// Test: TestCreate_ignoresClientSeq
+// What: a Seq supplied by the caller is overwritten with the store's own sequence
+// Input: transaction with Seq=99 created into an empty store
+// Output: stored Seq=1
+func TestCreate_ignoresClientSeq(t *testing.T) {
+	s := store.NewMemoryStore()
+	txn := makeTxn("a", 100, "USD", jan(1))
+	txn.Seq = 99
+	_ = s.Create(txn)
+
+	got, _ := s.Get("a")
+	if got.Seq != 1 {
+		t.Errorf("expected Seq=1, got %d", got.Seq)
+	}
+}
+
+// Test: TestCreate_duplicateByContentHash
+// What: the duplicate check compares content hashes, so representation differences don't matter
+// Input: create with metadata {a,b} at 2024-01-01T00:00Z; retry with the same metadata built in the
+// other order and the same instant expressed at -05:00
+// Output: ErrDuplicate
+func TestCreate_duplicateByContentHash(t *testing.T) {
+	s := store.NewMemoryStore()
+	original := makeTxn("txn-1", 100, "USD", jan(1))
+	original.Metadata = map[string]string{"a": "1", "b": "2"}
+	_ = s.Create(original)
+
+	retry := makeTxn("txn-1", 100, "USD", jan(1).In(time.FixedZone("EST", -5*60*60)))
+	retry.Metadata = map[string]string{"b": "2", "a": "1"}
+	if err := s.Create(retry); !errors.Is(err, store.ErrDuplicate) {
+		t.Fatalf("expected ErrDuplicate, got %v", err)
+	}
+}
+
+// Test: TestCreate_contentHashFollowsMetadataUpdates
+// What: the stored hash is refreshed when metadata is patched
+// Input: create with {k:v}, UpdateMetadata to {k:w}; retry the original, then the patched version
+// Output: ErrConflict for the original, ErrDuplicate for the patched version
+func TestCreate_contentHashFollowsMetadataUpdates(t *testing.T) {
+	s := store.NewMemoryStore()
+	original := makeTxn("txn-1", 100, "USD", jan(1))
+	original.Metadata = map[string]string{"k": "v"}
+	_ = s.Create(original)
+
+	w := "w"
+	_ = s.UpdateMetadata("txn-1", map[string]*string{"k": &w})
+
+	if err := s.Create(original); !errors.Is(err, store.ErrConflict) {
+		t.Errorf("expected ErrConflict for the pre-patch payload, got %v", err)
+	}
+	patched := original.Clone()
+	patched.Metadata["k"] = "w"
+	if err := s.Create(patched); !errors.Is(err, store.ErrDuplicate) {
+		t.Errorf("expected ErrDuplicate for the patched payload, got %v", err)
+	}
+}
+
+// Test: TestCreate_conflictErrorCarriesExisting
+// What: a conflicting create returns a *ConflictError that matches ErrConflict and holds a copy of the stored transaction
+// Input: create "a" with amount 1000 and metadata, then "a" with amount 9999; mutate the returned copy's metadata
+// Output: errors.Is ErrConflict; errors.As gives Existing with amount 1000; the stored metadata is unchanged
+func TestCreate_conflictErrorCarriesExisting(t *testing.T) {
+	s := store.NewMemoryStore()
+	original := makeTxn("a", 1000, "USD", jan(1))
+	original.Metadata = map[string]string{"k": "v"}
+	_ = s.Create(original)
+
+	err := s.Create(makeTxn("a", 9999, "USD", jan(1)))
+	if !errors.Is(err, store.ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	var conflict *store.ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("expected a *ConflictError, got %T", err)
+	}
+	if conflict.Existing.Amount != 1000 || conflict.Existing.Seq != 1 {
+		t.Errorf("expected the stored transaction, got %+v", conflict.Existing)
+	}
+
+	conflict.Existing.Metadata["k"] = "changed"
+	if got, _ := s.Get("a"); got.Metadata["k"] != "v" {
+		t.Errorf("expected the store's copy to be isolated, got %q", got.Metadata["k"])
+	}
+}
@@ -0,0 +1,51 @@
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/clock"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestCreate_assignsCreatedAt
+// What: Create stamps CreatedAt from the store clock in UTC, ignoring any client-supplied value
+// Input: fake clock at 2024-06-01 12:00 +02:00; transaction with CreatedAt set to 1999
+// Output: stored CreatedAt equals the clock time, in UTC
+func TestCreate_assignsCreatedAt(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	s := store.NewMemoryStoreWithClock(clock.NewFake(now))
+
+	txn := makeTxn("a", 100, "USD", jan(1))
+	txn.CreatedAt = time.Date(1999, 1, 1, 0, 0, 0, 0, time.UTC)
+	_ = s.Create(txn)
+
+	got, _ := s.Get("a")
+	if !got.CreatedAt.Equal(now) || got.CreatedAt.Location() != time.UTC {
+		t.Errorf("expected CreatedAt %v in UTC, got %v", now.UTC(), got.CreatedAt)
+	}
+}
+
+// Test: TestCreate_duplicateKeepsCreatedAt
+// What: an idempotent duplicate does not restamp CreatedAt, and neither do metadata updates
+// Input: create at t0, advance the clock, create the same transaction again, then UpdateMetadata
+// Output: ErrDuplicate; CreatedAt stays t0 throughout
+func TestCreate_duplicateKeepsCreatedAt(t *testing.T) {
+	t0 := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(t0)
+	s := store.NewMemoryStoreWithClock(clk)
+	txn := makeTxn("a", 100, "USD", jan(1))
+	_ = s.Create(txn)
+
+	clk.Advance(time.Hour)
+	if err := s.Create(txn); err != store.ErrDuplicate {
+		t.Fatalf("expected ErrDuplicate, got %v", err)
+	}
+	v := "x"
+	_ = s.UpdateMetadata("a", map[string]*string{"k": &v})
+
+	got, _ := s.Get("a")
+	if !got.CreatedAt.Equal(t0) {
+		t.Errorf("expected CreatedAt to stay %v, got %v", t0, got.CreatedAt)
+	}
+}
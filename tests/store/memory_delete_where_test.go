@@ -0,0 +1,74 @@
+package store_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestDeleteWhere_removesMatches
+// What: DeleteWhere removes matching transactions from every index and drops their Idempotency-Keys
+// Input: a1 (day 1), a2 (day 3) in acct-1, b1 (day 2) in acct-2; key k1 -> a1; delete effective_at before day 3
+// Output: 2 deleted; List is [a2], QueryAccount(acct-2) is empty, Get(a1) and k1 are ErrNotFound
+func TestDeleteWhere_removesMatches(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeAccountTxn("a1", "acct-1", 1))
+	_ = s.Create(makeAccountTxn("a2", "acct-1", 3))
+	_ = s.Create(makeAccountTxn("b1", "acct-2", 2))
+	_ = s.PutIdempotencyKey("k1", "a1")
+
+	n, err := s.DeleteWhere(func(txn model.Transaction) bool { return txn.EffectiveAt.Before(jan(3)) })
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 deleted, got %d, %v", n, err)
+	}
+
+	all, _ := s.List(10, 0)
+	if got := ids(all); len(got) != 1 || got[0] != "a2" {
+		t.Errorf("expected [a2], got %v", got)
+	}
+	if acct, _ := s.QueryAccount("acct-2", nil); len(acct) != 0 {
+		t.Errorf("expected acct-2 to be empty, got %v", ids(acct))
+	}
+	if acct, _ := s.QueryAccount("acct-1", nil); len(acct) != 1 || acct[0].ID != "a2" {
+		t.Errorf("expected acct-1 to hold [a2], got %v", ids(acct))
+	}
+	if _, err := s.Get("a1"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected a1 to be deleted, got %v", err)
+	}
+	if _, err := s.GetByIdempotencyKey("k1"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected k1 to be deleted, got %v", err)
+	}
+}
+
+// Test: TestDeleteWhere_nilMatchDeletesNothing
+// What: a nil predicate is a no-op rather than a full wipe
+// Input: 2 transactions, DeleteWhere(nil)
+// Output: 0 deleted, Count still 2
+func TestDeleteWhere_nilMatchDeletesNothing(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+	_ = s.Create(makeTxn("b", 100, "USD", jan(2)))
+
+	if n, err := s.DeleteWhere(nil); err != nil || n != 0 {
+		t.Errorf("expected 0 deleted, got %d, %v", n, err)
+	}
+	if s.Count() != 2 {
+		t.Errorf("expected Count 2, got %d", s.Count())
+	}
+}
+
+// Test: TestDeleteWhere_idCanBeReused
+// What: a deleted ID no longer blocks a create with different data
+// Input: create "a", delete it, create "a" with a different amount
+// Output: second create succeeds
+func TestDeleteWhere_idCanBeReused(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+	_, _ = s.DeleteWhere(func(model.Transaction) bool { return true })
+
+	if err := s.Create(makeTxn("a", 200, "USD", jan(1))); err != nil {
+		t.Errorf("expected create after delete to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package store_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestCreate_descriptionIdempotency
+// What: by default a retry with a different description conflicts; with ExcludeDescriptionFromIdempotency it is a duplicate that keeps the stored description
+// Input: "a" with description "Coffee", then "a" with description "COFFEE SHOP", on a default store and on an excluding store
+// Output: ErrConflict; ErrDuplicate and the stored description is still "Coffee"
+func TestCreate_descriptionIdempotency(t *testing.T) {
+	original := makeTxn("a", 100, "USD", jan(1))
+	original.Description = "Coffee"
+	retry := original
+	retry.Description = "COFFEE SHOP"
+
+	strict := store.NewMemoryStore()
+	_ = strict.Create(original)
+	if err := strict.Create(retry); !errors.Is(err, store.ErrConflict) {
+		t.Errorf("default store: expected ErrConflict, got %v", err)
+	}
+
+	lenient := store.NewMemoryStore()
+	lenient.ExcludeDescriptionFromIdempotency()
+	_ = lenient.Create(original)
+	if err := lenient.Create(retry); !errors.Is(err, store.ErrDuplicate) {
+		t.Errorf("excluding store: expected ErrDuplicate, got %v", err)
+	}
+	if got, _ := lenient.Get("a"); got.Description != "Coffee" {
+		t.Errorf("expected the stored description to be kept, got %q", got.Description)
+	}
+}
@@ -0,0 +1,153 @@
+package store_test
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestExportImport_roundTrip
+// What: exporting a store and importing the file into a fresh store reproduces its data and order
+// Input: b (metadata, tags), a (earlier effective_at), c soft-deleted; ExportTo, then ImportFrom into a new store
+// Output: one line per transaction in list order [a, b, c]; 3 created; each imported transaction
+// Equals the original, c is still deleted, and List returns the same order
+func TestExportImport_roundTrip(t *testing.T) {
+	src := store.NewMemoryStore()
+	b := makeTxn("b", 200, "EUR", jan(2))
+	b.Metadata = map[string]string{"source": "web"}
+	b.Tags = []string{"payroll"}
+	_ = src.Create(b)
+	_ = src.Create(makeTxn("a", 100, "USD", jan(1)))
+	_ = src.Create(makeTxn("c", 300, "USD", jan(3)))
+	_ = src.Delete("c")
+
+	var buf bytes.Buffer
+	if err := src.ExportTo(&buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 3 || !strings.Contains(lines[0], `"id":"a"`) {
+		t.Fatalf("expected 3 lines starting with a, got %q", buf.String())
+	}
+
+	dst := store.NewMemoryStore()
+	n, err := dst.ImportFrom(&buf)
+	if err != nil || n != 3 {
+		t.Fatalf("expected 3 created and no error, got %d, %v", n, err)
+	}
+
+	for _, id := range []string{"a", "b", "c"} {
+		want, _ := src.Get(id)
+		got, err := dst.Get(id)
+		if err != nil || !got.Equal(want) || got.Deleted != want.Deleted {
+			t.Errorf("%s: expected %+v, got %+v (%v)", id, want, got, err)
+		}
+	}
+	srcList, _ := src.Query(nil)
+	dstList, _ := dst.Query(nil)
+	if !reflect.DeepEqual(ids(dstList), ids(srcList)) {
+		t.Errorf("expected order %v, got %v", ids(srcList), ids(dstList))
+	}
+}
+
+// Test: TestImportFrom_reimportIsSafe
+// What: importing the same file twice creates nothing the second time and returns no error
+// Input: two JSON lines imported, then imported again
+// Output: 2 created, then 0 created; Count stays 2
+func TestImportFrom_reimportIsSafe(t *testing.T) {
+	data := `{"id":"a","amount":100,"currency":"USD","effective_at":"2024-01-01T00:00:00Z"}
+{"id":"b","amount":200,"currency":"USD","effective_at":"2024-01-02T00:00:00Z"}
+`
+	s := store.NewMemoryStore()
+	if n, err := s.ImportFrom(strings.NewReader(data)); err != nil || n != 2 {
+		t.Fatalf("first import: expected 2 created, got %d, %v", n, err)
+	}
+	if n, err := s.ImportFrom(strings.NewReader(data)); err != nil || n != 0 {
+		t.Errorf("second import: expected 0 created and no error, got %d, %v", n, err)
+	}
+	if s.Count() != 2 {
+		t.Errorf("expected 2 stored, got %d", s.Count())
+	}
+}
+
+// Test: TestImportFrom_stopsAtBadRecord
+// What: a conflicting or malformed record stops the import with its record number; earlier records stay
+// Input: a store holding "a"; import [b, a with a different amount, c], and separately [d, not json]
+// Output: ErrConflict naming record 2 after creating b (c is not created); a decode error naming record 2 after creating d
+func TestImportFrom_stopsAtBadRecord(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+
+	n, err := s.ImportFrom(strings.NewReader(`{"id":"b","amount":1,"currency":"USD","effective_at":"2024-01-02T00:00:00Z"}
+{"id":"a","amount":999,"currency":"USD","effective_at":"2024-01-01T00:00:00Z"}
+{"id":"c","amount":1,"currency":"USD","effective_at":"2024-01-03T00:00:00Z"}`))
+	if !errors.Is(err, store.ErrConflict) || !strings.Contains(err.Error(), "record 2") || n != 1 {
+		t.Errorf("expected ErrConflict at record 2 after 1 created, got %d, %v", n, err)
+	}
+	if _, err := s.Get("c"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected c not to be imported, got %v", err)
+	}
+
+	n, err = s.ImportFrom(strings.NewReader(`{"id":"d","amount":1,"currency":"USD","effective_at":"2024-01-04T00:00:00Z"}
+not json`))
+	if err == nil || !strings.Contains(err.Error(), "record 2") || n != 1 {
+		t.Errorf("expected a decode error at record 2 after 1 created, got %d, %v", n, err)
+	}
+}
+
+// Test: TestFileStore_importFromSaves
+// What: a FileStore import is written to its snapshot
+// Input: FileStore at <tmp>/txns.json; ImportFrom two records; reopen the path
+// Output: the reopened store holds both transactions
+func TestFileStore_importFromSaves(t *testing.T) {
+	dsn := "file://" + filepath.Join(t.TempDir(), "txns.json")
+	s, _ := store.Open(dsn)
+	fs := s.(*store.FileStore)
+	if _, err := fs.ImportFrom(strings.NewReader(`{"id":"a","amount":1,"currency":"USD","effective_at":"2024-01-01T00:00:00Z"}
+{"id":"b","amount":2,"currency":"USD","effective_at":"2024-01-02T00:00:00Z"}`)); err != nil {
+		t.Fatalf("ImportFrom failed: %v", err)
+	}
+
+	reopened, err := store.Open(dsn)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	if reopened.Count() != 2 {
+		t.Errorf("expected 2 transactions after reopening, got %d", reopened.Count())
+	}
+}
+
+// Test: TestExportTo_keepsSubmittedOffset
+// What: an export writes effective_at in the offset it was submitted with, and ImportFrom stores
+// it back in UTC with the same time_zone
+// Input: txn-1 at 12:00Z with time_zone -05:00; ExportTo, then ImportFrom into a fresh store
+// Output: the line has effective_at 07:00:00-05:00; the imported transaction is at 12:00 UTC
+// with time_zone -05:00
+func TestExportTo_keepsSubmittedOffset(t *testing.T) {
+	src := store.NewMemoryStore()
+	txn := makeTxn("txn-1", 100, "USD", jan(1).Add(12*time.Hour))
+	txn.TimeZone = "-05:00"
+	_ = src.Create(txn)
+
+	var buf bytes.Buffer
+	if err := src.ExportTo(&buf); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"effective_at":"2024-01-01T07:00:00-05:00"`) {
+		t.Errorf("expected effective_at in -05:00, got %q", buf.String())
+	}
+
+	dst := store.NewMemoryStore()
+	if _, err := dst.ImportFrom(&buf); err != nil {
+		t.Fatalf("ImportFrom failed: %v", err)
+	}
+	got, _ := dst.Get("txn-1")
+	if !got.EffectiveAt.Equal(txn.EffectiveAt) || got.EffectiveAt.Location() != time.UTC || got.TimeZone != "-05:00" {
+		t.Errorf("expected %v in -05:00, got %v in %q", txn.EffectiveAt, got.EffectiveAt, got.TimeZone)
+	}
+}
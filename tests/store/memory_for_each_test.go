@@ -0,0 +1,94 @@
+package store_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestForEach_visitsAllInOrder
+// What: ForEach visits every transaction once, in (effective_at, id) order, across several read chunks
+// Input: 600 transactions created in reverse time order, one soft-deleted
+// Output: 600 visits, sorted, the deleted one included
+func TestForEach_visitsAllInOrder(t *testing.T) {
+	s := store.NewMemoryStore()
+	base := jan(1)
+	for i := 599; i >= 0; i-- {
+		_ = s.Create(makeTxn(fmt.Sprintf("txn-%03d", i), 100, "USD", base.Add(time.Duration(i)*time.Minute)))
+	}
+	_ = s.Delete("txn-300")
+
+	var visited []model.Transaction
+	err := s.ForEach(func(txn model.Transaction) bool {
+		visited = append(visited, txn)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(visited) != 600 {
+		t.Fatalf("expected 600 visits, got %d", len(visited))
+	}
+	for i, txn := range visited {
+		if want := fmt.Sprintf("txn-%03d", i); txn.ID != want {
+			t.Fatalf("visit %d: expected %s, got %s", i, want, txn.ID)
+		}
+	}
+	if !visited[300].Deleted {
+		t.Errorf("expected txn-300 to be visited as deleted")
+	}
+}
+
+// Test: TestForEach_stopsEarly
+// What: ForEach stops as soon as fn returns false
+// Input: 10 transactions; fn returns false on the 3rd
+// Output: 3 calls, nil error
+func TestForEach_stopsEarly(t *testing.T) {
+	s := store.NewMemoryStore()
+	for i := 1; i <= 10; i++ {
+		_ = s.Create(makeTxn(fmt.Sprintf("txn-%02d", i), 100, "USD", jan(i)))
+	}
+
+	calls := 0
+	err := s.ForEach(func(model.Transaction) bool {
+		calls++
+		return calls < 3
+	})
+
+	if err != nil || calls != 3 {
+		t.Errorf("expected 3 calls and nil error, got %d calls, %v", calls, err)
+	}
+}
+
+// Test: TestForEach_fnCanWrite
+// What: fn runs without the store lock held, so it may write to the store, and gets clones
+// Input: 2 transactions; fn creates a later transaction and modifies the metadata it was given
+// Output: no deadlock; the created transaction is visited; stored metadata is unchanged
+func TestForEach_fnCanWrite(t *testing.T) {
+	s := store.NewMemoryStore()
+	first := makeTxn("a", 100, "USD", jan(1))
+	first.Metadata = map[string]string{"k": "v"}
+	_ = s.Create(first)
+	_ = s.Create(makeTxn("b", 100, "USD", jan(2)))
+
+	var visited []string
+	s.ForEach(func(txn model.Transaction) bool {
+		visited = append(visited, txn.ID)
+		if txn.ID == "a" {
+			txn.Metadata["k"] = "changed"
+			_ = s.Create(makeTxn("c", 100, "USD", jan(3)))
+		}
+		return true
+	})
+
+	if fmt.Sprint(visited) != "[a b c]" {
+		t.Errorf("expected [a b c], got %v", visited)
+	}
+	if got, _ := s.Get("a"); got.Metadata["k"] != "v" {
+		t.Errorf("expected stored metadata to be unchanged, got %v", got.Metadata)
+	}
+}
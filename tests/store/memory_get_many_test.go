@@ -0,0 +1,66 @@
+package store_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestGetMany_allFound
+// What: GetMany returns every requested transaction in request order
+// Input: store with a, b, c; request [c, a]
+// Output: found [c, a], missing empty, nil error
+func TestGetMany_allFound(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+	_ = s.Create(makeTxn("b", 200, "USD", jan(2)))
+	_ = s.Create(makeTxn("c", 300, "USD", jan(3)))
+
+	found, missing, err := s.GetMany([]string{"c", "a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ids(found), []string{"c", "a"}) {
+		t.Errorf("expected found [c a], got %v", ids(found))
+	}
+	if len(missing) != 0 {
+		t.Errorf("expected no missing IDs, got %v", missing)
+	}
+}
+
+// Test: TestGetMany_partial
+// What: unknown IDs are reported as missing; repeated IDs are returned once
+// Input: store with a; request [a, x, a, x]
+// Output: found [a], missing [x]
+func TestGetMany_partial(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+
+	found, missing, _ := s.GetMany([]string{"a", "x", "a", "x"})
+	if !reflect.DeepEqual(ids(found), []string{"a"}) {
+		t.Errorf("expected found [a], got %v", ids(found))
+	}
+	if !reflect.DeepEqual(missing, []string{"x"}) {
+		t.Errorf("expected missing [x], got %v", missing)
+	}
+}
+
+// Test: TestGetMany_returnsCopies
+// What: mutating returned metadata does not affect the store
+// Input: txn with metadata, modify the map returned by GetMany
+// Output: a later Get still sees the original metadata
+func TestGetMany_returnsCopies(t *testing.T) {
+	s := store.NewMemoryStore()
+	txn := makeTxn("a", 100, "USD", jan(1))
+	txn.Metadata = map[string]string{"k": "v"}
+	_ = s.Create(txn)
+
+	found, _, _ := s.GetMany([]string{"a"})
+	found[0].Metadata["k"] = "changed"
+
+	stored, _ := s.Get("a")
+	if stored.Metadata["k"] != "v" {
+		t.Errorf("expected store metadata to be unchanged, got %q", stored.Metadata["k"])
+	}
+}
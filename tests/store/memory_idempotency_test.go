@@ -0,0 +1,127 @@
+package store_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestIdempotencyKey_unknownKey
+// What: GetByIdempotencyKey returns ErrNotFound for a key that was never recorded
+// Input: empty store, lookup "key-1"
+// Output: ErrNotFound
+func TestIdempotencyKey_unknownKey(t *testing.T) {
+	s := store.NewMemoryStore()
+
+	_, err := s.GetByIdempotencyKey("key-1")
+	if !errors.Is(err, store.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// Test: TestIdempotencyKey_putThenGet
+// What: a recorded key maps back to its transaction ID, and re-putting the same pair is a no-op
+// Input: Put("key-1","txn-1") twice, then Get("key-1")
+// Output: both puts return nil, Get returns "txn-1"
+func TestIdempotencyKey_putThenGet(t *testing.T) {
+	s := store.NewMemoryStore()
+
+	if err := s.PutIdempotencyKey("key-1", "txn-1"); err != nil {
+		t.Fatalf("first put: unexpected error %v", err)
+	}
+	if err := s.PutIdempotencyKey("key-1", "txn-1"); err != nil {
+		t.Fatalf("repeat put: unexpected error %v", err)
+	}
+
+	id, err := s.GetByIdempotencyKey("key-1")
+	if err != nil || id != "txn-1" {
+		t.Errorf("expected txn-1, nil; got %q, %v", id, err)
+	}
+}
+
+// Test: TestIdempotencyKey_rebindConflict
+// What: a key already bound to one transaction cannot be rebound to another
+// Input: Put("key-1","txn-1"), then Put("key-1","txn-2")
+// Output: ErrConflict, and the key still maps to "txn-1"
+func TestIdempotencyKey_rebindConflict(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.PutIdempotencyKey("key-1", "txn-1")
+
+	if err := s.PutIdempotencyKey("key-1", "txn-2"); !errors.Is(err, store.ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if id, _ := s.GetByIdempotencyKey("key-1"); id != "txn-1" {
+		t.Errorf("expected key to still map to txn-1, got %q", id)
+	}
+}
+
+// Test: TestCreateWithIdempotencyKey_replayAndReuse
+// What: a key bound by a write replays the stored copy for the same ID and is refused for
+// another ID, which is not stored
+// Input: CreateWithIdempotencyKey("key-1", txn-1 amount 100), then with txn-1 amount 200, then
+// with txn-2
+// Output: created then a *ReplayError holding amount 100, then ErrIdempotencyKeyReused; txn-2
+// is not stored
+func TestCreateWithIdempotencyKey_replayAndReuse(t *testing.T) {
+	s := store.NewMemoryStore()
+
+	if created, err := s.CreateWithIdempotencyKey("key-1", makeTxn("txn-1", 100, "USD", jan(1)), false); err != nil || !created {
+		t.Fatalf("first write: expected created, got %v, %v", created, err)
+	}
+
+	_, err := s.CreateWithIdempotencyKey("key-1", makeTxn("txn-1", 200, "USD", jan(1)), false)
+	var replay *store.ReplayError
+	if !errors.As(err, &replay) || !errors.Is(err, store.ErrIdempotentReplay) || replay.Original.Amount != 100 {
+		t.Errorf("retry: expected a replay of amount 100, got %v", err)
+	}
+
+	if _, err := s.CreateWithIdempotencyKey("key-1", makeTxn("txn-2", 100, "USD", jan(1)), false); !errors.Is(err, store.ErrIdempotencyKeyReused) {
+		t.Errorf("other ID: expected ErrIdempotencyKeyReused, got %v", err)
+	}
+	if _, err := s.Get("txn-2"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected txn-2 not stored, got %v", err)
+	}
+}
+
+// Test: TestCreateWithIdempotencyKey_concurrentDifferentIDs
+// What: requests racing with one key and different IDs never both write
+// Input: 100 rounds, each racing txn-a and txn-b with "key-1" on a fresh store
+// Output: every round has exactly one nil and one ErrIdempotencyKeyReused, only the winner is
+// stored, and the key is bound to it
+func TestCreateWithIdempotencyKey_concurrentDifferentIDs(t *testing.T) {
+	for round := 0; round < 100; round++ {
+		s := store.NewMemoryStore()
+		txns := []model.Transaction{makeTxn("txn-a", 100, "USD", jan(1)), makeTxn("txn-b", 100, "USD", jan(1))}
+		var errs [2]error
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		for i, txn := range txns {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				_, errs[i] = s.CreateWithIdempotencyKey("key-1", txn, false)
+			}()
+		}
+		close(start)
+		wg.Wait()
+
+		winner, loser := txns[0], txns[1]
+		switch {
+		case errs[0] == nil && errors.Is(errs[1], store.ErrIdempotencyKeyReused):
+		case errs[1] == nil && errors.Is(errs[0], store.ErrIdempotencyKeyReused):
+			winner, loser = loser, winner
+		default:
+			t.Fatalf("round %d: expected one nil and one ErrIdempotencyKeyReused, got %v", round, errs)
+		}
+		if _, err := s.Get(loser.ID); !errors.Is(err, store.ErrNotFound) {
+			t.Fatalf("round %d: expected %s not stored, got %v", round, loser.ID, err)
+		}
+		if id, err := s.GetByIdempotencyKey("key-1"); err != nil || id != winner.ID {
+			t.Fatalf("round %d: expected key bound to %s, got %q, %v", round, winner.ID, id, err)
+		}
+	}
+}
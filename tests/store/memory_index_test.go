@@ -0,0 +1,124 @@
+package store_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/clock"
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestOrderedIndex_largeShuffledStore
+// What: listing, paging and range queries stay in (EffectiveAt, ID) order across many index
+// chunks when transactions arrive out of order and are later moved, removed and purged
+// Input: 5000 creates in shuffled effective_at order over two accounts; upserts that move 200
+// to new times; DeleteWhere on every tenth; then List pages, ListBetween, ForEach and QueryAccount
+// Output: every result strictly sorted; pages concatenate to the full list; counts add up
+func TestOrderedIndex_largeShuffledStore(t *testing.T) {
+	const n = 5000
+	s := store.NewMemoryStore()
+	at := func(sec int) time.Time { return jan(1).Add(time.Duration(sec) * time.Second) }
+	for i, sec := range rand.New(rand.NewPCG(7, 11)).Perm(n) {
+		txn := makeTxn(fmt.Sprintf("t%05d", i), 100, "USD", at(sec))
+		txn.AccountID = fmt.Sprintf("acct-%d", i%2)
+		if err := s.Create(txn); err != nil {
+			t.Fatalf("create %s: %v", txn.ID, err)
+		}
+	}
+
+	// Move some transactions to new times (and accounts) so the index removes and re-inserts them
+	for i := 0; i < n; i += 25 {
+		txn := makeTxn(fmt.Sprintf("t%05d", i), 100, "USD", at(n-i).Add(time.Millisecond))
+		txn.AccountID = "acct-1"
+		if _, err := s.Upsert(txn); err != nil {
+			t.Fatalf("upsert %s: %v", txn.ID, err)
+		}
+	}
+	purged, _ := s.DeleteWhere(func(txn model.Transaction) bool { return strings.HasSuffix(txn.ID, "0") })
+	remaining := n - purged
+	if purged != n/10 || s.Count() != remaining {
+		t.Fatalf("expected %d purged and %d left, got %d and %d", n/10, n-n/10, purged, s.Count())
+	}
+
+	all, _ := s.List(n, 0)
+	if len(all) != remaining || !assertStrictlySorted(t, "List", all) {
+		t.Fatalf("expected %d sorted transactions, got %d", remaining, len(all))
+	}
+
+	var paged []model.Transaction
+	for offset := 0; offset < remaining; offset += 333 {
+		page, _ := s.List(333, offset)
+		paged = append(paged, page...)
+	}
+	if len(paged) != remaining {
+		t.Fatalf("expected pages to hold %d transactions, got %d", remaining, len(paged))
+	}
+	for i := range all {
+		if paged[i].ID != all[i].ID {
+			t.Fatalf("page entry %d: expected %s, got %s", i, all[i].ID, paged[i].ID)
+		}
+	}
+
+	between, _ := s.ListBetween(at(1000), at(2000), n, 0)
+	want := 0
+	for _, txn := range all {
+		if !txn.EffectiveAt.Before(at(1000)) && !txn.EffectiveAt.After(at(2000)) {
+			want++
+		}
+	}
+	if len(between) != want || !assertStrictlySorted(t, "ListBetween", between) {
+		t.Errorf("expected %d sorted transactions in range, got %d", want, len(between))
+	}
+
+	var walked []model.Transaction
+	_ = s.ForEach(func(txn model.Transaction) bool { walked = append(walked, txn); return true })
+	if len(walked) != remaining || !assertStrictlySorted(t, "ForEach", walked) {
+		t.Errorf("expected ForEach to visit %d sorted transactions, got %d", remaining, len(walked))
+	}
+
+	acct0, _ := s.QueryAccount("acct-0", nil)
+	acct1, _ := s.QueryAccount("acct-1", nil)
+	if len(acct0)+len(acct1) != remaining || !assertStrictlySorted(t, "acct-0", acct0) || !assertStrictlySorted(t, "acct-1", acct1) {
+		t.Errorf("expected account indexes to hold %d sorted transactions, got %d + %d", remaining, len(acct0), len(acct1))
+	}
+}
+
+// Test: TestOrderedIndex_drainAndRefill
+// What: removing every transaction one by one empties the index cleanly, and it fills again
+// Input: 3000 creates newest first with a TTL of one hour; advance past it and create more,
+// which evicts expired IDs one at a time on reuse; then EvictExpired and 3000 fresh creates
+// Output: List is empty after eviction and holds the 3000 fresh transactions, sorted, afterwards
+func TestOrderedIndex_drainAndRefill(t *testing.T) {
+	const n = 3000
+	fake := clock.NewFake(jan(1))
+	s := store.NewMemoryStoreWithTTL(fake, time.Hour, 24*time.Hour)
+	defer s.Close()
+	for i := range n {
+		_ = s.Create(makeTxn(fmt.Sprintf("t%05d", i), 100, "USD", jan(1).Add(time.Duration(n-i)*time.Second)))
+	}
+	fake.Advance(2 * time.Hour)
+	for i := 0; i < n; i += 3 {
+		// Reusing an expired ID removes the old copy before inserting the new one
+		_ = s.Create(makeTxn(fmt.Sprintf("t%05d", i), 200, "USD", jan(2)))
+	}
+	fake.Advance(2 * time.Hour)
+	if evicted := s.EvictExpired(); evicted != n {
+		t.Fatalf("expected %d evicted, got %d", n, evicted)
+	}
+	if list, _ := s.List(n, 0); len(list) != 0 {
+		t.Fatalf("expected an empty store, got %d", len(list))
+	}
+
+	for i := range n {
+		_ = s.Create(makeTxn(fmt.Sprintf("r%05d", i), 100, "USD", jan(1).Add(time.Duration(i%500)*time.Second)))
+	}
+	list, _ := s.List(n, 0)
+	if len(list) != n {
+		t.Fatalf("expected %d transactions, got %d", n, len(list))
+	}
+	assertStrictlySorted(t, "List", list)
+}
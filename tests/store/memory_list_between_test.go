@@ -0,0 +1,149 @@
+package store_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// seedDays stores one transaction per day for January 1..n, IDs d01, d02, ...
+func seedDays(t *testing.T, n int) *store.MemoryStore {
+	t.Helper()
+	s := store.NewMemoryStore()
+	for day := 1; day <= n; day++ {
+		id := "d" + string(rune('0'+day/10)) + string(rune('0'+day%10))
+		if err := s.Create(makeTxn(id, int64(day), "USD", jan(day))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+// Test: TestListBetween_inclusiveBounds
+// What: transactions exactly at start and end are included
+// Input: one txn per day Jan 1..10, range Jan 3..Jan 5
+// Output: d03, d04, d05
+func TestListBetween_inclusiveBounds(t *testing.T) {
+	s := seedDays(t, 10)
+
+	got, err := s.ListBetween(jan(3), jan(5), 100, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"d03", "d04", "d05"}
+	if !reflect.DeepEqual(ids(got), want) {
+		t.Errorf("expected %v, got %v", want, ids(got))
+	}
+}
+
+// Test: TestListBetween_openBounds
+// What: a zero start or end leaves that side of the range open
+// Input: one txn per day Jan 1..5; (zero, Jan 2), (Jan 4, zero), (zero, zero)
+// Output: d01..d02, d04..d05, and all five
+func TestListBetween_openBounds(t *testing.T) {
+	s := seedDays(t, 5)
+
+	cases := []struct {
+		start, end time.Time
+		want       []string
+	}{
+		{time.Time{}, jan(2), []string{"d01", "d02"}},
+		{jan(4), time.Time{}, []string{"d04", "d05"}},
+		{time.Time{}, time.Time{}, []string{"d01", "d02", "d03", "d04", "d05"}},
+	}
+	for _, tc := range cases {
+		got, _ := s.ListBetween(tc.start, tc.end, 100, 0)
+		if !reflect.DeepEqual(ids(got), tc.want) {
+			t.Errorf("ListBetween(%v, %v): expected %v, got %v", tc.start, tc.end, tc.want, ids(got))
+		}
+	}
+}
+
+// Test: TestListBetween_pagination
+// What: limit and offset page within the range, not the whole store
+// Input: one txn per day Jan 1..10, range Jan 3..Jan 8, limit=2, offset=3
+// Output: d06, d07
+func TestListBetween_pagination(t *testing.T) {
+	s := seedDays(t, 10)
+
+	got, _ := s.ListBetween(jan(3), jan(8), 2, 3)
+	want := []string{"d06", "d07"}
+	if !reflect.DeepEqual(ids(got), want) {
+		t.Errorf("expected %v, got %v", want, ids(got))
+	}
+}
+
+// Test: TestListBetween_empty
+// What: an empty range, an inverted range, or an offset past the range returns an empty slice
+// Input: one txn per day Jan 1..5; (Feb 1, Feb 2), (Jan 4, Jan 2), (Jan 1..Jan 5 offset 5)
+// Output: non-nil empty slices, nil error
+func TestListBetween_empty(t *testing.T) {
+	s := seedDays(t, 5)
+	feb := func(day int) time.Time { return jan(day).AddDate(0, 1, 0) }
+
+	for _, got := range [][]string{
+		ids(mustListBetween(t, s, feb(1), feb(2), 100, 0)),
+		ids(mustListBetween(t, s, jan(4), jan(2), 100, 0)),
+		ids(mustListBetween(t, s, jan(1), jan(5), 100, 5)),
+	} {
+		if len(got) != 0 {
+			t.Errorf("expected empty result, got %v", got)
+		}
+	}
+}
+
+// Test: TestListBetween_matchesQuery
+// What: ListBetween returns the same rows as an equivalent Query predicate
+// Input: 30 txns, several sharing a timestamp, range Jan 10..Jan 20
+// Output: identical ID lists
+func TestListBetween_matchesQuery(t *testing.T) {
+	s := store.NewMemoryStore()
+	for i := 0; i < 30; i++ {
+		// Two transactions per day to exercise the ID tie-break at the bounds
+		id := "t" + string(rune('a'+i))
+		_ = s.Create(makeTxn(id, int64(i), "USD", jan(i/2+1)))
+	}
+	start, end := jan(10), jan(12)
+
+	got, _ := s.ListBetween(start, end, 100, 0)
+	want, _ := s.Query(func(txn model.Transaction) bool {
+		return !txn.EffectiveAt.Before(start) && !txn.EffectiveAt.After(end)
+	})
+	if !reflect.DeepEqual(ids(got), ids(want)) {
+		t.Errorf("expected %v, got %v", ids(want), ids(got))
+	}
+}
+
+// Test: TestListBetween_returnsCopies
+// What: mutating returned metadata does not affect the store
+// Input: txn with metadata, modify the returned map
+// Output: a later Get still sees the original metadata
+func TestListBetween_returnsCopies(t *testing.T) {
+	s := store.NewMemoryStore()
+	txn := makeTxn("a", 100, "USD", jan(1))
+	txn.Metadata = map[string]string{"k": "v"}
+	_ = s.Create(txn)
+
+	got, _ := s.ListBetween(jan(1), jan(1), 10, 0)
+	got[0].Metadata["k"] = "changed"
+
+	stored, _ := s.Get("a")
+	if stored.Metadata["k"] != "v" {
+		t.Errorf("expected store metadata to be unchanged, got %q", stored.Metadata["k"])
+	}
+}
+
+func mustListBetween(t *testing.T, s *store.MemoryStore, start, end time.Time, limit, offset int) []model.Transaction {
+	t.Helper()
+	got, err := s.ListBetween(start, end, limit, offset)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil {
+		t.Fatal("expected a non-nil slice")
+	}
+	return got
+}
@@ -0,0 +1,81 @@
+package store_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestListPage_totalCountsEveryMatch
+// What: total is the number of matches across the whole store, not the page length
+// Input: a1..a5 on days 1-5; ListPage(nil, 2, 1), then ListPage(nil, 10, 4), then ListPage(nil, 2, 9)
+// Output: [a2 a3] with total 5; [a5] with total 5; [] with total 5
+func TestListPage_totalCountsEveryMatch(t *testing.T) {
+	s := store.NewMemoryStore()
+	for i, id := range []string{"a1", "a2", "a3", "a4", "a5"} {
+		_ = s.Create(makeAccountTxn(id, "acct-1", i+1))
+	}
+
+	tests := []struct {
+		limit, offset int
+		want          []string
+	}{
+		{2, 1, []string{"a2", "a3"}},
+		{10, 4, []string{"a5"}},
+		{2, 9, []string{}},
+	}
+	for _, tt := range tests {
+		page, total, err := s.ListPage(nil, tt.limit, tt.offset)
+		if err != nil {
+			t.Fatalf("ListPage failed: %v", err)
+		}
+		if total != 5 {
+			t.Errorf("limit=%d offset=%d: expected total 5, got %d", tt.limit, tt.offset, total)
+		}
+		if got := ids(page); !slices.Equal(got, tt.want) {
+			t.Errorf("limit=%d offset=%d: expected %v, got %v", tt.limit, tt.offset, tt.want, got)
+		}
+	}
+}
+
+// Test: TestListPage_filterReducesTotal
+// What: the predicate narrows both the page and the total, and offsets count only matches
+// Input: 6 transactions alternating USD/EUR; ListPage(currency == EUR, 2, 1)
+// Output: the 2nd and 3rd EUR transactions, total 3
+func TestListPage_filterReducesTotal(t *testing.T) {
+	s := store.NewMemoryStore()
+	for i, id := range []string{"u1", "e1", "u2", "e2", "u3", "e3"} {
+		currency := "USD"
+		if id[0] == 'e' {
+			currency = "EUR"
+		}
+		_ = s.Create(makeTxn(id, 100, currency, jan(i+1)))
+	}
+
+	page, total, _ := s.ListPage(func(txn model.Transaction) bool { return txn.Currency == "EUR" }, 2, 1)
+	if total != 3 {
+		t.Errorf("expected total 3, got %d", total)
+	}
+	if got := ids(page); len(got) != 2 || got[0] != "e2" || got[1] != "e3" {
+		t.Errorf("expected [e2 e3], got %v", got)
+	}
+}
+
+// Test: TestListPage_returnsCopies
+// What: the page holds clones, so callers can't modify stored metadata through it
+// Input: a1 with metadata k=v; ListPage; set k=changed on the result; Get(a1)
+// Output: the stored metadata is still k=v
+func TestListPage_returnsCopies(t *testing.T) {
+	s := store.NewMemoryStore()
+	txn := makeTxn("a1", 100, "USD", jan(1))
+	txn.Metadata = map[string]string{"k": "v"}
+	_ = s.Create(txn)
+
+	page, _, _ := s.ListPage(nil, 1, 0)
+	page[0].Metadata["k"] = "changed"
+	if got, _ := s.Get("a1"); got.Metadata["k"] != "v" {
+		t.Errorf("expected stored metadata to be unchanged, got %v", got.Metadata)
+	}
+}
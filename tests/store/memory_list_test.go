@@ -1,8 +1,13 @@
 package store_test
 
 import (
+	"fmt"
+	"reflect"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/synctera/tech-challenge/internal/model"
 	"github.com/synctera/tech-challenge/internal/store"
 )
 
@@ -222,3 +227,174 @@ func TestList_returnsACopyOfMetadata(t *testing.T) {
 		t.Error("List should return deep copies; mutating returned Metadata should not affect the store")
 	}
 }
+
+// Test: TestQuery_filtersWithPredicate
+// What: Query returns only transactions matching the predicate, in list order
+// Input: store with a(USD), b(EUR), c(USD); predicate currency == USD
+// Output: [a, c]
+func TestQuery_filtersWithPredicate(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeTxn("c", 300, "USD", jan(3)))
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+	_ = s.Create(makeTxn("b", 200, "EUR", jan(2)))
+
+	got, err := s.Query(func(txn model.Transaction) bool { return txn.Currency == "USD" })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []string{"a", "c"}; !reflect.DeepEqual(ids(got), want) {
+		t.Errorf("expected %v, got %v", want, ids(got))
+	}
+}
+
+// Test: TestQuery_nilMatchReturnsAll
+// What: a nil predicate returns every transaction with no row cap
+// Input: store with 10,050 transactions, Query(nil)
+// Output: 10,050 transactions
+func TestQuery_nilMatchReturnsAll(t *testing.T) {
+	s := store.NewMemoryStore()
+	const n = 10050
+	for i := 0; i < n; i++ {
+		_ = s.Create(makeTxn(fmt.Sprintf("t%05d", i), int64(i), "USD", jan(1).Add(time.Duration(i)*time.Second)))
+	}
+
+	got, err := s.Query(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != n {
+		t.Errorf("expected %d transactions, got %d", n, len(got))
+	}
+}
+
+// Test: TestCount
+// What: Count returns the number of stored transactions, ignoring duplicate creates
+// Input: empty store, then 2 creates plus one duplicate
+// Output: 0, then 2
+func TestCount(t *testing.T) {
+	s := store.NewMemoryStore()
+	if got := s.Count(); got != 0 {
+		t.Errorf("expected 0, got %d", got)
+	}
+
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+	_ = s.Create(makeTxn("b", 200, "USD", jan(2)))
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+	if got := s.Count(); got != 2 {
+		t.Errorf("expected 2, got %d", got)
+	}
+}
+
+// Test: TestList_concurrentWithMetadataUpdates
+// What: List and Query clone outside the lock; concurrent metadata updates must not race with them
+// Input: one reader looping List/Query while a writer patches metadata 200 times (run with -race)
+// Output: no data race; every listed transaction has a metadata map
+func TestList_concurrentWithMetadataUpdates(t *testing.T) {
+	s := store.NewMemoryStore()
+	txn := makeTxn("a", 100, "USD", jan(1))
+	txn.Metadata = map[string]string{"n": "0"}
+	_ = s.Create(txn)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			v := fmt.Sprint(i)
+			_ = s.UpdateMetadata("a", map[string]*string{"n": &v})
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		list, _ := s.List(10, 0)
+		matched, _ := s.Query(nil)
+		if len(list) != 1 || list[0].Metadata == nil || len(matched) != 1 {
+			t.Fatalf("unexpected snapshot %+v / %+v", list, matched)
+		}
+	}
+}
+
+// assertStrictlySorted reports an error and returns false if list has an empty (zero-value)
+// entry or is not strictly ascending by (EffectiveAt, ID). Safe to call from any goroutine.
+func assertStrictlySorted(t *testing.T, name string, list []model.Transaction) bool {
+	t.Helper()
+	for i, txn := range list {
+		if txn.ID == "" {
+			t.Errorf("%s: zero-value transaction at index %d of %d", name, i, len(list))
+			return false
+		}
+		if i > 0 && !model.LessByEffectiveAtThenID(list[i-1], txn) {
+			t.Errorf("%s: %s (%s) listed after %s (%s)", name, txn.ID, txn.EffectiveAt, list[i-1].ID, list[i-1].EffectiveAt)
+			return false
+		}
+	}
+	return true
+}
+
+// Test: TestList_sortedDuringConcurrentCreates
+// What: every read sees a fully sorted index while creates shift it; Create's in-place insert
+// must never expose a half-shifted slice or the zero value used to grow it
+// Input: 4 writers creating 300 transactions each with scattered effective_at and shared
+// timestamps, enough for the index to split chunks, while 4 readers loop List, ListBetween,
+// Query and QueryAccount (run with -race)
+// Output: no data race; every result is strictly ascending by (EffectiveAt, ID) with no empty
+// entries; the final List holds all 1200
+func TestList_sortedDuringConcurrentCreates(t *testing.T) {
+	s := store.NewMemoryStore()
+	const writers, perWriter = 4, 300
+
+	var writes sync.WaitGroup
+	for w := range writers {
+		writes.Add(1)
+		go func() {
+			defer writes.Done()
+			for i := range perWriter {
+				// A stride coprime to 28 scatters inserts across the slice; several share a day
+				txn := makeTxn(fmt.Sprintf("w%d-%03d", w, i), 100, "USD", jan((i*11+w*7)%28+1))
+				txn.AccountID = "acct-1"
+				if err := s.Create(txn); err != nil {
+					t.Errorf("create %s: %v", txn.ID, err)
+				}
+			}
+		}()
+	}
+
+	done := make(chan struct{})
+	var reads sync.WaitGroup
+	for range 4 {
+		reads.Add(1)
+		go func() {
+			defer reads.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				list, _ := s.List(writers*perWriter, 0)
+				between, _ := s.ListBetween(jan(5), jan(20), writers*perWriter, 0)
+				matched, _ := s.Query(nil)
+				account, _ := s.QueryAccount("acct-1", nil)
+				if !assertStrictlySorted(t, "List", list) || !assertStrictlySorted(t, "ListBetween", between) ||
+					!assertStrictlySorted(t, "Query", matched) || !assertStrictlySorted(t, "QueryAccount", account) {
+					return
+				}
+			}
+		}()
+	}
+
+	writes.Wait()
+	close(done)
+	reads.Wait()
+
+	list, _ := s.List(writers*perWriter, 0)
+	account, _ := s.QueryAccount("acct-1", nil)
+	if len(list) != writers*perWriter || len(account) != writers*perWriter {
+		t.Fatalf("expected %d transactions, got %d (%d in the account)", writers*perWriter, len(list), len(account))
+	}
+	assertStrictlySorted(t, "final List", list)
+}
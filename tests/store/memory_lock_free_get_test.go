@@ -0,0 +1,153 @@
+package store_test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/clock"
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestLockFreeGets_followsWrites
+// What: with lock-free gets enabled, Get reflects every kind of write exactly like the locked path
+// Input: create; update amount and metadata; soft delete; reverse; DeleteWhere; Reset
+// Output: Get returns the current version after each write, ErrNotFound after purge and Reset
+func TestLockFreeGets_followsWrites(t *testing.T) {
+	s := store.NewMemoryStore()
+	s.EnableLockFreeGets()
+
+	get := func(id string) model.Transaction {
+		t.Helper()
+		txn, err := s.Get(id)
+		if err != nil {
+			t.Fatalf("Get(%s): %v", id, err)
+		}
+		return txn
+	}
+
+	_ = s.Create(makeTxn("txn-1", 100, "USD", jan(1)))
+	if got := get("txn-1"); got.Amount != 100 || got.Version != 1 {
+		t.Errorf("after create: expected amount 100 v1, got %d v%d", got.Amount, got.Version)
+	}
+	_ = s.UpdateAmount("txn-1", 150)
+	value := "x"
+	_ = s.UpdateMetadata("txn-1", map[string]*string{"k": &value})
+	if got := get("txn-1"); got.Amount != 150 || got.Metadata["k"] != "x" || got.Version != 3 {
+		t.Errorf("after updates: expected amount 150, k=x, v3, got %d, %v, v%d", got.Amount, got.Metadata, got.Version)
+	}
+
+	_ = s.Create(makeTxn("txn-2", 100, "USD", jan(2)))
+	_ = s.Delete("txn-2")
+	if got := get("txn-2"); !got.Deleted {
+		t.Error("after soft delete: expected Deleted")
+	}
+
+	if err := s.Reverse("txn-1", makeTxn("rev-1", 0, "", jan(3))); err != nil {
+		t.Fatalf("Reverse: %v", err)
+	}
+	if got := get("txn-1"); got.Metadata[model.MetadataReversedBy] != "rev-1" {
+		t.Errorf("after reverse: expected reversed_by rev-1, got %v", got.Metadata)
+	}
+	get("rev-1")
+
+	_, _ = s.DeleteWhere(func(txn model.Transaction) bool { return txn.ID == "txn-2" })
+	if _, err := s.Get("txn-2"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("after DeleteWhere: expected ErrNotFound, got %v", err)
+	}
+
+	s.Reset()
+	if _, err := s.Get("txn-1"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("after Reset: expected ErrNotFound, got %v", err)
+	}
+}
+
+// Test: TestLockFreeGets_returnsCopies
+// What: lock-free Get returns a deep copy, so callers can't modify the stored metadata
+// Input: create with metadata, Get, change the returned map, Get again
+// Output: the second Get still has the original value
+func TestLockFreeGets_returnsCopies(t *testing.T) {
+	s := store.NewMemoryStore()
+	s.EnableLockFreeGets()
+	txn := makeTxn("txn-1", 100, "USD", jan(1))
+	txn.Metadata = map[string]string{"k": "v"}
+	_ = s.Create(txn)
+
+	got, _ := s.Get("txn-1")
+	got.Metadata["k"] = "changed"
+	if again, _ := s.Get("txn-1"); again.Metadata["k"] != "v" {
+		t.Errorf("expected stored metadata unchanged, got %v", again.Metadata)
+	}
+}
+
+// Test: TestLockFreeGets_enabledAfterLoadAndTTL
+// What: enabling on a store with data mirrors what is already stored, and TTL eviction and
+// FileStore snapshot loads keep the mirror in step
+// Input: a file-backed store with txn-1 saved; reopen with LockFreeGets, a 1h TTL and a clock
+// 90 minutes ahead; create txn-2 and evict
+// Output: txn-1 is readable after reopening; after eviction txn-1 is gone and txn-2 remains
+func TestLockFreeGets_enabledAfterLoadAndTTL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "txns.json")
+	first, err := store.OpenWithOptions("file://"+path, store.Options{})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	_ = first.Create(makeTxn("txn-1", 100, "USD", jan(1)))
+
+	// txn-1 keeps its created_at from the first store, so by this clock it is already past the TTL
+	fake := clock.NewFake(time.Now().Add(90 * time.Minute))
+	s, err := store.OpenWithOptions("file://"+path, store.Options{Clock: fake, TTL: time.Hour, SweepEvery: 24 * time.Hour, LockFreeGets: true})
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer s.(interface{ Close() }).Close()
+	if _, err := s.Get("txn-1"); err != nil {
+		t.Fatalf("expected txn-1 after reopening, got %v", err)
+	}
+
+	_ = s.Create(makeTxn("txn-2", 100, "USD", jan(2)))
+	s.(interface{ EvictExpired() int }).EvictExpired()
+	if _, err := s.Get("txn-1"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected txn-1 evicted, got %v", err)
+	}
+	if _, err := s.Get("txn-2"); err != nil {
+		t.Errorf("expected txn-2 to remain, got %v", err)
+	}
+}
+
+// Test: TestLockFreeGets_concurrent
+// What: lock-free gets race cleanly with writers (run with -race)
+// Input: 4 writers updating amounts of 50 transactions while 4 readers Get them
+// Output: no data race; every Get succeeds with a version at least 1
+func TestLockFreeGets_concurrent(t *testing.T) {
+	s := store.NewMemoryStore()
+	s.EnableLockFreeGets()
+	for i := range 50 {
+		_ = s.Create(makeTxn(fmt.Sprintf("t%02d", i), 100, "USD", jan(1)))
+	}
+
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				_ = s.UpdateAmount(fmt.Sprintf("t%02d", (i+w)%50), int64(i))
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := range 200 {
+				if txn, err := s.Get(fmt.Sprintf("t%02d", (i+w)%50)); err != nil || txn.Version < 1 {
+					t.Errorf("Get: %+v, %v", txn, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,60 @@
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/clock"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestModifiedAt_setOnCreateAndUpdate
+// What: Create stamps ModifiedAt with the creation time, a change restamps it, and an
+// idempotent duplicate leaves it alone
+// Input: create at t0; duplicate create at t0+1h; UpdateMetadata at t0+2h
+// Output: ModifiedAt is t0 after the create and the duplicate, then t0+2h; CreatedAt stays t0
+func TestModifiedAt_setOnCreateAndUpdate(t *testing.T) {
+	t0 := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(t0)
+	s := store.NewMemoryStoreWithClock(clk)
+	txn := makeTxn("a", 100, "USD", jan(1))
+	_ = s.Create(txn)
+
+	clk.Advance(time.Hour)
+	_ = s.Create(txn)
+	if got, _ := s.Get("a"); !got.ModifiedAt.Equal(t0) {
+		t.Errorf("expected ModifiedAt %v after create and duplicate, got %v", t0, got.ModifiedAt)
+	}
+
+	clk.Advance(time.Hour)
+	v := "x"
+	if err := s.UpdateMetadata("a", map[string]*string{"k": &v}); err != nil {
+		t.Fatalf("UpdateMetadata failed: %v", err)
+	}
+	got, _ := s.Get("a")
+	if want := t0.Add(2 * time.Hour); !got.ModifiedAt.Equal(want) {
+		t.Errorf("expected ModifiedAt %v after the update, got %v", want, got.ModifiedAt)
+	}
+	if !got.CreatedAt.Equal(t0) {
+		t.Errorf("expected CreatedAt to stay %v, got %v", t0, got.CreatedAt)
+	}
+}
+
+// Test: TestModifiedAt_uniqueWithinOneTick
+// What: writes in the same clock instant still get distinct, increasing ModifiedAt values,
+// so a sync from any of them is exact
+// Input: frozen clock; create a and b, then soft-delete a
+// Output: a's create < b's create < a's delete, each one nanosecond apart
+func TestModifiedAt_uniqueWithinOneTick(t *testing.T) {
+	t0 := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	s := store.NewMemoryStoreWithClock(clock.NewFake(t0))
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+	_ = s.Create(makeTxn("b", 100, "USD", jan(2)))
+	b, _ := s.Get("b")
+	_ = s.Delete("a")
+	a, _ := s.Get("a")
+
+	if !b.ModifiedAt.Equal(t0.Add(time.Nanosecond)) || !a.ModifiedAt.Equal(t0.Add(2*time.Nanosecond)) {
+		t.Errorf("expected b at t0+1ns and a at t0+2ns, got %v and %v", b.ModifiedAt, a.ModifiedAt)
+	}
+}
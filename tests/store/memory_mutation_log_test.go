@@ -0,0 +1,131 @@
+package store_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/clock"
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+func newLoggedStore(t *testing.T, c clock.Clock) (*store.MemoryStore, *store.FileMutationLog) {
+	t.Helper()
+	log, err := store.OpenFileMutationLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatalf("OpenFileMutationLog failed: %v", err)
+	}
+	t.Cleanup(func() { log.Close() })
+	s := store.NewMemoryStoreWithClock(c)
+	s.EnableMutationLog(log)
+	return s, log
+}
+
+// Test: TestMutationLog_createAndDelete
+// What: a create and a soft delete each append an event retrievable by transaction ID
+// Input: create txn-1 and txn-2, advance the clock, delete txn-1
+// Output: txn-1 history is create (v1) then delete (v2) with the clock's timestamps; txn-2 has only its create
+func TestMutationLog_createAndDelete(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	s, _ := newLoggedStore(t, fake)
+
+	_ = s.Create(makeTxn("txn-1", 100, "USD", jan(1)))
+	_ = s.Create(makeTxn("txn-2", 100, "USD", jan(1)))
+	fake.Advance(time.Minute)
+	if err := s.Delete("txn-1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	got, err := s.MutationHistory("txn-1")
+	if err != nil {
+		t.Fatalf("MutationHistory failed: %v", err)
+	}
+	want := []store.MutationEvent{
+		{At: start, Type: store.MutationCreate, ID: "txn-1", Version: 1},
+		{At: start.Add(time.Minute), Type: store.MutationDelete, ID: "txn-1", Version: 2},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), got)
+	}
+	for i := range want {
+		if !got[i].At.Equal(want[i].At) || got[i].Type != want[i].Type || got[i].ID != want[i].ID || got[i].Version != want[i].Version {
+			t.Errorf("event %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+
+	other, _ := s.MutationHistory("txn-2")
+	if len(other) != 1 || other[0].Type != store.MutationCreate {
+		t.Errorf("expected only a create for txn-2, got %+v", other)
+	}
+}
+
+// Test: TestMutationLog_updatesPurgesAndRetries
+// What: updates and hard deletes are logged, while duplicate retries change nothing and log nothing
+// Input: create txn-1, retry the same create, patch its amount, then DeleteWhere it
+// Output: history is create, update, purge
+func TestMutationLog_updatesPurgesAndRetries(t *testing.T) {
+	s, _ := newLoggedStore(t, clock.Real{})
+
+	_ = s.Create(makeTxn("txn-1", 100, "USD", jan(1)))
+	if err := s.Create(makeTxn("txn-1", 100, "USD", jan(1))); !errors.Is(err, store.ErrDuplicate) {
+		t.Fatalf("expected ErrDuplicate, got %v", err)
+	}
+	if err := s.UpdateAmount("txn-1", 200); err != nil {
+		t.Fatalf("UpdateAmount failed: %v", err)
+	}
+	if _, err := s.DeleteWhere(func(model.Transaction) bool { return true }); err != nil {
+		t.Fatalf("DeleteWhere failed: %v", err)
+	}
+
+	got, _ := s.MutationHistory("txn-1")
+	var types []string
+	for _, event := range got {
+		types = append(types, event.Type)
+	}
+	want := []string{store.MutationCreate, store.MutationUpdate, store.MutationPurge}
+	if len(types) != len(want) || types[0] != want[0] || types[1] != want[1] || types[2] != want[2] {
+		t.Errorf("expected %v, got %v", want, types)
+	}
+}
+
+// Test: TestMutationLog_survivesReopen
+// What: the file log keeps its history when reopened, as after a restart
+// Input: create txn-1 with a log, close it, reopen the same file
+// Output: History("txn-1") on the reopened log returns the create
+func TestMutationLog_survivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := store.OpenFileMutationLog(path)
+	if err != nil {
+		t.Fatalf("OpenFileMutationLog failed: %v", err)
+	}
+	s := store.NewMemoryStore()
+	s.EnableMutationLog(log)
+	_ = s.Create(makeTxn("txn-1", 100, "USD", jan(1)))
+	log.Close()
+
+	reopened, err := store.OpenFileMutationLog(path)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	defer reopened.Close()
+	got, err := reopened.History("txn-1")
+	if err != nil || len(got) != 1 || got[0].Type != store.MutationCreate {
+		t.Errorf("expected one create, got %+v (err %v)", got, err)
+	}
+}
+
+// Test: TestMutationLog_disabled
+// What: a store without a mutation log reports it instead of returning an empty history
+// Input: NewMemoryStore, create txn-1, MutationHistory("txn-1")
+// Output: ErrMutationLogDisabled
+func TestMutationLog_disabled(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeTxn("txn-1", 100, "USD", jan(1)))
+
+	if _, err := s.MutationHistory("txn-1"); !errors.Is(err, store.ErrMutationLogDisabled) {
+		t.Errorf("expected ErrMutationLogDisabled, got %v", err)
+	}
+}
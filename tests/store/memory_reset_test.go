@@ -0,0 +1,39 @@
+package store_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestReset_clearsStore
+// What: Reset removes every transaction and idempotency key and restarts Seq numbering
+// Input: store with 2 transactions and a bound idempotency key; Reset; create "c"
+// Output: Get("a") is ErrNotFound, key lookup is ErrNotFound, List returns only "c" with Seq=1
+func TestReset_clearsStore(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+	_ = s.Create(makeTxn("b", 200, "USD", jan(2)))
+	_ = s.PutIdempotencyKey("key-1", "a")
+
+	s.Reset()
+
+	if _, err := s.Get("a"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected ErrNotFound after reset, got %v", err)
+	}
+	if _, err := s.GetByIdempotencyKey("key-1"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected idempotency key to be cleared, got %v", err)
+	}
+
+	if err := s.Create(makeTxn("c", 300, "USD", jan(3))); err != nil {
+		t.Fatalf("unexpected error creating after reset: %v", err)
+	}
+	got, _ := s.List(10, 0)
+	if len(got) != 1 || got[0].ID != "c" {
+		t.Fatalf("expected only [c], got %v", ids(got))
+	}
+	if got[0].Seq != 1 {
+		t.Errorf("expected Seq to restart at 1, got %d", got[0].Seq)
+	}
+}
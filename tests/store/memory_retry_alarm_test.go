@@ -0,0 +1,78 @@
+package store_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/clock"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+func newRetryAlarmStore(threshold int) (*store.MemoryStore, *clock.Fake, *bytes.Buffer) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := store.NewMemoryStoreWithClock(fake)
+	var buf bytes.Buffer
+	s.EnableRetryAlarm(threshold, time.Minute, slog.New(slog.NewTextHandler(&buf, nil)))
+	return s, fake, &buf
+}
+
+// Test: TestRetryAlarm_warnsOnceWhenThresholdCrossed
+// What: more than threshold creates of one ID within the window log a single warning naming the ID
+// Input: threshold 3, window 1m; txn-1 created 5 times one second apart, txn-2 created once
+// Output: exactly one warning, for txn-1
+func TestRetryAlarm_warnsOnceWhenThresholdCrossed(t *testing.T) {
+	s, fake, buf := newRetryAlarmStore(3)
+	txn := makeTxn("txn-1", 100, "USD", jan(1))
+
+	for range 5 {
+		s.Create(txn)
+		fake.Advance(time.Second)
+	}
+	s.Create(makeTxn("txn-2", 100, "USD", jan(1)))
+
+	out := buf.String()
+	if n := strings.Count(out, "retry storm"); n != 1 {
+		t.Fatalf("expected 1 warning, got %d: %s", n, out)
+	}
+	if !strings.Contains(out, "id=txn-1") {
+		t.Errorf("expected the warning to name txn-1, got %s", out)
+	}
+}
+
+// Test: TestRetryAlarm_ignoresSpacedOutCreates
+// What: creates of one ID spread wider than the window never warn
+// Input: threshold 3, window 1m; txn-1 created 6 times, 30s apart
+// Output: no warning
+func TestRetryAlarm_ignoresSpacedOutCreates(t *testing.T) {
+	s, fake, buf := newRetryAlarmStore(3)
+	txn := makeTxn("txn-1", 100, "USD", jan(1))
+
+	for range 6 {
+		s.Create(txn)
+		fake.Advance(30 * time.Second)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no warning, got %s", buf)
+	}
+}
+
+// Test: TestRetryAlarm_disabled
+// What: a threshold of 0 disables the alarm
+// Input: threshold 0; txn-1 created 10 times at the same instant
+// Output: no warning
+func TestRetryAlarm_disabled(t *testing.T) {
+	s, _, buf := newRetryAlarmStore(0)
+	txn := makeTxn("txn-1", 100, "USD", jan(1))
+
+	for range 10 {
+		s.Create(txn)
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("expected no warning, got %s", buf)
+	}
+}
@@ -0,0 +1,68 @@
+package store_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestReverse_storesReversalAndMarksOriginal
+// What: Reverse stores the reversal and sets reversed_by on the original in one step
+// Input: debit "a"; Reverse("a", a.Reversal("a-rev", jan(5)))
+// Output: "a-rev" stored as a credit with reverses=a; "a" has reversed_by=a-rev and keeps its Seq
+func TestReverse_storesReversalAndMarksOriginal(t *testing.T) {
+	s := store.NewMemoryStore()
+	original := makeTxn("a", 100, "USD", jan(1))
+	original.Direction = model.DirectionDebit
+	_ = s.Create(original)
+	before, _ := s.Get("a")
+
+	if err := s.Reverse("a", original.Reversal("a-rev", jan(5))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	reversal, err := s.Get("a-rev")
+	if err != nil {
+		t.Fatalf("expected reversal to be stored, got %v", err)
+	}
+	if reversal.Direction != model.DirectionCredit || reversal.Amount != 100 || reversal.Metadata[model.MetadataReverses] != "a" {
+		t.Errorf("unexpected reversal %+v", reversal)
+	}
+
+	marked, _ := s.Get("a")
+	if marked.Metadata[model.MetadataReversedBy] != "a-rev" {
+		t.Errorf("expected original reversed_by=a-rev, got %v", marked.Metadata)
+	}
+	if marked.Seq != before.Seq {
+		t.Errorf("expected original Seq %d, got %d", before.Seq, marked.Seq)
+	}
+}
+
+// Test: TestReverse_errors
+// What: Reverse rejects a missing original, a second reversal, and a reversal ID that is taken
+// Input: "a" already reversed by "a-rev", "b" unreversed, "c" existing
+// Output: ErrNotFound for "missing", ErrAlreadyReversed for "a", ErrConflict for reversing "b" as "c"
+func TestReverse_errors(t *testing.T) {
+	s := store.NewMemoryStore()
+	a := makeTxn("a", 100, "USD", jan(1))
+	b := makeTxn("b", 200, "USD", jan(2))
+	_ = s.Create(a)
+	_ = s.Create(b)
+	_ = s.Create(makeTxn("c", 300, "USD", jan(3)))
+	_ = s.Reverse("a", a.Reversal("a-rev", jan(4)))
+
+	if err := s.Reverse("missing", a.Reversal("x", jan(4))); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if err := s.Reverse("a", a.Reversal("a-rev-2", jan(4))); !errors.Is(err, store.ErrAlreadyReversed) {
+		t.Errorf("expected ErrAlreadyReversed, got %v", err)
+	}
+	if err := s.Reverse("b", b.Reversal("c", jan(4))); !errors.Is(err, store.ErrConflict) {
+		t.Errorf("expected ErrConflict, got %v", err)
+	}
+	if got, _ := s.Get("b"); got.Metadata != nil {
+		t.Errorf("expected b to stay unmarked after a failed reversal, got %v", got.Metadata)
+	}
+}
@@ -0,0 +1,62 @@
+package store_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestDelete_softDeletesInPlace
+// What: Delete only sets the flag; the transaction keeps its fields, Seq and idempotency behavior
+// Input: create a1; Delete(a1); Get(a1); re-create the identical a1
+// Output: Get returns a1 with Deleted=true and Seq 1; the re-create is ErrDuplicate
+func TestDelete_softDeletesInPlace(t *testing.T) {
+	s := store.NewMemoryStore()
+	txn := makeAccountTxn("a1", "acct-1", 1)
+	_ = s.Create(txn)
+
+	if err := s.Delete("a1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	got, err := s.Get("a1")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if !got.Deleted || got.Seq != 1 || !got.Equal(txn) {
+		t.Errorf("expected the original record marked deleted, got %+v", got)
+	}
+	if err := s.Create(txn); !errors.Is(err, store.ErrDuplicate) {
+		t.Errorf("expected ErrDuplicate, got %v", err)
+	}
+	if err := s.Delete("missing"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// Test: TestDelete_listBetweenAndCountsSkipDeleted
+// What: ListBetween and CountByCurrency skip soft-deleted rows, and offsets count only live ones
+// Input: a1..a4 on days 1-4; delete a2; ListBetween(open, open, 2, 1); CountByCurrency
+// Output: [a3 a4]; USD count 3; after a metadata update a2 is still deleted
+func TestDelete_listBetweenAndCountsSkipDeleted(t *testing.T) {
+	s := store.NewMemoryStore()
+	for i, id := range []string{"a1", "a2", "a3", "a4"} {
+		_ = s.Create(makeAccountTxn(id, "acct-1", i+1))
+	}
+	_ = s.Delete("a2")
+
+	page, _ := s.ListBetween(time.Time{}, time.Time{}, 2, 1)
+	if got := ids(page); len(got) != 2 || got[0] != "a3" || got[1] != "a4" {
+		t.Errorf("expected [a3 a4], got %v", got)
+	}
+	if counts, _ := s.CountByCurrency(time.Time{}, time.Time{}); counts["USD"] != 3 {
+		t.Errorf("expected 3 USD, got %v", counts)
+	}
+
+	v := "x"
+	_ = s.UpdateMetadata("a2", map[string]*string{"k": &v})
+	if got, _ := s.Get("a2"); !got.Deleted {
+		t.Error("expected a2 to stay deleted after a metadata update")
+	}
+}
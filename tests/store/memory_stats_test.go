@@ -0,0 +1,43 @@
+package store_test
+
+import (
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestStats_countsCreateOutcomes
+// What: Stats counts successful creates, identical retries, and conflicting retries separately
+// Input: create a and b, retry a identically twice, retry b with a different amount
+// Output: Created=2, Duplicate=2, Conflict=1
+func TestStats_countsCreateOutcomes(t *testing.T) {
+	s := store.NewMemoryStore()
+	a := makeTxn("a", 100, "USD", jan(1))
+	b := makeTxn("b", 200, "USD", jan(2))
+
+	_ = s.Create(a)
+	_ = s.Create(b)
+	_ = s.Create(a)
+	_ = s.Create(a)
+	_ = s.Create(makeTxn("b", 999, "USD", jan(2)))
+
+	want := store.Stats{Created: 2, Duplicate: 2, Conflict: 1}
+	if got := s.Stats(); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+// Test: TestStats_survivesReset
+// What: Reset clears data but not the monotonic outcome counters
+// Input: create a, Reset, create a again
+// Output: Created=2
+func TestStats_survivesReset(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+	s.Reset()
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+
+	if got := s.Stats().Created; got != 2 {
+		t.Errorf("expected Created=2, got %d", got)
+	}
+}
@@ -0,0 +1,87 @@
+package store_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestSubscribe_receivesCreates
+// What: a subscriber receives each new transaction once, in creation order; retries and
+// updates are not published
+// Input: subscribe, create txn-1, retry it, patch its amount, create txn-2
+// Output: the channel yields txn-1 (seq 1) then txn-2 (seq 2) and nothing else
+func TestSubscribe_receivesCreates(t *testing.T) {
+	s := store.NewMemoryStore()
+	events, cancel := s.Subscribe(10)
+	defer cancel()
+
+	_ = s.Create(makeTxn("txn-1", 100, "USD", jan(1)))
+	if err := s.Create(makeTxn("txn-1", 100, "USD", jan(1))); !errors.Is(err, store.ErrDuplicate) {
+		t.Fatalf("expected ErrDuplicate, got %v", err)
+	}
+	_ = s.UpdateAmount("txn-1", 200)
+	_ = s.Create(makeTxn("txn-2", 100, "USD", jan(2)))
+
+	for _, want := range []struct {
+		id  string
+		seq uint64
+	}{{"txn-1", 1}, {"txn-2", 2}} {
+		select {
+		case got := <-events:
+			if got.ID != want.id || got.Seq != want.seq {
+				t.Errorf("expected %s (seq %d), got %s (seq %d)", want.id, want.seq, got.ID, got.Seq)
+			}
+		default:
+			t.Fatalf("expected an event for %s", want.id)
+		}
+	}
+	select {
+	case got := <-events:
+		t.Errorf("expected no more events, got %s", got.ID)
+	default:
+	}
+}
+
+// Test: TestSubscribe_slowSubscriberDropped
+// What: a subscriber that falls a full buffer behind is dropped without blocking Create
+// Input: subscribe with a buffer of 2, create 3 transactions without reading
+// Output: all creates succeed; the channel yields the first 2 events and is then closed
+func TestSubscribe_slowSubscriberDropped(t *testing.T) {
+	s := store.NewMemoryStore()
+	events, cancel := s.Subscribe(2)
+	defer cancel()
+
+	for _, id := range []string{"a", "b", "c"} {
+		if err := s.Create(makeTxn(id, 100, "USD", jan(1))); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+	}
+
+	var got []string
+	for txn := range events {
+		got = append(got, txn.ID)
+	}
+	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("expected [a b] before the channel closed, got %v", got)
+	}
+}
+
+// Test: TestSubscribe_cancel
+// What: cancel closes the channel and stops delivery; calling it twice is safe
+// Input: subscribe, cancel twice, create a transaction
+// Output: the channel is closed and empty; Create succeeds
+func TestSubscribe_cancel(t *testing.T) {
+	s := store.NewMemoryStore()
+	events, cancel := s.Subscribe(10)
+	cancel()
+	cancel()
+
+	if err := s.Create(makeTxn("txn-1", 100, "USD", jan(1))); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if txn, ok := <-events; ok {
+		t.Errorf("expected a closed channel, got %s", txn.ID)
+	}
+}
@@ -0,0 +1,59 @@
+package store_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/clock"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestThroughput_windows
+// What: Throughput counts creates in the trailing 1, 5 and 15 minutes by the store clock and evicts older ones
+// Input: 3 creates 14 minutes ago, 2 creates 3 minutes ago, 1 create 30 seconds ago, and a duplicate retry now;
+// then the clock moves 2 more minutes
+// Output: 1m=1, 5m=3, 15m=6; after the move, 1m=0, 5m=1, 15m=3
+func TestThroughput_windows(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	s := store.NewMemoryStoreWithClock(fake)
+
+	for i := range 3 {
+		_ = s.Create(makeTxn(string(rune('a'+i)), 100, "USD", jan(1)))
+	}
+	fake.Advance(11 * time.Minute)
+	_ = s.Create(makeTxn("d", 100, "USD", jan(1)))
+	_ = s.Create(makeTxn("e", 100, "USD", jan(1)))
+	fake.Advance(2*time.Minute + 30*time.Second)
+	_ = s.Create(makeTxn("f", 100, "USD", jan(1)))
+	fake.Advance(30 * time.Second)
+	_ = s.Create(makeTxn("f", 100, "USD", jan(1)))
+
+	want := store.Throughput{LastMinute: 1, LastFiveMinutes: 3, LastFifteenMinutes: 6}
+	if got := s.Throughput(); got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	fake.Advance(2 * time.Minute)
+	want = store.Throughput{LastMinute: 0, LastFiveMinutes: 1, LastFifteenMinutes: 3}
+	if got := s.Throughput(); got != want {
+		t.Errorf("after 2 minutes: expected %+v, got %+v", want, got)
+	}
+}
+
+// Test: TestThroughput_bucketReuse
+// What: a ring bucket reused after a full 15 minutes drops its old count instead of adding to it
+// Input: a create at T, then a create at exactly T+15m
+// Output: 15m=1
+func TestThroughput_bucketReuse(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	s := store.NewMemoryStoreWithClock(fake)
+
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+	fake.Advance(15 * time.Minute)
+	_ = s.Create(makeTxn("b", 100, "USD", jan(1)))
+
+	if got := s.Throughput().LastFifteenMinutes; got != 1 {
+		t.Errorf("expected 1, got %d", got)
+	}
+}
@@ -0,0 +1,133 @@
+package store_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/clock"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// newTTLStore returns a store with a one-hour idempotency window whose sweeper effectively never
+// fires, so tests drive eviction through EvictExpired.
+func newTTLStore(t *testing.T, clk clock.Clock) *store.MemoryStore {
+	t.Helper()
+	s := store.NewMemoryStoreWithTTL(clk, time.Hour, 24*time.Hour)
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Test: TestCreate_conflictWithinTTL
+// What: inside the idempotency window a transaction still blocks a conflicting create
+// Input: create "a", advance the clock 59m, create "a" with a different amount
+// Output: ErrConflict; the original amount is kept
+func TestCreate_conflictWithinTTL(t *testing.T) {
+	clk := clock.NewFake(jan(1))
+	s := newTTLStore(t, clk)
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+
+	clk.Advance(59 * time.Minute)
+	if err := s.Create(makeTxn("a", 200, "USD", jan(1))); !errors.Is(err, store.ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if got, _ := s.Get("a"); got.Amount != 100 {
+		t.Errorf("expected amount 100, got %d", got.Amount)
+	}
+}
+
+// Test: TestCreate_overwritesAfterTTL
+// What: once the window has passed, a conflicting create replaces the old transaction
+// Input: create "a" at t0, advance the clock by the TTL, create "a" with a different amount and date
+// Output: nil error; Get returns the new amount with a fresh CreatedAt, and List holds one "a"
+func TestCreate_overwritesAfterTTL(t *testing.T) {
+	clk := clock.NewFake(jan(1))
+	s := newTTLStore(t, clk)
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+
+	clk.Advance(time.Hour)
+	if err := s.Create(makeTxn("a", 200, "USD", jan(5))); err != nil {
+		t.Fatalf("expected overwrite, got %v", err)
+	}
+
+	got, _ := s.Get("a")
+	if got.Amount != 200 || !got.CreatedAt.Equal(jan(1).Add(time.Hour)) {
+		t.Errorf("expected amount 200 created at %v, got %d at %v", jan(1).Add(time.Hour), got.Amount, got.CreatedAt)
+	}
+	if all, _ := s.List(10, 0); len(all) != 1 || !all[0].EffectiveAt.Equal(jan(5)) {
+		t.Errorf("expected one transaction at %v, got %+v", jan(5), all)
+	}
+}
+
+// Test: TestEvictExpired
+// What: EvictExpired removes only transactions older than the TTL, along with their Idempotency-Keys
+// Input: "old" at t0 with key k1, "new" at t0+30m with key k2; evict at t0+1h
+// Output: 1 evicted; "old" and k1 gone, "new" and k2 kept
+func TestEvictExpired(t *testing.T) {
+	clk := clock.NewFake(jan(1))
+	s := newTTLStore(t, clk)
+	_ = s.Create(makeTxn("old", 100, "USD", jan(1)))
+	_ = s.PutIdempotencyKey("k1", "old")
+	clk.Advance(30 * time.Minute)
+	_ = s.Create(makeTxn("new", 100, "USD", jan(2)))
+	_ = s.PutIdempotencyKey("k2", "new")
+
+	clk.Advance(30 * time.Minute)
+	if n := s.EvictExpired(); n != 1 {
+		t.Fatalf("expected 1 eviction, got %d", n)
+	}
+
+	if _, err := s.Get("old"); err != store.ErrNotFound {
+		t.Errorf("expected old to be evicted, got %v", err)
+	}
+	if _, err := s.GetByIdempotencyKey("k1"); err != store.ErrNotFound {
+		t.Errorf("expected k1 to be evicted, got %v", err)
+	}
+	if _, err := s.Get("new"); err != nil {
+		t.Errorf("expected new to be kept, got %v", err)
+	}
+	if id, _ := s.GetByIdempotencyKey("k2"); id != "new" {
+		t.Errorf("expected k2 -> new, got %q", id)
+	}
+	if got := s.Count(); got != 1 {
+		t.Errorf("expected Count 1, got %d", got)
+	}
+}
+
+// Test: TestEvictExpired_noTTL
+// What: a store built without a TTL never evicts
+// Input: NewMemoryStoreWithClock, one transaction, clock advanced a year
+// Output: 0 evicted; the transaction is still there
+func TestEvictExpired_noTTL(t *testing.T) {
+	clk := clock.NewFake(jan(1))
+	s := store.NewMemoryStoreWithClock(clk)
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+
+	clk.Advance(365 * 24 * time.Hour)
+	if n := s.EvictExpired(); n != 0 {
+		t.Errorf("expected 0 evictions, got %d", n)
+	}
+	if s.Count() != 1 {
+		t.Errorf("expected the transaction to be kept")
+	}
+}
+
+// Test: TestSweeper_evictsInBackground
+// What: the sweeper goroutine evicts expired transactions without an explicit call
+// Input: TTL 1h with a 1ms sweep; fake clock advanced past the TTL
+// Output: Count drops to 0 within a second
+func TestSweeper_evictsInBackground(t *testing.T) {
+	clk := clock.NewFake(jan(1))
+	s := store.NewMemoryStoreWithTTL(clk, time.Hour, time.Millisecond)
+	defer s.Close()
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+
+	clk.Advance(2 * time.Hour)
+	deadline := time.Now().Add(time.Second)
+	for s.Count() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("sweeper did not evict the expired transaction")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
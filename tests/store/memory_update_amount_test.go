@@ -0,0 +1,101 @@
+package store_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/synctera/tech-challenge/internal/clock"
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+func amountHistory(t *testing.T, txn model.Transaction) []model.AmountChange {
+	t.Helper()
+	var history []model.AmountChange
+	if err := json.Unmarshal([]byte(txn.Metadata[model.MetadataAmountHistory]), &history); err != nil {
+		t.Fatalf("invalid amount_history %q: %v", txn.Metadata[model.MetadataAmountHistory], err)
+	}
+	return history
+}
+
+// Test: TestUpdateAmount_recordsHistory
+// What: UpdateAmount changes the amount and appends each prior amount, stamped with the store clock
+// Input: "a" with amount 100 and metadata {source:web}; UpdateAmount to 150, then 120 a minute later
+// Output: amount 120; amount_history [{100, t0}, {150, t0+1m}]; other metadata, Seq and list position kept
+func TestUpdateAmount_recordsHistory(t *testing.T) {
+	t0 := time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(t0)
+	s := store.NewMemoryStoreWithClock(fake)
+	txn := makeTxn("a", 100, "USD", jan(1))
+	txn.Metadata = map[string]string{"source": "web"}
+	_ = s.Create(txn)
+	_ = s.Create(makeTxn("b", 200, "USD", jan(2)))
+	before, _ := s.Get("a")
+
+	if err := s.UpdateAmount("a", 150); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fake.Advance(time.Minute)
+	if err := s.UpdateAmount("a", 120); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, _ := s.Get("a")
+	if got.Amount != 120 {
+		t.Errorf("expected amount 120, got %d", got.Amount)
+	}
+	want := []model.AmountChange{{Amount: 100, ChangedAt: t0}, {Amount: 150, ChangedAt: t0.Add(time.Minute)}}
+	if history := amountHistory(t, got); !reflect.DeepEqual(history, want) {
+		t.Errorf("expected history %v, got %v", want, history)
+	}
+	if got.Metadata["source"] != "web" {
+		t.Errorf("expected other metadata to be kept, got %v", got.Metadata)
+	}
+	if got.Seq != before.Seq {
+		t.Errorf("expected Seq %d to be preserved, got %d", before.Seq, got.Seq)
+	}
+	list, _ := s.List(10, 0)
+	if list[0].ID != "a" || list[0].Amount != 120 {
+		t.Errorf("expected a first in the list with amount 120, got %+v", list[0])
+	}
+}
+
+// Test: TestUpdateAmount_sameAmountIsNoop
+// What: setting the amount a transaction already has records nothing
+// Input: "a" with amount 100; UpdateAmount to 100
+// Output: nil error; no amount_history; Version still 1
+func TestUpdateAmount_sameAmountIsNoop(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+
+	if err := s.UpdateAmount("a", 100); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, _ := s.Get("a")
+	if _, ok := got.Metadata[model.MetadataAmountHistory]; ok || got.Version != 1 {
+		t.Errorf("expected no change, got %+v", got)
+	}
+}
+
+// Test: TestUpdateAmount_errors
+// What: UpdateAmount reports unknown IDs, and UpdateAmountIfVersion rejects a stale version
+// Input: UpdateAmount on "missing"; UpdateAmountIfVersion on "a" with version 2 while it is at 1
+// Output: ErrNotFound; ErrPreconditionFailed and the amount unchanged
+func TestUpdateAmount_errors(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+
+	if err := s.UpdateAmount("missing", 1); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+	if err := s.UpdateAmountIfVersion("a", 2, 150); !errors.Is(err, store.ErrPreconditionFailed) {
+		t.Errorf("expected ErrPreconditionFailed, got %v", err)
+	}
+	if got, _ := s.Get("a"); got.Amount != 100 {
+		t.Errorf("expected amount 100, got %d", got.Amount)
+	}
+}
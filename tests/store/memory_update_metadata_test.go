@@ -0,0 +1,149 @@
+package store_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+func strPtr(s string) *string { return &s }
+
+// Test: TestUpdateMetadata_mergeSemantics
+// What: UpdateMetadata adds new keys, overwrites existing ones, and deletes keys patched to nil
+// Input: "a" with metadata {keep:1, change:old, drop:x}; patch {change:new, drop:nil, add:2}
+// Output: metadata {keep:1, change:new, add:2}; Seq and list position unchanged
+func TestUpdateMetadata_mergeSemantics(t *testing.T) {
+	s := store.NewMemoryStore()
+	txn := makeTxn("a", 100, "USD", jan(1))
+	txn.Metadata = map[string]string{"keep": "1", "change": "old", "drop": "x"}
+	_ = s.Create(txn)
+	_ = s.Create(makeTxn("b", 200, "USD", jan(2)))
+	before, _ := s.Get("a")
+
+	err := s.UpdateMetadata("a", map[string]*string{"change": strPtr("new"), "drop": nil, "add": strPtr("2")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, _ := s.Get("a")
+	want := map[string]string{"keep": "1", "change": "new", "add": "2"}
+	if !reflect.DeepEqual(got.Metadata, want) {
+		t.Errorf("expected metadata %v, got %v", want, got.Metadata)
+	}
+	if got.Seq != before.Seq {
+		t.Errorf("expected Seq %d to be preserved, got %d", before.Seq, got.Seq)
+	}
+
+	list, _ := s.List(10, 0)
+	if list[0].ID != "a" || !reflect.DeepEqual(list[0].Metadata, want) {
+		t.Errorf("expected ordered slice to hold the updated transaction first, got %+v", list[0])
+	}
+}
+
+// Test: TestUpdateMetadata_deleteLastKey
+// What: deleting every key leaves nil metadata
+// Input: "a" with metadata {k:v}; patch {k:nil}
+// Output: Metadata is nil
+func TestUpdateMetadata_deleteLastKey(t *testing.T) {
+	s := store.NewMemoryStore()
+	txn := makeTxn("a", 100, "USD", jan(1))
+	txn.Metadata = map[string]string{"k": "v"}
+	_ = s.Create(txn)
+
+	_ = s.UpdateMetadata("a", map[string]*string{"k": nil})
+
+	got, _ := s.Get("a")
+	if got.Metadata != nil {
+		t.Errorf("expected nil metadata, got %v", got.Metadata)
+	}
+}
+
+// Test: TestUpdateMetadata_notFound
+// What: UpdateMetadata on an unknown ID returns ErrNotFound
+// Input: empty store, UpdateMetadata("missing", ...)
+// Output: ErrNotFound
+func TestUpdateMetadata_notFound(t *testing.T) {
+	s := store.NewMemoryStore()
+
+	if err := s.UpdateMetadata("missing", map[string]*string{"k": strPtr("v")}); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// Test: TestUpdateMetadataIfVersion_versioning
+// What: every change bumps Version; a patch holding the current version applies, a stale one is rejected
+// Input: create "a" (version 1); UpdateMetadataIfVersion at 1, then again at 1, then Delete
+// Output: first patch succeeds (version 2), the stale one returns ErrPreconditionFailed and
+// leaves metadata unchanged, and Delete bumps the version to 3
+func TestUpdateMetadataIfVersion_versioning(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+	created, _ := s.Get("a")
+	if created.Version != 1 {
+		t.Fatalf("expected version 1 after create, got %d", created.Version)
+	}
+
+	if err := s.UpdateMetadataIfVersion("a", 1, map[string]*string{"k": strPtr("first")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.UpdateMetadataIfVersion("a", 1, map[string]*string{"k": strPtr("stale")}); !errors.Is(err, store.ErrPreconditionFailed) {
+		t.Errorf("expected ErrPreconditionFailed for a stale version, got %v", err)
+	}
+
+	got, _ := s.Get("a")
+	if got.Version != 2 || got.Metadata["k"] != "first" {
+		t.Errorf("expected version 2 with k=first, got version %d metadata %v", got.Version, got.Metadata)
+	}
+
+	_ = s.Delete("a")
+	if got, _ := s.Get("a"); got.Version != 3 {
+		t.Errorf("expected Delete to bump the version to 3, got %d", got.Version)
+	}
+}
+
+// Test: TestUpdateMetadata_concurrentPatchesRespectLimit
+// What: concurrent patches that each fit under MaxMetadataEntries can't together exceed it,
+// because the store checks the merged metadata under its write lock
+// Input: "a" with MaxMetadataEntries-1 keys; 8 goroutines each add a different new key
+// Output: exactly one patch succeeds, the rest return ErrTooManyMetadataEntries, and the
+// stored metadata has MaxMetadataEntries keys
+func TestUpdateMetadata_concurrentPatchesRespectLimit(t *testing.T) {
+	s := store.NewMemoryStore()
+	txn := makeTxn("a", 100, "USD", jan(1))
+	txn.Metadata = map[string]string{}
+	for i := range model.MaxMetadataEntries - 1 {
+		txn.Metadata[fmt.Sprintf("k%02d", i)] = "v"
+	}
+	_ = s.Create(txn)
+
+	const writers = 8
+	var errs [writers]error
+	var wg sync.WaitGroup
+	for i := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = s.UpdateMetadata("a", map[string]*string{fmt.Sprintf("new%d", i): strPtr("v")})
+		}()
+	}
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, store.ErrTooManyMetadataEntries):
+			t.Errorf("expected nil or ErrTooManyMetadataEntries, got %v", err)
+		}
+	}
+	got, _ := s.Get("a")
+	if succeeded != 1 || len(got.Metadata) != model.MaxMetadataEntries {
+		t.Errorf("expected 1 success and %d keys, got %d successes and %d keys", model.MaxMetadataEntries, succeeded, len(got.Metadata))
+	}
+}
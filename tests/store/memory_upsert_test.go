@@ -0,0 +1,117 @@
+package store_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/model"
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestUpsert_insertsNew
+// What: Upsert stores a transaction under a new ID and reports it as created
+// Input: empty store; Upsert "a"
+// Output: created=true, nil error; "a" is stored with Seq 1 and Version 1
+func TestUpsert_insertsNew(t *testing.T) {
+	s := store.NewMemoryStore()
+
+	created, err := s.Upsert(makeTxn("a", 100, "USD", jan(1)))
+
+	if !created || err != nil {
+		t.Fatalf("expected created=true and nil error, got %v, %v", created, err)
+	}
+	got, _ := s.Get("a")
+	if got.Seq != 1 || got.Version != 1 {
+		t.Errorf("expected Seq 1 and Version 1, got %+v", got)
+	}
+}
+
+// Test: TestUpsert_replacesExisting
+// What: Upsert replaces a stored transaction with a different payload and re-sorts it
+// Input: "a" on Jan 1 and "b" on Jan 2; Upsert "a" with amount 500 on Jan 3
+// Output: created=false; "a" has amount 500, its original Seq and CreatedAt, Version 2; list order [b a]
+func TestUpsert_replacesExisting(t *testing.T) {
+	s := store.NewMemoryStore()
+	_ = s.Create(makeTxn("a", 100, "USD", jan(1)))
+	_ = s.Create(makeTxn("b", 200, "USD", jan(2)))
+	before, _ := s.Get("a")
+
+	created, err := s.Upsert(makeTxn("a", 500, "USD", jan(3)))
+
+	if created || err != nil {
+		t.Fatalf("expected created=false and nil error, got %v, %v", created, err)
+	}
+	got, _ := s.Get("a")
+	if got.Amount != 500 || got.Seq != before.Seq || !got.CreatedAt.Equal(before.CreatedAt) || got.Version != 2 {
+		t.Errorf("expected amount 500, Seq %d, original CreatedAt and Version 2, got %+v", before.Seq, got)
+	}
+	list, _ := s.List(10, 0)
+	if ids := ids(list); !reflect.DeepEqual(ids, []string{"b", "a"}) {
+		t.Errorf("expected order [b a], got %v", ids)
+	}
+}
+
+// Test: TestUpsert_identicalIsNoop
+// What: upserting the stored payload again changes nothing, while Create still conflicts on a different one
+// Input: "a"; Upsert identical "a"; then Create "a" with a different amount
+// Output: created=false, Version still 1; Create returns ErrConflict
+func TestUpsert_identicalIsNoop(t *testing.T) {
+	s := store.NewMemoryStore()
+	txn := makeTxn("a", 100, "USD", jan(1))
+	_ = s.Create(txn)
+
+	created, err := s.Upsert(txn)
+
+	if created || err != nil {
+		t.Fatalf("expected created=false and nil error, got %v, %v", created, err)
+	}
+	if got, _ := s.Get("a"); got.Version != 1 {
+		t.Errorf("expected Version 1, got %d", got.Version)
+	}
+	if err := s.Create(makeTxn("a", 999, "USD", jan(1))); !errors.Is(err, store.ErrConflict) {
+		t.Errorf("expected ErrConflict from Create, got %v", err)
+	}
+}
+
+// Test: TestUpsert_keepsServerMetadata
+// What: replacing a reversed transaction keeps reversed_by, records the amount change, and
+// leaves it unreversible; re-sending the same upsert is still a no-op
+// Input: "a" (100) reversed by "a-rev"; Upsert "a" with amount 999 and metadata {note: x};
+// the same upsert again; Reverse "a" again
+// Output: reversed_by=a-rev, note=x, amount_history with one entry for 100, Version 3; the repeat
+// keeps Version 3; the second Reverse returns ErrAlreadyReversed
+func TestUpsert_keepsServerMetadata(t *testing.T) {
+	s := store.NewMemoryStore()
+	original := makeTxn("a", 100, "USD", jan(1))
+	_ = s.Create(original)
+	if err := s.Reverse("a", original.Reversal("a-rev", jan(2))); err != nil {
+		t.Fatalf("Reverse failed: %v", err)
+	}
+
+	replacement := makeTxn("a", 999, "USD", jan(1))
+	replacement.Metadata = map[string]string{"note": "x"}
+	if _, err := s.Upsert(replacement); err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	got, _ := s.Get("a")
+	var history []model.AmountChange
+	if err := json.Unmarshal([]byte(got.Metadata[model.MetadataAmountHistory]), &history); err != nil {
+		t.Fatalf("invalid amount_history %q: %v", got.Metadata[model.MetadataAmountHistory], err)
+	}
+	if got.Amount != 999 || got.Metadata[model.MetadataReversedBy] != "a-rev" || got.Metadata["note"] != "x" ||
+		len(history) != 1 || history[0].Amount != 100 || got.Version != 3 {
+		t.Errorf("unexpected transaction after upsert: %+v", got)
+	}
+
+	if _, err := s.Upsert(replacement); err != nil {
+		t.Fatalf("repeat Upsert failed: %v", err)
+	}
+	if again, _ := s.Get("a"); again.Version != 3 {
+		t.Errorf("expected the repeat to be a no-op at Version 3, got %d", again.Version)
+	}
+	if err := s.Reverse("a", original.Reversal("a-rev-2", jan(3))); !errors.Is(err, store.ErrAlreadyReversed) {
+		t.Errorf("expected ErrAlreadyReversed, got %v", err)
+	}
+}
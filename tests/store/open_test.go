@@ -0,0 +1,88 @@
+package store_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/synctera/tech-challenge/internal/store"
+)
+
+// Test: TestOpen_memory
+// What: the memory scheme resolves to a MemoryStore
+// Input: Open("memory://")
+// Output: a *store.MemoryStore, no error
+func TestOpen_memory(t *testing.T) {
+	s, err := store.Open(store.DefaultDSN)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if _, ok := s.(*store.MemoryStore); !ok {
+		t.Errorf("expected *store.MemoryStore, got %T", s)
+	}
+}
+
+// Test: TestOpen_file
+// What: the file scheme resolves to a FileStore whose data survives reopening the same path
+// Input: Open(file://<tmp>/txns.json); create a1 under key k1, soft-delete it; reopen; create a2
+// Output: a *store.FileStore; after reopening a1 is still deleted with Seq 1, k1 -> a1, and a2 gets Seq 2
+func TestOpen_file(t *testing.T) {
+	dsn := "file://" + filepath.Join(t.TempDir(), "txns.json")
+
+	s, err := store.Open(dsn)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if _, ok := s.(*store.FileStore); !ok {
+		t.Fatalf("expected *store.FileStore, got %T", s)
+	}
+	_, _ = s.CreateWithIdempotencyKey("k1", makeAccountTxn("a1", "acct-1", 1), false)
+	_ = s.Delete("a1")
+
+	reopened, err := store.Open(dsn)
+	if err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	got, err := reopened.Get("a1")
+	if err != nil || !got.Deleted || got.Seq != 1 {
+		t.Errorf("expected a1 deleted with seq 1, got %+v, %v", got, err)
+	}
+	if id, _ := reopened.(*store.FileStore).GetByIdempotencyKey("k1"); id != "a1" {
+		t.Errorf("expected k1 -> a1, got %q", id)
+	}
+	if acct, _ := reopened.QueryAccount("acct-1", nil); len(acct) != 1 {
+		t.Errorf("expected the account index to be rebuilt, got %v", ids(acct))
+	}
+
+	_ = reopened.Create(makeAccountTxn("a2", "acct-1", 2))
+	if got, _ := reopened.Get("a2"); got.Seq != 2 {
+		t.Errorf("expected a2 to get seq 2, got %d", got.Seq)
+	}
+}
+
+// Test: TestOpen_fileCorrupt
+// What: a snapshot that isn't valid JSON is reported rather than silently starting empty
+// Input: a file containing "not json"; Open(file://<that file>)
+// Output: an error
+func TestOpen_fileCorrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "txns.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Open("file://" + path); err == nil {
+		t.Error("expected an error for a corrupt snapshot")
+	}
+}
+
+// Test: TestOpen_invalid
+// What: unknown schemes, a file DSN without a path, and unparseable DSNs are rejected
+// Input: "postgres://localhost/txns", "file://", "", "://bad"
+// Output: an error for each
+func TestOpen_invalid(t *testing.T) {
+	for _, dsn := range []string{"postgres://localhost/txns", "file://", "", "://bad"} {
+		if s, err := store.Open(dsn); err == nil {
+			t.Errorf("%q: expected an error, got %T", dsn, s)
+		}
+	}
+}
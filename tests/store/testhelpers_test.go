@@ -18,3 +18,11 @@ func makeTxn(id string, amount int64, currency string, effectiveAt time.Time) mo
 func jan(day int) time.Time {
 	return time.Date(2024, time.January, day, 0, 0, 0, 0, time.UTC)
 }
+
+func ids(txns []model.Transaction) []string {
+	out := make([]string, len(txns))
+	for i, txn := range txns {
+		out[i] = txn.ID
+	}
+	return out
+}